@@ -11,14 +11,11 @@ import (
 	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"hash"
 	"io"
 	"io/ioutil"
-	"log"
 	"math"
-	"net/http"
 	"net/url"
 	"os"
 	"strconv"
@@ -204,70 +201,11 @@ func CalculateNetProfit(amount, priceThen, priceNow, costs float64) float64 {
 }
 
 func SendHTTPRequest(method, path string, headers map[string]string, body io.Reader) (string, error) {
-	result := strings.ToUpper(method)
-
-	if result != "POST" && result != "GET" && result != "DELETE" {
-		return "", errors.New("Invalid HTTP method specified.")
-	}
-
-	req, err := http.NewRequest(method, path, body)
-
-	if err != nil {
-		return "", err
-	}
-
-	for k, v := range headers {
-		req.Header.Add(k, v)
-	}
-
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
-
-	if err != nil {
-		return "", err
-	}
-
-	contents, err := ioutil.ReadAll(resp.Body)
-	defer resp.Body.Close()
-
-	if err != nil {
-		return "", err
-	}
-
-	return string(contents), nil
+	return DefaultRequester.SendHTTPRequest(method, path, headers, body)
 }
 
 func SendHTTPGetRequest(url string, jsonDecode bool, result interface{}) (err error) {
-	res, err := http.Get(url)
-
-	if err != nil {
-		return err
-	}
-
-	if res.StatusCode != 200 {
-		log.Printf("HTTP status code: %d\n", res.StatusCode)
-		return errors.New("Status code was not 200.")
-	}
-
-	contents, err := ioutil.ReadAll(res.Body)
-
-	if err != nil {
-		return err
-	}
-
-	defer res.Body.Close()
-
-	if jsonDecode {
-		err := JSONDecode(contents, &result)
-		if err != nil {
-			log.Println(string(contents[:]))
-			return err
-		}
-	} else {
-		result = &contents
-	}
-
-	return nil
+	return DefaultRequester.SendHTTPGetRequest(url, jsonDecode, result)
 }
 
 func JSONEncode(v interface{}) ([]byte, error) {
@@ -3,6 +3,8 @@ package common
 import (
 	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 	"time"
@@ -304,3 +306,32 @@ func TestGetURIPath(t *testing.T) {
 		}
 	}
 }
+
+func TestSendHTTPGetRequest(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"Rate limit exceeded"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"ok"}`))
+	}))
+	defer server.Close()
+
+	result := make(map[string]string)
+	err := SendHTTPGetRequest(server.URL+"/ok", true, &result)
+	if err != nil || result["result"] != "ok" {
+		t.Error(fmt.Sprintf("Test failed. Unexpected result %v error %v.", result, err))
+	}
+
+	err = SendHTTPGetRequest(server.URL+"/limited", true, &result)
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatal("Test failed. Expected an HTTPError.")
+	}
+
+	if httpErr.StatusCode != http.StatusTooManyRequests || httpErr.Body != `{"message":"Rate limit exceeded"}` {
+		t.Error(fmt.Sprintf("Test failed. Unexpected HTTPError %d %s.", httpErr.StatusCode, httpErr.Body))
+	}
+}
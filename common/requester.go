@@ -0,0 +1,170 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	HTTP_DEFAULT_TIMEOUT       = time.Second * 30
+	HTTP_DEFAULT_RETRIES       = 2
+	HTTP_DEFAULT_RETRY_BACKOFF = time.Second
+)
+
+var DefaultRequester = NewRequester(HTTP_DEFAULT_TIMEOUT, HTTP_DEFAULT_RETRIES)
+
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (h *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP status code: %d", h.StatusCode)
+}
+
+//Requester sends HTTP requests with a shared client. Idempotent requests which
+//fail with a transport error or a 5xx status are retried with an exponential
+//backoff. POST requests are never retried, as the exchange may have processed
+//them before the failure was reported
+type Requester struct {
+	HTTPClient   *http.Client
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+func NewRequester(timeout time.Duration, maxRetries int) *Requester {
+	return &Requester{
+		HTTPClient:   &http.Client{Timeout: timeout, Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}},
+		MaxRetries:   maxRetries,
+		RetryBackoff: HTTP_DEFAULT_RETRY_BACKOFF,
+	}
+}
+
+func (r *Requester) SetProxy(proxy string) error {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return errors.New("Invalid proxy URL specified.")
+	}
+
+	r.HTTPClient.Transport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	return nil
+}
+
+func (r *Requester) SetTransport(transport http.RoundTripper) {
+	r.HTTPClient.Transport = transport
+}
+
+func (r *Requester) SendHTTPRequest(method, path string, headers map[string]string, body io.Reader) (string, error) {
+	contents, _, err := r.SendHTTPRequestWithHeaders(method, path, headers, body)
+	return contents, err
+}
+
+//SendHTTPRequestWithHeaders sends the request as SendHTTPRequest and also
+//returns the response headers, which some exchanges use for paging cursors
+func (r *Requester) SendHTTPRequestWithHeaders(method, path string, headers map[string]string, body io.Reader) (string, http.Header, error) {
+	contents, statusCode, responseHeaders, err := r.send(method, path, headers, body)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if statusCode >= 400 {
+		return contents, responseHeaders, &HTTPError{StatusCode: statusCode, Body: contents}
+	}
+
+	return contents, responseHeaders, nil
+}
+
+func (r *Requester) SendHTTPGetRequest(url string, jsonDecode bool, result interface{}) error {
+	contents, statusCode, _, err := r.send("GET", url, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 200 {
+		log.Printf("HTTP status code: %d\n", statusCode)
+		return &HTTPError{StatusCode: statusCode, Body: contents}
+	}
+
+	if jsonDecode {
+		err := JSONDecode([]byte(contents), &result)
+		if err != nil {
+			log.Println(contents)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Requester) send(method, path string, headers map[string]string, body io.Reader) (string, int, http.Header, error) {
+	method = strings.ToUpper(method)
+
+	if method != "POST" && method != "GET" && method != "DELETE" {
+		return "", 0, nil, errors.New("Invalid HTTP method specified.")
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = ioutil.ReadAll(body)
+		if err != nil {
+			return "", 0, nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		contents, statusCode, responseHeaders, err := r.doRequest(method, path, headers, payload)
+		retryable := err != nil || statusCode >= 500
+		if !retryable || method == "POST" || attempt >= r.MaxRetries {
+			return contents, statusCode, responseHeaders, err
+		}
+
+		backoff := r.RetryBackoff * time.Duration(1<<uint(attempt))
+		if err == nil {
+			err = &HTTPError{StatusCode: statusCode, Body: contents}
+		}
+		log.Printf("%s request to %s failed: %s. Retrying in %s.\n", method, path, err, backoff)
+		time.Sleep(backoff)
+	}
+}
+
+func (r *Requester) doRequest(method, path string, headers map[string]string, payload []byte) (string, int, http.Header, error) {
+	req, err := http.NewRequest(method, path, bytes.NewReader(payload))
+
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Add(k, v)
+	}
+
+	resp, err := r.HTTPClient.Do(req)
+
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	contents, err := ioutil.ReadAll(resp.Body)
+	defer resp.Body.Close()
+
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	return string(contents), resp.StatusCode, resp.Header, nil
+}
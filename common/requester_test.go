@@ -0,0 +1,52 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequesterRetry(t *testing.T) {
+	t.Parallel()
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"result":"ok"}`))
+	}))
+	defer server.Close()
+
+	requester := NewRequester(time.Second, 2)
+	requester.RetryBackoff = time.Millisecond
+
+	result := make(map[string]string)
+	err := requester.SendHTTPGetRequest(server.URL, true, &result)
+	if err != nil || result["result"] != "ok" || attempts != 3 {
+		t.Errorf("Test failed. Unexpected result %v error %v after %d attempts.", result, err, attempts)
+	}
+
+	attempts = 0
+	_, err = requester.SendHTTPRequest("POST", server.URL, nil, nil)
+	if err == nil || attempts != 1 {
+		t.Errorf("Test failed. POST request retried %d times.", attempts-1)
+	}
+}
+
+func TestRequesterSetProxy(t *testing.T) {
+	t.Parallel()
+	requester := NewRequester(time.Second, 0)
+
+	err := requester.SetProxy("proxy")
+	if err == nil {
+		t.Error("Test failed. SetProxy() accepted an invalid proxy.")
+	}
+
+	err = requester.SetProxy("http://127.0.0.1:3128")
+	if err != nil {
+		t.Errorf("Test failed. SetProxy() error: %s", err)
+	}
+}
@@ -25,12 +25,18 @@ const (
 	CONFIG_FILE_ENCRYPTION_DISABLED = -1
 )
 
+const (
+	EXCHANGE_PLATFORM_ALPHAPOINT = "Alphapoint"
+)
+
 var (
 	ErrExchangeNameEmpty                            = "Exchange #%d in config: Exchange name is empty."
 	ErrExchangeAvailablePairsEmpty                  = "Exchange %s: Available pairs is empty."
 	ErrExchangeEnabledPairsEmpty                    = "Exchange %s: Enabled pairs is empty."
 	ErrExchangeBaseCurrenciesEmpty                  = "Exchange %s: Base currencies is empty."
 	ErrExchangeNotFound                             = "Exchange %s: Not found."
+	ErrExchangePlatformNotSupported                 = "Exchange %s: Platform %s is not supported."
+	ErrExchangePlatformURLsEmpty                    = "Exchange %s: Platform %s requires the venue APIUrl and WebsocketURL."
 	ErrNoEnabledExchanges                           = "No Exchanges enabled."
 	ErrCryptocurrenciesEmpty                        = "Cryptocurrencies variable is empty."
 	ErrFailureOpeningConfig                         = "Fatal error opening %s file. Error: %s"
@@ -79,19 +85,35 @@ type Config struct {
 	Exchanges        []ExchangeConfig        `json:"Exchanges"`
 }
 
+//ExchangeConfig : Settings of a single exchange. Platform runs the exchange on
+//the client of a shared trading platform such as Alphapoint, so several venues
+//of one platform can be configured under different names
 type ExchangeConfig struct {
-	Name                    string
-	Enabled                 bool
-	Verbose                 bool
-	Websocket               bool
-	RESTPollingDelay        time.Duration
-	AuthenticatedAPISupport bool
-	APIKey                  string
-	APISecret               string
-	ClientID                string `json:",omitempty"`
-	AvailablePairs          string
-	EnabledPairs            string
-	BaseCurrencies          string
+	Name                             string
+	Platform                         string `json:",omitempty"`
+	Enabled                          bool
+	Verbose                          bool
+	Websocket                        bool
+	RESTPollingDelay                 time.Duration
+	AuthenticatedAPISupport          bool
+	APIKey                           string
+	APISecret                        string
+	ClientID                         string `json:",omitempty"`
+	AvailablePairs                   string
+	EnabledPairs                     string
+	BaseCurrencies                   string
+	AuthenticatedRequestsPerMinute   int    `json:",omitempty"`
+	UnauthenticatedRequestsPerMinute int    `json:",omitempty"`
+	RequestBurst                     int    `json:",omitempty"`
+	APIUrl                           string `json:",omitempty"`
+	WebsocketURL                     string `json:",omitempty"`
+	HTTPTimeoutSeconds               int    `json:",omitempty"`
+	HTTPRetries                      int    `json:",omitempty"`
+	HTTPProxy                        string `json:",omitempty"`
+	StaleDataSeconds                 int    `json:",omitempty"`
+	RecordDirectory                  string `json:",omitempty"`
+	ReplayFile                       string `json:",omitempty"`
+	ReplayRealtime                   bool   `json:",omitempty"`
 }
 
 func (c *Config) GetConfigEnabledExchanges() int {
@@ -163,12 +185,18 @@ func (c *Config) CheckExchangeConfigValues() error {
 			if exch.BaseCurrencies == "" {
 				return fmt.Errorf(ErrExchangeBaseCurrenciesEmpty, exch.Name)
 			}
+			if exch.Platform != "" && exch.Platform != EXCHANGE_PLATFORM_ALPHAPOINT {
+				return fmt.Errorf(ErrExchangePlatformNotSupported, exch.Name, exch.Platform)
+			}
+			if exch.Platform != "" && (exch.APIUrl == "" || exch.WebsocketURL == "") {
+				return fmt.Errorf(ErrExchangePlatformURLsEmpty, exch.Name, exch.Platform)
+			}
 			if exch.AuthenticatedAPISupport { // non-fatal error
 				if exch.APIKey == "" || exch.APISecret == "" || exch.APIKey == "Key" || exch.APISecret == "Secret" {
 					c.Exchanges[i].AuthenticatedAPISupport = false
 					log.Printf(WarningExchangeAuthAPIDefaultOrEmptyValues, exch.Name)
 					continue
-				} else if exch.Name == "ITBIT" || exch.Name == "Bitstamp" || exch.Name == "Coinbase" {
+				} else if exch.Name == "ITBIT" || exch.Name == "Bitstamp" || exch.Name == "Coinbase" || exch.Name == "Alphapoint" || exch.Platform == EXCHANGE_PLATFORM_ALPHAPOINT {
 					if exch.ClientID == "" || exch.ClientID == "ClientID" {
 						c.Exchanges[i].AuthenticatedAPISupport = false
 						log.Printf(WarningExchangeAuthAPIDefaultOrEmptyValues, exch.Name)
@@ -81,6 +81,46 @@ func TestCheckExchangeConfigValues(t *testing.T) {
 	}
 }
 
+func TestCheckExchangeConfigPlatform(t *testing.T) {
+	t.Parallel()
+
+	exch := ExchangeConfig{Name: "Regional", Enabled: true, Platform: EXCHANGE_PLATFORM_ALPHAPOINT, AvailablePairs: "BTCUSD", EnabledPairs: "BTCUSD", BaseCurrencies: "USD", AuthenticatedAPISupport: true, APIKey: "key", APISecret: "secret", ClientID: "id", APIUrl: "https://api.example.com", WebsocketURL: "wss://ws.example.com/"}
+	checkExchangeConfigPlatform := Config{Cryptocurrencies: "BTC", Exchanges: []ExchangeConfig{exch}}
+	err := checkExchangeConfigPlatform.CheckExchangeConfigValues()
+	if err != nil || !checkExchangeConfigPlatform.Exchanges[0].AuthenticatedAPISupport {
+		t.Errorf("Test failed. CheckExchangeConfigValues rejected an Alphapoint exchange: %v", err)
+	}
+
+	exch.ClientID = ""
+	checkExchangeConfigPlatform.Exchanges = []ExchangeConfig{exch}
+	checkExchangeConfigPlatform.CheckExchangeConfigValues()
+	if checkExchangeConfigPlatform.Exchanges[0].AuthenticatedAPISupport {
+		t.Error("Test failed. CheckExchangeConfigValues accepted an Alphapoint exchange without a ClientID")
+	}
+
+	exch.WebsocketURL = ""
+	checkExchangeConfigPlatform.Exchanges = []ExchangeConfig{exch}
+	err = checkExchangeConfigPlatform.CheckExchangeConfigValues()
+	if err == nil {
+		t.Error("Test failed. CheckExchangeConfigValues accepted an Alphapoint exchange without a WebsocketURL")
+	}
+
+	exch.WebsocketURL = "wss://ws.example.com/"
+	exch.APIUrl = ""
+	checkExchangeConfigPlatform.Exchanges = []ExchangeConfig{exch}
+	err = checkExchangeConfigPlatform.CheckExchangeConfigValues()
+	if err == nil {
+		t.Error("Test failed. CheckExchangeConfigValues accepted an Alphapoint exchange without an APIUrl")
+	}
+
+	exch.Platform = "Unknown"
+	checkExchangeConfigPlatform.Exchanges = []ExchangeConfig{exch}
+	err = checkExchangeConfigPlatform.CheckExchangeConfigValues()
+	if err == nil {
+		t.Error("Test failed. CheckExchangeConfigValues accepted an unknown platform")
+	}
+}
+
 func TestCheckWebserverConfigValues(t *testing.T) {
 	t.Parallel()
 
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 	"strconv"
-	"time"
 
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
@@ -127,14 +126,12 @@ func (e *Event) CheckCondition() bool { //Add error handling
 	condition := common.SplitStrings(e.Condition, ",")
 	targetPrice, _ := strconv.ParseFloat(condition[1], 64)
 
-	ticker, err := ticker.GetTickerByExchange(e.Exchange)
+	t, err := ticker.GetTicker(e.Exchange, pair.NewCurrencyPair(e.FirstCurrency, e.SecondCurrency))
 	if err != nil {
 		return false
 	}
 
-	t := ticker.Price[pair.CurrencyItem(e.FirstCurrency)][pair.CurrencyItem(e.SecondCurrency)]
-
-	lastPrice = ticker.Price[pair.CurrencyItem(e.FirstCurrency)][pair.CurrencyItem(e.SecondCurrency)].Last
+	lastPrice = t.Last
 
 	if lastPrice == 0 {
 		return false
@@ -202,24 +199,34 @@ func IsValidEvent(Exchange, Item, Condition string, Action func(e *Event, t *tic
 	return nil
 }
 
+//CheckEvents checks the pending events of an exchange pair whenever the ticker
+//store pushes a price change for it
 func CheckEvents() {
-	for {
-		total, executed := GetEventCounter()
-		if total > 0 && executed != total {
-			for _, event := range Events {
-				if !event.Executed {
-					success := event.CheckCondition()
-					if success {
-						log.Printf("Event %d triggered on %s successfully.\n", event.ID, event.Exchange)
-						event.Executed = true
-					}
-				}
+	subscription := ticker.Subscribe("", pair.CurrencyPair{})
+	defer ticker.Unsubscribe(subscription)
+
+	for price := range subscription.C {
+		for _, event := range Events {
+			if event.Executed || !event.MatchesTicker(price) {
+				continue
+			}
+
+			success := event.CheckCondition()
+			if success {
+				log.Printf("Event %d triggered on %s successfully.\n", event.ID, event.Exchange)
+				event.Executed = true
 			}
 		}
-		time.Sleep(time.Second)
 	}
 }
 
+//MatchesTicker returns whether the ticker price is for the event exchange pair
+func (e *Event) MatchesTicker(price ticker.TickerPrice) bool {
+	return e.Exchange == price.ExchangeName &&
+		pair.CurrencyItem(e.FirstCurrency) == price.Pair.GetFirstCurrency() &&
+		pair.CurrencyItem(e.SecondCurrency) == price.Pair.GetSecondCurrency()
+}
+
 func IsValidCurrency(currencies ...string) bool {
 	for _, whatIsIt := range currencies {
 		whatIsIt = common.StringToUpper(whatIsIt)
@@ -6,14 +6,14 @@ import (
 	"fmt"
 	"log"
 	"strconv"
-	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/config"
 	"github.com/champii/gocryptotrader/exchanges"
 )
 
 const (
+	ALPHAPOINT_DEFAULT_NAME      = "Alphapoint"
 	ALPHAPOINT_DEFAULT_API_URL   = "https://sim3.alphapoint.com:8400"
 	ALPHAPOINT_API_VERSION       = "1"
 	ALPHAPOINT_TICKER            = "GetTicker"
@@ -36,14 +36,71 @@ const (
 	ALPHAPOINT_ORDER_FEE         = "GetOrderFee"
 )
 
+const (
+	ALPHAPOINT_RECENT_TRADES_COUNT = 100
+	ALPHAPOINT_AUTH_RATE_LIMIT     = 60
+	ALPHAPOINT_UNAUTH_RATE_LIMIT   = 60
+)
+
+//Alphapoint : Client of the Alphapoint trading platform. Every venue running on
+//the platform is a separate instance with its own name and endpoints
 type Alphapoint struct {
 	exchange.ExchangeBase
-	WebsocketConn *websocket.Conn
+	WebsocketConn *exchange.WebsocketConnection
 }
 
+//NewAlphapoint returns an instance for the Alphapoint venue configured under
+//name. SetDefaults keeps the name
+func NewAlphapoint(name string) *Alphapoint {
+	a := new(Alphapoint)
+	a.Name = name
+	return a
+}
+
+//SetDefaults points the default exchange at the Alphapoint sandbox. Named
+//venues have no endpoints until Setup applies their own
 func (a *Alphapoint) SetDefaults() {
-	a.APIUrl = ALPHAPOINT_DEFAULT_API_URL
-	a.WebsocketURL = ALPHAPOINT_DEFAULT_WEBSOCKET_URL
+	if a.Name == "" {
+		a.Name = ALPHAPOINT_DEFAULT_NAME
+	}
+	a.Enabled = false
+	a.Verbose = false
+	a.Websocket = false
+	a.RESTPollingDelay = 10
+	a.APIUrl = ""
+	a.WebsocketURL = ""
+	if a.Name == ALPHAPOINT_DEFAULT_NAME {
+		a.APIUrl = ALPHAPOINT_DEFAULT_API_URL
+		a.WebsocketURL = ALPHAPOINT_DEFAULT_WEBSOCKET_URL
+	}
+	a.SetRateLimit(ALPHAPOINT_AUTH_RATE_LIMIT, ALPHAPOINT_UNAUTH_RATE_LIMIT)
+	a.WebsocketConn = a.newWebsocketConnection()
+}
+
+//Setup applies the exchange config. A named venue without its endpoints is
+//disabled rather than run against the sandbox
+func (a *Alphapoint) Setup(exch config.ExchangeConfig) {
+	if !exch.Enabled {
+		a.SetEnabled(false)
+	} else if a.Name != ALPHAPOINT_DEFAULT_NAME && (exch.APIUrl == "" || exch.WebsocketURL == "") {
+		log.Printf(config.ErrExchangePlatformURLsEmpty+"\n", a.GetName(), config.EXCHANGE_PLATFORM_ALPHAPOINT)
+		a.SetEnabled(false)
+	} else {
+		a.Enabled = true
+		a.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
+		a.SetAPIKeys(exch.APIKey, exch.APISecret, exch.ClientID, false)
+		a.RESTPollingDelay = exch.RESTPollingDelay
+		a.UpdateRateLimit(exch)
+		err := a.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", a.GetName(), err)
+		}
+		a.Verbose = exch.Verbose
+		a.Websocket = exch.Websocket
+		a.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
+		a.AvailablePairs = common.SplitStrings(exch.AvailablePairs, ",")
+		a.EnabledPairs = common.SplitStrings(exch.EnabledPairs, ",")
+	}
 }
 
 func (a *Alphapoint) GetTicker(symbol string) (AlphapointTicker, error) {
@@ -274,7 +331,7 @@ func (a *Alphapoint) CreateOrder(symbol, side string, orderType int, quantity, p
 	return response.ServerOrderID, nil
 }
 
-func (a *Alphapoint) ModifyOrder(symbol string, OrderID, action int64) (int64, error) {
+func (a *Alphapoint) ModifyExistingOrder(symbol string, OrderID, action int64) (int64, error) {
 	request := make(map[string]interface{})
 	request["ins"] = symbol
 	request["serverOrderId"] = OrderID
@@ -300,7 +357,7 @@ func (a *Alphapoint) ModifyOrder(symbol string, OrderID, action int64) (int64, e
 	return response.ModifyOrderID, nil
 }
 
-func (a *Alphapoint) CancelOrder(symbol string, OrderID int64) (int64, error) {
+func (a *Alphapoint) CancelExistingOrder(symbol string, OrderID int64) (int64, error) {
 	request := make(map[string]interface{})
 	request["ins"] = symbol
 	request["serverOrderId"] = OrderID
@@ -386,6 +443,7 @@ func (a *Alphapoint) GetOrderFee(symbol, side string, quantity, price float64) (
 }
 
 func (a *Alphapoint) SendRequest(method, path string, data map[string]interface{}, result interface{}) error {
+	a.WaitRateLimit(false)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
 	path = fmt.Sprintf("%s/ajax/v%s/%s", a.APIUrl, ALPHAPOINT_API_VERSION, path)
@@ -394,25 +452,31 @@ func (a *Alphapoint) SendRequest(method, path string, data map[string]interface{
 	if err != nil {
 		return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
 	}
-	resp, err := common.SendHTTPRequest(method, path, headers, bytes.NewBuffer(PayloadJson))
+	resp, err := a.SendHTTPRequest(method, path, headers, bytes.NewBuffer(PayloadJson))
 
 	if err != nil {
-		return err
+		return exchange.NewExchangeError(a.Name, err, resp)
+	}
+
+	errResponse := AlphapointErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && !errResponse.IsAccepted && errResponse.RejectReason != "" {
+		return exchange.NewExchangeError(a.Name, errors.New(errResponse.RejectReason), resp)
 	}
 
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return exchange.NewExchangeError(a.Name, errors.New("Unable to JSON Unmarshal response."), resp)
 	}
 	return nil
 }
 
 func (a *Alphapoint) SendAuthenticatedHTTPRequest(method, path string, data map[string]interface{}, result interface{}) error {
+	a.WaitRateLimit(true)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
 	data["apiKey"] = a.APIKey
-	nonce := time.Now().UnixNano()
+	nonce := a.GetNonce().Get()
 	nonceStr := strconv.FormatInt(nonce, 10)
 	data["apiNonce"] = nonce
 	hmac := common.GetHMAC(common.HASH_SHA256, []byte(nonceStr+a.ClientID+a.APIKey), []byte(a.APISecret))
@@ -424,16 +488,21 @@ func (a *Alphapoint) SendAuthenticatedHTTPRequest(method, path string, data map[
 		return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
 	}
 
-	resp, err := common.SendHTTPRequest(method, path, headers, bytes.NewBuffer(PayloadJson))
+	resp, err := a.SendHTTPRequest(method, path, headers, bytes.NewBuffer(PayloadJson))
 
 	if err != nil {
-		return err
+		return a.CheckNonceError(exchange.NewExchangeError(a.Name, err, resp))
+	}
+
+	errResponse := AlphapointErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && !errResponse.IsAccepted && errResponse.RejectReason != "" {
+		return a.CheckNonceError(exchange.NewExchangeError(a.Name, errors.New(errResponse.RejectReason), resp))
 	}
 
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return a.CheckNonceError(exchange.NewExchangeError(a.Name, errors.New("Unable to JSON Unmarshal response."), resp))
 	}
 	return nil
 }
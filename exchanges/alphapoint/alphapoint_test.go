@@ -1,8 +1,15 @@
 package alphapoint
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+
+	"github.com/champii/gocryptotrader/config"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/gorilla/websocket"
 )
 
 func TestSetDefaults(t *testing.T) {
@@ -18,6 +25,74 @@ func TestSetDefaults(t *testing.T) {
 	}
 }
 
+func TestSetupInstances(t *testing.T) {
+	t.Parallel()
+	newServer := func(last string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/ajax/v1/GetTicker" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"high":1010,"last":` + last + `,"bid":999,"volume":12.5,"low":990,"ask":1001,"isAccepted":true}`))
+		}))
+	}
+	serverA := newServer("1000")
+	defer serverA.Close()
+	serverB := newServer("2000")
+	defer serverB.Close()
+
+	venueA := NewAlphapoint("Alphapoint Venue A")
+	venueA.SetDefaults()
+	venueA.Setup(config.ExchangeConfig{Name: "Alphapoint Venue A", Enabled: true, APIUrl: serverA.URL, WebsocketURL: "wss://a.example.com/", EnabledPairs: "BTCUSD"})
+	venueB := NewAlphapoint("Alphapoint Venue B")
+	venueB.SetDefaults()
+	venueB.Setup(config.ExchangeConfig{Name: "Alphapoint Venue B", Enabled: true, APIUrl: serverB.URL, WebsocketURL: "wss://b.example.com/", EnabledPairs: "BTCUSD"})
+
+	if venueA.GetName() != "Alphapoint Venue A" || venueA.WebsocketURL != "wss://a.example.com/" || venueB.WebsocketURL != "wss://b.example.com/" {
+		t.Errorf("Test Failed - Setup() did not apply the venue settings: %s %s %s", venueA.GetName(), venueA.WebsocketURL, venueB.WebsocketURL)
+	}
+
+	venueC := NewAlphapoint("Alphapoint Venue C")
+	venueC.SetDefaults()
+	venueC.Setup(config.ExchangeConfig{Name: "Alphapoint Venue C", Enabled: true, APIUrl: serverA.URL, EnabledPairs: "BTCUSD"})
+	if venueC.IsEnabled() || venueC.WebsocketURL != "" {
+		t.Errorf("Test Failed - Setup() enabled a venue without a websocket endpoint: %s", venueC.WebsocketURL)
+	}
+
+	p := pair.NewCurrencyPair("BTC", "USD")
+	for x, y := range map[*Alphapoint]float64{venueA: 1000, venueB: 2000} {
+		x.SetRateLimit(0, 0)
+		price, err := x.GetTickerPrice(p)
+		if err != nil || price.Last != y || price.Ask != 1001 {
+			t.Errorf("Test Failed - %s GetTickerPrice() incorrect: %+v %v", x.GetName(), price, err)
+		}
+
+		stored, err := ticker.GetTicker(x.GetName(), p)
+		if err != nil || stored.Last != y {
+			t.Errorf("Test Failed - %s GetTickerPrice() did not store the ticker: %+v %v", x.GetName(), stored, err)
+		}
+	}
+}
+
+func TestWebsocketProcessTicker(t *testing.T) {
+	t.Parallel()
+	a := NewAlphapoint("Alphapoint Websocket Test")
+	a.SetDefaults()
+	a.EnabledPairs = []string{"BTCUSD"}
+
+	a.WebsocketHandleMessage(websocket.TextMessage, []byte(`{"messageType":"Ticker","prodPair":"LTCUSD","last":50}`))
+	a.WebsocketHandleMessage(websocket.TextMessage, []byte(`{"messageType":"Ticker","prodPair":"BTCUSD","high":1010,"low":990,"last":1000,"volume":12.5,"bid":999,"ask":1001}`))
+
+	price, err := ticker.GetTicker(a.GetName(), pair.NewCurrencyPair("BTC", "USD"))
+	if err != nil || price.Last != 1000 || price.Bid != 999 || price.Volume != 12.5 {
+		t.Errorf("Test Failed - WebsocketHandleMessage() ticker incorrect: %+v %v", price, err)
+	}
+
+	if _, err = ticker.GetTicker(a.GetName(), pair.NewCurrencyPair("LTC", "USD")); err == nil {
+		t.Error("Test Failed - WebsocketHandleMessage() stored a ticker of a pair which is not enabled")
+	}
+}
+
 func TestGetTicker(t *testing.T) {
 	GetTicker := Alphapoint{}
 	GetTicker.SetDefaults()
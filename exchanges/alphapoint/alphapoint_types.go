@@ -157,3 +157,8 @@ type AlphapointWebsocketTicker struct {
 	BuyOrderCount           int     `json:"buyOrderCount"`
 	SellOrderCount          int     `json:"sellOrderCount"`
 }
+
+type AlphapointErrorResponse struct {
+	IsAccepted   bool   `json:"isAccepted"`
+	RejectReason string `json:"rejectReason"`
+}
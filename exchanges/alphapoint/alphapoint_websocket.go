@@ -2,10 +2,12 @@ package alphapoint
 
 import (
 	"log"
-	"net/http"
 
 	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
 )
 
 const (
@@ -13,59 +15,67 @@ const (
 )
 
 func (a *Alphapoint) WebsocketClient() {
-	for a.Enabled && a.Websocket {
-		var Dialer websocket.Dialer
-		var err error
-		a.WebsocketConn, _, err = Dialer.Dial(a.WebsocketURL, http.Header{})
-
-		if err != nil {
-			log.Printf("%s Unable to connect to Websocket. Error: %s\n", a.Name, err)
-			continue
-		}
+	a.WebsocketConn.ExchangeName = a.Name
+	a.WebsocketConn.URL = a.WebsocketURL
+	a.WebsocketConn.Verbose = a.Verbose
+	a.WebsocketConn.Run(a.GetWebsocketContext())
+}
 
-		if a.Verbose {
-			log.Printf("%s Connected to Websocket.\n", a.Name)
+//WebsocketHandleMessage decodes a ticker message and stores it
+func (a *Alphapoint) WebsocketHandleMessage(msgType int, resp []byte) {
+	switch msgType {
+	case websocket.TextMessage:
+		type MsgType struct {
+			MessageType string `json:"messageType"`
 		}
 
-		err = a.WebsocketConn.WriteMessage(websocket.TextMessage, []byte(`{"messageType": "logon"}`))
-
+		msgType := MsgType{}
+		err := common.JSONDecode(resp, &msgType)
 		if err != nil {
 			log.Println(err)
 			return
 		}
 
-		for a.Enabled && a.Websocket {
-			msgType, resp, err := a.WebsocketConn.ReadMessage()
+		switch msgType.MessageType {
+		case "Ticker":
+			tick := AlphapointWebsocketTicker{}
+			err = common.JSONDecode(resp, &tick)
 			if err != nil {
 				log.Println(err)
-				break
+				return
 			}
+			a.WebsocketProcessTicker(tick)
+		}
+	}
+}
 
-			switch msgType {
-			case websocket.TextMessage:
-				type MsgType struct {
-					MessageType string `json:"messageType"`
-				}
+//WebsocketProcessTicker stores a ticker message in the ticker store. Tickers
+//are pushed for every product pair, so pairs which are not enabled are skipped
+func (a *Alphapoint) WebsocketProcessTicker(tick AlphapointWebsocketTicker) {
+	if len(tick.ProductPair) != 6 || !common.DataContains(a.EnabledPairs, common.StringToUpper(tick.ProductPair)) {
+		return
+	}
 
-				msgType := MsgType{}
-				err := common.JSONDecode(resp, &msgType)
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+	p := pair.NewCurrencyPair(tick.ProductPair[0:3], tick.ProductPair[3:])
+	ticker.ProcessTicker(a.GetName(), p, ticker.TickerPrice{
+		Pair:   p,
+		Last:   tick.Last,
+		High:   tick.High,
+		Low:    tick.Low,
+		Bid:    tick.Bid,
+		Ask:    tick.Ask,
+		Volume: tick.Volume,
+	})
+}
 
-				switch msgType.MessageType {
-				case "Ticker":
-					ticker := AlphapointWebsocketTicker{}
-					err = common.JSONDecode(resp, &ticker)
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-				}
-			}
-		}
-		a.WebsocketConn.Close()
-		log.Printf("%s Websocket client disconnected.", a.Name)
-	}
+//websocketLogon starts the ticker stream, which is sent without subscriptions
+func (a *Alphapoint) websocketLogon() error {
+	return a.WebsocketConn.Send([]byte(`{"messageType": "logon"}`))
+}
+
+func (a *Alphapoint) newWebsocketConnection() *exchange.WebsocketConnection {
+	conn := exchange.NewWebsocketConnection(a.Name, a.WebsocketURL)
+	conn.OnConnect = a.websocketLogon
+	conn.OnMessage = a.WebsocketHandleMessage
+	return conn
 }
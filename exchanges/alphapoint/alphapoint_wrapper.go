@@ -1,14 +1,72 @@
 package alphapoint
 
 import (
+	"errors"
 	"log"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/currency/pair"
 	"github.com/champii/gocryptotrader/exchanges"
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
+func (a *Alphapoint) Start() {
+	go a.Run()
+}
+
+func (a *Alphapoint) Run() {
+	if a.Verbose {
+		log.Printf("%s Websocket: %s. (url: %s).\n", a.GetName(), common.IsEnabled(a.Websocket), a.WebsocketURL)
+		log.Printf("%s polling delay: %ds.\n", a.GetName(), a.RESTPollingDelay)
+		log.Printf("%s %d currencies enabled: %s.\n", a.GetName(), len(a.EnabledPairs), a.EnabledPairs)
+	}
+
+	if a.Websocket {
+		go a.WebsocketClient()
+	}
+
+	productPairs, err := a.GetProductPairs()
+	if err != nil {
+		log.Printf("%s Failed to get available symbols.\n", a.GetName())
+	} else {
+		var exchangeProducts []string
+		for _, x := range productPairs.ProductPairs {
+			exchangeProducts = append(exchangeProducts, x.Name)
+		}
+		err = a.UpdateAvailableCurrencies(exchangeProducts)
+		if err != nil {
+			log.Printf("%s Failed to get config.\n", a.GetName())
+		}
+	}
+
+	err = a.UpdatePairInfo()
+	if err != nil {
+		log.Printf("%s Failed to get pair info.\n", a.GetName())
+	}
+
+	for a.Enabled {
+		for _, x := range a.EnabledPairs {
+			currency := pair.NewCurrencyPair(x[0:3], x[3:])
+			a.Poll(x, func() {
+				ticker, err := a.GetTickerPrice(currency)
+				if err != nil {
+					log.Println(err)
+					return
+				}
+				log.Printf("%s %s: Last %f High %f Low %f Volume %f\n", a.GetName(), currency.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
+				stats.AddExchangeInfo(a.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
+			})
+		}
+		time.Sleep(time.Second * a.RESTPollingDelay)
+	}
+}
+
 //GetExchangeAccountInfo : Retrieves balances for all enabled currencies for the Alphapoint exchange
 func (e *Alphapoint) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	var response exchange.ExchangeAccountInfo
@@ -29,17 +87,27 @@ func (e *Alphapoint) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, err
 	return response, nil
 }
 
-func (a *Alphapoint) GetTickerPrice(p pair.CurrencyPair) ticker.TickerPrice {
+func (a *Alphapoint) GetTickerPrice(p pair.CurrencyPair) (ticker.TickerPrice, error) {
+	tickerNew, err := ticker.GetTicker(a.GetName(), p)
+	if err == nil {
+		return tickerNew, nil
+	}
+
 	var tickerPrice ticker.TickerPrice
-	tick, err := a.GetTicker(p.Pair().String())
+	tick, err := a.GetTicker(p.Pair().Upper().String())
 	if err != nil {
-		log.Println(err)
-		return ticker.TickerPrice{}
+		return tickerPrice, err
 	}
+
 	tickerPrice.Pair = p
 	tickerPrice.Ask = tick.Ask
 	tickerPrice.Bid = tick.Bid
-	return tickerPrice
+	tickerPrice.Last = tick.Last
+	tickerPrice.High = tick.High
+	tickerPrice.Low = tick.Low
+	tickerPrice.Volume = tick.Volume
+	ticker.ProcessTicker(a.GetName(), p, tickerPrice)
+	return tickerPrice, nil
 }
 
 func (a *Alphapoint) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase, error) {
@@ -68,3 +136,217 @@ func (a *Alphapoint) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBas
 	orderbook.ProcessOrderbook(a.GetName(), p, orderBook)
 	return orderBook, nil
 }
+
+//SubmitOrder : Places a new order on Alphapoint
+func (a *Alphapoint) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := a.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	orderType := 1
+	if order.IsMarket() {
+		orderType = 0
+	}
+
+	orderID, err := a.CreateOrder(order.Pair.Pair().String(), common.StringToLower(string(order.Side)), orderType, order.Amount, order.Price)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = a.GetName()
+	result.OrderID = strconv.FormatInt(orderID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (a *Alphapoint) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.CancelExistingOrder(p.Pair().String(), id)
+	return err
+}
+
+//ModifyOrder : Alphapoint order modification only changes queue priority, so
+//amending price or amount is not supported
+func (a *Alphapoint) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves an order from the account's open orders
+func (a *Alphapoint) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.Atoi(orderID)
+	if err != nil {
+		return detail, err
+	}
+
+	orders, err := a.GetOrders()
+	if err != nil {
+		return detail, err
+	}
+
+	for _, x := range orders {
+		for _, y := range x.Openorders {
+			if y.Serverorderid == id {
+				return a.getOrderDetail(x.Instrument, y), nil
+			}
+		}
+	}
+	return detail, errors.New(exchange.ErrOrderNotFound)
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (a *Alphapoint) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := a.GetOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range response {
+		for _, y := range x.Openorders {
+			orders = append(orders, a.getOrderDetail(x.Instrument, y))
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Not supported, Alphapoint only exposes fills through account trades
+func (a *Alphapoint) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+func (a *Alphapoint) getOrderDetail(instrument string, order AlphapointOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = a.GetName()
+	detail.OrderID = strconv.Itoa(order.Serverorderid)
+	if len(instrument) == 6 {
+		detail.Pair = pair.NewCurrencyPair(instrument[0:3], instrument[3:])
+	}
+	detail.Side = exchange.OrderSideBuy
+	if order.Side == 1 {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	detail.Price = float64(order.Price)
+	detail.Amount = float64(order.QtyTotal)
+	detail.FilledAmount = float64(order.QtyTotal - order.QtyRemaining)
+	detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, true, false)
+	detail.CreatedAt = time.Unix(0, order.ReceiveTime*int64(time.Millisecond))
+	return detail
+}
+
+//GetFills : Not supported, Alphapoint account trades do not identify which side belongs to the account
+func (a *Alphapoint) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetDepositAddress : Retrieves the deposit address for a product from the
+//account's deposit address list
+func (a *Alphapoint) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	var address exchange.DepositAddress
+	currency = common.StringToUpper(currency)
+	response, err := a.GetDepositAddresses()
+	if err != nil {
+		return address, exchange.NewFundingError(a.GetName(), currency, err)
+	}
+
+	for _, x := range response {
+		if common.StringToUpper(x.Name) != currency || x.DepositAddress == "" {
+			continue
+		}
+		address.Exchange = a.GetName()
+		address.Currency = currency
+		address.Address = x.DepositAddress
+		return address, nil
+	}
+	return address, exchange.NewFundingError(a.GetName(), currency, errors.New(exchange.ErrDepositAddressNotFound))
+}
+
+//WithdrawCryptocurrency : Withdraws a product to an external address.
+//Alphapoint does not return an ID for the withdrawal
+func (a *Alphapoint) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	currency := common.StringToUpper(request.Currency)
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(a.GetName(), currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(a.GetName(), currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	err = a.WithdrawCoins(currency, currency, request.Amount, request.Address)
+	if err != nil {
+		return result, exchange.NewFundingError(a.GetName(), currency, err)
+	}
+
+	result.Exchange = a.GetName()
+	result.Currency = currency
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//UpdatePairInfo : Populates the pair metadata table from the Alphapoint
+//product pairs
+func (a *Alphapoint) UpdatePairInfo() error {
+	response, err := a.GetProductPairs()
+	if err != nil {
+		return err
+	}
+
+	var info []exchange.PairInfo
+	for _, x := range response.ProductPairs {
+		info = append(info, exchange.PairInfo{
+			Pair:           pair.NewCurrencyPair(x.Product1Label, x.Product2Label),
+			AmountStep:     exchange.GetPrecisionStep(x.Product1Decimalplaces),
+			PriceTick:      exchange.GetPrecisionStep(x.Product2Decimalplaces),
+			QuotePrecision: x.Product2Decimalplaces,
+			Status:         exchange.PairStatusTrading,
+		})
+	}
+	a.SetPairInfo(info)
+	return nil
+}
+
+//GetHistoricCandles : Not supported, Alphapoint has no candle endpoint
+func (a *Alphapoint) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair, a start
+//index of -1 requests the most recent trades
+func (a *Alphapoint) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := a.GetTrades(p.Pair().Upper().String(), -1, ALPHAPOINT_RECENT_TRADES_COUNT)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response.Trades {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Quantity,
+			Timestamp: time.Unix(int64(x.Unixtime), 0),
+		}
+		if x.IncomingOrderSide == 1 {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(a.GetName(), p, result)
+	return result, nil
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -20,6 +19,8 @@ const (
 	ANX_DATA_TOKEN      = "dataToken"
 	ANX_ORDER_NEW       = "order/new"
 	ANX_ORDER_INFO      = "order/info"
+	ANX_ORDER_CANCEL    = "order/cancel"
+	ANX_ORDER_LIST      = "order/list"
 	ANX_SEND            = "send"
 	ANX_SUBACCOUNT_NEW  = "subaccount/new"
 	ANX_RECEIVE_ADDRESS = "receive"
@@ -27,6 +28,11 @@ const (
 	ANX_TICKER          = "money/ticker"
 )
 
+const (
+	ANX_AUTH_RATE_LIMIT   = 60
+	ANX_UNAUTH_RATE_LIMIT = 60
+)
+
 type ANX struct {
 	exchange.ExchangeBase
 }
@@ -39,6 +45,8 @@ func (a *ANX) SetDefaults() {
 	a.Verbose = false
 	a.Websocket = false
 	a.RESTPollingDelay = 10
+	a.SetRateLimit(ANX_AUTH_RATE_LIMIT, ANX_UNAUTH_RATE_LIMIT)
+	a.SetNonceResolution(time.Millisecond)
 }
 
 //Setup is run on startup to setup exchange with config values
@@ -50,6 +58,11 @@ func (a *ANX) Setup(exch config.ExchangeConfig) {
 		a.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		a.SetAPIKeys(exch.APIKey, exch.APISecret, "", true)
 		a.RESTPollingDelay = exch.RESTPollingDelay
+		a.UpdateRateLimit(exch)
+		err := a.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", a.GetName(), err)
+		}
 		a.Verbose = exch.Verbose
 		a.Websocket = exch.Websocket
 		a.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -67,7 +80,7 @@ func (a *ANX) GetFee(maker bool) float64 {
 
 func (a *ANX) GetTicker(currency string) (ANXTicker, error) {
 	var ticker ANXTicker
-	err := common.SendHTTPGetRequest(fmt.Sprintf("%sapi/2/%s/%s", ANX_API_URL, currency, ANX_TICKER), true, &ticker)
+	err := a.SendHTTPGetRequest(fmt.Sprintf("%sapi/2/%s/%s", a.GetAPIUrl(ANX_API_URL), currency, ANX_TICKER), true, &ticker)
 	if err != nil {
 		return ANXTicker{}, err
 	}
@@ -76,7 +89,7 @@ func (a *ANX) GetTicker(currency string) (ANXTicker, error) {
 
 func (a *ANX) GetAPIKey(username, password, otp, deviceID string) (string, string, error) {
 	request := make(map[string]interface{})
-	request["nonce"] = strconv.FormatInt(time.Now().UnixNano(), 10)[0:13]
+	request["nonce"] = a.GetNonce().GetString()
 	request["username"] = username
 	request["password"] = password
 
@@ -129,14 +142,14 @@ func (a *ANX) GetDataToken() (string, error) {
 }
 
 func (a *ANX) NewOrder(orderType string, buy bool, tradedCurrency, tradedCurrencyAmount, settlementCurrency, settlementCurrencyAmount, limitPriceSettlement string,
-	replace bool, replaceUUID string, replaceIfActive bool) error {
+	replace bool, replaceUUID string, replaceIfActive bool) (string, error) {
 	request := make(map[string]interface{})
 
 	var order ANXOrder
 	order.OrderType = orderType
 	order.BuyTradedCurrency = buy
 
-	if buy {
+	if tradedCurrencyAmount != "" {
 		order.TradedCurrencyAmount = tradedCurrencyAmount
 	} else {
 		order.SettlementCurrencyAmount = settlementCurrencyAmount
@@ -161,12 +174,34 @@ func (a *ANX) NewOrder(orderType string, buy bool, tradedCurrency, tradedCurrenc
 	var response OrderResponse
 
 	err := a.SendAuthenticatedHTTPRequest(ANX_ORDER_NEW, request, &response)
+	if err != nil {
+		return "", err
+	}
+
+	if response.ResultCode != "OK" {
+		return "", errors.New("Response code is not OK: " + response.ResultCode)
+	}
+	return response.OrderID, nil
+}
+
+func (a *ANX) CancelExistingOrder(orderID string) error {
+	request := make(map[string]interface{})
+	request["orderId"] = orderID
+
+	type CancelResponse struct {
+		OrderID    string `json:"orderId"`
+		ResultCode string `json:"resultCode"`
+		Timestamp  int64  `json:"timestamp"`
+	}
+	var response CancelResponse
+
+	err := a.SendAuthenticatedHTTPRequest(ANX_ORDER_CANCEL, request, &response)
 	if err != nil {
 		return err
 	}
 
 	if response.ResultCode != "OK" {
-		return errors.New("Response code is not OK: %s" + response.ResultCode)
+		return errors.New("Response code is not OK: " + response.ResultCode)
 	}
 	return nil
 }
@@ -195,6 +230,31 @@ func (a *ANX) OrderInfo(orderID string) (ANXOrderResponse, error) {
 	return response.Order, nil
 }
 
+func (a *ANX) GetOrders(activeOnly bool, max int) ([]ANXOrderResponse, error) {
+	request := make(map[string]interface{})
+	request["activeOnly"] = activeOnly
+	if max > 0 {
+		request["max"] = max
+	}
+
+	type OrderListResponse struct {
+		Orders     []ANXOrderResponse `json:"orders"`
+		ResultCode string             `json:"resultCode"`
+		Timestamp  int64              `json:"timestamp"`
+	}
+	var response OrderListResponse
+
+	err := a.SendAuthenticatedHTTPRequest(ANX_ORDER_LIST, request, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.ResultCode != "OK" {
+		return nil, errors.New("Response code is not OK: " + response.ResultCode)
+	}
+	return response.Orders, nil
+}
+
 func (a *ANX) Send(currency, address, otp, amount string) (string, error) {
 	request := make(map[string]interface{})
 	request["ccy"] = currency
@@ -250,7 +310,7 @@ func (a *ANX) CreateNewSubAccount(currency, name string) (string, error) {
 	return response.SubAccount, nil
 }
 
-func (a *ANX) GetDepositAddress(currency, name string, new bool) (string, error) {
+func (a *ANX) GetReceiveAddress(currency, name string, new bool) (string, error) {
 	request := make(map[string]interface{})
 	request["ccy"] = currency
 
@@ -286,8 +346,9 @@ func (a *ANX) GetDepositAddress(currency, name string, new bool) (string, error)
 }
 
 func (a *ANX) SendAuthenticatedHTTPRequest(path string, params map[string]interface{}, result interface{}) error {
+	a.WaitRateLimit(true)
 	request := make(map[string]interface{})
-	request["nonce"] = strconv.FormatInt(time.Now().UnixNano(), 10)[0:13]
+	request["nonce"] = a.GetNonce().GetString()
 	path = fmt.Sprintf("api/%s/%s", ANX_API_VERSION, path)
 
 	if params != nil {
@@ -312,16 +373,25 @@ func (a *ANX) SendAuthenticatedHTTPRequest(path string, params map[string]interf
 	headers["Rest-Sign"] = common.Base64Encode([]byte(hmac))
 	headers["Content-Type"] = "application/json"
 
-	resp, err := common.SendHTTPRequest("POST", ANX_API_URL+path, headers, bytes.NewBuffer(PayloadJson))
+	resp, err := a.SendHTTPRequest("POST", a.GetAPIUrl(ANX_API_URL)+path, headers, bytes.NewBuffer(PayloadJson))
 
 	if a.Verbose {
 		log.Printf("Recieved raw: \n%s\n", resp)
 	}
 
+	errResponse := ANXErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.ResultCode != "" && errResponse.ResultCode != "OK" {
+		return a.CheckNonceError(exchange.NewExchangeError(a.Name, errors.New(common.TrimString(errResponse.ResultCode+" "+errResponse.Error, " ")), resp))
+	}
+
+	if err != nil {
+		return a.CheckNonceError(exchange.NewExchangeError(a.Name, err, resp))
+	}
+
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return a.CheckNonceError(exchange.NewExchangeError(a.Name, errors.New("Unable to JSON Unmarshal response."), resp))
 	}
 
 	return nil
@@ -137,7 +137,7 @@ func TestCreateNewSubAccount(t *testing.T) {
 
 }
 
-func TestGetDepositAddress(t *testing.T) {
+func TestGetReceiveAddress(t *testing.T) {
 
 }
 
@@ -52,3 +52,8 @@ type ANXTicker struct {
 		UpdateTime string             `json:"dataUpdateTime"`
 	} `json:"data"`
 }
+
+type ANXErrorResponse struct {
+	ResultCode string `json:"resultCode"`
+	Error      string `json:"error"`
+}
@@ -1,14 +1,18 @@
 package anx
 
 import (
+	"errors"
 	"log"
+	"strconv"
 	"time"
 
+	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/currency/pair"
 	"github.com/champii/gocryptotrader/exchanges"
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (a *ANX) Start() {
@@ -24,7 +28,7 @@ func (a *ANX) Run() {
 	for a.Enabled {
 		for _, x := range a.EnabledPairs {
 			currency := pair.NewCurrencyPair(x[0:3], x[3:])
-			go func() {
+			a.Poll(x, func() {
 				ticker, err := a.GetTickerPrice(currency)
 				if err != nil {
 					log.Println(err)
@@ -32,7 +36,7 @@ func (a *ANX) Run() {
 				}
 				log.Printf("ANX %s: Last %f High %f Low %f Volume %f\n", currency.Pair(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(a.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * a.RESTPollingDelay)
 	}
@@ -71,3 +75,176 @@ func (e *ANX) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	response.ExchangeName = e.GetName()
 	return response, nil
 }
+
+//SubmitOrder : Places a new order on ANX
+func (a *ANX) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return a.placeOrder(order, "")
+}
+
+//CancelOrder : Cancels an order by its ID
+func (a *ANX) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	return a.CancelExistingOrder(orderID)
+}
+
+//ModifyOrder : Replaces an existing order using ANX's native order replacement
+func (a *ANX) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return a.placeOrder(order, orderID)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (a *ANX) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	response, err := a.OrderInfo(orderID)
+	if err != nil {
+		return detail, err
+	}
+
+	return a.getOrderDetail(response), nil
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (a *ANX) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return a.getOrders(true, filter)
+}
+
+//GetOrderHistory : Retrieves all orders, including closed ones, matching the filter
+func (a *ANX) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return a.getOrders(false, filter)
+}
+
+func (a *ANX) getOrders(activeOnly bool, filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := a.GetOrders(activeOnly, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range response {
+		orders = append(orders, a.getOrderDetail(x))
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+func (a *ANX) getOrderDetail(response ANXOrderResponse) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	amount, _ := strconv.ParseFloat(response.TradedCurrencyAmount, 64)
+	outstanding, _ := strconv.ParseFloat(response.TradedCurrencyOutstanding, 64)
+	detail.Exchange = a.GetName()
+	detail.OrderID = response.OrderID
+	detail.Pair = pair.NewCurrencyPair(response.TradedCurrency, response.SettlementCurrency)
+	detail.Side = exchange.OrderSideSell
+	if response.BuyTradedCurrency {
+		detail.Side = exchange.OrderSideBuy
+	}
+	detail.Type = exchange.OrderType(common.StringToUpper(response.OrderType))
+	detail.Price, _ = strconv.ParseFloat(response.LimitPriceInSettlementCurrency, 64)
+	detail.AveragePrice, _ = strconv.ParseFloat(response.ExecutedAverageRate, 64)
+	detail.Amount = amount
+	detail.FilledAmount = amount - outstanding
+	detail.CreatedAt = time.Unix(0, response.Timestamp*int64(time.Millisecond))
+
+	switch response.OrderStatus {
+	case "ACTIVE", "PENDING":
+		detail.Status = exchange.OrderStatusActive
+	case "PARTIAL_FILL":
+		detail.Status = exchange.OrderStatusPartiallyFilled
+	case "FULL_FILL":
+		detail.Status = exchange.OrderStatusFilled
+	case "CANCEL", "CANCELLED":
+		detail.Status = exchange.OrderStatusCancelled
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail
+}
+
+func (a *ANX) placeOrder(order exchange.OrderRequest, replaceOrderID string) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := a.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	var price string
+	if !order.IsMarket() {
+		price = strconv.FormatFloat(order.Price, 'f', -1, 64)
+	}
+
+	orderID, err := a.NewOrder(string(order.Type), order.IsBuy(), order.Pair.GetFirstCurrency().String(),
+		strconv.FormatFloat(order.Amount, 'f', -1, 64), order.Pair.GetSecondCurrency().String(), "", price,
+		replaceOrderID != "", replaceOrderID, replaceOrderID != "")
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = a.GetName()
+	result.OrderID = orderID
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//GetFills : Not supported, ANX has no trade history endpoint
+func (a *ANX) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetDepositAddress : Retrieves the main account receive address for a currency
+func (a *ANX) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	var address exchange.DepositAddress
+	currency = common.StringToUpper(currency)
+	response, err := a.GetReceiveAddress(currency, "", false)
+	if err != nil {
+		return address, exchange.NewFundingError(a.GetName(), currency, err)
+	}
+
+	if response == "" {
+		return address, exchange.NewFundingError(a.GetName(), currency, errors.New(exchange.ErrDepositAddressNotFound))
+	}
+
+	address.Exchange = a.GetName()
+	address.Currency = currency
+	address.Address = response
+	return address, nil
+}
+
+//WithdrawCryptocurrency : Sends a currency to an external address. Accounts
+//with two factor authentication enabled will be rejected as no OTP is sent
+func (a *ANX) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	currency := common.StringToUpper(request.Currency)
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(a.GetName(), currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(a.GetName(), currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	id, err := a.Send(currency, request.Address, "", strconv.FormatFloat(request.Amount, 'f', -1, 64))
+	if err != nil {
+		return result, exchange.NewFundingError(a.GetName(), currency, err)
+	}
+
+	result.Exchange = a.GetName()
+	result.WithdrawalID = id
+	result.Currency = currency
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//UpdatePairInfo : Not supported, ANX does not publish pair constraints
+func (a *ANX) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, ANX has no candle endpoint
+func (a *ANX) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Not supported, ANX has no public trades endpoint
+func (a *ANX) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
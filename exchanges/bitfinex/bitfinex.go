@@ -9,7 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
 	"github.com/champii/gocryptotrader/exchanges"
@@ -36,6 +35,7 @@ const (
 	BITFINEX_ORDER_CANCEL_REPLACE = "order/cancel/replace"
 	BITFINEX_ORDER_STATUS         = "order/status"
 	BITFINEX_ORDERS               = "orders"
+	BITFINEX_ORDERS_HISTORY       = "orders/hist"
 	BITFINEX_POSITIONS            = "positions"
 	BITFINEX_CLAIM_POSITION       = "position/claim"
 	BITFINEX_HISTORY              = "history"
@@ -54,10 +54,22 @@ const (
 	BITFINEX_WITHDRAWAL           = "withdrawal"
 )
 
+const (
+	BITFINEX_TRADE_HISTORY_LIMIT = 500
+	BITFINEX_AUTH_RATE_LIMIT     = 30
+	BITFINEX_UNAUTH_RATE_LIMIT   = 50
+)
+
+var bitfinexErrorRules = []exchange.ErrorRule{
+	{Match: "could not find a key", Kind: exchange.ErrorKindAuthFailure},
+	{Match: "order could not be cancelled", Kind: exchange.ErrorKindOrderNotFound},
+}
+
 type Bitfinex struct {
 	exchange.ExchangeBase
-	WebsocketConn         *websocket.Conn
+	WebsocketConn         *exchange.WebsocketConnection
 	WebsocketSubdChannels map[int]BitfinexWebsocketChanInfo
+	WebsocketAccount      *BitfinexWebsocketAccount
 }
 
 func (b *Bitfinex) SetDefaults() {
@@ -66,7 +78,10 @@ func (b *Bitfinex) SetDefaults() {
 	b.Verbose = false
 	b.Websocket = false
 	b.RESTPollingDelay = 10
+	b.SetRateLimit(BITFINEX_AUTH_RATE_LIMIT, BITFINEX_UNAUTH_RATE_LIMIT)
 	b.WebsocketSubdChannels = make(map[int]BitfinexWebsocketChanInfo)
+	b.WebsocketAccount = NewBitfinexWebsocketAccount()
+	b.WebsocketConn = b.newWebsocketConnection()
 }
 
 func (b *Bitfinex) Setup(exch config.ExchangeConfig) {
@@ -77,6 +92,11 @@ func (b *Bitfinex) Setup(exch config.ExchangeConfig) {
 		b.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		b.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		b.RESTPollingDelay = exch.RESTPollingDelay
+		b.UpdateRateLimit(exch)
+		err := b.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", b.GetName(), err)
+		}
 		b.Verbose = exch.Verbose
 		b.Websocket = exch.Websocket
 		b.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -86,9 +106,9 @@ func (b *Bitfinex) Setup(exch config.ExchangeConfig) {
 }
 
 func (b *Bitfinex) GetTicker(symbol string, values url.Values) (BitfinexTicker, error) {
-	path := common.EncodeURLValues(BITFINEX_API_URL+BITFINEX_TICKER+symbol, values)
+	path := common.EncodeURLValues(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_TICKER+symbol, values)
 	response := BitfinexTicker{}
-	err := common.SendHTTPGetRequest(path, true, &response)
+	err := b.SendHTTPGetRequest(path, true, &response)
 	if err != nil {
 		return response, err
 	}
@@ -97,7 +117,7 @@ func (b *Bitfinex) GetTicker(symbol string, values url.Values) (BitfinexTicker,
 
 func (b *Bitfinex) GetStats(symbol string) ([]BitfinexStats, error) {
 	response := []BitfinexStats{}
-	err := common.SendHTTPGetRequest(BITFINEX_API_URL+BITFINEX_STATS+symbol, true, &response)
+	err := b.SendHTTPGetRequest(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_STATS+symbol, true, &response)
 	if err != nil {
 		return response, err
 	}
@@ -108,9 +128,9 @@ func (b *Bitfinex) GetLendbook(symbol string, values url.Values) (BitfinexLendbo
 	if len(symbol) == 6 {
 		symbol = symbol[:3]
 	}
-	path := common.EncodeURLValues(BITFINEX_API_URL+BITFINEX_LENDBOOK+symbol, values)
+	path := common.EncodeURLValues(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_LENDBOOK+symbol, values)
 	response := BitfinexLendbook{}
-	err := common.SendHTTPGetRequest(path, true, &response)
+	err := b.SendHTTPGetRequest(path, true, &response)
 	if err != nil {
 		return response, err
 	}
@@ -118,9 +138,9 @@ func (b *Bitfinex) GetLendbook(symbol string, values url.Values) (BitfinexLendbo
 }
 
 func (b *Bitfinex) GetOrderbook(symbol string, values url.Values) (BitfinexOrderbook, error) {
-	path := common.EncodeURLValues(BITFINEX_API_URL+BITFINEX_ORDERBOOK+symbol, values)
+	path := common.EncodeURLValues(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_ORDERBOOK+symbol, values)
 	response := BitfinexOrderbook{}
-	err := common.SendHTTPGetRequest(path, true, &response)
+	err := b.SendHTTPGetRequest(path, true, &response)
 	if err != nil {
 		return response, err
 	}
@@ -128,9 +148,9 @@ func (b *Bitfinex) GetOrderbook(symbol string, values url.Values) (BitfinexOrder
 }
 
 func (b *Bitfinex) GetTrades(symbol string, values url.Values) ([]BitfinexTradeStructure, error) {
-	path := common.EncodeURLValues(BITFINEX_API_URL+BITFINEX_TRADES+symbol, values)
+	path := common.EncodeURLValues(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_TRADES+symbol, values)
 	response := []BitfinexTradeStructure{}
-	err := common.SendHTTPGetRequest(path, true, &response)
+	err := b.SendHTTPGetRequest(path, true, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -138,9 +158,9 @@ func (b *Bitfinex) GetTrades(symbol string, values url.Values) ([]BitfinexTradeS
 }
 
 func (b *Bitfinex) GetLends(symbol string, values url.Values) ([]BitfinexLends, error) {
-	path := common.EncodeURLValues(BITFINEX_API_URL+BITFINEX_LENDS+symbol, values)
+	path := common.EncodeURLValues(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_LENDS+symbol, values)
 	response := []BitfinexLends{}
-	err := common.SendHTTPGetRequest(path, true, &response)
+	err := b.SendHTTPGetRequest(path, true, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -149,7 +169,7 @@ func (b *Bitfinex) GetLends(symbol string, values url.Values) ([]BitfinexLends,
 
 func (b *Bitfinex) GetSymbols() ([]string, error) {
 	products := []string{}
-	err := common.SendHTTPGetRequest(BITFINEX_API_URL+BITFINEX_SYMBOLS, true, &products)
+	err := b.SendHTTPGetRequest(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_SYMBOLS, true, &products)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +178,7 @@ func (b *Bitfinex) GetSymbols() ([]string, error) {
 
 func (b *Bitfinex) GetSymbolsDetails() ([]BitfinexSymbolDetails, error) {
 	response := []BitfinexSymbolDetails{}
-	err := common.SendHTTPGetRequest(BITFINEX_API_URL+BITFINEX_SYMBOLS_DETAILS, true, &response)
+	err := b.SendHTTPGetRequest(b.GetAPIUrl(BITFINEX_API_URL)+BITFINEX_SYMBOLS_DETAILS, true, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +252,7 @@ func (b *Bitfinex) NewOrderMulti(orders []BitfinexPlaceOrder) (BitfinexOrderMult
 	return response, nil
 }
 
-func (b *Bitfinex) CancelOrder(OrderID int64) (BitfinexOrder, error) {
+func (b *Bitfinex) CancelExistingOrder(OrderID int64) (BitfinexOrder, error) {
 	request := make(map[string]interface{})
 	request["order_id"] = OrderID
 	response := BitfinexOrder{}
@@ -313,7 +333,7 @@ func (b *Bitfinex) GetOrderStatus(OrderID int64) (BitfinexOrder, error) {
 	return orderStatus, err
 }
 
-func (b *Bitfinex) GetActiveOrders() ([]BitfinexOrder, error) {
+func (b *Bitfinex) GetOpenOrders() ([]BitfinexOrder, error) {
 	response := []BitfinexOrder{}
 	err := b.SendAuthenticatedHTTPRequest("POST", BITFINEX_ORDERS, nil, &response)
 
@@ -324,6 +344,21 @@ func (b *Bitfinex) GetActiveOrders() ([]BitfinexOrder, error) {
 	return response, nil
 }
 
+func (b *Bitfinex) GetInactiveOrders(limit int) ([]BitfinexOrder, error) {
+	request := make(map[string]interface{})
+	if limit > 0 {
+		request["limit"] = limit
+	}
+	response := []BitfinexOrder{}
+	err := b.SendAuthenticatedHTTPRequest("POST", BITFINEX_ORDERS_HISTORY, request, &response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 func (b *Bitfinex) GetActivePositions() ([]BitfinexPosition, error) {
 	response := []BitfinexPosition{}
 	err := b.SendAuthenticatedHTTPRequest("POST", BITFINEX_POSITIONS, nil, &response)
@@ -411,15 +446,15 @@ func (b *Bitfinex) GetMovementHistory(symbol, method string, timeSince, timeUnti
 
 func (b *Bitfinex) GetTradeHistory(symbol string, timestamp, until time.Time, limit, reverse int) ([]BitfinexTradeHistory, error) {
 	request := make(map[string]interface{})
-	request["currency"] = symbol
-	request["timestamp"] = timestamp
+	request["symbol"] = symbol
+	request["timestamp"] = strconv.FormatInt(timestamp.Unix(), 10)
 
 	if !until.IsZero() {
-		request["until"] = until
+		request["until"] = strconv.FormatInt(until.Unix(), 10)
 	}
 
 	if limit > 0 {
-		request["limit"] = limit
+		request["limit_trades"] = limit
 	}
 
 	if reverse > 0 {
@@ -589,13 +624,14 @@ func (b *Bitfinex) Withdrawal(withdrawType, wallet, address string, amount float
 }
 
 func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) error {
+	b.WaitRateLimit(true)
 	if len(b.APIKey) == 0 {
-		return errors.New("SendAuthenticatedHTTPRequest: Invalid API key")
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New("SendAuthenticatedHTTPRequest: Invalid API key"), ""))
 	}
 
 	request := make(map[string]interface{})
 	request["request"] = fmt.Sprintf("/v%s/%s", BITFINEX_API_VERSION, path)
-	request["nonce"] = strconv.FormatInt(time.Now().UnixNano(), 10)
+	request["nonce"] = b.GetNonce().GetString()
 
 	if params != nil {
 		for key, value := range params {
@@ -619,22 +655,24 @@ func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[
 	headers["X-BFX-PAYLOAD"] = PayloadBase64
 	headers["X-BFX-SIGNATURE"] = common.HexEncodeToString(hmac)
 
-	resp, err := common.SendHTTPRequest(method, BITFINEX_API_URL+path, headers, strings.NewReader(""))
-	if err != nil {
-		return err
+	resp, err := b.SendHTTPRequest(method, b.GetAPIUrl(BITFINEX_API_URL)+path, headers, strings.NewReader(""))
+
+	if b.Verbose {
+		log.Printf("Recieved raw: \n%s\n", resp)
 	}
 
-	if strings.Contains(resp, "message") {
-		return errors.New("SendAuthenticatedHTTPRequest: " + resp[11:])
+	errResponse := BitfinexErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.Message != "" {
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New(errResponse.Message), resp, bitfinexErrorRules...))
 	}
 
-	if b.Verbose {
-		log.Printf("Recieved raw: \n%s\n", resp)
+	if err != nil {
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, err, resp, bitfinexErrorRules...))
 	}
 
 	err = common.JSONDecode([]byte(resp), &result)
 	if err != nil {
-		return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON Unmarshal response.")
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New("SendAuthenticatedHTTPRequest: Unable to JSON Unmarshal response."), resp))
 	}
 
 	return nil
@@ -2,6 +2,8 @@ package bitfinex
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -479,6 +481,30 @@ func TestGetSymbols(t *testing.T) {
 	}
 }
 
+func TestGetSymbolsStandIn(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/"+BITFINEX_SYMBOLS {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`["btcusd","ltcusd"]`))
+	}))
+	defer server.Close()
+
+	BitfinexStandIn := Bitfinex{}
+	BitfinexStandIn.SetDefaults()
+	BitfinexStandIn.Setup(config.ExchangeConfig{Enabled: true, APIUrl: server.URL + "/v1/"})
+
+	symbols, err := BitfinexStandIn.GetSymbols()
+	if err != nil {
+		t.Fatal("Test Failed - Bitfinex GetSymbols() stand-in error: ", err)
+	}
+	if len(symbols) != 2 || symbols[0] != "btcusd" {
+		t.Error("Test Failed - Bitfinex GetSymbols() unexpected stand-in symbols: ", symbols)
+	}
+}
+
 //Live Testing
 func TestGetSymbolsDetails(t *testing.T) {
 	t.Parallel()
@@ -1052,25 +1078,25 @@ func TestNewOrderMulti(t *testing.T) {
 	}
 }
 
-func TestCancelOrder(t *testing.T) {
+func TestCancelExistingOrder(t *testing.T) {
 	newConfig := config.Config{}
 
 	err := newConfig.LoadConfig("../../testdata/configtest.dat")
 	if err != nil {
-		t.Errorf("Test Failed - Bitfinex CancelOrder init error: %s\n", err)
+		t.Errorf("Test Failed - Bitfinex CancelExistingOrder init error: %s\n", err)
 	}
 	exchangeConfig, err := newConfig.GetExchangeConfig("Bitfinex")
 	if err != nil {
-		t.Errorf("Test Failed - Bitfinex CancelOrder init error: %s\n", err)
+		t.Errorf("Test Failed - Bitfinex CancelExistingOrder init error: %s\n", err)
 	}
 
-	BitfinexCancelOrder := Bitfinex{}
-	BitfinexCancelOrder.Setup(exchangeConfig)
+	BitfinexCancelExistingOrder := Bitfinex{}
+	BitfinexCancelExistingOrder.Setup(exchangeConfig)
 
 	if ACCOUNT_LIVE_TEST {
-		_, err := BitfinexCancelOrder.CancelOrder(1337)
+		_, err := BitfinexCancelExistingOrder.CancelExistingOrder(1337)
 		if err == nil {
-			t.Errorf("Test Failed - Bitfinex CancelOrder - Error: %s", err)
+			t.Errorf("Test Failed - Bitfinex CancelExistingOrder - Error: %s", err)
 		}
 	}
 }
@@ -1168,25 +1194,48 @@ func TestGetOrderStatus(t *testing.T) {
 	}
 }
 
-func TestGetActiveOrders(t *testing.T) {
+func TestGetOpenOrders(t *testing.T) {
+	newConfig := config.Config{}
+
+	err := newConfig.LoadConfig("../../testdata/configtest.dat")
+	if err != nil {
+		t.Errorf("Test Failed - Bitfinex GetOpenOrders init error: %s\n", err)
+	}
+	exchangeConfig, err := newConfig.GetExchangeConfig("Bitfinex")
+	if err != nil {
+		t.Errorf("Test Failed - Bitfinex GetOpenOrders init error: %s\n", err)
+	}
+
+	BitfinexGetOpenOrders := Bitfinex{}
+	BitfinexGetOpenOrders.Setup(exchangeConfig)
+
+	if ACCOUNT_LIVE_TEST {
+		_, err := BitfinexGetOpenOrders.GetOpenOrders()
+		if err != nil {
+			t.Error("Test Failed - Bitfinex GetOpenOrders - Expected Error")
+		}
+	}
+}
+
+func TestGetInactiveOrders(t *testing.T) {
 	newConfig := config.Config{}
 
 	err := newConfig.LoadConfig("../../testdata/configtest.dat")
 	if err != nil {
-		t.Errorf("Test Failed - Bitfinex GetActiveOrders init error: %s\n", err)
+		t.Errorf("Test Failed - Bitfinex GetInactiveOrders init error: %s\n", err)
 	}
 	exchangeConfig, err := newConfig.GetExchangeConfig("Bitfinex")
 	if err != nil {
-		t.Errorf("Test Failed - Bitfinex GetActiveOrders init error: %s\n", err)
+		t.Errorf("Test Failed - Bitfinex GetInactiveOrders init error: %s\n", err)
 	}
 
-	BitfinexGetActiveOrders := Bitfinex{}
-	BitfinexGetActiveOrders.Setup(exchangeConfig)
+	BitfinexGetInactiveOrders := Bitfinex{}
+	BitfinexGetInactiveOrders.Setup(exchangeConfig)
 
 	if ACCOUNT_LIVE_TEST {
-		_, err := BitfinexGetActiveOrders.GetActiveOrders()
+		_, err := BitfinexGetInactiveOrders.GetInactiveOrders(10)
 		if err != nil {
-			t.Error("Test Failed - Bitfinex GetActiveOrders - Expected Error")
+			t.Error("Test Failed - Bitfinex GetInactiveOrders - Expected Error")
 		}
 	}
 }
@@ -134,7 +134,7 @@ type BitfinexAccountInfo struct {
 }
 
 type BitfinexDepositResponse struct {
-	Result   string `json:"string"`
+	Result   string `json:"result"`
 	Method   string `json:"method"`
 	Currency string `json:"currency"`
 	Address  string `json:"address"`
@@ -226,6 +226,10 @@ type BitfinexWithdrawal struct {
 	WithdrawalID int64  `json:"withdrawal_id"`
 }
 
+type BitfinexErrorResponse struct {
+	Message string `json:"message"`
+}
+
 type BitfinexGenericResponse struct {
 	Result string `json:"result"`
 }
@@ -257,6 +261,8 @@ type BitfinexWebsocketTicker struct {
 	DialyChangePerc float64
 	LastPrice       float64
 	Volume          float64
+	High            float64
+	Low             float64
 }
 
 type BitfinexWebsocketPosition struct {
@@ -2,13 +2,18 @@ package bitfinex
 
 import (
 	"log"
-	"net/http"
+	"math"
 	"reflect"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 const (
@@ -43,17 +48,7 @@ func (b *Bitfinex) WebsocketPingHandler() error {
 }
 
 func (b *Bitfinex) WebsocketSend(data interface{}) error {
-	json, err := common.JSONEncode(data)
-	if err != nil {
-		return err
-	}
-
-	err = b.WebsocketConn.WriteMessage(websocket.TextMessage, json)
-
-	if err != nil {
-		return err
-	}
-	return nil
+	return b.WebsocketConn.SendJSON(data)
 }
 
 func (b *Bitfinex) WebsocketSubscribe(channel string, params map[string]string) error {
@@ -94,202 +89,291 @@ func (b *Bitfinex) WebsocketAddSubscriptionChannel(chanID int, channel, pair str
 	}
 }
 
-func (b *Bitfinex) WebsocketClient() {
-	channels := []string{"book", "trades", "ticker"}
-	for b.Enabled && b.Websocket {
-		var Dialer websocket.Dialer
-		var err error
-		b.WebsocketConn, _, err = Dialer.Dial(BITFINEX_WEBSOCKET, http.Header{})
+//WebsocketGetDepth returns the websocket maintained orderbook of the pair. The
+//book channel sends a new snapshot on every subscription, so the REST orderbook
+//is only fetched when updates arrive before it
+func (b *Bitfinex) WebsocketGetDepth(currencyPair string) *orderbook.Depth {
+	p := pair.NewCurrencyPair(currencyPair[0:3], currencyPair[3:])
+	return orderbook.GetDepth(b.GetName(), p, func() (orderbook.OrderbookBase, error) {
+		return b.GetOrderbookEx(p)
+	})
+}
 
-		if err != nil {
-			log.Printf("%s Unable to connect to Websocket. Error: %s\n", b.GetName(), err)
-			continue
+//WebsocketProcessBookSnapshot replaces the orderbook of the pair with a book
+//channel snapshot. Positive amounts are bids and negative amounts asks
+func (b *Bitfinex) WebsocketProcessBookSnapshot(currencyPair string, book []BitfinexWebsocketBook) {
+	snapshot := orderbook.OrderbookBase{}
+	for _, x := range book {
+		if x.Amount > 0 {
+			snapshot.Bids = append(snapshot.Bids, orderbook.OrderbookItem{Price: x.Price, Amount: x.Amount})
+		} else {
+			snapshot.Asks = append(snapshot.Asks, orderbook.OrderbookItem{Price: x.Price, Amount: -x.Amount})
 		}
+	}
 
-		msgType, resp, err := b.WebsocketConn.ReadMessage()
-		if err != nil {
-			log.Printf("%s Unable to read from Websocket. Error: %s\n", b.GetName(), err)
-			continue
-		}
-		if msgType != websocket.TextMessage {
+	err := b.WebsocketGetDepth(currencyPair).LoadSnapshot(snapshot)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+//WebsocketProcessBookUpdate applies book channel updates to the orderbook of
+//the pair. A zero count removes the price level, 1 for bids and -1 for asks
+func (b *Bitfinex) WebsocketProcessBookUpdate(currencyPair string, book []BitfinexWebsocketBook) {
+	updates := []orderbook.DepthUpdate{}
+	for _, x := range book {
+		if x.Count == 0 {
+			updates = append(updates, orderbook.DepthUpdate{Bid: x.Amount > 0, Action: orderbook.DEPTH_ACTION_DELETE, Price: x.Price})
 			continue
 		}
+		updates = append(updates, orderbook.DepthUpdate{Bid: x.Amount > 0, Action: orderbook.DEPTH_ACTION_UPDATE, Price: x.Price, Amount: math.Abs(x.Amount)})
+	}
 
-		type WebsocketHandshake struct {
-			Event   string  `json:"event"`
-			Code    int64   `json:"code"`
-			Version float64 `json:"version"`
-		}
+	err := b.WebsocketGetDepth(currencyPair).Apply(0, updates)
+	if err != nil {
+		log.Println(err)
+	}
+}
 
-		hs := WebsocketHandshake{}
-		err = common.JSONDecode(resp, &hs)
-		if err != nil {
-			log.Println(err)
-			continue
-		}
+//WebsocketProcessTicker stores a ticker channel frame in the ticker store
+func (b *Bitfinex) WebsocketProcessTicker(currencyPair string, tick BitfinexWebsocketTicker) {
+	p := pair.NewCurrencyPair(currencyPair[0:3], currencyPair[3:])
+	ticker.ProcessTicker(b.GetName(), p, ticker.TickerPrice{
+		Last:   tick.LastPrice,
+		High:   tick.High,
+		Low:    tick.Low,
+		Bid:    tick.Bid,
+		Ask:    tick.Ask,
+		Volume: tick.Volume,
+	})
+}
 
-		if hs.Event == "info" {
-			if b.Verbose {
-				log.Printf("%s Connected to Websocket.\n", b.GetName())
-			}
+//WebsocketProcessTrades stores trade channel prints in the trade store. The
+//amount is negative when the taker sold. Trades executed, but not yet updated
+//with their ID, are skipped so each trade is stored once
+func (b *Bitfinex) WebsocketProcessTrades(currencyPair string, executed []BitfinexWebsocketTrade) {
+	p := pair.NewCurrencyPair(currencyPair[0:3], currencyPair[3:])
+	result := []trades.Trade{}
+	for _, x := range executed {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.ID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    math.Abs(x.Amount),
+			Timestamp: time.Unix(x.Timestamp, 0),
 		}
-
-		for _, x := range channels {
-			for _, y := range b.EnabledPairs {
-				params := make(map[string]string)
-				if x == "book" {
-					params["prec"] = "P0"
-				}
-				params["pair"] = y
-				b.WebsocketSubscribe(x, params)
-			}
+		if x.ID == 0 {
+			trade.TradeID = ""
 		}
+		if x.Amount < 0 {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
 
-		if b.AuthenticatedAPISupport {
-			err = b.WebsocketSendAuth()
+	if len(result) > 0 {
+		trades.ProcessTrades(b.GetName(), p, result)
+	}
+}
+
+func (b *Bitfinex) WebsocketClient() {
+	b.WebsocketConn.URL = b.GetWebsocketURL(BITFINEX_WEBSOCKET)
+	b.WebsocketConn.Verbose = b.Verbose
+
+	channels := []string{"book", "trades", "ticker"}
+	for _, x := range channels {
+		for _, y := range b.EnabledPairs {
+			params := make(map[string]string)
+			if x == "book" {
+				params["prec"] = "P0"
+			}
+			params["pair"] = y
+			err := b.WebsocketConn.Subscribe(exchange.WebsocketSubscription{Channel: x, Params: params})
 			if err != nil {
-				log.Println(err)
+				log.Printf("%s Websocket subscription error: %s\n", b.GetName(), err)
 			}
 		}
+	}
+	b.WebsocketConn.Run(b.GetWebsocketContext())
+}
 
-		for b.Enabled && b.Websocket {
-			msgType, resp, err := b.WebsocketConn.ReadMessage()
-			if err != nil {
-				log.Println(err)
-				break
-			}
+//WebsocketHandleMessage routes an event or channel message to its handler
+func (b *Bitfinex) WebsocketHandleMessage(msgType int, resp []byte) {
+	switch msgType {
+	case websocket.TextMessage:
+		var result interface{}
+		err := common.JSONDecode(resp, &result)
+		if err != nil {
+			log.Println(err)
+			return
+		}
 
-			switch msgType {
-			case websocket.TextMessage:
-				var result interface{}
-				err := common.JSONDecode(resp, &result)
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+		switch reflect.TypeOf(result).String() {
+		case "map[string]interface {}":
+			eventData := result.(map[string]interface{})
+			event := eventData["event"]
 
-				switch reflect.TypeOf(result).String() {
-				case "map[string]interface {}":
-					eventData := result.(map[string]interface{})
-					event := eventData["event"]
+			switch event {
+			case "info":
+				code, ok := eventData["code"].(float64)
+				if ok && strconv.FormatFloat(code, 'f', -1, 64) == BITFINEX_WEBSOCKET_ALERT_RESTARTING {
+					log.Printf("%s Websocket server restarting, reconnecting.\n", b.GetName())
+					b.WebsocketConn.Close()
+				}
+			case "subscribed":
+				b.WebsocketAddSubscriptionChannel(int(eventData["chanId"].(float64)), eventData["channel"].(string), eventData["pair"].(string))
+			case "auth":
+				status := eventData["status"].(string)
 
-					switch event {
-					case "subscribed":
-						b.WebsocketAddSubscriptionChannel(int(eventData["chanId"].(float64)), eventData["channel"].(string), eventData["pair"].(string))
-					case "auth":
-						status := eventData["status"].(string)
+				if status == "OK" {
+					b.WebsocketAddSubscriptionChannel(0, "account", "N/A")
+				} else if status == "fail" {
+					log.Printf("%s Websocket unable to AUTH. Error code: %s\n", b.GetName(), eventData["code"].(string))
+					b.AuthenticatedAPISupport = false
+				}
+			}
+		case "[]interface {}":
+			chanData := result.([]interface{})
+			chanID := int(chanData[0].(float64))
+			chanInfo, ok := b.WebsocketSubdChannels[chanID]
 
-						if status == "OK" {
-							b.WebsocketAddSubscriptionChannel(0, "account", "N/A")
-						} else if status == "fail" {
-							log.Printf("%s Websocket unable to AUTH. Error code: %s\n", b.GetName(), eventData["code"].(string))
-							b.AuthenticatedAPISupport = false
+			if !ok {
+				log.Printf("Unable to locate chanID: %d\n", chanID)
+			} else {
+				if len(chanData) == 2 {
+					if reflect.TypeOf(chanData[1]).String() == "string" {
+						if chanData[1].(string) == BITFINEX_WEBSOCKET_HEARTBEAT {
+							return
 						}
 					}
-				case "[]interface {}":
-					chanData := result.([]interface{})
-					chanID := int(chanData[0].(float64))
-					chanInfo, ok := b.WebsocketSubdChannels[chanID]
-
-					if !ok {
-						log.Printf("Unable to locate chanID: %d\n", chanID)
-					} else {
-						if len(chanData) == 2 {
-							if reflect.TypeOf(chanData[1]).String() == "string" {
-								if chanData[1].(string) == BITFINEX_WEBSOCKET_HEARTBEAT {
-									continue
-								}
-							}
+				}
+				switch chanInfo.Channel {
+				case "book":
+					book := []BitfinexWebsocketBook{}
+					switch len(chanData) {
+					case 2:
+						data := chanData[1].([]interface{})
+						for _, x := range data {
+							y := x.([]interface{})
+							book = append(book, BitfinexWebsocketBook{Price: y[0].(float64), Count: int(y[1].(float64)), Amount: y[2].(float64)})
 						}
-						switch chanInfo.Channel {
-						case "book":
-							orderbook := []BitfinexWebsocketBook{}
-							switch len(chanData) {
-							case 2:
-								data := chanData[1].([]interface{})
-								for _, x := range data {
-									y := x.([]interface{})
-									orderbook = append(orderbook, BitfinexWebsocketBook{Price: y[0].(float64), Count: int(y[1].(float64)), Amount: y[2].(float64)})
-								}
-							case 4:
-								orderbook = append(orderbook, BitfinexWebsocketBook{Price: chanData[1].(float64), Count: int(chanData[2].(float64)), Amount: chanData[3].(float64)})
-							}
-						case "ticker":
-							ticker := BitfinexWebsocketTicker{Bid: chanData[1].(float64), BidSize: chanData[2].(float64), Ask: chanData[3].(float64), AskSize: chanData[4].(float64),
-								DailyChange: chanData[5].(float64), DialyChangePerc: chanData[6].(float64), LastPrice: chanData[7].(float64), Volume: chanData[8].(float64)}
+						b.WebsocketProcessBookSnapshot(chanInfo.Pair, book)
+					case 4:
+						book = append(book, BitfinexWebsocketBook{Price: chanData[1].(float64), Count: int(chanData[2].(float64)), Amount: chanData[3].(float64)})
+						b.WebsocketProcessBookUpdate(chanInfo.Pair, book)
+					}
+				case "ticker":
+					ticker := BitfinexWebsocketTicker{Bid: chanData[1].(float64), BidSize: chanData[2].(float64), Ask: chanData[3].(float64), AskSize: chanData[4].(float64),
+						DailyChange: chanData[5].(float64), DialyChangePerc: chanData[6].(float64), LastPrice: chanData[7].(float64), Volume: chanData[8].(float64)}
+					if len(chanData) > 10 {
+						ticker.High = chanData[9].(float64)
+						ticker.Low = chanData[10].(float64)
+					}
+					b.WebsocketProcessTicker(chanInfo.Pair, ticker)
 
-							log.Printf("Bitfinex %s Websocket Last %f Volume %f\n", chanInfo.Pair, ticker.LastPrice, ticker.Volume)
-						case "account":
-							switch chanData[1].(string) {
-							case BITFINEX_WEBSOCKET_POSITION_SNAPSHOT:
-								positionSnapshot := []BitfinexWebsocketPosition{}
-								data := chanData[2].([]interface{})
-								for _, x := range data {
-									y := x.([]interface{})
-									positionSnapshot = append(positionSnapshot, BitfinexWebsocketPosition{Pair: y[0].(string), Status: y[1].(string), Amount: y[2].(float64), Price: y[3].(float64),
-										MarginFunding: y[4].(float64), MarginFundingType: int(y[5].(float64))})
-								}
-								log.Println(positionSnapshot)
-							case BITFINEX_WEBSOCKET_POSITION_NEW, BITFINEX_WEBSOCKET_POSITION_UPDATE, BITFINEX_WEBSOCKET_POSITION_CLOSE:
-								data := chanData[2].([]interface{})
-								position := BitfinexWebsocketPosition{Pair: data[0].(string), Status: data[1].(string), Amount: data[2].(float64), Price: data[3].(float64),
-									MarginFunding: data[4].(float64), MarginFundingType: int(data[5].(float64))}
-								log.Println(position)
-							case BITFINEX_WEBSOCKET_WALLET_SNAPSHOT:
-								data := chanData[2].([]interface{})
-								walletSnapshot := []BitfinexWebsocketWallet{}
-								for _, x := range data {
-									y := x.([]interface{})
-									walletSnapshot = append(walletSnapshot, BitfinexWebsocketWallet{Name: y[0].(string), Currency: y[1].(string), Balance: y[2].(float64), UnsettledInterest: y[3].(float64)})
-								}
-								log.Println(walletSnapshot)
-							case BITFINEX_WEBSOCKET_WALLET_UPDATE:
-								data := chanData[2].([]interface{})
-								wallet := BitfinexWebsocketWallet{Name: data[0].(string), Currency: data[1].(string), Balance: data[2].(float64), UnsettledInterest: data[3].(float64)}
-								log.Println(wallet)
-							case BITFINEX_WEBSOCKET_ORDER_SNAPSHOT:
-								orderSnapshot := []BitfinexWebsocketOrder{}
-								data := chanData[2].([]interface{})
-								for _, x := range data {
-									y := x.([]interface{})
-									orderSnapshot = append(orderSnapshot, BitfinexWebsocketOrder{OrderID: int64(y[0].(float64)), Pair: y[1].(string), Amount: y[2].(float64), OrigAmount: y[3].(float64),
-										OrderType: y[4].(string), Status: y[5].(string), Price: y[6].(float64), PriceAvg: y[7].(float64), Timestamp: y[8].(string)})
-								}
-								log.Println(orderSnapshot)
-							case BITFINEX_WEBSOCKET_ORDER_NEW, BITFINEX_WEBSOCKET_ORDER_UPDATE, BITFINEX_WEBSOCKET_ORDER_CANCEL:
-								data := chanData[2].([]interface{})
-								order := BitfinexWebsocketOrder{OrderID: int64(data[0].(float64)), Pair: data[1].(string), Amount: data[2].(float64), OrigAmount: data[3].(float64),
-									OrderType: data[4].(string), Status: data[5].(string), Price: data[6].(float64), PriceAvg: data[7].(float64), Timestamp: data[8].(string), Notify: int(data[9].(float64))}
-								log.Println(order)
-							case BITFINEX_WEBSOCKET_TRADE_EXECUTED:
-								data := chanData[2].([]interface{})
-								trade := BitfinexWebsocketTradeExecuted{TradeID: int64(data[0].(float64)), Pair: data[1].(string), Timestamp: int64(data[2].(float64)), OrderID: int64(data[3].(float64)),
-									AmountExecuted: data[4].(float64), PriceExecuted: data[5].(float64)}
-								log.Println(trade)
+					if b.Verbose {
+						log.Printf("Bitfinex %s Websocket Last %f Volume %f\n", chanInfo.Pair, ticker.LastPrice, ticker.Volume)
+					}
+				case "account":
+					switch chanData[1].(string) {
+					case BITFINEX_WEBSOCKET_POSITION_SNAPSHOT:
+						positionSnapshot := []BitfinexWebsocketPosition{}
+						data := chanData[2].([]interface{})
+						for _, x := range data {
+							y := x.([]interface{})
+							positionSnapshot = append(positionSnapshot, BitfinexWebsocketPosition{Pair: y[0].(string), Status: y[1].(string), Amount: y[2].(float64), Price: y[3].(float64),
+								MarginFunding: y[4].(float64), MarginFundingType: int(y[5].(float64))})
+						}
+						b.WebsocketAccount.SetPositions(positionSnapshot)
+					case BITFINEX_WEBSOCKET_POSITION_NEW, BITFINEX_WEBSOCKET_POSITION_UPDATE, BITFINEX_WEBSOCKET_POSITION_CLOSE:
+						data := chanData[2].([]interface{})
+						position := BitfinexWebsocketPosition{Pair: data[0].(string), Status: data[1].(string), Amount: data[2].(float64), Price: data[3].(float64),
+							MarginFunding: data[4].(float64), MarginFundingType: int(data[5].(float64))}
+						b.WebsocketAccount.UpdatePosition(position, chanData[1].(string) == BITFINEX_WEBSOCKET_POSITION_CLOSE)
+					case BITFINEX_WEBSOCKET_WALLET_SNAPSHOT:
+						data := chanData[2].([]interface{})
+						walletSnapshot := []BitfinexWebsocketWallet{}
+						for _, x := range data {
+							y := x.([]interface{})
+							walletSnapshot = append(walletSnapshot, BitfinexWebsocketWallet{Name: y[0].(string), Currency: y[1].(string), Balance: y[2].(float64), UnsettledInterest: y[3].(float64)})
+						}
+						b.WebsocketAccount.SetWallets(walletSnapshot)
+					case BITFINEX_WEBSOCKET_WALLET_UPDATE:
+						data := chanData[2].([]interface{})
+						wallet := BitfinexWebsocketWallet{Name: data[0].(string), Currency: data[1].(string), Balance: data[2].(float64), UnsettledInterest: data[3].(float64)}
+						b.WebsocketAccount.UpdateWallet(wallet)
+					case BITFINEX_WEBSOCKET_ORDER_SNAPSHOT:
+						orderSnapshot := []BitfinexWebsocketOrder{}
+						data := chanData[2].([]interface{})
+						for _, x := range data {
+							y := x.([]interface{})
+							orderSnapshot = append(orderSnapshot, BitfinexWebsocketOrder{OrderID: int64(y[0].(float64)), Pair: y[1].(string), Amount: y[2].(float64), OrigAmount: y[3].(float64),
+								OrderType: y[4].(string), Status: y[5].(string), Price: y[6].(float64), PriceAvg: y[7].(float64), Timestamp: y[8].(string)})
+						}
+						b.WebsocketAccount.SetOrders(orderSnapshot)
+					case BITFINEX_WEBSOCKET_ORDER_NEW, BITFINEX_WEBSOCKET_ORDER_UPDATE, BITFINEX_WEBSOCKET_ORDER_CANCEL:
+						data := chanData[2].([]interface{})
+						order := BitfinexWebsocketOrder{OrderID: int64(data[0].(float64)), Pair: data[1].(string), Amount: data[2].(float64), OrigAmount: data[3].(float64),
+							OrderType: data[4].(string), Status: data[5].(string), Price: data[6].(float64), PriceAvg: data[7].(float64), Timestamp: data[8].(string), Notify: int(data[9].(float64))}
+						b.WebsocketAccount.UpdateOrder(order, chanData[1].(string) == BITFINEX_WEBSOCKET_ORDER_CANCEL)
+					case BITFINEX_WEBSOCKET_TRADE_EXECUTED:
+						data := chanData[2].([]interface{})
+						trade := BitfinexWebsocketTradeExecuted{TradeID: int64(data[0].(float64)), Pair: data[1].(string), Timestamp: int64(data[2].(float64)), OrderID: int64(data[3].(float64)),
+							AmountExecuted: data[4].(float64), PriceExecuted: data[5].(float64)}
+						b.WebsocketAccount.AddFill(b.getWebsocketFill(trade))
+					}
+				case "trades":
+					trades := []BitfinexWebsocketTrade{}
+					switch len(chanData) {
+					case 2:
+						data := chanData[1].([]interface{})
+						for _, x := range data {
+							y := x.([]interface{})
+							trade := BitfinexWebsocketTrade{Timestamp: int64(y[1].(float64)), Price: y[2].(float64), Amount: y[3].(float64)}
+							if id, ok := y[0].(float64); ok {
+								trade.ID = int64(id)
 							}
-						case "trades":
-							trades := []BitfinexWebsocketTrade{}
-							switch len(chanData) {
-							case 2:
-								data := chanData[1].([]interface{})
-								for _, x := range data {
-									y := x.([]interface{})
-									trades = append(trades, BitfinexWebsocketTrade{ID: int64(y[0].(float64)), Timestamp: int64(y[1].(float64)), Price: y[2].(float64), Amount: y[3].(float64)})
-								}
-							case 5:
-								trade := BitfinexWebsocketTrade{ID: int64(chanData[1].(float64)), Timestamp: int64(chanData[2].(float64)), Price: chanData[3].(float64), Amount: chanData[4].(float64)}
-								trades = append(trades, trade)
+							trades = append(trades, trade)
+						}
+					case 5:
+						trade := BitfinexWebsocketTrade{ID: int64(chanData[1].(float64)), Timestamp: int64(chanData[2].(float64)), Price: chanData[3].(float64), Amount: chanData[4].(float64)}
+						trades = append(trades, trade)
+					case 7:
+						trade := BitfinexWebsocketTrade{ID: int64(chanData[3].(float64)), Timestamp: int64(chanData[4].(float64)), Price: chanData[5].(float64), Amount: chanData[6].(float64)}
+						trades = append(trades, trade)
+					}
 
-								if b.Verbose {
-									log.Printf("Bitfinex %s Websocket Trade ID %d Timestamp %d Price %f Amount %f\n", chanInfo.Pair, trade.ID, trade.Timestamp, trade.Price, trade.Amount)
-								}
-							}
+					for _, x := range trades {
+						if b.Verbose {
+							log.Printf("Bitfinex %s Websocket Trade ID %d Timestamp %d Price %f Amount %f\n", chanInfo.Pair, x.ID, x.Timestamp, x.Price, x.Amount)
 						}
 					}
+					b.WebsocketProcessTrades(chanInfo.Pair, trades)
 				}
 			}
 		}
-		b.WebsocketConn.Close()
-		log.Printf("%s Websocket client disconnected.\n", b.GetName())
 	}
 }
+
+//websocketConnect drops the channel IDs of the previous connection and
+//authenticates, so the account channel is sent again
+func (b *Bitfinex) websocketConnect() error {
+	b.WebsocketSubdChannels = make(map[int]BitfinexWebsocketChanInfo)
+	if !b.AuthenticatedAPISupport {
+		return nil
+	}
+	return b.WebsocketSendAuth()
+}
+
+func (b *Bitfinex) newWebsocketConnection() *exchange.WebsocketConnection {
+	conn := exchange.NewWebsocketConnection(b.GetName(), BITFINEX_WEBSOCKET)
+	conn.OnConnect = b.websocketConnect
+	conn.OnSubscribe = func(subscription exchange.WebsocketSubscription) error {
+		return b.WebsocketSubscribe(subscription.Channel, subscription.Params)
+	}
+	conn.OnMessage = b.WebsocketHandleMessage
+	conn.OnDisconnect = b.WebsocketAccount.Reset
+	conn.Ping = b.WebsocketPingHandler
+	return conn
+}
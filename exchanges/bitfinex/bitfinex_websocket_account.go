@@ -0,0 +1,230 @@
+package bitfinex
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+const (
+	BITFINEX_WEBSOCKET_FILLS_LIMIT = 1000
+)
+
+//BitfinexWebsocketAccount : Account state pushed over the authenticated
+//websocket channel. It is synced once the wallet snapshot has been received
+type BitfinexWebsocketAccount struct {
+	mtx       sync.RWMutex
+	synced    bool
+	wallets   map[string]BitfinexWebsocketWallet
+	positions map[string]BitfinexWebsocketPosition
+	orders    map[int64]BitfinexWebsocketOrder
+	fills     []exchange.Fill
+}
+
+//NewBitfinexWebsocketAccount returns an empty account awaiting its snapshots
+func NewBitfinexWebsocketAccount() *BitfinexWebsocketAccount {
+	return &BitfinexWebsocketAccount{
+		wallets:   make(map[string]BitfinexWebsocketWallet),
+		positions: make(map[string]BitfinexWebsocketPosition),
+		orders:    make(map[int64]BitfinexWebsocketOrder),
+	}
+}
+
+//IsSynced returns whether the account holds the websocket wallet snapshot
+func (a *BitfinexWebsocketAccount) IsSynced() bool {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.synced
+}
+
+//Reset drops the account state, so it is only used again once the snapshots
+//are resent after the next authentication
+func (a *BitfinexWebsocketAccount) Reset() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.synced = false
+	a.wallets = make(map[string]BitfinexWebsocketWallet)
+	a.positions = make(map[string]BitfinexWebsocketPosition)
+	a.orders = make(map[int64]BitfinexWebsocketOrder)
+}
+
+//SetWallets replaces every wallet balance with the snapshot
+func (a *BitfinexWebsocketAccount) SetWallets(wallets []BitfinexWebsocketWallet) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.wallets = make(map[string]BitfinexWebsocketWallet)
+	for _, x := range wallets {
+		a.wallets[x.Name+x.Currency] = x
+	}
+	a.synced = true
+}
+
+//UpdateWallet stores the balance of a single wallet currency
+func (a *BitfinexWebsocketAccount) UpdateWallet(wallet BitfinexWebsocketWallet) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.wallets[wallet.Name+wallet.Currency] = wallet
+}
+
+//SetPositions replaces every margin position with the snapshot
+func (a *BitfinexWebsocketAccount) SetPositions(positions []BitfinexWebsocketPosition) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.positions = make(map[string]BitfinexWebsocketPosition)
+	for _, x := range positions {
+		a.positions[x.Pair] = x
+	}
+}
+
+//UpdatePosition stores a margin position, removing it once it is closed
+func (a *BitfinexWebsocketAccount) UpdatePosition(position BitfinexWebsocketPosition, closed bool) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if closed {
+		delete(a.positions, position.Pair)
+		return
+	}
+	a.positions[position.Pair] = position
+}
+
+//SetOrders replaces every active order with the snapshot
+func (a *BitfinexWebsocketAccount) SetOrders(orders []BitfinexWebsocketOrder) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.orders = make(map[int64]BitfinexWebsocketOrder)
+	for _, x := range orders {
+		a.orders[x.OrderID] = x
+	}
+}
+
+//UpdateOrder stores an active order, removing it once it is cancelled or fully
+//executed
+func (a *BitfinexWebsocketAccount) UpdateOrder(order BitfinexWebsocketOrder, closed bool) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if closed {
+		delete(a.orders, order.OrderID)
+		return
+	}
+	a.orders[order.OrderID] = order
+}
+
+//AddFill records an execution, keeping the most recent fills only
+func (a *BitfinexWebsocketAccount) AddFill(fill exchange.Fill) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if len(a.fills) >= BITFINEX_WEBSOCKET_FILLS_LIMIT {
+		a.fills = a.fills[1:]
+	}
+	a.fills = append(a.fills, fill)
+}
+
+//GetWallets returns a copy of the wallet balances
+func (a *BitfinexWebsocketAccount) GetWallets() []BitfinexWebsocketWallet {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	var result []BitfinexWebsocketWallet
+	for _, x := range a.wallets {
+		result = append(result, x)
+	}
+	return result
+}
+
+//GetPositions returns a copy of the open margin positions
+func (a *BitfinexWebsocketAccount) GetPositions() []BitfinexWebsocketPosition {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	var result []BitfinexWebsocketPosition
+	for _, x := range a.positions {
+		result = append(result, x)
+	}
+	return result
+}
+
+//GetOrders returns a copy of the active orders
+func (a *BitfinexWebsocketAccount) GetOrders() []BitfinexWebsocketOrder {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	var result []BitfinexWebsocketOrder
+	for _, x := range a.orders {
+		result = append(result, x)
+	}
+	return result
+}
+
+//GetFills returns a copy of the recent executions
+func (a *BitfinexWebsocketAccount) GetFills() []exchange.Fill {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return append([]exchange.Fill(nil), a.fills...)
+}
+
+//getWebsocketOrderDetail converts a websocket order, whose amounts are signed by
+//side and hold the remaining rather than the executed amount
+func (b *Bitfinex) getWebsocketOrderDetail(order BitfinexWebsocketOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = b.GetName()
+	detail.OrderID = strconv.FormatInt(order.OrderID, 10)
+	if len(order.Pair) == 6 {
+		detail.Pair = pair.NewCurrencyPair(common.StringToUpper(order.Pair[0:3]), common.StringToUpper(order.Pair[3:]))
+	}
+	detail.Side = exchange.OrderSideBuy
+	if order.OrigAmount < 0 {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	if common.StringContains(common.StringToUpper(order.OrderType), "MARKET") {
+		detail.Type = exchange.OrderTypeMarket
+	}
+
+	status := common.StringToUpper(order.Status)
+	active := common.StringContains(status, "ACTIVE") || common.StringContains(status, "PARTIALLY FILLED")
+	cancelled := common.StringContains(status, "CANCELED")
+	detail.Amount = math.Abs(order.OrigAmount)
+	detail.FilledAmount = detail.Amount - math.Abs(order.Amount)
+	detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, active, cancelled)
+	detail.Price = order.Price
+	detail.AveragePrice = order.PriceAvg
+
+	created, err := time.Parse(time.RFC3339Nano, order.Timestamp)
+	if err == nil {
+		detail.CreatedAt = created
+	}
+	detail.UpdatedAt = time.Now()
+	return detail
+}
+
+//getWebsocketFill converts a websocket trade execution. Sells are reported
+//with a negative executed amount
+func (b *Bitfinex) getWebsocketFill(trade BitfinexWebsocketTradeExecuted) exchange.Fill {
+	var fill exchange.Fill
+	fill.Exchange = b.GetName()
+	fill.TradeID = strconv.FormatInt(trade.TradeID, 10)
+	fill.OrderID = strconv.FormatInt(trade.OrderID, 10)
+	if len(trade.Pair) == 6 {
+		fill.Pair = pair.NewCurrencyPair(common.StringToUpper(trade.Pair[0:3]), common.StringToUpper(trade.Pair[3:]))
+	}
+	fill.Side = exchange.OrderSideBuy
+	if trade.AmountExecuted < 0 {
+		fill.Side = exchange.OrderSideSell
+	}
+	fill.Price = trade.PriceExecuted
+	fill.Amount = math.Abs(trade.AmountExecuted)
+	fill.Timestamp = time.Unix(trade.Timestamp, 0)
+	return fill
+}
@@ -1,19 +1,19 @@
 package bitfinex
 
 import (
-	"net/http"
 	"testing"
 
 	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/exchanges"
 )
 
 func TestWebsocketPingHandler(t *testing.T) {
 	wsPingHandler := Bitfinex{}
-	var Dialer websocket.Dialer
 	var err error
 
-	wsPingHandler.WebsocketConn, _, err = Dialer.Dial(BITFINEX_WEBSOCKET, http.Header{})
+	wsPingHandler.WebsocketConn = exchange.NewWebsocketConnection("Bitfinex", BITFINEX_WEBSOCKET)
+	err = wsPingHandler.WebsocketConn.Connect()
 	if err != nil {
 		t.Errorf("Test Failed - Bitfinex dialer error: %s", err)
 	}
@@ -29,7 +29,6 @@ func TestWebsocketPingHandler(t *testing.T) {
 
 func TestWebsocketSend(t *testing.T) {
 	wsSend := Bitfinex{}
-	var Dialer websocket.Dialer
 	var err error
 
 	type WebsocketHandshake struct {
@@ -45,7 +44,8 @@ func TestWebsocketSend(t *testing.T) {
 	hs := WebsocketHandshake{}
 
 	for {
-		wsSend.WebsocketConn, _, err = Dialer.Dial(BITFINEX_WEBSOCKET, http.Header{})
+		wsSend.WebsocketConn = exchange.NewWebsocketConnection("Bitfinex", BITFINEX_WEBSOCKET)
+		err = wsSend.WebsocketConn.Connect()
 		if err != nil {
 			if err.Error() == "websocket: close 1006 (abnormal closure): unexpected EOF" {
 				err = wsSend.WebsocketConn.Close()
@@ -154,12 +154,12 @@ func TestWebsocketSend(t *testing.T) {
 
 func TestWebsocketSubscribe(t *testing.T) {
 	websocketSubcribe := Bitfinex{}
-	var Dialer websocket.Dialer
 	var err error
 	params := make(map[string]string)
 	params["pair"] = "BTCUSD"
 
-	websocketSubcribe.WebsocketConn, _, err = Dialer.Dial(BITFINEX_WEBSOCKET, http.Header{})
+	websocketSubcribe.WebsocketConn = exchange.NewWebsocketConnection("Bitfinex", BITFINEX_WEBSOCKET)
+	err = websocketSubcribe.WebsocketConn.Connect()
 	if err != nil {
 		t.Errorf("Test Failed - Bitfinex Dialer error: %s", err)
 	}
@@ -176,10 +176,10 @@ func TestWebsocketSubscribe(t *testing.T) {
 
 func TestWebsocketSendAuth(t *testing.T) {
 	wsSendAuth := Bitfinex{}
-	var Dialer websocket.Dialer
 	var err error
 
-	wsSendAuth.WebsocketConn, _, err = Dialer.Dial(BITFINEX_WEBSOCKET, http.Header{})
+	wsSendAuth.WebsocketConn = exchange.NewWebsocketConnection("Bitfinex", BITFINEX_WEBSOCKET)
+	err = wsSendAuth.WebsocketConn.Connect()
 	if err != nil {
 		t.Errorf("Test Failed - Bitfinex Dialer error: %s", err)
 	}
@@ -191,10 +191,10 @@ func TestWebsocketSendAuth(t *testing.T) {
 
 func TestWebsocketSendUnauth(t *testing.T) {
 	wsSendUnauth := Bitfinex{}
-	var Dialer websocket.Dialer
 	var err error
 
-	wsSendUnauth.WebsocketConn, _, err = Dialer.Dial(BITFINEX_WEBSOCKET, http.Header{})
+	wsSendUnauth.WebsocketConn = exchange.NewWebsocketConnection("Bitfinex", BITFINEX_WEBSOCKET)
+	err = wsSendUnauth.WebsocketConn.Connect()
 	if err != nil {
 		t.Errorf("Test Failed - Bitfinex Dialer error: %s", err)
 	}
@@ -207,10 +207,10 @@ func TestWebsocketSendUnauth(t *testing.T) {
 func TestWebsocketAddSubscriptionChannel(t *testing.T) {
 	wsAddSubscriptionChannel := Bitfinex{}
 	wsAddSubscriptionChannel.SetDefaults()
-	var Dialer websocket.Dialer
 	var err error
 
-	wsAddSubscriptionChannel.WebsocketConn, _, err = Dialer.Dial(BITFINEX_WEBSOCKET, http.Header{})
+	wsAddSubscriptionChannel.WebsocketConn = exchange.NewWebsocketConnection("Bitfinex", BITFINEX_WEBSOCKET)
+	err = wsAddSubscriptionChannel.WebsocketConn.Connect()
 	if err != nil {
 		t.Errorf("Test Failed - Bitfinex Dialer error: %s", err)
 	}
@@ -230,3 +230,34 @@ func TestWebsocketAddSubscriptionChannel(t *testing.T) {
 // func TestWebsocketClient(t *testing.T) {
 //
 // }
+
+func TestWebsocketAccount(t *testing.T) {
+	b := Bitfinex{}
+	b.SetDefaults()
+	b.Websocket = true
+
+	b.WebsocketAccount.SetWallets([]BitfinexWebsocketWallet{{Name: "exchange", Currency: "BTC", Balance: 1}})
+	b.WebsocketAccount.UpdateWallet(BitfinexWebsocketWallet{Name: "exchange", Currency: "BTC", Balance: 2})
+	b.WebsocketAccount.SetOrders([]BitfinexWebsocketOrder{{OrderID: 1, Pair: "BTCUSD", Amount: -0.5, OrigAmount: -2, OrderType: "EXCHANGE LIMIT", Status: "PARTIALLY FILLED @ 100(-1.5)", Price: 100}})
+	b.WebsocketAccount.UpdateOrder(BitfinexWebsocketOrder{OrderID: 2, Pair: "BTCUSD", Amount: 1, OrigAmount: 1, Status: "ACTIVE", Price: 90}, false)
+	b.WebsocketAccount.UpdateOrder(BitfinexWebsocketOrder{OrderID: 2}, true)
+
+	info, err := b.GetExchangeAccountInfo()
+	if err != nil || len(info.Currencies) != 1 || info.Currencies[0].TotalValue != 2 {
+		t.Errorf("Test Failed - Bitfinex websocket account balances are incorrect: %+v", info)
+	}
+
+	orders, err := b.GetActiveOrders(exchange.OrderFilter{})
+	if err != nil || len(orders) != 1 {
+		t.Fatalf("Test Failed - Bitfinex websocket active orders are incorrect: %+v", orders)
+	}
+
+	if orders[0].Side != exchange.OrderSideSell || orders[0].FilledAmount != 1.5 || orders[0].Status != exchange.OrderStatusPartiallyFilled {
+		t.Errorf("Test Failed - Bitfinex websocket order detail is incorrect: %+v", orders[0])
+	}
+
+	b.WebsocketAccount.Reset()
+	if b.WebsocketAccount.IsSynced() {
+		t.Error("Test Failed - Bitfinex websocket account is synced after reset")
+	}
+}
@@ -1,7 +1,10 @@
 package bitfinex
 
 import (
+	"errors"
 	"log"
+	"math"
+	"sort"
 	"strconv"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (b *Bitfinex) Start() {
@@ -38,17 +42,22 @@ func (b *Bitfinex) Run() {
 		}
 	}
 
+	err = b.UpdatePairInfo()
+	if err != nil {
+		log.Printf("%s Failed to get pair info.\n", b.GetName())
+	}
+
 	for b.Enabled {
 		for _, x := range b.EnabledPairs {
 			currency := pair.NewCurrencyPair(x[0:3], x[3:])
-			go func() {
+			b.Poll(x, func() {
 				ticker, err := b.GetTickerPrice(currency)
 				if err != nil {
 					return
 				}
 				log.Printf("Bitfinex %s Last %f High %f Low %f Volume %f\n", currency.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(b.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * b.RESTPollingDelay)
 	}
@@ -105,10 +114,21 @@ func (b *Bitfinex) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase,
 	return orderBook, nil
 }
 
-//GetExchangeAccountInfo : Retrieves balances for all enabled currencies for the Bitfinex exchange
+//GetExchangeAccountInfo : Retrieves balances for all enabled currencies for the Bitfinex exchange.
+//Balances pushed over the authenticated websocket are used once received
 func (e *Bitfinex) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	var response exchange.ExchangeAccountInfo
 	response.ExchangeName = e.GetName()
+	if e.Websocket && e.WebsocketAccount != nil && e.WebsocketAccount.IsSynced() {
+		for _, x := range e.WebsocketAccount.GetWallets() {
+			var exchangeCurrency exchange.ExchangeAccountCurrencyInfo
+			exchangeCurrency.CurrencyName = common.StringToUpper(x.Currency)
+			exchangeCurrency.TotalValue = x.Balance
+			response.Currencies = append(response.Currencies, exchangeCurrency)
+		}
+		return response, nil
+	}
+
 	accountBalance, err := e.GetAccountBalance()
 	if err != nil {
 		return response, err
@@ -127,3 +147,351 @@ func (e *Bitfinex) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error
 	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new exchange order on Bitfinex
+func (b *Bitfinex) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := b.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	response, err := b.NewOrder(order.Pair.Pair().Lower().String(), order.Amount, order.Price, order.IsBuy(), b.getOrderType(order.Type), false)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = b.GetName()
+	result.OrderID = strconv.FormatInt(response.OrderID, 10)
+	result.ClientID = order.ClientID
+	result.FilledAmount = response.ExecutedAmount
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (b *Bitfinex) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.CancelExistingOrder(id)
+	return err
+}
+
+//ModifyOrder : Replaces an existing order with the supplied order parameters
+func (b *Bitfinex) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := b.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return result, err
+	}
+
+	response, err := b.ReplaceOrder(id, order.Pair.Pair().Lower().String(), order.Amount, order.Price, order.IsBuy(), b.getOrderType(order.Type), false)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = b.GetName()
+	result.OrderID = strconv.FormatInt(response.OrderID, 10)
+	result.ClientID = order.ClientID
+	result.FilledAmount = response.ExecutedAmount
+	return result, nil
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (b *Bitfinex) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return detail, err
+	}
+
+	response, err := b.GetOrderStatus(id)
+	if err != nil {
+		return detail, err
+	}
+	return b.getOrderDetail(response), nil
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter. Orders
+//pushed over the authenticated websocket are used once received
+func (b *Bitfinex) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	if b.Websocket && b.WebsocketAccount != nil && b.WebsocketAccount.IsSynced() {
+		var orders []exchange.OrderDetail
+		for _, x := range b.WebsocketAccount.GetOrders() {
+			orders = append(orders, b.getWebsocketOrderDetail(x))
+		}
+		return exchange.FilterOrders(orders, filter), nil
+	}
+
+	response, err := b.GetOpenOrders()
+	if err != nil {
+		return nil, err
+	}
+	return b.getOrderDetails(response, filter), nil
+}
+
+//GetOrderHistory : Retrieves closed and cancelled orders matching the filter.
+//Bitfinex only keeps inactive orders for the last two weeks
+func (b *Bitfinex) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := b.GetInactiveOrders(0)
+	if err != nil {
+		return nil, err
+	}
+	return b.getOrderDetails(response, filter), nil
+}
+
+//GetFills : Retrieves the account's executions matching the filter, paging
+//forward through the trade history of each pair
+func (b *Bitfinex) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	var fills []exchange.Fill
+	for _, x := range b.getFilterPairs(filter) {
+		symbol := common.StringToLower(x.GetFirstCurrency().String() + x.GetSecondCurrency().String())
+		start := filter.StartTime
+		if start.IsZero() {
+			start = time.Unix(0, 0)
+		}
+
+		seen := make(map[int64]bool)
+		for {
+			response, err := b.GetTradeHistory(symbol, start, filter.EndTime, BITFINEX_TRADE_HISTORY_LIMIT, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			added := 0
+			for _, y := range response {
+				if seen[y.TID] {
+					continue
+				}
+				seen[y.TID] = true
+				added++
+
+				fill := b.getFill(x, y)
+				if fill.Timestamp.After(start) {
+					start = fill.Timestamp
+				}
+				fills = append(fills, fill)
+			}
+
+			if added == 0 || len(response) < BITFINEX_TRADE_HISTORY_LIMIT {
+				break
+			}
+		}
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+func (b *Bitfinex) getFilterPairs(filter exchange.OrderFilter) []pair.CurrencyPair {
+	if len(filter.Pairs) > 0 {
+		return filter.Pairs
+	}
+
+	var pairs []pair.CurrencyPair
+	for _, x := range b.EnabledPairs {
+		pairs = append(pairs, pair.NewCurrencyPair(x[0:3], x[3:]))
+	}
+	return pairs
+}
+
+func (b *Bitfinex) getFill(p pair.CurrencyPair, trade BitfinexTradeHistory) exchange.Fill {
+	var fill exchange.Fill
+	fill.Exchange = b.GetName()
+	fill.TradeID = strconv.FormatInt(trade.TID, 10)
+	fill.OrderID = strconv.FormatInt(trade.OrderID, 10)
+	fill.Pair = p
+	fill.Side = exchange.OrderSide(common.StringToUpper(trade.Type))
+	fill.Price = trade.Price
+	fill.Amount = trade.Amount
+	fill.Fee = math.Abs(trade.FeeAmount)
+	fill.FeeCurrency = trade.FeeCurrency
+
+	timestamp, err := strconv.ParseFloat(trade.Timestamp, 64)
+	if err == nil {
+		fill.Timestamp = time.Unix(int64(timestamp), 0)
+	}
+	return fill
+}
+
+func (b *Bitfinex) getOrderDetails(response []BitfinexOrder, filter exchange.OrderFilter) []exchange.OrderDetail {
+	var orders []exchange.OrderDetail
+	for _, x := range response {
+		orders = append(orders, b.getOrderDetail(x))
+	}
+	return exchange.FilterOrders(orders, filter)
+}
+
+func (b *Bitfinex) getOrderType(orderType exchange.OrderType) string {
+	if orderType == exchange.OrderTypeMarket {
+		return "exchange market"
+	}
+	return "exchange limit"
+}
+
+func (b *Bitfinex) getOrderDetail(order BitfinexOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = b.GetName()
+	detail.OrderID = strconv.FormatInt(order.ID, 10)
+	if len(order.Symbol) == 6 {
+		detail.Pair = pair.NewCurrencyPair(common.StringToUpper(order.Symbol[0:3]), common.StringToUpper(order.Symbol[3:]))
+	}
+	detail.Side = exchange.OrderSideBuy
+	if order.Side == "sell" {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	if common.StringContains(order.Type, "market") {
+		detail.Type = exchange.OrderTypeMarket
+	}
+	detail.Status = exchange.GetOrderStatus(order.OriginalAmount, order.ExecutedAmount, order.IsLive, order.IsCancelled)
+	detail.Price = order.Price
+	detail.Amount = order.OriginalAmount
+	detail.FilledAmount = order.ExecutedAmount
+	detail.AveragePrice = order.AverageExecutionPrice
+
+	timestamp, err := strconv.ParseFloat(order.Timestamp, 64)
+	if err == nil {
+		detail.CreatedAt = time.Unix(int64(timestamp), 0)
+	}
+	return detail
+}
+
+//GetDepositAddress : Retrieves the exchange wallet deposit address for a currency
+func (b *Bitfinex) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	var address exchange.DepositAddress
+	method, ok := b.getFundingMethod(currency)
+	if !ok {
+		return address, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	response, err := b.NewDeposit(method, "exchange", 0)
+	if err != nil {
+		return address, exchange.NewFundingError(b.GetName(), currency, err)
+	}
+
+	if response.Result != "success" || response.Address == "" {
+		return address, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrDepositAddressNotFound))
+	}
+
+	address.Exchange = b.GetName()
+	address.Currency = common.StringToUpper(currency)
+	address.Address = response.Address
+	return address, nil
+}
+
+//WithdrawCryptocurrency : Withdraws a currency from the exchange wallet
+func (b *Bitfinex) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	method, ok := b.getFundingMethod(request.Currency)
+	if !ok {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	response, err := b.Withdrawal(method, "exchange", request.Address, request.Amount)
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, err)
+	}
+
+	if len(response) == 0 {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, errors.New(exchange.ErrWithdrawRejected))
+	}
+
+	if response[0].Status != "success" {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, exchange.NewExchangeError(b.GetName(), errors.New(response[0].Message), "", bitfinexErrorRules...))
+	}
+
+	result.Exchange = b.GetName()
+	result.WithdrawalID = strconv.FormatInt(response[0].WithdrawalID, 10)
+	result.Currency = common.StringToUpper(request.Currency)
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//getFundingMethod maps a currency onto the method name used by the Bitfinex
+//deposit and withdrawal endpoints
+func (b *Bitfinex) getFundingMethod(currency string) (string, bool) {
+	methods := map[string]string{
+		"BTC": "bitcoin",
+		"LTC": "litecoin",
+		"ETH": "ethereum",
+		"ETC": "ethereumc",
+		"ZEC": "zcash",
+	}
+	method, ok := methods[common.StringToUpper(currency)]
+	return method, ok
+}
+
+//UpdatePairInfo : Populates the pair metadata table from the Bitfinex symbol
+//details. Bitfinex prices are limited to a number of significant digits
+func (b *Bitfinex) UpdatePairInfo() error {
+	symbols, err := b.GetSymbolsDetails()
+	if err != nil {
+		return err
+	}
+
+	var info []exchange.PairInfo
+	for _, x := range symbols {
+		if len(x.Pair) != 6 {
+			continue
+		}
+		info = append(info, exchange.PairInfo{
+			Pair:                   pair.NewCurrencyPair(common.StringToUpper(x.Pair[0:3]), common.StringToUpper(x.Pair[3:])),
+			MinAmount:              x.MinimumOrderSize,
+			MaxAmount:              x.MaximumOrderSize,
+			AmountStep:             exchange.GetPrecisionStep(8),
+			PriceSignificantDigits: x.PricePrecision,
+			Status:                 exchange.PairStatusTrading,
+		})
+	}
+	b.SetPairInfo(info)
+	return nil
+}
+
+//GetHistoricCandles : Not supported, the Bitfinex v1 API has no candle endpoint
+func (b *Bitfinex) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair
+func (b *Bitfinex) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := b.GetTrades(p.Pair().Lower().String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.Tid, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Timestamp: time.Unix(x.Timestamp, 0),
+		}
+		if x.Type == "sell" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		trade.Price, _ = strconv.ParseFloat(x.Price, 64)
+		trade.Amount, _ = strconv.ParseFloat(x.Amount, 64)
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(b.GetName(), p, result)
+	return result, nil
+}
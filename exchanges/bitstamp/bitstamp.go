@@ -8,7 +8,6 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
@@ -44,6 +43,15 @@ const (
 	BITSTAMP_API_XRP_DESPOIT         = "xrp_address"
 )
 
+const (
+	BITSTAMP_AUTH_RATE_LIMIT   = 30
+	BITSTAMP_UNAUTH_RATE_LIMIT = 30
+)
+
+var bitstampErrorRules = []exchange.ErrorRule{
+	{Match: "check your account balance", Kind: exchange.ErrorKindInsufficientFunds},
+}
+
 type Bitstamp struct {
 	exchange.ExchangeBase
 	Balance BitstampBalances
@@ -55,6 +63,7 @@ func (b *Bitstamp) SetDefaults() {
 	b.Verbose = false
 	b.Websocket = false
 	b.RESTPollingDelay = 10
+	b.SetRateLimit(BITSTAMP_AUTH_RATE_LIMIT, BITSTAMP_UNAUTH_RATE_LIMIT)
 }
 
 func (b *Bitstamp) Setup(exch config.ExchangeConfig) {
@@ -65,6 +74,11 @@ func (b *Bitstamp) Setup(exch config.ExchangeConfig) {
 		b.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		b.SetAPIKeys(exch.APIKey, exch.APISecret, exch.ClientID, false)
 		b.RESTPollingDelay = exch.RESTPollingDelay
+		b.UpdateRateLimit(exch)
+		err := b.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", b.GetName(), err)
+		}
 		b.Verbose = exch.Verbose
 		b.Websocket = exch.Websocket
 		b.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -96,10 +110,10 @@ func (b *Bitstamp) GetTicker(currency string, hourly bool) (BitstampTicker, erro
 		tickerEndpoint = BITSTAMP_API_TICKER_HOURLY
 	}
 
-	path := fmt.Sprintf("%s/v%s/%s/%s/", BITSTAMP_API_URL, BITSTAMP_API_VERSION, tickerEndpoint, common.StringToLower(currency))
+	path := fmt.Sprintf("%s/v%s/%s/%s/", b.GetAPIUrl(BITSTAMP_API_URL), BITSTAMP_API_VERSION, tickerEndpoint, common.StringToLower(currency))
 	ticker := BitstampTicker{}
 
-	err := common.SendHTTPGetRequest(path, true, &ticker)
+	err := b.SendHTTPGetRequest(path, true, &ticker)
 
 	if err != nil {
 		return ticker, err
@@ -116,8 +130,8 @@ func (b *Bitstamp) GetOrderbook(currency string) (BitstampOrderbook, error) {
 	}
 
 	resp := response{}
-	path := fmt.Sprintf("%s/v%s/%s/%s/", BITSTAMP_API_URL, BITSTAMP_API_VERSION, BITSTAMP_API_ORDERBOOK, common.StringToLower(currency))
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	path := fmt.Sprintf("%s/v%s/%s/%s/", b.GetAPIUrl(BITSTAMP_API_URL), BITSTAMP_API_VERSION, BITSTAMP_API_ORDERBOOK, common.StringToLower(currency))
+	err := b.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return BitstampOrderbook{}, err
 	}
@@ -157,9 +171,9 @@ func (b *Bitstamp) GetOrderbook(currency string) (BitstampOrderbook, error) {
 }
 
 func (b *Bitstamp) GetTransactions(currency string, values url.Values) ([]BitstampTransactions, error) {
-	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s/", BITSTAMP_API_URL, BITSTAMP_API_VERSION, BITSTAMP_API_TRANSACTIONS, common.StringToLower(currency)), values)
+	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s/", b.GetAPIUrl(BITSTAMP_API_URL), BITSTAMP_API_VERSION, BITSTAMP_API_TRANSACTIONS, common.StringToLower(currency)), values)
 	transactions := []BitstampTransactions{}
-	err := common.SendHTTPGetRequest(path, true, &transactions)
+	err := b.SendHTTPGetRequest(path, true, &transactions)
 	if err != nil {
 		return nil, err
 	}
@@ -168,8 +182,8 @@ func (b *Bitstamp) GetTransactions(currency string, values url.Values) ([]Bitsta
 
 func (b *Bitstamp) GetEURUSDConversionRate() (BitstampEURUSDConversionRate, error) {
 	rate := BitstampEURUSDConversionRate{}
-	path := fmt.Sprintf("%s/%s", BITSTAMP_API_URL, BITSTAMP_API_EURUSD)
-	err := common.SendHTTPGetRequest(path, true, &rate)
+	path := fmt.Sprintf("%s/%s", b.GetAPIUrl(BITSTAMP_API_URL), BITSTAMP_API_EURUSD)
+	err := b.SendHTTPGetRequest(path, true, &rate)
 
 	if err != nil {
 		return rate, err
@@ -266,7 +280,7 @@ func (b *Bitstamp) GetOrderStatus(OrderID int64) (BitstampOrderStatus, error) {
 	req.Add("id", strconv.FormatInt(OrderID, 10))
 	resp := BitstampOrderStatus{}
 
-	err := b.SendAuthenticatedHTTPRequest(BITSTAMP_API_CANCEL_ORDER, false, req, &resp)
+	err := b.SendAuthenticatedHTTPRequest(BITSTAMP_API_ORDER_STATUS, false, req, &resp)
 
 	if err != nil {
 		return resp, err
@@ -275,7 +289,7 @@ func (b *Bitstamp) GetOrderStatus(OrderID int64) (BitstampOrderStatus, error) {
 	return resp, nil
 }
 
-func (b *Bitstamp) CancelOrder(OrderID int64) (bool, error) {
+func (b *Bitstamp) CancelExistingOrder(OrderID int64) (bool, error) {
 	var req = url.Values{}
 	result := false
 	req.Add("id", strconv.FormatInt(OrderID, 10))
@@ -468,7 +482,8 @@ func (b *Bitstamp) GetXRPDepositAddress() (BitstampXRPDepositResponse, error) {
 }
 
 func (b *Bitstamp) SendAuthenticatedHTTPRequest(path string, v2 bool, values url.Values, result interface{}) (err error) {
-	nonce := strconv.FormatInt(time.Now().UnixNano(), 10)
+	b.WaitRateLimit(true)
+	nonce := b.GetNonce().GetString()
 
 	if values == nil {
 		values = url.Values{}
@@ -480,9 +495,9 @@ func (b *Bitstamp) SendAuthenticatedHTTPRequest(path string, v2 bool, values url
 	values.Set("signature", common.StringToUpper(common.HexEncodeToString(hmac)))
 
 	if v2 {
-		path = fmt.Sprintf("%s/v%s/%s/", BITSTAMP_API_URL, BITSTAMP_API_VERSION, path)
+		path = fmt.Sprintf("%s/v%s/%s/", b.GetAPIUrl(BITSTAMP_API_URL), BITSTAMP_API_VERSION, path)
 	} else {
-		path = fmt.Sprintf("%s/%s/", BITSTAMP_API_URL, path)
+		path = fmt.Sprintf("%s/%s/", b.GetAPIUrl(BITSTAMP_API_URL), path)
 	}
 
 	if b.Verbose {
@@ -492,19 +507,30 @@ func (b *Bitstamp) SendAuthenticatedHTTPRequest(path string, v2 bool, values url
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	resp, err := common.SendHTTPRequest("POST", path, headers, strings.NewReader(values.Encode()))
+	resp, err := b.SendHTTPRequest("POST", path, headers, strings.NewReader(values.Encode()))
 	if err != nil {
-		return err
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, err, resp, bitstampErrorRules...))
 	}
 
 	if b.Verbose {
 		log.Printf("Recieved raw: %s\n", resp)
 	}
 
+	errResponse := BitstampErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil {
+		if errResponse.Status == "error" {
+			return b.CheckNonceError(exchange.NewExchangeError(b.Name, fmt.Errorf("%v", errResponse.Reason), resp, bitstampErrorRules...))
+		}
+
+		if errResponse.Error != nil {
+			return b.CheckNonceError(exchange.NewExchangeError(b.Name, fmt.Errorf("%v", errResponse.Error), resp, bitstampErrorRules...))
+		}
+	}
+
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New("Unable to JSON Unmarshal response."), resp))
 	}
 
 	return nil
@@ -1,5 +1,11 @@
 package bitstamp
 
+import (
+	"strconv"
+
+	"github.com/champii/gocryptotrader/common"
+)
+
 type BitstampTicker struct {
 	Last      float64 `json:"last,string"`
 	High      float64 `json:"high,string"`
@@ -70,22 +76,65 @@ type BitstampUserTransactions struct {
 }
 
 type BitstampOrder struct {
-	ID     int64   `json:"id"`
-	Date   string  `json:"datetime"`
-	Type   int     `json:"type"`
-	Price  float64 `json:"price"`
-	Amount float64 `json:"amount"`
+	ID           int64   `json:"id"`
+	Date         string  `json:"datetime"`
+	Type         int     `json:"type"`
+	Price        float64 `json:"price"`
+	Amount       float64 `json:"amount"`
+	CurrencyPair string  `json:"currency_pair"`
 }
 
 type BitstampOrderStatus struct {
 	Status       string
-	Transactions []struct {
-		TradeID int64   `json:"tid"`
-		USD     float64 `json:"usd,string"`
-		Price   float64 `json:"price,string"`
-		Fee     float64 `json:"fee,string"`
-		BTC     float64 `json:"btc,string"`
+	Transactions []BitstampOrderTransaction
+}
+
+//BitstampOrderTransaction : Fill of an order. Amounts holds the traded amount
+//of each currency of the pair keyed by its lower case name, as Bitstamp names
+//the amount fields after the currencies
+type BitstampOrderTransaction struct {
+	TradeID int64
+	Price   float64
+	Fee     float64
+	Amounts map[string]float64
+}
+
+//UnmarshalJSON decodes the transaction, whose numbers may be sent as strings
+func (t *BitstampOrderTransaction) UnmarshalJSON(data []byte) error {
+	fields := make(map[string]interface{})
+	err := common.JSONDecode(data, &fields)
+	if err != nil {
+		return err
 	}
+
+	t.Amounts = make(map[string]float64)
+	for k, v := range fields {
+		var value float64
+		switch x := v.(type) {
+		case float64:
+			value = x
+		case string:
+			value, err = strconv.ParseFloat(x, 64)
+			if err != nil {
+				continue
+			}
+		default:
+			continue
+		}
+
+		switch k {
+		case "tid":
+			t.TradeID = int64(value)
+		case "price":
+			t.Price = value
+		case "fee":
+			t.Fee = value
+		case "type":
+		default:
+			t.Amounts[common.StringToLower(k)] = value
+		}
+	}
+	return nil
 }
 
 type BitstampWithdrawalRequests struct {
@@ -107,3 +156,9 @@ type BitstampXRPDepositResponse struct {
 	Address        string `json:"address"`
 	DestinationTag int64  `json:"destination_tag"`
 }
+
+type BitstampErrorResponse struct {
+	Status string      `json:"status"`
+	Reason interface{} `json:"reason"`
+	Error  interface{} `json:"error"`
+}
@@ -2,71 +2,327 @@ package bitstamp
 
 import (
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 	"github.com/toorop/go-pusher"
 )
 
 type BitstampPusherOrderbook struct {
-	Asks [][]string `json:"asks"`
-	Bids [][]string `json:"bids"`
+	Timestamp int64      `json:"timestamp,string"`
+	Asks      [][]string `json:"asks"`
+	Bids      [][]string `json:"bids"`
 }
 type BitstampPusherTrade struct {
-	Price  float64 `json:"price"`
-	Amount float64 `json:"amount"`
-	ID     int64   `json:"id"`
+	Price       float64 `json:"price"`
+	Amount      float64 `json:"amount"`
+	ID          int64   `json:"id"`
+	Type        int     `json:"type"`
+	Timestamp   int64   `json:"timestamp,string"`
+	BuyOrderID  int64   `json:"buy_order_id"`
+	SellOrderID int64   `json:"sell_order_id"`
 }
 
 const (
-	BITSTAMP_PUSHER_KEY = "de504dc5763aeef9ff52"
+	BITSTAMP_PUSHER_KEY             = "de504dc5763aeef9ff52"
+	BITSTAMP_PUSHER_LIVE_TRADES     = "live_trades"
+	BITSTAMP_PUSHER_ORDER_BOOK      = "order_book"
+	BITSTAMP_PUSHER_DIFF_ORDER_BOOK = "diff_order_book"
+	BITSTAMP_PUSHER_DEFAULT_PAIR    = "BTCUSD"
+	BITSTAMP_PUSHER_TRADE_SELL      = 1
 )
 
+//GetPusherChannel returns the name of the channel for the pair. The channels
+//of the default pair carry no pair suffix
+func GetPusherChannel(channel string, p pair.CurrencyPair) string {
+	if p.Pair().Upper().String() == BITSTAMP_PUSHER_DEFAULT_PAIR {
+		return channel
+	}
+	return channel + "_" + p.Pair().Lower().String()
+}
+
+//GetPusherChannelPair splits a channel name into the channel and its pair
+func GetPusherChannelPair(name string) (string, pair.CurrencyPair, bool) {
+	for _, x := range []string{BITSTAMP_PUSHER_LIVE_TRADES, BITSTAMP_PUSHER_ORDER_BOOK, BITSTAMP_PUSHER_DIFF_ORDER_BOOK} {
+		if name == x {
+			return x, pair.NewCurrencyPair(BITSTAMP_PUSHER_DEFAULT_PAIR[0:3], BITSTAMP_PUSHER_DEFAULT_PAIR[3:]), true
+		}
+
+		if strings.HasPrefix(name, x+"_") && len(name) == len(x)+7 {
+			suffix := common.StringToUpper(name[len(x)+1:])
+			return x, pair.NewCurrencyPair(suffix[0:3], suffix[3:]), true
+		}
+	}
+	return "", pair.CurrencyPair{}, false
+}
+
+//PusherGetDepth returns the orderbook of the pair maintained from the
+//diff_order_book channel, which starts from the REST orderbook. Both carry a
+//timestamp, which sequences the depth
+func (b *Bitstamp) PusherGetDepth(p pair.CurrencyPair) *orderbook.Depth {
+	depth := orderbook.GetDepth(b.GetName(), p, func() (orderbook.OrderbookBase, error) {
+		return b.getOrderbook(p)
+	})
+	depth.SetTimestamped(true)
+	return depth
+}
+
+//PusherProcessDepth applies a diff_order_book message to the orderbook of the
+//pair. A zero amount removes the price level, and diffs at or before the
+//timestamp of the REST orderbook are dropped
+func (b *Bitstamp) PusherProcessDepth(p pair.CurrencyPair, diff BitstampPusherOrderbook) error {
+	book, err := getPusherOrderbook(diff)
+	if err != nil {
+		return err
+	}
+
+	updates := []orderbook.DepthUpdate{}
+	for i, levels := range [][]orderbook.OrderbookItem{book.Bids, book.Asks} {
+		for _, x := range levels {
+			update := orderbook.DepthUpdate{Bid: i == 0, Action: orderbook.DEPTH_ACTION_UPDATE, Price: x.Price, Amount: x.Amount}
+			if x.Amount == 0 {
+				update.Action = orderbook.DEPTH_ACTION_DELETE
+			}
+			updates = append(updates, update)
+		}
+	}
+	return b.PusherGetDepth(p).Apply(diff.Timestamp, updates)
+}
+
+//PusherProcessOrderbook stores an order_book snapshot until the depth of the
+//pair is synced from diff_order_book, as the snapshot only holds the top
+//levels. The best bid and ask of every snapshot update the ticker
+func (b *Bitstamp) PusherProcessOrderbook(p pair.CurrencyPair, snapshot BitstampPusherOrderbook) error {
+	book, err := getPusherOrderbook(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if !b.PusherGetDepth(p).IsSynced() {
+		book.Pair = p
+		orderbook.ProcessOrderbook(b.GetName(), p, book)
+	}
+
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil
+	}
+
+	price := b.getPusherTicker(p)
+	price.Bid = book.Bids[0].Price
+	price.Ask = book.Asks[0].Price
+	ticker.ProcessTicker(b.GetName(), p, price)
+	return nil
+}
+
+//PusherProcessTrade stores a live_trades print in the trade store and updates
+//the last, high and low prices of the ticker
+func (b *Bitstamp) PusherProcessTrade(p pair.CurrencyPair, result BitstampPusherTrade) {
+	trade := trades.Trade{
+		Pair:      p,
+		TradeID:   strconv.FormatInt(result.ID, 10),
+		Side:      trades.TRADE_SIDE_BUY,
+		Price:     result.Price,
+		Amount:    result.Amount,
+		Timestamp: time.Unix(result.Timestamp, 0),
+	}
+	if result.Type == BITSTAMP_PUSHER_TRADE_SELL {
+		trade.Side = trades.TRADE_SIDE_SELL
+	}
+	if len(trades.ProcessTrades(b.GetName(), p, []trades.Trade{trade})) == 0 {
+		return
+	}
+
+	price := b.getPusherTicker(p)
+	price.Last = result.Price
+	if price.High < result.Price {
+		price.High = result.Price
+	}
+	if price.Low == 0 || price.Low > result.Price {
+		price.Low = result.Price
+	}
+	ticker.ProcessTicker(b.GetName(), p, price)
+}
+
+//PusherHandleEvent routes an event of the bound Pusher events to the handler of
+//its channel
+func (b *Bitstamp) PusherHandleEvent(event *pusher.Event) {
+	exchange.RecordWebsocketMessage(b.GetName())
+	channel, p, ok := GetPusherChannelPair(event.Channel)
+	if !ok {
+		return
+	}
+
+	switch channel {
+	case BITSTAMP_PUSHER_ORDER_BOOK, BITSTAMP_PUSHER_DIFF_ORDER_BOOK:
+		result := BitstampPusherOrderbook{}
+		err := common.JSONDecode([]byte(event.Data), &result)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if channel == BITSTAMP_PUSHER_ORDER_BOOK {
+			err = b.PusherProcessOrderbook(p, result)
+		} else {
+			err = b.PusherProcessDepth(p, result)
+		}
+		if err != nil {
+			log.Println(err)
+		}
+	case BITSTAMP_PUSHER_LIVE_TRADES:
+		result := BitstampPusherTrade{}
+		err := common.JSONDecode([]byte(event.Data), &result)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		b.PusherProcessTrade(p, result)
+	}
+}
+
+//getPusherTicker returns the stored ticker of the pair to update, so the
+//fields a message does not carry keep their REST values
+func (b *Bitstamp) getPusherTicker(p pair.CurrencyPair) ticker.TickerPrice {
+	price, err := ticker.GetTicker(b.GetName(), p)
+	if err != nil {
+		return ticker.TickerPrice{Pair: p}
+	}
+	price.LastUpdated = time.Time{}
+	return price
+}
+
+func getPusherOrderbook(book BitstampPusherOrderbook) (orderbook.OrderbookBase, error) {
+	result := orderbook.OrderbookBase{}
+	for i, levels := range [][][]string{book.Bids, book.Asks} {
+		for _, x := range levels {
+			if len(x) < 2 {
+				continue
+			}
+
+			price, err := strconv.ParseFloat(x[0], 64)
+			if err != nil {
+				return result, err
+			}
+			amount, err := strconv.ParseFloat(x[1], 64)
+			if err != nil {
+				return result, err
+			}
+
+			item := orderbook.OrderbookItem{Price: price, Amount: amount}
+			if i == 0 {
+				result.Bids = append(result.Bids, item)
+			} else {
+				result.Asks = append(result.Asks, item)
+			}
+		}
+	}
+	return result, nil
+}
+
+//pusherRecord writes the event to the stream recording of the exchange
+func (b *Bitstamp) pusherRecord(event *pusher.Event) {
+	recorder := exchange.GetStreamRecorder(b.GetName())
+	if recorder == nil {
+		return
+	}
+
+	data, err := common.JSONEncode(event)
+	if err == nil {
+		recorder.RecordWebsocketEvent(event.Event, data)
+	}
+}
+
+//pusherReplay passes the recorded events to PusherHandleEvent
+func (b *Bitstamp) pusherReplay(replayer *exchange.StreamReplayer) {
+	replayer.Replay(b.GetWebsocketContext(), func(entry exchange.StreamEntry) {
+		event := pusher.Event{}
+		err := common.JSONDecode(entry.Data, &event)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		b.PusherHandleEvent(&event)
+	})
+}
+
+//PusherClient keeps the Pusher connection up. Diffs are missed while it is
+//down, so the synced depths are rebuilt from the REST orderbook on reconnect
 func (b *Bitstamp) PusherClient() {
+	if replayer := exchange.GetStreamReplayer(b.GetName()); replayer != nil {
+		b.pusherReplay(replayer)
+		return
+	}
+
+	ctx := b.GetWebsocketContext()
+	backoff := exchange.WebsocketBackoff{}
 	for b.Enabled && b.Websocket {
 		pusherClient, err := pusher.NewClient(BITSTAMP_PUSHER_KEY)
 		if err != nil {
 			log.Printf("%s Unable to connect to Websocket. Error: %s\n", b.GetName(), err)
+			if !backoff.Wait(ctx) {
+				return
+			}
 			continue
 		}
 
-		err = pusherClient.Subscribe("live_trades")
-		if err != nil {
-			log.Printf("%s Websocket Trade subscription error: %s\n", b.GetName(), err)
-		}
+		for _, x := range b.EnabledPairs {
+			p := pair.NewCurrencyPair(x[0:3], x[3:])
+			for _, y := range []string{BITSTAMP_PUSHER_LIVE_TRADES, BITSTAMP_PUSHER_ORDER_BOOK, BITSTAMP_PUSHER_DIFF_ORDER_BOOK} {
+				channel := GetPusherChannel(y, p)
+				err = pusherClient.Subscribe(channel)
+				if err != nil {
+					log.Printf("%s Websocket %s subscription error: %s\n", b.GetName(), channel, err)
+				}
+			}
 
-		err = pusherClient.Subscribe("order_book")
-		if err != nil {
-			log.Printf("%s Websocket Trade subscription error: %s\n", b.GetName(), err)
+			depth := b.PusherGetDepth(p)
+			if depth.IsSynced() {
+				depth.Desync()
+			}
 		}
 
 		dataChannelTrade, err := pusherClient.Bind("data")
 		if err != nil {
 			log.Printf("%s Websocket Bind error: %s\n", b.GetName(), err)
+			pusherClient.Close()
+			if !backoff.Wait(ctx) {
+				return
+			}
 			continue
 		}
 		tradeChannelTrade, err := pusherClient.Bind("trade")
 		if err != nil {
 			log.Printf("%s Websocket Bind error: %s\n", b.GetName(), err)
+			pusherClient.Close()
+			if !backoff.Wait(ctx) {
+				return
+			}
 			continue
 		}
+		backoff.Reset()
+		exchange.RecordWebsocketState(b.GetName(), exchange.WEBSOCKET_STATE_CONNECTED)
 
 		log.Printf("%s Pusher client connected.\n", b.GetName())
 
 		for b.Websocket {
 			select {
+			case <-ctx.Done():
+				pusherClient.Close()
+				exchange.RecordWebsocketState(b.GetName(), exchange.WEBSOCKET_STATE_STOPPED)
+				return
 			case data := <-dataChannelTrade:
-				result := BitstampPusherOrderbook{}
-				err := common.JSONDecode([]byte(data.Data), &result)
-				if err != nil {
-					log.Println(err)
-				}
+				b.pusherRecord(data)
+				b.PusherHandleEvent(data)
 			case trade := <-tradeChannelTrade:
-				result := BitstampPusherTrade{}
-				err := common.JSONDecode([]byte(trade.Data), &result)
-				if err != nil {
-					log.Println(err)
-				}
-				log.Printf("%s Pusher trade: Price: %f Amount: %f\n", b.GetName(), result.Price, result.Amount)
+				b.pusherRecord(trade)
+				b.PusherHandleEvent(trade)
 			}
 		}
 	}
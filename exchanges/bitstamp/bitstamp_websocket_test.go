@@ -0,0 +1,101 @@
+package bitstamp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
+	"github.com/toorop/go-pusher"
+)
+
+func TestGetPusherChannel(t *testing.T) {
+	if channel := GetPusherChannel(BITSTAMP_PUSHER_ORDER_BOOK, pair.NewCurrencyPair("BTC", "USD")); channel != "order_book" {
+		t.Errorf("Test Failed - GetPusherChannel() expected order_book, received %s", channel)
+	}
+
+	channel := GetPusherChannel(BITSTAMP_PUSHER_DIFF_ORDER_BOOK, pair.NewCurrencyPair("XRP", "EUR"))
+	if channel != "diff_order_book_xrpeur" {
+		t.Errorf("Test Failed - GetPusherChannel() expected diff_order_book_xrpeur, received %s", channel)
+	}
+
+	name, p, ok := GetPusherChannelPair(channel)
+	if !ok || name != BITSTAMP_PUSHER_DIFF_ORDER_BOOK || p.Pair().String() != "XRPEUR" {
+		t.Errorf("Test Failed - GetPusherChannelPair() incorrect: %s %s", name, p.Pair())
+	}
+
+	name, p, ok = GetPusherChannelPair("live_trades")
+	if !ok || name != BITSTAMP_PUSHER_LIVE_TRADES || p.Pair().String() != "BTCUSD" {
+		t.Errorf("Test Failed - GetPusherChannelPair() incorrect: %s %s", name, p.Pair())
+	}
+
+	if _, _, ok = GetPusherChannelPair("order_book_btc"); ok {
+		t.Error("Test Failed - GetPusherChannelPair() accepted an invalid channel")
+	}
+}
+
+func TestPusherHandleEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"timestamp":"1500000000","bids":[["100.00","1.0"],["99.00","2.0"],["98.00","3.0"]],"asks":[["101.00","1.0"],["102.00","2.0"]]}`))
+	}))
+	defer server.Close()
+
+	b := Bitstamp{}
+	b.SetDefaults()
+	b.APIUrl = server.URL
+	p := pair.NewCurrencyPair("BTC", "EUR")
+
+	b.PusherHandleEvent(&pusher.Event{Event: "data", Channel: "order_book_btceur", Data: `{"bids":[["100.00","1.0"]],"asks":[["101.00","1.0"]]}`})
+	book, err := orderbook.GetOrderbook(b.GetName(), p)
+	if err != nil || len(book.Bids) != 1 || len(book.Asks) != 1 {
+		t.Fatalf("Test Failed - order_book snapshot not stored: %+v %v", book, err)
+	}
+
+	price, err := ticker.GetTicker(b.GetName(), p)
+	if err != nil || price.Bid != 100 || price.Ask != 101 {
+		t.Errorf("Test Failed - order_book snapshot ticker incorrect: %+v %v", price, err)
+	}
+
+	b.PusherHandleEvent(&pusher.Event{Event: "data", Channel: "diff_order_book_btceur", Data: `{"timestamp":"1500000000","bids":[["98.00","0"]],"asks":[]}`})
+	b.PusherHandleEvent(&pusher.Event{Event: "data", Channel: "diff_order_book_btceur", Data: `{"timestamp":"1500000001","bids":[["99.00","0"]],"asks":[["101.50","4.0"]]}`})
+	depth := b.PusherGetDepth(p)
+	for i := 0; i < 100 && !depth.IsSynced(); i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	book, err = depth.GetOrderbook()
+	if err != nil {
+		t.Fatalf("Test Failed - diff_order_book depth not synced: %s", err)
+	}
+	if len(book.Bids) != 2 || book.Bids[1].Price != 98 || len(book.Asks) != 3 || book.Asks[1].Price != 101.5 {
+		t.Errorf("Test Failed - diff_order_book depth incorrect: %+v", book)
+	}
+
+	b.PusherHandleEvent(&pusher.Event{Event: "data", Channel: "diff_order_book_btceur", Data: `{"timestamp":"1499999999","bids":[["100.00","0"]],"asks":[]}`})
+	b.PusherHandleEvent(&pusher.Event{Event: "data", Channel: "diff_order_book_btceur", Data: `{"timestamp":"1500000001","bids":[["97.00","1.0"]],"asks":[]}`})
+	book, _ = depth.GetOrderbook()
+	if len(book.Bids) != 3 || book.Bids[0].Price != 100 || book.Bids[2].Price != 97 {
+		t.Errorf("Test Failed - diff_order_book applied a stale diff or dropped a current one: %+v", book)
+	}
+
+	b.PusherHandleEvent(&pusher.Event{Event: "data", Channel: "order_book_btceur", Data: `{"bids":[["100.50","1.0"]],"asks":[["101.00","1.0"]]}`})
+	stored, _ := orderbook.GetOrderbook(b.GetName(), p)
+	if len(stored.Bids) != 3 {
+		t.Errorf("Test Failed - order_book snapshot replaced the synced depth: %+v", stored)
+	}
+
+	b.PusherHandleEvent(&pusher.Event{Event: "trade", Channel: "live_trades_btceur", Data: `{"amount":0.5,"price":102.5,"id":7,"type":1,"timestamp":"1500000002"}`})
+	result, err := trades.GetTrades(b.GetName(), p)
+	if err != nil || len(result) != 1 || result[0].Side != trades.TRADE_SIDE_SELL || result[0].Price != 102.5 {
+		t.Errorf("Test Failed - live_trades trade incorrect: %+v %v", result, err)
+	}
+
+	price, _ = ticker.GetTicker(b.GetName(), p)
+	if price.Last != 102.5 || price.High != 102.5 || price.Low != 102.5 || price.Bid != 100.5 {
+		t.Errorf("Test Failed - live_trades ticker incorrect: %+v", price)
+	}
+}
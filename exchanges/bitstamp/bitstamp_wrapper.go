@@ -1,7 +1,11 @@
 package bitstamp
 
 import (
+	"errors"
 	"log"
+	"math"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -10,6 +14,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (b *Bitstamp) Start() {
@@ -30,7 +35,7 @@ func (b *Bitstamp) Run() {
 	for b.Enabled {
 		for _, x := range b.EnabledPairs {
 			currency := pair.NewCurrencyPair(x[0:3], x[3:])
-			go func() {
+			b.Poll(x, func() {
 				ticker, err := b.GetTickerPrice(currency)
 				if err != nil {
 					log.Println(err)
@@ -38,7 +43,7 @@ func (b *Bitstamp) Run() {
 				}
 				log.Printf("Bitstamp %s: Last %f High %f Low %f Volume %f\n", currency.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(b.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * b.RESTPollingDelay)
 	}
@@ -73,6 +78,16 @@ func (b *Bitstamp) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase,
 		return ob, nil
 	}
 
+	orderBook, err := b.getOrderbook(p)
+	if err != nil {
+		return orderBook, err
+	}
+	orderbook.ProcessOrderbook(b.GetName(), p, orderBook)
+	return orderBook, nil
+}
+
+//getOrderbook fetches the REST orderbook of the pair without storing it
+func (b *Bitstamp) getOrderbook(p pair.CurrencyPair) (orderbook.OrderbookBase, error) {
 	var orderBook orderbook.OrderbookBase
 	orderbookNew, err := b.GetOrderbook(p.Pair().String())
 	if err != nil {
@@ -90,7 +105,7 @@ func (b *Bitstamp) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase,
 	}
 
 	orderBook.Pair = p
-	orderbook.ProcessOrderbook(b.GetName(), p, orderBook)
+	orderBook.Sequence = orderbookNew.Timestamp
 	return orderBook, nil
 }
 
@@ -128,3 +143,243 @@ func (e *Bitstamp) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error
 	})
 	return response, nil
 }
+
+//SubmitOrder : Places a new order on Bitstamp
+func (b *Bitstamp) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := b.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	response, err := b.PlaceOrder(order.Pair.Pair().String(), order.Price, order.Amount, order.IsBuy(), order.IsMarket())
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = b.GetName()
+	result.OrderID = strconv.FormatInt(response.ID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (b *Bitstamp) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.CancelExistingOrder(id)
+	return err
+}
+
+//ModifyOrder : Bitstamp does not support amending orders
+func (b *Bitstamp) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order. The price and amount
+//of a resting order come from the open orders, those of a finished order from
+//its fills
+func (b *Bitstamp) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return detail, err
+	}
+
+	response, err := b.GetOrderStatus(id)
+	if err != nil {
+		return detail, err
+	}
+
+	detail.Exchange = b.GetName()
+	detail.OrderID = orderID
+	detail.Pair = p
+	detail.Type = exchange.OrderTypeLimit
+
+	base := p.GetFirstCurrency().Lower().String()
+	quote := p.GetSecondCurrency().Lower().String()
+	var cost float64
+	for _, x := range response.Transactions {
+		detail.FilledAmount += math.Abs(x.Amounts[base])
+		cost += math.Abs(x.Amounts[quote])
+	}
+	if detail.FilledAmount > 0 {
+		detail.AveragePrice = cost / detail.FilledAmount
+	}
+
+	switch response.Status {
+	case "In Queue", "Open":
+		orders, err := b.GetOpenOrders(p.Pair().Lower().String())
+		if err != nil {
+			return detail, err
+		}
+
+		for _, x := range orders {
+			if x.ID != id {
+				continue
+			}
+			detail.Side = exchange.OrderSideBuy
+			if x.Type == 1 {
+				detail.Side = exchange.OrderSideSell
+			}
+			detail.Price = x.Price
+			detail.Amount = x.Amount + detail.FilledAmount
+			detail.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", x.Date)
+		}
+		detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, true, false)
+	case "Finished":
+		detail.Price = detail.AveragePrice
+		detail.Amount = detail.FilledAmount
+		detail.Status = exchange.OrderStatusFilled
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail, nil
+}
+
+//GetActiveOrders : Retrieves all resting orders across every pair matching the filter
+func (b *Bitstamp) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := b.GetOpenOrders("all")
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range response {
+		var detail exchange.OrderDetail
+		detail.Exchange = b.GetName()
+		detail.OrderID = strconv.FormatInt(x.ID, 10)
+		if common.StringContains(x.CurrencyPair, "/") {
+			detail.Pair = pair.NewCurrencyPairDelimiter(x.CurrencyPair, "/")
+		}
+		detail.Side = exchange.OrderSideBuy
+		if x.Type == 1 {
+			detail.Side = exchange.OrderSideSell
+		}
+		detail.Type = exchange.OrderTypeLimit
+		detail.Status = exchange.OrderStatusActive
+		detail.Price = x.Price
+		detail.Amount = x.Amount
+		detail.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", x.Date)
+		orders = append(orders, detail)
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Not supported, Bitstamp only exposes fills through user transactions
+func (b *Bitstamp) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetFills : Not supported, Bitstamp user transactions do not identify the traded pair
+func (b *Bitstamp) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetDepositAddress : Retrieves the deposit address for BTC or XRP. XRP deposits
+//require the returned destination tag
+func (b *Bitstamp) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	var address exchange.DepositAddress
+	currency = common.StringToUpper(currency)
+
+	switch currency {
+	case "BTC":
+		response, err := b.GetBitcoinDepositAddress()
+		if err != nil {
+			return address, exchange.NewFundingError(b.GetName(), currency, err)
+		}
+		address.Address = response
+	case "XRP":
+		response, err := b.GetXRPDepositAddress()
+		if err != nil {
+			return address, exchange.NewFundingError(b.GetName(), currency, err)
+		}
+		address.Address = response.Address
+		address.Tag = strconv.FormatInt(response.DestinationTag, 10)
+	default:
+		return address, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	if address.Address == "" {
+		return address, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrDepositAddressNotFound))
+	}
+
+	address.Exchange = b.GetName()
+	address.Currency = currency
+	return address, nil
+}
+
+//WithdrawCryptocurrency : Withdraws BTC or XRP to an external address
+func (b *Bitstamp) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	currency := common.StringToUpper(request.Currency)
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), currency, err)
+	}
+
+	var id string
+	switch currency {
+	case "BTC":
+		if request.Tag != "" {
+			return result, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+		}
+		id, err = b.BitcoinWithdrawal(request.Amount, request.Address, false)
+	case "XRP":
+		id, err = b.XRPWithdrawal(request.Amount, request.Address, request.Tag)
+	default:
+		return result, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), currency, err)
+	}
+
+	result.Exchange = b.GetName()
+	result.WithdrawalID = id
+	result.Currency = currency
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//UpdatePairInfo : Not supported, the Bitstamp client has no trading pairs info endpoint
+func (b *Bitstamp) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, Bitstamp has no candle endpoint
+func (b *Bitstamp) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the public transactions of the last hour for a pair
+func (b *Bitstamp) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := b.GetTransactions(p.Pair().Lower().String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TradeID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(x.Date, 0),
+		}
+		if x.Type == 1 {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(b.GetName(), p, result)
+	return result, nil
+}
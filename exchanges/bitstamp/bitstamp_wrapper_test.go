@@ -0,0 +1,42 @@
+package bitstamp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+func TestGetOrderInfo(t *testing.T) {
+	status := "Open"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/order_status/":
+			w.Write([]byte(`{"status":"` + status + `","transactions":[{"tid":1,"price":"0.05","fee":"0.0001","eth":"2","btc":"0.1","type":2},{"tid":2,"price":"0.06","fee":"0.0001","eth":"1","btc":"0.06","type":2}]}`))
+		case "/v2/open_orders/ethbtc/":
+			w.Write([]byte(`[{"id":7,"datetime":"2017-01-01 00:00:00","type":1,"price":0.05,"amount":2,"currency_pair":"ETH/BTC"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	b := Bitstamp{}
+	b.SetDefaults()
+	b.APIUrl = server.URL
+	b.SetRateLimit(0, 0)
+	p := pair.NewCurrencyPair("ETH", "BTC")
+
+	detail, err := b.GetOrderInfo("7", p)
+	if err != nil || detail.FilledAmount != 3 || detail.Amount != 5 || detail.Price != 0.05 || detail.Side != exchange.OrderSideSell || detail.Status != exchange.OrderStatusPartiallyFilled {
+		t.Errorf("Test Failed - GetOrderInfo() open order incorrect: %+v %v", detail, err)
+	}
+
+	status = "Finished"
+	detail, err = b.GetOrderInfo("7", p)
+	if err != nil || detail.FilledAmount != 3 || detail.Amount != 3 || detail.Price < 0.0533 || detail.Price > 0.0534 || detail.Status != exchange.OrderStatusFilled {
+		t.Errorf("Test Failed - GetOrderInfo() finished order incorrect: %+v %v", detail, err)
+	}
+}
@@ -42,6 +42,17 @@ const (
 	BTCC_STOPORDERS               = "getStopOrders"
 )
 
+const (
+	BTCC_AUTH_RATE_LIMIT   = 60
+	BTCC_UNAUTH_RATE_LIMIT = 60
+	BTCC_ORDERS_LIMIT      = 1000
+)
+
+const (
+	ErrBTCCOrderNotCancelled = "BTCC did not cancel order %d."
+	ErrBTCCWithdrawalAddress = "BTCC only withdraws to the registered address %s."
+)
+
 type BTCC struct {
 	exchange.ExchangeBase
 }
@@ -53,6 +64,8 @@ func (b *BTCC) SetDefaults() {
 	b.Verbose = false
 	b.Websocket = false
 	b.RESTPollingDelay = 10
+	b.SetRateLimit(BTCC_AUTH_RATE_LIMIT, BTCC_UNAUTH_RATE_LIMIT)
+	b.SetNonceResolution(time.Microsecond)
 }
 
 //Setup is run on startup to setup exchange with config values
@@ -64,6 +77,11 @@ func (b *BTCC) Setup(exch config.ExchangeConfig) {
 		b.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		b.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		b.RESTPollingDelay = exch.RESTPollingDelay
+		b.UpdateRateLimit(exch)
+		err := b.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", b.GetName(), err)
+		}
 		b.Verbose = exch.Verbose
 		b.Websocket = exch.Websocket
 		b.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -82,8 +100,8 @@ func (b *BTCC) GetTicker(symbol string) (BTCCTicker, error) {
 	}
 
 	resp := Response{}
-	req := fmt.Sprintf("%sdata/ticker?market=%s", BTCC_API_URL, symbol)
-	err := common.SendHTTPGetRequest(req, true, &resp)
+	req := fmt.Sprintf("%sdata/ticker?market=%s", b.GetAPIUrl(BTCC_API_URL), symbol)
+	err := b.SendHTTPGetRequest(req, true, &resp)
 	if err != nil {
 		return BTCCTicker{}, err
 	}
@@ -91,8 +109,8 @@ func (b *BTCC) GetTicker(symbol string) (BTCCTicker, error) {
 }
 
 func (b *BTCC) GetTradesLast24h(symbol string) bool {
-	req := fmt.Sprintf("%sdata/trades?market=%s", BTCC_API_URL, symbol)
-	err := common.SendHTTPGetRequest(req, true, nil)
+	req := fmt.Sprintf("%sdata/trades?market=%s", b.GetAPIUrl(BTCC_API_URL), symbol)
+	err := b.SendHTTPGetRequest(req, true, nil)
 	if err != nil {
 		log.Println(err)
 		return false
@@ -101,7 +119,7 @@ func (b *BTCC) GetTradesLast24h(symbol string) bool {
 }
 
 func (b *BTCC) GetTradeHistory(symbol string, limit, sinceTid int64, time time.Time) bool {
-	req := fmt.Sprintf("%sdata/historydata?market=%s", BTCC_API_URL, symbol)
+	req := fmt.Sprintf("%sdata/historydata?market=%s", b.GetAPIUrl(BTCC_API_URL), symbol)
 	v := url.Values{}
 
 	if limit > 0 {
@@ -115,7 +133,7 @@ func (b *BTCC) GetTradeHistory(symbol string, limit, sinceTid int64, time time.T
 	}
 
 	req = common.EncodeURLValues(req, v)
-	err := common.SendHTTPGetRequest(req, true, nil)
+	err := b.SendHTTPGetRequest(req, true, nil)
 	if err != nil {
 		log.Println(err)
 		return false
@@ -125,8 +143,8 @@ func (b *BTCC) GetTradeHistory(symbol string, limit, sinceTid int64, time time.T
 
 func (b *BTCC) GetOrderBook(symbol string, limit int) (BTCCOrderbook, error) {
 	result := BTCCOrderbook{}
-	req := fmt.Sprintf("%sdata/orderbook?market=%s&limit=%d", BTCC_API_URL, symbol, limit)
-	err := common.SendHTTPGetRequest(req, true, &result)
+	req := fmt.Sprintf("%sdata/orderbook?market=%s&limit=%d", b.GetAPIUrl(BTCC_API_URL), symbol, limit)
+	err := b.SendHTTPGetRequest(req, true, &result)
 	if err != nil {
 		return BTCCOrderbook{}, err
 	}
@@ -134,23 +152,27 @@ func (b *BTCC) GetOrderBook(symbol string, limit int) (BTCCOrderbook, error) {
 	return result, nil
 }
 
-func (b *BTCC) GetAccountInfo(infoType string) {
+func (b *BTCC) GetAccountInfo(infoType string) (BTCCAccountInfo, error) {
 	params := make([]interface{}, 0)
 
 	if len(infoType) > 0 {
 		params = append(params, infoType)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_ACCOUNT_INFO, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := BTCCAccountInfo{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_ACCOUNT_INFO, params, &result)
+	return result, err
 }
 
-func (b *BTCC) PlaceOrder(buyOrder bool, price, amount float64, market string) {
+//PlaceOrder places a limit order and returns its ID. A price of zero places a
+//market order
+func (b *BTCC) PlaceOrder(buyOrder bool, price, amount float64, market string) (int64, error) {
 	params := make([]interface{}, 0)
-	params = append(params, strconv.FormatFloat(price, 'f', -1, 64))
+	if price > 0 {
+		params = append(params, strconv.FormatFloat(price, 'f', -1, 64))
+	} else {
+		params = append(params, nil)
+	}
 	params = append(params, strconv.FormatFloat(amount, 'f', -1, 64))
 
 	if len(market) > 0 {
@@ -162,14 +184,13 @@ func (b *BTCC) PlaceOrder(buyOrder bool, price, amount float64, market string) {
 		req = BTCC_ORDER_SELL
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(req, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	var orderID int64
+	err := b.SendAuthenticatedHTTPRequest(req, params, &orderID)
+	return orderID, err
 }
 
-func (b *BTCC) CancelOrder(orderID int64, market string) {
+//CancelExistingOrder returns whether the order was cancelled
+func (b *BTCC) CancelExistingOrder(orderID int64, market string) (bool, error) {
 	params := make([]interface{}, 0)
 	params = append(params, orderID)
 
@@ -177,14 +198,12 @@ func (b *BTCC) CancelOrder(orderID int64, market string) {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_ORDER_CANCEL, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	var result bool
+	err := b.SendAuthenticatedHTTPRequest(BTCC_ORDER_CANCEL, params, &result)
+	return result, err
 }
 
-func (b *BTCC) GetDeposits(currency string, pending bool) {
+func (b *BTCC) GetDeposits(currency string, pending bool) ([]BTCCDeposit, error) {
 	params := make([]interface{}, 0)
 	params = append(params, currency)
 
@@ -192,14 +211,16 @@ func (b *BTCC) GetDeposits(currency string, pending bool) {
 		params = append(params, pending)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_DEPOSITS, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Deposits []BTCCDeposit `json:"deposit"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_DEPOSITS, params, &result)
+	return result.Deposits, err
 }
 
-func (b *BTCC) GetMarketDepth(market string, limit int64) {
+func (b *BTCC) GetMarketDepth(market string, limit int64) (BTCCDepth, error) {
 	params := make([]interface{}, 0)
 
 	if limit > 0 {
@@ -210,14 +231,16 @@ func (b *BTCC) GetMarketDepth(market string, limit int64) {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_MARKETDEPTH, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		MarketDepth BTCCDepth `json:"market_depth"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_MARKETDEPTH, params, &result)
+	return result.MarketDepth, err
 }
 
-func (b *BTCC) GetOrder(orderID int64, market string, detailed bool) {
+func (b *BTCC) GetOrder(orderID int64, market string, detailed bool) (BTCCOrder, error) {
 	params := make([]interface{}, 0)
 	params = append(params, orderID)
 
@@ -229,14 +252,17 @@ func (b *BTCC) GetOrder(orderID int64, market string, detailed bool) {
 		params = append(params, detailed)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_ORDER, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Order BTCCOrder `json:"order"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_ORDER, params, &result)
+	return result.Order, err
 }
 
-func (b *BTCC) GetOrders(openonly bool, market string, limit, offset, since int64, detailed bool) {
+//GetOrders returns the orders of a single market, newest first
+func (b *BTCC) GetOrders(openonly bool, market string, limit, offset, since int64, detailed bool) (BTCCOrders, error) {
 	params := make([]interface{}, 0)
 
 	if openonly {
@@ -263,14 +289,12 @@ func (b *BTCC) GetOrders(openonly bool, market string, limit, offset, since int6
 		params = append(params, detailed)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_ORDERS, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := BTCCOrders{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_ORDERS, params, &result)
+	return result, err
 }
 
-func (b *BTCC) GetTransactions(transType string, limit, offset, since int64, sinceType string) {
+func (b *BTCC) GetTransactions(transType string, limit, offset, since int64, sinceType string) ([]BTCCTransaction, error) {
 	params := make([]interface{}, 0)
 
 	if len(transType) > 0 {
@@ -293,14 +317,16 @@ func (b *BTCC) GetTransactions(transType string, limit, offset, since int64, sin
 		params = append(params, sinceType)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_TRANSACTIONS, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Transactions []BTCCTransaction `json:"transaction"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_TRANSACTIONS, params, &result)
+	return result.Transactions, err
 }
 
-func (b *BTCC) GetWithdrawal(withdrawalID int64, currency string) {
+func (b *BTCC) GetWithdrawal(withdrawalID int64, currency string) (BTCCWithdrawal, error) {
 	params := make([]interface{}, 0)
 	params = append(params, withdrawalID)
 
@@ -308,14 +334,16 @@ func (b *BTCC) GetWithdrawal(withdrawalID int64, currency string) {
 		params = append(params, currency)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_WITHDRAWAL, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Withdrawal BTCCWithdrawal `json:"withdrawal"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_WITHDRAWAL, params, &result)
+	return result.Withdrawal, err
 }
 
-func (b *BTCC) GetWithdrawals(currency string, pending bool) {
+func (b *BTCC) GetWithdrawals(currency string, pending bool) ([]BTCCWithdrawal, error) {
 	params := make([]interface{}, 0)
 	params = append(params, currency)
 
@@ -323,26 +351,29 @@ func (b *BTCC) GetWithdrawals(currency string, pending bool) {
 		params = append(params, pending)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_WITHDRAWALS, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Withdrawals []BTCCWithdrawal `json:"withdrawal"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_WITHDRAWALS, params, &result)
+	return result.Withdrawals, err
 }
 
-func (b *BTCC) RequestWithdrawal(currency string, amount float64) {
+//RequestWithdrawal withdraws to the address registered on the account and
+//returns the withdrawal ID
+func (b *BTCC) RequestWithdrawal(currency string, amount float64) (int64, error) {
 	params := make([]interface{}, 0)
 	params = append(params, currency)
 	params = append(params, amount)
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_WITHDRAWAL_REQUEST, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := BTCCWithdrawalRequest{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_WITHDRAWAL_REQUEST, params, &result)
+	return result.ID, err
 }
 
-func (b *BTCC) IcebergOrder(buyOrder bool, price, amount, discAmount, variance float64, market string) {
+//IcebergOrder places an iceberg order and returns its ID
+func (b *BTCC) IcebergOrder(buyOrder bool, price, amount, discAmount, variance float64, market string) (int64, error) {
 	params := make([]interface{}, 0)
 	params = append(params, strconv.FormatFloat(price, 'f', -1, 64))
 	params = append(params, strconv.FormatFloat(amount, 'f', -1, 64))
@@ -358,14 +389,12 @@ func (b *BTCC) IcebergOrder(buyOrder bool, price, amount, discAmount, variance f
 		req = BTCC_ICEBERG_SELL
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(req, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	var orderID int64
+	err := b.SendAuthenticatedHTTPRequest(req, params, &orderID)
+	return orderID, err
 }
 
-func (b *BTCC) GetIcebergOrder(orderID int64, market string) {
+func (b *BTCC) GetIcebergOrder(orderID int64, market string) (BTCCIcebergOrder, error) {
 	params := make([]interface{}, 0)
 	params = append(params, orderID)
 
@@ -373,14 +402,16 @@ func (b *BTCC) GetIcebergOrder(orderID int64, market string) {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_ICEBERG_ORDER, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Order BTCCIcebergOrder `json:"iceberg_order"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_ICEBERG_ORDER, params, &result)
+	return result.Order, err
 }
 
-func (b *BTCC) GetIcebergOrders(limit, offset int64, market string) {
+func (b *BTCC) GetIcebergOrders(limit, offset int64, market string) ([]BTCCIcebergOrder, error) {
 	params := make([]interface{}, 0)
 
 	if limit > 0 {
@@ -395,14 +426,16 @@ func (b *BTCC) GetIcebergOrders(limit, offset int64, market string) {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_ICEBERG_ORDERS, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Orders []BTCCIcebergOrder `json:"iceberg_orders"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_ICEBERG_ORDERS, params, &result)
+	return result.Orders, err
 }
 
-func (b *BTCC) CancelIcebergOrder(orderID int64, market string) {
+func (b *BTCC) CancelIcebergOrder(orderID int64, market string) (bool, error) {
 	params := make([]interface{}, 0)
 	params = append(params, orderID)
 
@@ -410,14 +443,13 @@ func (b *BTCC) CancelIcebergOrder(orderID int64, market string) {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_ICEBERG_CANCEL, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	var result bool
+	err := b.SendAuthenticatedHTTPRequest(BTCC_ICEBERG_CANCEL, params, &result)
+	return result, err
 }
 
-func (b *BTCC) PlaceStopOrder(buyOder bool, stopPrice, price, amount, trailingAmt, trailingPct float64, market string) {
+//PlaceStopOrder places a stop order and returns its ID
+func (b *BTCC) PlaceStopOrder(buyOder bool, stopPrice, price, amount, trailingAmt, trailingPct float64, market string) (int64, error) {
 	params := make([]interface{}, 0)
 
 	if stopPrice > 0 {
@@ -444,14 +476,12 @@ func (b *BTCC) PlaceStopOrder(buyOder bool, stopPrice, price, amount, trailingAm
 		req = BTCC_STOPORDER_SELL
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(req, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	var orderID int64
+	err := b.SendAuthenticatedHTTPRequest(req, params, &orderID)
+	return orderID, err
 }
 
-func (b *BTCC) GetStopOrder(orderID int64, market string) {
+func (b *BTCC) GetStopOrder(orderID int64, market string) (BTCCStopOrder, error) {
 	params := make([]interface{}, 0)
 	params = append(params, orderID)
 
@@ -459,14 +489,16 @@ func (b *BTCC) GetStopOrder(orderID int64, market string) {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_STOPORDER, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Order BTCCStopOrder `json:"stop_order"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_STOPORDER, params, &result)
+	return result.Order, err
 }
 
-func (b *BTCC) GetStopOrders(status, orderType string, stopPrice float64, limit, offset int64, market string) {
+func (b *BTCC) GetStopOrders(status, orderType string, stopPrice float64, limit, offset int64, market string) ([]BTCCStopOrder, error) {
 	params := make([]interface{}, 0)
 
 	if len(status) > 0 {
@@ -486,21 +518,23 @@ func (b *BTCC) GetStopOrders(status, orderType string, stopPrice float64, limit,
 	}
 
 	if offset > 0 {
-		params = append(params, limit)
+		params = append(params, offset)
 	}
 
 	if len(market) > 0 {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_STOPORDERS, params)
-
-	if err != nil {
-		log.Println(err)
+	type Response struct {
+		Orders []BTCCStopOrder `json:"stop_orders"`
 	}
+
+	result := Response{}
+	err := b.SendAuthenticatedHTTPRequest(BTCC_STOPORDERS, params, &result)
+	return result.Orders, err
 }
 
-func (b *BTCC) CancelStopOrder(orderID int64, market string) {
+func (b *BTCC) CancelStopOrder(orderID int64, market string) (bool, error) {
 	params := make([]interface{}, 0)
 	params = append(params, orderID)
 
@@ -508,15 +542,16 @@ func (b *BTCC) CancelStopOrder(orderID int64, market string) {
 		params = append(params, market)
 	}
 
-	err := b.SendAuthenticatedHTTPRequest(BTCC_STOPORDER_CANCEL, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	var result bool
+	err := b.SendAuthenticatedHTTPRequest(BTCC_STOPORDER_CANCEL, params, &result)
+	return result, err
 }
 
-func (b *BTCC) SendAuthenticatedHTTPRequest(method string, params []interface{}) (err error) {
-	nonce := strconv.FormatInt(time.Now().UnixNano(), 10)[0:16]
+//SendAuthenticatedHTTPRequest calls a trade API method and decodes its result
+//into result
+func (b *BTCC) SendAuthenticatedHTTPRequest(method string, params []interface{}, result interface{}) (err error) {
+	b.WaitRateLimit(true)
+	nonce := b.GetNonce().GetString()
 	encoded := fmt.Sprintf("tonce=%s&accesskey=%s&requestmethod=post&id=%d&method=%s&params=", nonce, b.APIKey, 1, method)
 
 	if len(params) == 0 {
@@ -538,6 +573,10 @@ func (b *BTCC) SendAuthenticatedHTTPRequest(method string, params []interface{})
 				{
 					items = append(items, fmt.Sprintf("%f", x))
 				}
+			case "<nil>":
+				{
+					items = append(items, "")
+				}
 			case "bool":
 				{
 					if x == true {
@@ -563,7 +602,7 @@ func (b *BTCC) SendAuthenticatedHTTPRequest(method string, params []interface{})
 	postData["method"] = method
 	postData["params"] = params
 	postData["id"] = 1
-	apiURL := BTCC_API_URL + BTCC_API_AUTHENTICATED_METHOD
+	apiURL := b.GetAPIUrl(BTCC_API_URL) + BTCC_API_AUTHENTICATED_METHOD
 	data, err := common.JSONEncode(postData)
 
 	if err != nil {
@@ -579,15 +618,26 @@ func (b *BTCC) SendAuthenticatedHTTPRequest(method string, params []interface{})
 	headers["Authorization"] = "Basic " + common.Base64Encode([]byte(b.APIKey+":"+common.HexEncodeToString(hmac)))
 	headers["Json-Rpc-Tonce"] = nonce
 
-	resp, err := common.SendHTTPRequest("POST", apiURL, headers, strings.NewReader(string(data)))
+	resp, err := b.SendHTTPRequest("POST", apiURL, headers, strings.NewReader(string(data)))
 
 	if err != nil {
-		return err
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, err, resp))
 	}
 
 	if b.Verbose {
 		log.Printf("Recv'd :%s\n", resp)
 	}
 
+	response := BTCCResponse{Result: result}
+	err = common.JSONDecode([]byte(resp), &response)
+
+	if err != nil {
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New("Unable to JSON Unmarshal response."), resp))
+	}
+
+	if response.Error != nil {
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New(response.Error.Message), resp))
+	}
+
 	return nil
 }
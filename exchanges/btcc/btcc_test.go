@@ -0,0 +1,94 @@
+package btcc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+func TestAuthenticatedEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}{}
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		if r.URL.Path != "/"+BTCC_API_AUTHENTICATED_METHOD || r.Header.Get("Json-Rpc-Tonce") == "" || common.JSONDecode(body, &request) != nil {
+			w.Write([]byte(`{"error":{"code":-32003,"message":"Invalid request"},"id":1}`))
+			return
+		}
+
+		switch request.Method {
+		case BTCC_ACCOUNT_INFO:
+			w.Write([]byte(`{"result":{"profile":{"btc_deposit_address":"1btcdeposit","btc_withdrawal_address":"1btcwithdraw"},"balance":{"cny":{"currency":"CNY","amount":"500.50"},"btc":{"currency":"BTC","amount":"1.25"}},"frozen":{"btc":{"currency":"BTC","amount":"0.5"}}},"id":1}`))
+		case BTCC_ORDER_BUY:
+			if len(request.Params) != 3 || request.Params[0] != "900" || request.Params[2] != "BTCCNY" {
+				w.Write([]byte(`{"error":{"code":-32019,"message":"Invalid params"},"id":1}`))
+				return
+			}
+			w.Write([]byte(`{"result":42,"id":1}`))
+		case BTCC_ORDER_CANCEL:
+			w.Write([]byte(`{"result":false,"id":1}`))
+		case BTCC_ORDER:
+			w.Write([]byte(`{"result":{"order":{"id":42,"type":"bid","price":"900","currency":"CNY","amount":"0.75","amount_original":"1","date":1500000000,"status":"open"}},"id":1}`))
+		case BTCC_ORDERS:
+			w.Write([]byte(`{"result":{"order":[{"id":42,"type":"bid","price":"900","amount":"0.75","amount_original":"1","date":1500000000,"status":"open"},{"id":41,"type":"ask","price":null,"amount":"0","amount_original":"2","date":1499999000,"status":"closed"}],"date":1500000001},"id":1}`))
+		case BTCC_WITHDRAWAL_REQUEST:
+			w.Write([]byte(`{"result":{"id":"7"},"id":1}`))
+		}
+	}))
+	defer server.Close()
+
+	b := BTCC{}
+	b.SetDefaults()
+	b.Name = "BTCC Test"
+	b.APIUrl = server.URL + "/"
+	b.SetRateLimit(0, 0)
+	b.APIKey = "key"
+	b.APISecret = "secret"
+	p := pair.NewCurrencyPair("BTC", "CNY")
+
+	info, err := b.GetExchangeAccountInfo()
+	if err != nil || len(info.Currencies) != 2 || info.Currencies[0].CurrencyName != "BTC" || info.Currencies[0].Hold != 0.5 || info.Currencies[1].TotalValue != 500.5 {
+		t.Errorf("Test Failed - GetExchangeAccountInfo() incorrect: %+v %v", info, err)
+	}
+
+	result, err := b.SubmitOrder(exchange.OrderRequest{Pair: p, Side: exchange.OrderSideBuy, Type: exchange.OrderTypeLimit, Amount: 1, Price: 900})
+	if err != nil || result.OrderID != "42" {
+		t.Errorf("Test Failed - SubmitOrder() incorrect: %+v %v", result, err)
+	}
+
+	if err = b.CancelOrder("42", p); err == nil {
+		t.Error("Test Failed - CancelOrder() accepted an order which was not cancelled")
+	}
+
+	order, err := b.GetOrderInfo("42", p)
+	if err != nil || order.Status != exchange.OrderStatusPartiallyFilled || order.FilledAmount != 0.25 {
+		t.Errorf("Test Failed - GetOrderInfo() incorrect: %+v %v", order, err)
+	}
+
+	orders, err := b.GetOrderHistory(exchange.OrderFilter{Pairs: []pair.CurrencyPair{p}})
+	if err != nil || len(orders) != 1 || orders[0].Type != exchange.OrderTypeMarket || orders[0].Status != exchange.OrderStatusFilled {
+		t.Errorf("Test Failed - GetOrderHistory() incorrect: %+v %v", orders, err)
+	}
+
+	address, err := b.GetDepositAddress("btc")
+	if err != nil || address.Address != "1btcdeposit" {
+		t.Errorf("Test Failed - GetDepositAddress() incorrect: %+v %v", address, err)
+	}
+
+	_, err = b.WithdrawCryptocurrency(exchange.WithdrawRequest{Currency: "BTC", Address: "1other", Amount: 1})
+	if err == nil {
+		t.Error("Test Failed - WithdrawCryptocurrency() accepted an unregistered address")
+	}
+
+	withdrawal, err := b.WithdrawCryptocurrency(exchange.WithdrawRequest{Currency: "BTC", Address: "1btcwithdraw", Amount: 1})
+	if err != nil || withdrawal.WithdrawalID != "7" {
+		t.Errorf("Test Failed - WithdrawCryptocurrency() incorrect: %+v %v", withdrawal, err)
+	}
+}
@@ -22,10 +22,10 @@ type BTCCProfile struct {
 	TradeFeeBTCLTC       float64 `json:"trade_fee_btcltc"`
 	DailyBTCLimit        float64 `json:"daily_btc_limit"`
 	DailyLTCLimit        float64 `json:"daily_ltc_limit"`
-	BTCDespoitAddress    string  `json:"btc_despoit_address"`
+	BTCDespoitAddress    string  `json:"btc_deposit_address"`
 	BTCWithdrawalAddress string  `json:"btc_withdrawal_address"`
 	LTCDepositAddress    string  `json:"ltc_deposit_address"`
-	LTCWithdrawalAddress string  `json:"ltc_withdrawal_request"`
+	LTCWithdrawalAddress string  `json:"ltc_withdrawal_address"`
 	APIKeyPermission     int64   `json:"api_key_permission"`
 }
 
@@ -38,92 +38,112 @@ type BTCCOrderbook struct {
 type BTCCCurrencyGeneric struct {
 	Currency      string
 	Symbol        string
-	Amount        string
-	AmountInt     int64   `json:"amount_integer"`
-	AmountDecimal float64 `json:"amount_decimal"`
+	Amount        float64 `json:"amount,string"`
+	AmountInt     int64   `json:"amount_integer,string"`
+	AmountDecimal int64   `json:"amount_decimal"`
 }
 
+//BTCCAccountInfo : Account profile and balances keyed by lower case currency
+type BTCCAccountInfo struct {
+	Profile BTCCProfile                    `json:"profile"`
+	Balance map[string]BTCCCurrencyGeneric `json:"balance"`
+	Frozen  map[string]BTCCCurrencyGeneric `json:"frozen"`
+	Loan    map[string]BTCCCurrencyGeneric `json:"loan"`
+}
+
+//BTCCOrder : Trade API order. Amount is the unfilled amount and Price is
+//empty for market orders
 type BTCCOrder struct {
-	ID         int64
-	Type       string
-	Price      float64
-	Currency   string
-	Amount     float64
-	AmountOrig float64 `json:"amount_original"`
-	Date       int64
-	Status     string
-	Detail     BTCCOrderDetail
+	ID         int64             `json:"id"`
+	Type       string            `json:"type"`
+	Price      float64           `json:"price,string"`
+	Currency   string            `json:"currency"`
+	Amount     float64           `json:"amount,string"`
+	AmountOrig float64           `json:"amount_original,string"`
+	Date       int64             `json:"date"`
+	Status     string            `json:"status"`
+	Details    []BTCCOrderDetail `json:"details"`
 }
 
 type BTCCOrderDetail struct {
-	Dateline int64
-	Price    float64
-	Amount   float64
+	Dateline int64   `json:"dateline"`
+	Price    float64 `json:"price,string"`
+	Amount   float64 `json:"amount,string"`
+}
+
+type BTCCOrders struct {
+	Orders []BTCCOrder `json:"order"`
+	Date   int64       `json:"date"`
 }
 
 type BTCCWithdrawal struct {
-	ID          int64
-	Address     string
-	Currency    string
-	Amount      float64
-	Date        int64
-	Transaction string
-	Status      string
+	ID          int64   `json:"id"`
+	Address     string  `json:"address"`
+	Currency    string  `json:"currency"`
+	Amount      float64 `json:"amount,string"`
+	Date        int64   `json:"date"`
+	Transaction string  `json:"transaction"`
+	Status      string  `json:"status"`
+}
+
+type BTCCWithdrawalRequest struct {
+	ID int64 `json:"id,string"`
 }
 
 type BTCCDeposit struct {
-	ID       int64
-	Address  string
-	Currency string
-	Amount   float64
-	Date     int64
-	Status   string
+	ID       int64   `json:"id"`
+	Address  string  `json:"address"`
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount,string"`
+	Date     int64   `json:"date"`
+	Status   string  `json:"status"`
 }
 
 type BTCCBidAsk struct {
-	Price  float64
-	Amount float64
+	Price  float64 `json:"price"`
+	Amount float64 `json:"amount"`
 }
 
 type BTCCDepth struct {
-	Bid []BTCCBidAsk
-	Ask []BTCCBidAsk
+	Bid  []BTCCBidAsk `json:"bid"`
+	Ask  []BTCCBidAsk `json:"ask"`
+	Date int64        `json:"date"`
 }
 
 type BTCCTransaction struct {
-	ID        int64
-	Type      string
-	BTCAmount float64 `json:"btc_amount"`
-	LTCAmount float64 `json:"ltc_amount"`
-	CNYAmount float64 `json:"cny_amount"`
-	Date      int64
+	ID        int64   `json:"id"`
+	Type      string  `json:"type"`
+	BTCAmount float64 `json:"btc_amount,string"`
+	LTCAmount float64 `json:"ltc_amount,string"`
+	CNYAmount float64 `json:"cny_amount,string"`
+	Date      int64   `json:"date"`
 }
 
 type BTCCIcebergOrder struct {
-	ID              int64
-	Type            string
-	Price           float64
-	Market          string
-	Amount          float64
-	AmountOrig      float64 `json:"amount_original"`
-	DisclosedAmount float64 `json:"disclosed_amount"`
-	Variance        float64
-	Date            int64
-	Status          string
+	ID              int64   `json:"id"`
+	Type            string  `json:"type"`
+	Price           float64 `json:"price,string"`
+	Market          string  `json:"market"`
+	Amount          float64 `json:"amount,string"`
+	AmountOrig      float64 `json:"amount_original,string"`
+	DisclosedAmount float64 `json:"disclosed_amount,string"`
+	Variance        float64 `json:"variance,string"`
+	Date            int64   `json:"date"`
+	Status          string  `json:"status"`
 }
 
 type BTCCStopOrder struct {
-	ID          int64
-	Type        string
-	StopPrice   float64 `json:"stop_price"`
-	TrailingAmt float64 `json:"trailing_amount"`
-	TrailingPct float64 `json:"trailing_percentage"`
-	Price       float64
-	Market      string
-	Amount      float64
-	Date        int64
-	Status      string
-	OrderID     int64 `json:"order_id"`
+	ID          int64   `json:"id"`
+	Type        string  `json:"type"`
+	StopPrice   float64 `json:"stop_price,string"`
+	TrailingAmt float64 `json:"trailing_amount,string"`
+	TrailingPct float64 `json:"trailing_percentage,string"`
+	Price       float64 `json:"price,string"`
+	Market      string  `json:"market"`
+	Amount      float64 `json:"amount,string"`
+	Date        int64   `json:"date"`
+	Status      string  `json:"status"`
+	OrderID     int64   `json:"order_id"`
 }
 
 type BTCCWebsocketOrder struct {
@@ -160,3 +180,13 @@ type BTCCWebsocketTicker struct {
 	Volume    float64 `json:"vol"`
 	Vwap      float64 `json:"vwap"`
 }
+
+//BTCCResponse : JSON-RPC envelope of the trade API. Result is decoded into the
+//value it is set to
+type BTCCResponse struct {
+	Result interface{} `json:"result"`
+	Error  *struct {
+		Code    int64  `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/exchanges"
 	"github.com/thrasher-/socketio"
 )
 
@@ -15,6 +16,7 @@ const (
 var BTCCSocket *socketio.SocketIO
 
 func (b *BTCC) OnConnect(output chan socketio.Message) {
+	exchange.RecordWebsocketState(b.GetName(), exchange.WEBSOCKET_STATE_CONNECTED)
 	if b.Verbose {
 		log.Printf("%s Connected to Websocket.", b.GetName())
 	}
@@ -38,16 +40,16 @@ func (b *BTCC) OnConnect(output chan socketio.Message) {
 }
 
 func (b *BTCC) OnDisconnect(output chan socketio.Message) {
+	exchange.RecordWebsocketState(b.GetName(), exchange.WEBSOCKET_STATE_DISCONNECTED)
 	log.Printf("%s Disconnected from websocket server.. Reconnecting.\n", b.GetName())
-	b.WebsocketClient()
 }
 
 func (b *BTCC) OnError() {
 	log.Printf("%s Error with Websocket connection.. Reconnecting.\n", b.GetName())
-	b.WebsocketClient()
 }
 
 func (b *BTCC) OnMessage(message []byte, output chan socketio.Message) {
+	exchange.RecordWebsocketMessage(b.GetName())
 	if b.Verbose {
 		log.Printf("%s Websocket message received which isn't handled by default.\n", b.GetName())
 		log.Println(string(message))
@@ -55,6 +57,7 @@ func (b *BTCC) OnMessage(message []byte, output chan socketio.Message) {
 }
 
 func (b *BTCC) OnTicker(message []byte, output chan socketio.Message) {
+	exchange.RecordWebsocketMessage(b.GetName())
 	type Response struct {
 		Ticker BTCCWebsocketTicker `json:"ticker"`
 	}
@@ -68,6 +71,7 @@ func (b *BTCC) OnTicker(message []byte, output chan socketio.Message) {
 }
 
 func (b *BTCC) OnGroupOrder(message []byte, output chan socketio.Message) {
+	exchange.RecordWebsocketMessage(b.GetName())
 	type Response struct {
 		GroupOrder BTCCWebsocketGroupOrder `json:"grouporder"`
 	}
@@ -81,6 +85,7 @@ func (b *BTCC) OnGroupOrder(message []byte, output chan socketio.Message) {
 }
 
 func (b *BTCC) OnTrade(message []byte, output chan socketio.Message) {
+	exchange.RecordWebsocketMessage(b.GetName())
 	trade := BTCCWebsocketTrade{}
 	err := common.JSONDecode(message, &trade)
 
@@ -90,12 +95,39 @@ func (b *BTCC) OnTrade(message []byte, output chan socketio.Message) {
 	}
 }
 
+//websocketHandler returns handler, writing the events to the stream recording
+//first when the exchange is recorded
+func (b *BTCC) websocketHandler(event string, handler func(message []byte, output chan socketio.Message)) func(message []byte, output chan socketio.Message) {
+	recorder := exchange.GetStreamRecorder(b.GetName())
+	if recorder == nil {
+		return handler
+	}
+
+	return func(message []byte, output chan socketio.Message) {
+		recorder.RecordWebsocketEvent(event, message)
+		handler(message, output)
+	}
+}
+
 func (b *BTCC) WebsocketClient() {
 	events := make(map[string]func(message []byte, output chan socketio.Message))
 	events["grouporder"] = b.OnGroupOrder
 	events["ticker"] = b.OnTicker
 	events["trade"] = b.OnTrade
 
+	if replayer := exchange.GetStreamReplayer(b.GetName()); replayer != nil {
+		replayer.Replay(b.GetWebsocketContext(), func(entry exchange.StreamEntry) {
+			if handler, ok := events[entry.Channel]; ok {
+				handler(entry.Data, nil)
+			}
+		})
+		return
+	}
+
+	for event, handler := range events {
+		events[event] = b.websocketHandler(event, handler)
+	}
+
 	BTCCSocket = &socketio.SocketIO{
 		Version:      1,
 		OnConnect:    b.OnConnect,
@@ -105,12 +137,18 @@ func (b *BTCC) WebsocketClient() {
 		OnDisconnect: b.OnDisconnect,
 	}
 
+	ctx := b.GetWebsocketContext()
+	backoff := exchange.WebsocketBackoff{}
 	for b.Enabled && b.Websocket {
-		err := socketio.ConnectToSocket(BTCC_SOCKETIO_ADDRESS, BTCCSocket)
+		err := socketio.ConnectToSocket(b.GetWebsocketURL(BTCC_SOCKETIO_ADDRESS), BTCCSocket)
 		if err != nil {
 			log.Printf("%s Unable to connect to Websocket. Err: %s\n", b.GetName(), err)
-			continue
+		} else {
+			log.Printf("%s Disconnected from Websocket.\n", b.GetName())
+		}
+
+		if !backoff.Wait(ctx) {
+			return
 		}
-		log.Printf("%s Disconnected from Websocket.\n", b.GetName())
 	}
 }
@@ -1,7 +1,11 @@
 package btcc
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -10,6 +14,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (b *BTCC) Start() {
@@ -30,7 +35,7 @@ func (b *BTCC) Run() {
 	for b.Enabled {
 		for _, x := range b.EnabledPairs {
 			currency := pair.NewCurrencyPair(x[0:3], x[3:])
-			go func() {
+			b.Poll(x, func() {
 				ticker, err := b.GetTickerPrice(currency)
 				if err != nil {
 					log.Println(err)
@@ -38,7 +43,7 @@ func (b *BTCC) Run() {
 				}
 				log.Printf("BTCC %s: Last %f High %f Low %f Volume %f\n", currency.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(b.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * b.RESTPollingDelay)
 	}
@@ -52,6 +57,9 @@ func (b *BTCC) GetTickerPrice(p pair.CurrencyPair) (ticker.TickerPrice, error) {
 
 	var tickerPrice ticker.TickerPrice
 	tick, err := b.GetTicker(p.Pair().Lower().String())
+	if err != nil {
+		return tickerPrice, err
+	}
 	tickerPrice.Pair = p
 	tickerPrice.Ask = tick.Sell
 	tickerPrice.Bid = tick.Buy
@@ -90,10 +98,270 @@ func (b *BTCC) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase, err
 	return orderBook, nil
 }
 
-//TODO: Retrieve BTCC info
-//GetExchangeAccountInfo : Retrieves balances for all enabled currencies for the Kraken exchange
+//GetExchangeAccountInfo : Retrieves balances for all currencies held on the
+//BTCC exchange
 func (e *BTCC) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	var response exchange.ExchangeAccountInfo
 	response.ExchangeName = e.GetName()
+
+	info, err := e.GetAccountInfo("")
+	if err != nil {
+		return response, err
+	}
+
+	var currencies []string
+	for x := range info.Balance {
+		currencies = append(currencies, x)
+	}
+	sort.Strings(currencies)
+
+	for _, x := range currencies {
+		var exchangeCurrency exchange.ExchangeAccountCurrencyInfo
+		exchangeCurrency.CurrencyName = common.StringToUpper(x)
+		exchangeCurrency.TotalValue = info.Balance[x].Amount
+		exchangeCurrency.Hold = info.Frozen[x].Amount
+		response.Currencies = append(response.Currencies, exchangeCurrency)
+	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new order on BTCC
+func (b *BTCC) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := b.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	price := order.Price
+	if order.IsMarket() {
+		price = 0
+	}
+
+	orderID, err := b.PlaceOrder(order.IsBuy(), price, order.Amount, getMarket(order.Pair))
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = b.GetName()
+	result.OrderID = strconv.FormatInt(orderID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (b *BTCC) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	cancelled, err := b.CancelExistingOrder(id, getMarket(p))
+	if err != nil {
+		return err
+	}
+
+	if !cancelled {
+		return exchange.NewExchangeError(b.GetName(), fmt.Errorf(ErrBTCCOrderNotCancelled, id), "")
+	}
+	return nil
+}
+
+//ModifyOrder : Not supported, BTCC does not support amending orders
+func (b *BTCC) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (b *BTCC) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	order, err := b.GetOrder(id, getMarket(p), false)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	if order.ID == 0 {
+		return exchange.OrderDetail{}, errors.New(exchange.ErrOrderNotFound)
+	}
+	return b.getOrderDetail(p, order), nil
+}
+
+//GetActiveOrders : Retrieves all open orders matching the filter
+func (b *BTCC) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, x := range b.getFilterPairs(filter) {
+		response, err := b.GetOrders(true, getMarket(x), 0, 0, 0, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, y := range response.Orders {
+			orders = append(orders, b.getOrderDetail(x, y))
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Retrieves closed and cancelled orders matching the filter.
+//Only the latest BTCC_ORDERS_LIMIT orders of each pair are searched
+func (b *BTCC) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	var since int64
+	if !filter.StartTime.IsZero() {
+		since = filter.StartTime.Unix()
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range b.getFilterPairs(filter) {
+		response, err := b.GetOrders(false, getMarket(x), BTCC_ORDERS_LIMIT, 0, since, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, y := range response.Orders {
+			if isOrderActive(y.Status) {
+				continue
+			}
+			orders = append(orders, b.getOrderDetail(x, y))
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetFills : Not supported, BTCC transactions are not linked to their orders
+func (b *BTCC) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+func (b *BTCC) getFilterPairs(filter exchange.OrderFilter) []pair.CurrencyPair {
+	if len(filter.Pairs) > 0 {
+		return filter.Pairs
+	}
+
+	var pairs []pair.CurrencyPair
+	for _, x := range b.EnabledPairs {
+		pairs = append(pairs, pair.NewCurrencyPair(x[0:3], x[3:]))
+	}
+	return pairs
+}
+
+//getMarket returns the trade API market name of a pair
+func getMarket(p pair.CurrencyPair) string {
+	return p.Pair().Upper().String()
+}
+
+func isOrderActive(status string) bool {
+	return status == "open" || status == "pending"
+}
+
+//getOrderDetail converts a trade API order. Orders do not carry their market,
+//so the pair they were requested for is used
+func (b *BTCC) getOrderDetail(p pair.CurrencyPair, order BTCCOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = b.GetName()
+	detail.OrderID = strconv.FormatInt(order.ID, 10)
+	detail.Pair = p
+	detail.Side = exchange.OrderSideBuy
+	if order.Type == "ask" {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	if order.Price == 0 {
+		detail.Type = exchange.OrderTypeMarket
+	}
+
+	detail.Price = order.Price
+	detail.Amount = order.AmountOrig
+	detail.FilledAmount = order.AmountOrig - order.Amount
+	detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, isOrderActive(order.Status), order.Status == "cancelled")
+	if order.Status == "error" {
+		detail.Status = exchange.OrderStatusRejected
+	}
+	detail.CreatedAt = time.Unix(order.Date, 0)
+	return detail
+}
+
+//GetDepositAddress : Retrieves the BTC or LTC deposit address from the account profile
+func (b *BTCC) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	info, err := b.GetAccountInfo("profile")
+	if err != nil {
+		return exchange.DepositAddress{}, exchange.NewFundingError(b.GetName(), currency, err)
+	}
+
+	var result exchange.DepositAddress
+	switch common.StringToUpper(currency) {
+	case "BTC":
+		result.Address = info.Profile.BTCDespoitAddress
+	case "LTC":
+		result.Address = info.Profile.LTCDepositAddress
+	default:
+		return result, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	result.Exchange = b.GetName()
+	result.Currency = currency
+	return result, nil
+}
+
+//WithdrawCryptocurrency : Withdraws BTC or LTC. BTCC only withdraws to the
+//address registered on the account, so any other address is refused
+func (b *BTCC) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	info, err := b.GetAccountInfo("profile")
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, err)
+	}
+
+	var address string
+	switch common.StringToUpper(request.Currency) {
+	case "BTC":
+		address = info.Profile.BTCWithdrawalAddress
+	case "LTC":
+		address = info.Profile.LTCWithdrawalAddress
+	default:
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	if address != request.Address {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, fmt.Errorf(ErrBTCCWithdrawalAddress, address))
+	}
+
+	withdrawalID, err := b.RequestWithdrawal(common.StringToUpper(request.Currency), request.Amount)
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), request.Currency, err)
+	}
+
+	result.Exchange = b.GetName()
+	result.WithdrawalID = strconv.FormatInt(withdrawalID, 10)
+	result.Currency = request.Currency
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//UpdatePairInfo : Not supported, BTCC does not publish pair constraints
+func (b *BTCC) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, BTCC has no candle endpoint
+func (b *BTCC) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Not supported, the BTCC REST client does not parse trade responses yet
+func (b *BTCC) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
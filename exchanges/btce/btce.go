@@ -15,8 +15,9 @@ import (
 )
 
 const (
-	BTCE_API_PUBLIC_URL      = "https://btc-e.com/api"
-	BTCE_API_PRIVATE_URL     = "https://btc-e.com/tapi"
+	BTCE_API_URL             = "https://btc-e.com"
+	BTCE_API_PUBLIC          = "api"
+	BTCE_API_PRIVATE         = "tapi"
 	BTCE_API_PUBLIC_VERSION  = "3"
 	BTCE_API_PRIVATE_VERSION = "1"
 	BTCE_INFO                = "info"
@@ -33,6 +34,13 @@ const (
 	BTCE_WITHDRAW_COIN       = "WithdrawCoin"
 	BTCE_CREATE_COUPON       = "CreateCoupon"
 	BTCE_REDEEM_COUPON       = "RedeemCoupon"
+	BTCE_TRADE_HISTORY_LIMIT = 1000
+	BTCE_NONCE_ERROR_PATTERN = `you should send:\s*(\d+)`
+)
+
+const (
+	BTCE_AUTH_RATE_LIMIT   = 60
+	BTCE_UNAUTH_RATE_LIMIT = 60
 )
 
 type BTCE struct {
@@ -47,6 +55,9 @@ func (b *BTCE) SetDefaults() {
 	b.Verbose = false
 	b.Websocket = false
 	b.RESTPollingDelay = 10
+	b.SetRateLimit(BTCE_AUTH_RATE_LIMIT, BTCE_UNAUTH_RATE_LIMIT)
+	b.SetNonceResolution(time.Second)
+	b.SetNonceErrorPattern(BTCE_NONCE_ERROR_PATTERN)
 	b.Ticker = make(map[string]BTCeTicker)
 }
 
@@ -58,6 +69,17 @@ func (b *BTCE) Setup(exch config.ExchangeConfig) {
 		b.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		b.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		b.RESTPollingDelay = exch.RESTPollingDelay
+		b.UpdateRateLimit(exch)
+		err := b.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", b.GetName(), err)
+		}
+		if b.AuthenticatedAPISupport {
+			err = b.EnableNoncePersistence()
+			if err != nil {
+				log.Printf("%s Failed to load the stored nonce: %s.\n", b.GetName(), err)
+			}
+		}
 		b.Verbose = exch.Verbose
 		b.Websocket = exch.Websocket
 		b.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -72,9 +94,9 @@ func (b *BTCE) GetFee() float64 {
 }
 
 func (b *BTCE) GetInfo() (BTCEInfo, error) {
-	req := fmt.Sprintf("%s/%s/%s/", BTCE_API_PUBLIC_URL, BTCE_API_PUBLIC_VERSION, BTCE_INFO)
+	req := fmt.Sprintf("%s/%s/%s/%s/", b.GetAPIUrl(BTCE_API_URL), BTCE_API_PUBLIC, BTCE_API_PUBLIC_VERSION, BTCE_INFO)
 	resp := BTCEInfo{}
-	err := common.SendHTTPGetRequest(req, true, &resp)
+	err := b.SendHTTPGetRequest(req, true, &resp)
 
 	if err != nil {
 		return resp, err
@@ -89,8 +111,8 @@ func (b *BTCE) GetTicker(symbol string) (map[string]BTCeTicker, error) {
 	}
 
 	response := Response{}
-	req := fmt.Sprintf("%s/%s/%s/%s", BTCE_API_PUBLIC_URL, BTCE_API_PUBLIC_VERSION, BTCE_TICKER, symbol)
-	err := common.SendHTTPGetRequest(req, true, &response.Data)
+	req := fmt.Sprintf("%s/%s/%s/%s/%s", b.GetAPIUrl(BTCE_API_URL), BTCE_API_PUBLIC, BTCE_API_PUBLIC_VERSION, BTCE_TICKER, symbol)
+	err := b.SendHTTPGetRequest(req, true, &response.Data)
 
 	if err != nil {
 		return nil, err
@@ -104,9 +126,9 @@ func (b *BTCE) GetDepth(symbol string) (BTCEOrderbook, error) {
 	}
 
 	response := Response{}
-	req := fmt.Sprintf("%s/%s/%s/%s", BTCE_API_PUBLIC_URL, BTCE_API_PUBLIC_VERSION, BTCE_DEPTH, symbol)
+	req := fmt.Sprintf("%s/%s/%s/%s/%s", b.GetAPIUrl(BTCE_API_URL), BTCE_API_PUBLIC, BTCE_API_PUBLIC_VERSION, BTCE_DEPTH, symbol)
 
-	err := common.SendHTTPGetRequest(req, true, &response.Data)
+	err := b.SendHTTPGetRequest(req, true, &response.Data)
 	if err != nil {
 		return BTCEOrderbook{}, err
 	}
@@ -121,9 +143,9 @@ func (b *BTCE) GetTrades(symbol string) ([]BTCETrades, error) {
 	}
 
 	response := Response{}
-	req := fmt.Sprintf("%s/%s/%s/%s", BTCE_API_PUBLIC_URL, BTCE_API_PUBLIC_VERSION, BTCE_TRADES, symbol)
+	req := fmt.Sprintf("%s/%s/%s/%s/%s", b.GetAPIUrl(BTCE_API_URL), BTCE_API_PUBLIC, BTCE_API_PUBLIC_VERSION, BTCE_TRADES, symbol)
 
-	err := common.SendHTTPGetRequest(req, true, &response.Data)
+	err := b.SendHTTPGetRequest(req, true, &response.Data)
 	if err != nil {
 		return []BTCETrades{}, err
 	}
@@ -143,9 +165,11 @@ func (b *BTCE) GetAccountInfo() (BTCEAccountInfo, error) {
 	return result, nil
 }
 
-func (b *BTCE) GetActiveOrders(pair string) (map[string]BTCEActiveOrders, error) {
+func (b *BTCE) GetOpenOrders(pair string) (map[string]BTCEActiveOrders, error) {
 	req := url.Values{}
-	req.Add("pair", pair)
+	if pair != "" {
+		req.Add("pair", pair)
+	}
 
 	var result map[string]BTCEActiveOrders
 	err := b.SendAuthenticatedHTTPRequest(BTCE_ACTIVE_ORDERS, req, &result)
@@ -157,7 +181,7 @@ func (b *BTCE) GetActiveOrders(pair string) (map[string]BTCEActiveOrders, error)
 	return result, nil
 }
 
-func (b *BTCE) GetOrderInfo(OrderID int64) (map[string]BTCEOrderInfo, error) {
+func (b *BTCE) OrderInfo(OrderID int64) (map[string]BTCEOrderInfo, error) {
 	req := url.Values{}
 	req.Add("order_id", strconv.FormatInt(OrderID, 10))
 
@@ -171,7 +195,7 @@ func (b *BTCE) GetOrderInfo(OrderID int64) (map[string]BTCEOrderInfo, error) {
 	return result, nil
 }
 
-func (b *BTCE) CancelOrder(OrderID int64) (bool, error) {
+func (b *BTCE) CancelExistingOrder(OrderID int64) (bool, error) {
 	req := url.Values{}
 	req.Add("order_id", strconv.FormatInt(OrderID, 10))
 
@@ -226,14 +250,33 @@ func (b *BTCE) GetTransactionHistory(TIDFrom, Count, TIDEnd int64, order, since,
 func (b *BTCE) GetTradeHistory(TIDFrom, Count, TIDEnd int64, order, since, end, pair string) (map[string]BTCETradeHistory, error) {
 	req := url.Values{}
 
-	req.Add("from", strconv.FormatInt(TIDFrom, 10))
-	req.Add("count", strconv.FormatInt(Count, 10))
-	req.Add("from_id", strconv.FormatInt(TIDFrom, 10))
-	req.Add("end_id", strconv.FormatInt(TIDEnd, 10))
-	req.Add("order", order)
-	req.Add("since", since)
-	req.Add("end", end)
-	req.Add("pair", pair)
+	if TIDFrom > 0 {
+		req.Add("from_id", strconv.FormatInt(TIDFrom, 10))
+	}
+
+	if Count > 0 {
+		req.Add("count", strconv.FormatInt(Count, 10))
+	}
+
+	if TIDEnd > 0 {
+		req.Add("end_id", strconv.FormatInt(TIDEnd, 10))
+	}
+
+	if order != "" {
+		req.Add("order", order)
+	}
+
+	if since != "" {
+		req.Add("since", since)
+	}
+
+	if end != "" {
+		req.Add("end", end)
+	}
+
+	if pair != "" {
+		req.Add("pair", pair)
+	}
 
 	var result map[string]BTCETradeHistory
 	err := b.SendAuthenticatedHTTPRequest(BTCE_TRADE_HISTORY, req, &result)
@@ -293,15 +336,18 @@ func (b *BTCE) RedeemCoupon(coupon string) (BTCERedeemCoupon, error) {
 }
 
 func (b *BTCE) SendAuthenticatedHTTPRequest(method string, values url.Values, result interface{}) (err error) {
-	nonce := strconv.FormatInt(time.Now().Unix(), 10)
+	b.WaitRateLimit(true)
+	nonce := b.GetNonce().GetString()
 	values.Set("nonce", nonce)
 	values.Set("method", method)
 
 	encoded := values.Encode()
 	hmac := common.GetHMAC(common.HASH_SHA512, []byte(encoded), []byte(b.APISecret))
 
+	path := fmt.Sprintf("%s/%s", b.GetAPIUrl(BTCE_API_URL), BTCE_API_PRIVATE)
+
 	if b.Verbose {
-		log.Printf("Sending POST request to %s calling method %s with params %s\n", BTCE_API_PRIVATE_URL, method, encoded)
+		log.Printf("Sending POST request to %s calling method %s with params %s\n", path, method, encoded)
 	}
 
 	headers := make(map[string]string)
@@ -309,21 +355,21 @@ func (b *BTCE) SendAuthenticatedHTTPRequest(method string, values url.Values, re
 	headers["Sign"] = common.HexEncodeToString(hmac)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	resp, err := common.SendHTTPRequest("POST", BTCE_API_PRIVATE_URL, headers, strings.NewReader(encoded))
+	resp, err := b.SendHTTPRequest("POST", path, headers, strings.NewReader(encoded))
 
 	if err != nil {
-		return err
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, err, resp))
 	}
 
 	response := BTCEResponse{}
 	err = common.JSONDecode([]byte(resp), &response)
 
 	if err != nil {
-		return err
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, err, resp))
 	}
 
 	if response.Success != 1 {
-		return errors.New(response.Error)
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New(response.Error), resp))
 	}
 
 	JSONEncoded, err := common.JSONEncode(response.Return)
@@ -19,7 +19,7 @@ type BTCEOrderbook struct {
 
 type BTCETrades struct {
 	Type      string  `json:"type"`
-	Price     float64 `json:"bid"`
+	Price     float64 `json:"price"`
 	Amount    float64 `json:"amount"`
 	TID       int64   `json:"tid"`
 	Timestamp int64   `json:"timestamp"`
@@ -59,7 +59,7 @@ type BTCEAccountInfo struct {
 
 type BTCEActiveOrders struct {
 	Pair             string  `json:"pair"`
-	Type             string  `json:"sell"`
+	Type             string  `json:"type"`
 	Amount           float64 `json:"amount"`
 	Rate             float64 `json:"rate"`
 	TimestampCreated float64 `json:"time_created"`
@@ -68,7 +68,7 @@ type BTCEActiveOrders struct {
 
 type BTCEOrderInfo struct {
 	Pair             string  `json:"pair"`
-	Type             string  `json:"sell"`
+	Type             string  `json:"type"`
 	StartAmount      float64 `json:"start_amount"`
 	Amount           float64 `json:"amount"`
 	Rate             float64 `json:"rate"`
@@ -3,6 +3,8 @@ package btce
 import (
 	"errors"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -11,6 +13,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (b *BTCE) Start() {
@@ -24,6 +27,11 @@ func (b *BTCE) Run() {
 		log.Printf("%s %d currencies enabled: %s.\n", b.GetName(), len(b.EnabledPairs), b.EnabledPairs)
 	}
 
+	err := b.UpdatePairInfo()
+	if err != nil {
+		log.Printf("%s Failed to get pair info.\n", b.GetName())
+	}
+
 	pairs := []string{}
 	for _, x := range b.EnabledPairs {
 		x = common.StringToLower(x[0:3] + "_" + x[3:6])
@@ -32,7 +40,7 @@ func (b *BTCE) Run() {
 	pairsString := common.JoinStrings(pairs, "-")
 
 	for b.Enabled {
-		go func() {
+		b.Poll("tickers", func() {
 			ticker, err := b.GetTicker(pairsString)
 			if err != nil {
 				log.Println(err)
@@ -45,7 +53,7 @@ func (b *BTCE) Run() {
 				b.GetTickerPrice2(pair.NewCurrencyPairFromString(pairsString), b.Ticker[x])
 				stats.AddExchangeInfo(b.GetName(), common.StringToUpper(x[0:3]), common.StringToUpper(x[4:]), y.Last, y.Vol_cur)
 			}
-		}()
+		})
 		time.Sleep(time.Second * b.RESTPollingDelay)
 	}
 }
@@ -127,3 +135,259 @@ func (e *BTCE) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 
 	return response, nil
 }
+
+//SubmitOrder : Places a new limit order on BTC-e
+func (b *BTCE) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := b.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	orderID, err := b.Trade(b.formatOrderPair(order.Pair), common.StringToLower(string(order.Side)), order.Amount, order.Price)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = b.GetName()
+	result.OrderID = strconv.FormatInt(int64(orderID), 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (b *BTCE) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.CancelExistingOrder(id)
+	return err
+}
+
+//ModifyOrder : BTC-e does not support amending orders
+func (b *BTCE) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (b *BTCE) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return detail, err
+	}
+
+	response, err := b.OrderInfo(id)
+	if err != nil {
+		return detail, err
+	}
+
+	order, ok := response[orderID]
+	if !ok {
+		return detail, errors.New(exchange.ErrOrderNotFound)
+	}
+
+	detail.Exchange = b.GetName()
+	detail.OrderID = orderID
+	detail.Pair = pair.NewCurrencyPairDelimiter(common.StringToUpper(order.Pair), "_")
+	detail.Side = exchange.OrderSide(common.StringToUpper(order.Type))
+	detail.Type = exchange.OrderTypeLimit
+	detail.Price = order.Rate
+	detail.Amount = order.StartAmount
+	detail.FilledAmount = order.StartAmount - order.Amount
+	detail.CreatedAt = time.Unix(int64(order.TimestampCreated), 0)
+
+	switch order.Status {
+	case 0:
+		detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, true, false)
+	case 1:
+		detail.Status = exchange.OrderStatusFilled
+	case 2, 3:
+		detail.Status = exchange.OrderStatusCancelled
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail, nil
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (b *BTCE) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := b.GetOpenOrders("")
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for id, x := range response {
+		var detail exchange.OrderDetail
+		detail.Exchange = b.GetName()
+		detail.OrderID = id
+		detail.Pair = pair.NewCurrencyPairDelimiter(common.StringToUpper(x.Pair), "_")
+		detail.Side = exchange.OrderSide(common.StringToUpper(x.Type))
+		detail.Type = exchange.OrderTypeLimit
+		detail.Status = exchange.OrderStatusActive
+		detail.Price = x.Rate
+		detail.Amount = x.Amount
+		detail.CreatedAt = time.Unix(int64(x.TimestampCreated), 0)
+		orders = append(orders, detail)
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Not supported, BTC-e only exposes fills through its trade history
+func (b *BTCE) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetFills : Retrieves the account's executions matching the filter, paging
+//forward by trade ID. BTC-e does not report fees on its trade history
+func (b *BTCE) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	var since, end, currencyPair string
+	if !filter.StartTime.IsZero() {
+		since = strconv.FormatInt(filter.StartTime.Unix(), 10)
+	}
+
+	if !filter.EndTime.IsZero() {
+		end = strconv.FormatInt(filter.EndTime.Unix(), 10)
+	}
+
+	if len(filter.Pairs) == 1 {
+		currencyPair = b.formatOrderPair(filter.Pairs[0])
+	}
+
+	var fills []exchange.Fill
+	var fromID int64
+	for {
+		response, err := b.GetTradeHistory(fromID, BTCE_TRADE_HISTORY_LIMIT, 0, "ASC", since, end, currencyPair)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, x := range response {
+			tradeID, err := strconv.ParseInt(id, 10, 64)
+			if err == nil && tradeID >= fromID {
+				fromID = tradeID + 1
+			}
+
+			var fill exchange.Fill
+			fill.Exchange = b.GetName()
+			fill.TradeID = id
+			fill.OrderID = strconv.FormatInt(int64(x.OrderID), 10)
+			fill.Pair = pair.NewCurrencyPairDelimiter(common.StringToUpper(x.Pair), "_")
+			fill.Side = exchange.OrderSide(common.StringToUpper(x.Type))
+			fill.Price = x.Rate
+			fill.Amount = x.Amount
+			fill.Timestamp = time.Unix(int64(x.Timestamp), 0)
+			fills = append(fills, fill)
+		}
+
+		if len(response) < BTCE_TRADE_HISTORY_LIMIT {
+			break
+		}
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+func (b *BTCE) formatOrderPair(p pair.CurrencyPair) string {
+	return common.StringToLower(p.GetFirstCurrency().String()) + "_" + common.StringToLower(p.GetSecondCurrency().String())
+}
+
+//GetDepositAddress : Not supported, BTCE has no deposit address endpoint
+func (b *BTCE) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Withdraws a currency to an external address
+func (b *BTCE) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	currency := common.StringToUpper(request.Currency)
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	response, err := b.WithdrawCoins(currency, request.Amount, request.Address)
+	if err != nil {
+		return result, exchange.NewFundingError(b.GetName(), currency, err)
+	}
+
+	result.Exchange = b.GetName()
+	result.WithdrawalID = strconv.FormatInt(response.TID, 10)
+	result.Currency = currency
+	result.Address = request.Address
+	result.Amount = response.AmountSent
+	return result, nil
+}
+
+//UpdatePairInfo : Populates the pair metadata table from the BTCE info
+//endpoint. Hidden pairs are marked as halted
+func (b *BTCE) UpdatePairInfo() error {
+	response, err := b.GetInfo()
+	if err != nil {
+		return err
+	}
+
+	var info []exchange.PairInfo
+	for symbol, x := range response.Pairs {
+		if !common.StringContains(symbol, "_") {
+			continue
+		}
+		status := exchange.PairStatusTrading
+		if x.Hidden == 1 {
+			status = exchange.PairStatusHalted
+		}
+		info = append(info, exchange.PairInfo{
+			Pair:           pair.NewCurrencyPairDelimiter(common.StringToUpper(symbol), "_"),
+			MinAmount:      x.MinAmount,
+			AmountStep:     exchange.GetPrecisionStep(8),
+			PriceTick:      exchange.GetPrecisionStep(x.DecimalPlaces),
+			QuotePrecision: x.DecimalPlaces,
+			Status:         status,
+		})
+	}
+	b.SetPairInfo(info)
+	return nil
+}
+
+//GetHistoricCandles : Not supported, BTC-e has no candle endpoint
+func (b *BTCE) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair
+func (b *BTCE) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := b.GetTrades(b.formatOrderPair(p))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(x.Timestamp, 0),
+		}
+		if x.Type == "ask" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(b.GetName(), p, result)
+	return result, nil
+}
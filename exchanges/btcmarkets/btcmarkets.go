@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"log"
 	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -26,6 +25,11 @@ const (
 	BTCMARKETS_ORDER_DETAIL        = "/order/detail"
 )
 
+const (
+	BTCMARKETS_AUTH_RATE_LIMIT   = 60
+	BTCMARKETS_UNAUTH_RATE_LIMIT = 60
+)
+
 type BTCMarkets struct {
 	exchange.ExchangeBase
 	Ticker map[string]BTCMarketsTicker
@@ -38,6 +42,8 @@ func (b *BTCMarkets) SetDefaults() {
 	b.Verbose = false
 	b.Websocket = false
 	b.RESTPollingDelay = 10
+	b.SetRateLimit(BTCMARKETS_AUTH_RATE_LIMIT, BTCMARKETS_UNAUTH_RATE_LIMIT)
+	b.SetNonceResolution(time.Millisecond)
 	b.Ticker = make(map[string]BTCMarketsTicker)
 }
 
@@ -49,6 +55,11 @@ func (b *BTCMarkets) Setup(exch config.ExchangeConfig) {
 		b.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		b.SetAPIKeys(exch.APIKey, exch.APISecret, "", true)
 		b.RESTPollingDelay = exch.RESTPollingDelay
+		b.UpdateRateLimit(exch)
+		err := b.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", b.GetName(), err)
+		}
 		b.Verbose = exch.Verbose
 		b.Websocket = exch.Websocket
 		b.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -65,7 +76,7 @@ func (b *BTCMarkets) GetFee() float64 {
 func (b *BTCMarkets) GetTicker(symbol string) (BTCMarketsTicker, error) {
 	ticker := BTCMarketsTicker{}
 	path := fmt.Sprintf("/market/%s/AUD/tick", symbol)
-	err := common.SendHTTPGetRequest(BTCMARKETS_API_URL+path, true, &ticker)
+	err := b.SendHTTPGetRequest(b.GetAPIUrl(BTCMARKETS_API_URL)+path, true, &ticker)
 	if err != nil {
 		return BTCMarketsTicker{}, err
 	}
@@ -75,7 +86,7 @@ func (b *BTCMarkets) GetTicker(symbol string) (BTCMarketsTicker, error) {
 func (b *BTCMarkets) GetOrderbook(symbol string) (BTCMarketsOrderbook, error) {
 	orderbook := BTCMarketsOrderbook{}
 	path := fmt.Sprintf("/market/%s/AUD/orderbook", symbol)
-	err := common.SendHTTPGetRequest(BTCMARKETS_API_URL+path, true, &orderbook)
+	err := b.SendHTTPGetRequest(b.GetAPIUrl(BTCMARKETS_API_URL)+path, true, &orderbook)
 	if err != nil {
 		return BTCMarketsOrderbook{}, err
 	}
@@ -84,15 +95,15 @@ func (b *BTCMarkets) GetOrderbook(symbol string) (BTCMarketsOrderbook, error) {
 
 func (b *BTCMarkets) GetTrades(symbol string, values url.Values) ([]BTCMarketsTrade, error) {
 	trades := []BTCMarketsTrade{}
-	path := common.EncodeURLValues(fmt.Sprintf("%s/market/%s/AUD/trades", BTCMARKETS_API_URL, symbol), values)
-	err := common.SendHTTPGetRequest(path, true, &trades)
+	path := common.EncodeURLValues(fmt.Sprintf("%s/market/%s/AUD/trades", b.GetAPIUrl(BTCMARKETS_API_URL), symbol), values)
+	err := b.SendHTTPGetRequest(path, true, &trades)
 	if err != nil {
 		return nil, err
 	}
 	return trades, nil
 }
 
-func (b *BTCMarkets) Order(currency, instrument string, price, amount int64, orderSide, orderType, clientReq string) (int, error) {
+func (b *BTCMarkets) Order(currency, instrument string, price, amount float64, orderSide, orderType, clientReq string) (int, error) {
 	type Order struct {
 		Currency        string `json:"currency"`
 		Instrument      string `json:"instrument"`
@@ -105,8 +116,8 @@ func (b *BTCMarkets) Order(currency, instrument string, price, amount int64, ord
 	order := Order{}
 	order.Currency = currency
 	order.Instrument = instrument
-	order.Price = price * common.SATOSHIS_PER_BTC
-	order.Volume = amount * common.SATOSHIS_PER_BTC
+	order.Price = int64(price * common.SATOSHIS_PER_BTC)
+	order.Volume = int64(amount * common.SATOSHIS_PER_BTC)
 	order.OrderSide = orderSide
 	order.OrderType = orderType
 	order.ClientRequestId = clientReq
@@ -132,7 +143,7 @@ func (b *BTCMarkets) Order(currency, instrument string, price, amount int64, ord
 	return resp.ID, nil
 }
 
-func (b *BTCMarkets) CancelOrder(orderID []int64) (bool, error) {
+func (b *BTCMarkets) CancelExistingOrder(orderID []int64) (bool, error) {
 	type CancelOrder struct {
 		OrderIDs []int64 `json:"orderIds"`
 	}
@@ -225,6 +236,39 @@ func (b *BTCMarkets) GetOrders(currency, instrument string, limit, since int64,
 	return resp.Orders, nil
 }
 
+func (b *BTCMarkets) GetTradeHistory(currency, instrument string, limit, since int64) ([]BTCMarketsTradeResponse, error) {
+	request := make(map[string]interface{})
+	request["currency"] = currency
+	request["instrument"] = instrument
+	request["limit"] = limit
+	request["since"] = since
+
+	type response struct {
+		Success      bool                      `json:"success"`
+		ErrorCode    int                       `json:"errorCode"`
+		ErrorMessage string                    `json:"errorMessage"`
+		Trades       []BTCMarketsTradeResponse `json:"trades"`
+	}
+
+	resp := response{}
+	err := b.SendAuthenticatedRequest("POST", BTCMARKETS_ORDER_TRADE_HISTORY, request, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, errors.New(resp.ErrorMessage)
+	}
+
+	for i := range resp.Trades {
+		resp.Trades[i].Fee = resp.Trades[i].Fee / common.SATOSHIS_PER_BTC
+		resp.Trades[i].Price = resp.Trades[i].Price / common.SATOSHIS_PER_BTC
+		resp.Trades[i].Volume = resp.Trades[i].Volume / common.SATOSHIS_PER_BTC
+	}
+	return resp.Trades, nil
+}
+
 func (b *BTCMarkets) GetOrderDetail(orderID []int64) ([]BTCMarketsOrder, error) {
 	type OrderDetail struct {
 		OrderIDs []int64 `json:"orderIds"`
@@ -282,7 +326,8 @@ func (b *BTCMarkets) GetAccountBalance() ([]BTCMarketsAccountBalance, error) {
 }
 
 func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interface{}, result interface{}) (err error) {
-	nonce := strconv.FormatInt(time.Now().UnixNano(), 10)[0:13]
+	b.WaitRateLimit(true)
+	nonce := b.GetNonce().GetString()
 	request := ""
 	payload := []byte("")
 
@@ -299,7 +344,7 @@ func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interfa
 	hmac := common.GetHMAC(common.HASH_SHA512, []byte(request), []byte(b.APISecret))
 
 	if b.Verbose {
-		log.Printf("Sending %s request to URL %s with params %s\n", reqType, BTCMARKETS_API_URL+path, request)
+		log.Printf("Sending %s request to URL %s with params %s\n", reqType, b.GetAPIUrl(BTCMARKETS_API_URL)+path, request)
 	}
 
 	headers := make(map[string]string)
@@ -310,20 +355,25 @@ func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data interfa
 	headers["timestamp"] = nonce
 	headers["signature"] = common.Base64Encode(hmac)
 
-	resp, err := common.SendHTTPRequest(reqType, BTCMARKETS_API_URL+path, headers, bytes.NewBuffer(payload))
+	resp, err := b.SendHTTPRequest(reqType, b.GetAPIUrl(BTCMARKETS_API_URL)+path, headers, bytes.NewBuffer(payload))
 
 	if err != nil {
-		return err
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, err, resp))
 	}
 
 	if b.Verbose {
 		log.Printf("Recieved raw: %s\n", resp)
 	}
 
+	errResponse := BTCMarketsErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && !errResponse.Success && errResponse.ErrorMessage != "" {
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, errors.New(errResponse.ErrorMessage), resp))
+	}
+
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return err
+		return b.CheckNonceError(exchange.NewExchangeError(b.Name, err, resp))
 	}
 
 	return nil
@@ -31,6 +31,8 @@ type BTCMarketsTradeResponse struct {
 	Price        float64 `json:"price"`
 	Volume       float64 `json:"volume"`
 	Fee          float64 `json:"fee"`
+	Side         string  `json:"side"`
+	OrderID      int64   `json:"orderId"`
 }
 
 type BTCMarketsOrder struct {
@@ -54,3 +56,9 @@ type BTCMarketsAccountBalance struct {
 	PendingFunds float64 `json:"pendingFunds"`
 	Currency     string  `json:"currency"`
 }
+
+type BTCMarketsErrorResponse struct {
+	Success      bool   `json:"success"`
+	ErrorCode    int64  `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
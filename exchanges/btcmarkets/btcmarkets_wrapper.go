@@ -1,7 +1,10 @@
 package btcmarkets
 
 import (
+	"errors"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/currency"
@@ -10,6 +13,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (b *BTCMarkets) Start() {
@@ -25,7 +29,7 @@ func (b *BTCMarkets) Run() {
 	for b.Enabled {
 		for _, x := range b.EnabledPairs {
 			curr := pair.NewCurrencyPair(x, "AUD")
-			go func() {
+			b.Poll(x, func() {
 				ticker, err := b.GetTickerPrice(curr)
 				if err != nil {
 					return
@@ -36,7 +40,7 @@ func (b *BTCMarkets) Run() {
 				log.Printf("BTC Markets %s: Last %f (%f) Bid %f (%f) Ask %f (%f)\n", curr.Pair().String(), BTCMarketsLastUSD, ticker.Last, BTCMarketsBestBidUSD, ticker.Bid, BTCMarketsBestAskUSD, ticker.Ask)
 				stats.AddExchangeInfo(b.GetName(), curr.GetFirstCurrency().String(), curr.GetSecondCurrency().String(), ticker.Last, 0)
 				stats.AddExchangeInfo(b.GetName(), curr.GetFirstCurrency().String(), "USD", BTCMarketsLastUSD, 0)
-			}()
+			})
 		}
 		time.Sleep(time.Second * b.RESTPollingDelay)
 	}
@@ -106,3 +110,243 @@ func (e *BTCMarkets) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, err
 	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new order on BTC Markets
+func (b *BTCMarkets) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := b.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	orderSide := "Bid"
+	if !order.IsBuy() {
+		orderSide = "Ask"
+	}
+
+	orderType := "Limit"
+	if order.IsMarket() {
+		orderType = "Market"
+	}
+
+	orderID, err := b.Order(order.Pair.GetSecondCurrency().String(), order.Pair.GetFirstCurrency().String(), order.Price, order.Amount, orderSide, orderType, order.ClientID)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = b.GetName()
+	result.OrderID = strconv.Itoa(orderID)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (b *BTCMarkets) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.CancelExistingOrder([]int64{id})
+	return err
+}
+
+//ModifyOrder : BTC Markets does not support amending orders
+func (b *BTCMarkets) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (b *BTCMarkets) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return detail, err
+	}
+
+	orders, err := b.GetOrderDetail([]int64{id})
+	if err != nil {
+		return detail, err
+	}
+
+	if len(orders) == 0 {
+		return detail, errors.New(exchange.ErrOrderNotFound)
+	}
+	return b.getOrderDetail(orders[0]), nil
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (b *BTCMarkets) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return b.getOrders(false, filter)
+}
+
+//GetOrderHistory : Retrieves closed and cancelled orders matching the filter
+func (b *BTCMarkets) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return b.getOrders(true, filter)
+}
+
+//getOrders queries each requested pair in turn as BTC Markets requires an
+//instrument and currency on every order listing
+func (b *BTCMarkets) getOrders(historic bool, filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	pairs := filter.Pairs
+	if len(pairs) == 0 {
+		for _, x := range b.EnabledPairs {
+			pairs = append(pairs, pair.NewCurrencyPair(x, "AUD"))
+		}
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range pairs {
+		response, err := b.GetOrders(x.GetSecondCurrency().String(), x.GetFirstCurrency().String(), 200, 0, historic)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, y := range response {
+			orders = append(orders, b.getOrderDetail(y))
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetFills : Retrieves the account's executions matching the filter, paging
+//forward by trade ID for each pair
+func (b *BTCMarkets) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	pairs := filter.Pairs
+	if len(pairs) == 0 {
+		for _, x := range b.EnabledPairs {
+			pairs = append(pairs, pair.NewCurrencyPair(x, "AUD"))
+		}
+	}
+
+	var fills []exchange.Fill
+	for _, x := range pairs {
+		var since int64
+		for {
+			response, err := b.GetTradeHistory(x.GetSecondCurrency().String(), x.GetFirstCurrency().String(), 200, since)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, y := range response {
+				if y.ID > since {
+					since = y.ID
+				}
+
+				var fill exchange.Fill
+				fill.Exchange = b.GetName()
+				fill.TradeID = strconv.FormatInt(y.ID, 10)
+				fill.OrderID = strconv.FormatInt(y.OrderID, 10)
+				fill.Pair = x
+				fill.Side = exchange.OrderSideBuy
+				if y.Side == "Ask" {
+					fill.Side = exchange.OrderSideSell
+				}
+				fill.Price = y.Price
+				fill.Amount = y.Volume
+				fill.Fee = y.Fee
+				fill.FeeCurrency = x.GetSecondCurrency().String()
+				fill.Timestamp = time.Unix(0, int64(y.CreationTime)*int64(time.Millisecond))
+				fills = append(fills, fill)
+			}
+
+			if len(response) < 200 {
+				break
+			}
+		}
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+func (b *BTCMarkets) getOrderDetail(order BTCMarketsOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = b.GetName()
+	detail.OrderID = strconv.FormatInt(order.ID, 10)
+	detail.ClientID = order.ClientRequestId
+	detail.Pair = pair.NewCurrencyPair(order.Instrument, order.Currency)
+	detail.Side = exchange.OrderSideBuy
+	if order.OrderSide == "Ask" {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	if order.OrderType == "Market" {
+		detail.Type = exchange.OrderTypeMarket
+	}
+	detail.Price = order.Price
+	detail.Amount = order.Volume
+	detail.FilledAmount = order.Volume - order.OpenVolume
+	detail.CreatedAt = time.Unix(0, int64(order.CreationTime)*int64(time.Millisecond))
+
+	var total float64
+	for _, x := range order.Trades {
+		total += x.Price * x.Volume
+	}
+	if detail.FilledAmount > 0 {
+		detail.AveragePrice = total / detail.FilledAmount
+	}
+
+	switch order.Status {
+	case "New", "Placed":
+		detail.Status = exchange.OrderStatusActive
+	case "Partially Matched":
+		detail.Status = exchange.OrderStatusPartiallyFilled
+	case "Fully Matched":
+		detail.Status = exchange.OrderStatusFilled
+	case "Cancelled", "Partially Cancelled":
+		detail.Status = exchange.OrderStatusCancelled
+	case "Failed", "Error":
+		detail.Status = exchange.OrderStatusRejected
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail
+}
+
+//GetDepositAddress : Not supported, the BTCMarkets client has no funding endpoints
+func (b *BTCMarkets) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(b.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Not supported, the BTCMarkets client has no funding endpoints
+func (b *BTCMarkets) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	return exchange.WithdrawResult{}, exchange.NewFundingError(b.GetName(), request.Currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//UpdatePairInfo : Not supported, BTC Markets does not publish pair constraints
+func (b *BTCMarkets) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, BTC Markets has no candle endpoint
+func (b *BTCMarkets) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest public trades for an AUD pair. BTC
+//Markets does not report the trade side
+func (b *BTCMarkets) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	if p.GetSecondCurrency().Upper().String() != "AUD" {
+		return nil, errors.New(exchange.ErrCurrencyNotSupported)
+	}
+
+	response, err := b.GetTrades(p.GetFirstCurrency().Upper().String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		result = append(result, trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TradeID, 10),
+			Side:      trades.TRADE_SIDE_UNKNOWN,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(x.Date, 0),
+		})
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(b.GetName(), p, result)
+	return result, nil
+}
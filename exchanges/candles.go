@@ -0,0 +1,79 @@
+package exchange
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+const (
+	ErrCandleIntervalNotSupported = "Candle interval not supported by exchange."
+	ErrInvalidCandleRange         = "Candle start time must be before the end time."
+)
+
+//Candle : A single OHLCV bar, Time is the opening time of the bar
+type Candle struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+//CandleSeries : Candles for a pair at a fixed interval, sorted by time
+type CandleSeries struct {
+	Exchange string
+	Pair     pair.CurrencyPair
+	Interval time.Duration
+	Candles  []Candle
+}
+
+//ByCandleTime : Sorts candles by their opening time
+type ByCandleTime []Candle
+
+func (c ByCandleTime) Len() int {
+	return len(c)
+}
+
+func (c ByCandleTime) Less(i, j int) bool {
+	return c[i].Time.Before(c[j].Time)
+}
+
+func (c ByCandleTime) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+//ValidateCandleRange checks a candle request time range and interval
+func ValidateCandleRange(start, end time.Time, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New(ErrCandleIntervalNotSupported)
+	}
+
+	if !start.Before(end) {
+		return errors.New(ErrInvalidCandleRange)
+	}
+	return nil
+}
+
+//NewCandleSeries sorts the candles by time, removes duplicates returned by
+//overlapping pages and drops candles opening outside of the requested range
+func NewCandleSeries(exchangeName string, p pair.CurrencyPair, start, end time.Time, interval time.Duration, candles []Candle) CandleSeries {
+	sort.Sort(ByCandleTime(candles))
+
+	series := CandleSeries{Exchange: exchangeName, Pair: p, Interval: interval, Candles: []Candle{}}
+	for _, x := range candles {
+		if x.Time.Before(start) || x.Time.After(end) {
+			continue
+		}
+
+		last := len(series.Candles) - 1
+		if last >= 0 && series.Candles[last].Time.Equal(x.Time) {
+			continue
+		}
+		series.Candles = append(series.Candles, x)
+	}
+	return series
+}
@@ -0,0 +1,47 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+func TestValidateCandleRange(t *testing.T) {
+	now := time.Now()
+	if err := ValidateCandleRange(now.Add(-time.Hour), now, time.Minute); err != nil {
+		t.Errorf("Test Failed - ValidateCandleRange() error: %s", err)
+	}
+
+	if err := ValidateCandleRange(now, now.Add(-time.Hour), time.Minute); err == nil {
+		t.Error("Test Failed - ValidateCandleRange() accepted an inverted range")
+	}
+
+	if err := ValidateCandleRange(now.Add(-time.Hour), now, 0); err == nil {
+		t.Error("Test Failed - ValidateCandleRange() accepted a zero interval")
+	}
+}
+
+func TestNewCandleSeries(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	end := start.Add(time.Minute * 3)
+	candles := []Candle{
+		{Time: start.Add(time.Minute * 2), Close: 3},
+		{Time: start, Close: 1},
+		{Time: start.Add(time.Minute), Close: 2},
+		{Time: start.Add(time.Minute), Close: 2},
+		{Time: start.Add(-time.Minute), Close: 0},
+		{Time: end.Add(time.Minute), Close: 5},
+	}
+
+	series := NewCandleSeries("test", pair.NewCurrencyPair("BTC", "USD"), start, end, time.Minute, candles)
+	if len(series.Candles) != 3 {
+		t.Fatalf("Test Failed - NewCandleSeries() expected 3 candles, got %d", len(series.Candles))
+	}
+
+	for i, x := range series.Candles {
+		if x.Close != float64(i+1) {
+			t.Errorf("Test Failed - NewCandleSeries() candle %d out of order", i)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package exchange
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/champii/gocryptotrader/common"
+)
+
+//ErrorKind : Classification of an exchange error which retry and alerting
+//logic can branch on
+type ErrorKind string
+
+const (
+	ErrorKindInsufficientFunds ErrorKind = "INSUFFICIENT_FUNDS"
+	ErrorKindInvalidNonce      ErrorKind = "INVALID_NONCE"
+	ErrorKindRateLimited       ErrorKind = "RATE_LIMITED"
+	ErrorKindAuthFailure       ErrorKind = "AUTH_FAILURE"
+	ErrorKindOrderNotFound     ErrorKind = "ORDER_NOT_FOUND"
+	ErrorKindMarketClosed      ErrorKind = "MARKET_CLOSED"
+	ErrorKindTransport         ErrorKind = "TRANSPORT_ERROR"
+	ErrorKindOutcomeUnknown    ErrorKind = "OUTCOME_UNKNOWN"
+	ErrorKindUnknown           ErrorKind = "UNKNOWN"
+)
+
+//ExchangeError : Error returned by an exchange API call. Payload holds the raw
+//response body when the exchange returned one
+type ExchangeError struct {
+	Exchange   string
+	Kind       ErrorKind
+	Message    string
+	StatusCode int
+	Payload    string
+}
+
+//ErrorRule : Maps a lower case fragment of an exchange error message to a kind.
+//Underscores in the message are matched as spaces
+type ErrorRule struct {
+	Match string
+	Kind  ErrorKind
+}
+
+//DefaultErrorRules are matched against every error message after the rules
+//supplied by the wrapper
+var DefaultErrorRules = []ErrorRule{
+	{Match: "insufficient", Kind: ErrorKindInsufficientFunds},
+	{Match: "not enough", Kind: ErrorKindInsufficientFunds},
+	{Match: "exceeds available", Kind: ErrorKindInsufficientFunds},
+	{Match: "nonce", Kind: ErrorKindInvalidNonce},
+	{Match: "rate limit", Kind: ErrorKindRateLimited},
+	{Match: "ratelimit", Kind: ErrorKindRateLimited},
+	{Match: "too many requests", Kind: ErrorKindRateLimited},
+	{Match: "request limit", Kind: ErrorKindRateLimited},
+	{Match: "invalid key", Kind: ErrorKindAuthFailure},
+	{Match: "api key", Kind: ErrorKindAuthFailure},
+	{Match: "apikey", Kind: ErrorKindAuthFailure},
+	{Match: "signature", Kind: ErrorKindAuthFailure},
+	{Match: "permission", Kind: ErrorKindAuthFailure},
+	{Match: "unauthorized", Kind: ErrorKindAuthFailure},
+	{Match: "authenticat", Kind: ErrorKindAuthFailure},
+	{Match: "order not found", Kind: ErrorKindOrderNotFound},
+	{Match: "unknown order", Kind: ErrorKindOrderNotFound},
+	{Match: "no such order", Kind: ErrorKindOrderNotFound},
+	{Match: "order does not exist", Kind: ErrorKindOrderNotFound},
+	{Match: "market closed", Kind: ErrorKindMarketClosed},
+	{Match: "market is closed", Kind: ErrorKindMarketClosed},
+	{Match: "trading halted", Kind: ErrorKindMarketClosed},
+	{Match: "trading is disabled", Kind: ErrorKindMarketClosed},
+	{Match: "maintenance", Kind: ErrorKindMarketClosed},
+}
+
+//Error returns the formatted exchange error
+func (e *ExchangeError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Exchange, e.Kind, e.Message)
+}
+
+//NewExchangeError classifies err for the exchange. The payload is kept on the
+//error and matched against the rules along with the error message. Wrapper
+//rules take precedence over DefaultErrorRules
+func NewExchangeError(exchangeName string, err error, payload string, rules ...ErrorRule) error {
+	if err == nil {
+		return nil
+	}
+
+	if exchangeErr, ok := err.(*ExchangeError); ok {
+		return exchangeErr
+	}
+
+	result := &ExchangeError{Exchange: exchangeName, Message: err.Error(), Payload: payload}
+	if httpErr, ok := err.(*common.HTTPError); ok {
+		result.StatusCode = httpErr.StatusCode
+		if result.Payload == "" {
+			result.Payload = httpErr.Body
+		}
+	}
+	result.Kind = classifyError(err, result.Message+" "+result.Payload, result.StatusCode, rules)
+	return result
+}
+
+//GetErrorKind returns the kind of err. Errors which were not wrapped by an
+//exchange, such as HTTP and network errors from common, are classified here
+func GetErrorKind(err error) ErrorKind {
+	switch e := err.(type) {
+	case nil:
+		return ""
+	case *ExchangeError:
+		return e.Kind
+	case *FundingError:
+		if e.Kind != "" {
+			return e.Kind
+		}
+	}
+	return NewExchangeError("", err, "").(*ExchangeError).Kind
+}
+
+//IsErrorKind returns whether err is of the supplied kind
+func IsErrorKind(err error, kind ErrorKind) bool {
+	return err != nil && GetErrorKind(err) == kind
+}
+
+//IsRetryable returns whether the request which failed with err can be sent
+//again unchanged. Requests of ErrorKindOutcomeUnknown may have been executed,
+//so the result has to be queried before they are sent again
+func IsRetryable(err error) bool {
+	switch GetErrorKind(err) {
+	case ErrorKindRateLimited, ErrorKindInvalidNonce, ErrorKindTransport:
+		return true
+	}
+	return false
+}
+
+func classifyError(err error, message string, statusCode int, rules []ErrorRule) ErrorKind {
+	if _, ok := err.(net.Error); ok {
+		return ErrorKindTransport
+	}
+
+	message = strings.Replace(strings.ToLower(message), "_", " ", -1)
+	for _, x := range rules {
+		if strings.Contains(message, x.Match) {
+			return x.Kind
+		}
+	}
+
+	for _, x := range DefaultErrorRules {
+		if strings.Contains(message, x.Match) {
+			return x.Kind
+		}
+	}
+
+	switch {
+	case statusCode == 429:
+		return ErrorKindRateLimited
+	case statusCode == 401 || statusCode == 403:
+		return ErrorKindAuthFailure
+	case statusCode >= 500:
+		return ErrorKindTransport
+	}
+	return ErrorKindUnknown
+}
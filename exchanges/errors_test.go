@@ -0,0 +1,77 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/champii/gocryptotrader/common"
+)
+
+func TestNewExchangeError(t *testing.T) {
+	if NewExchangeError("TESTNAME", nil, "") != nil {
+		t.Error("Test Failed - NewExchangeError() wrapped a nil error")
+	}
+
+	tests := []struct {
+		err     error
+		payload string
+		kind    ErrorKind
+	}{
+		{errors.New("Insufficient funds"), "", ErrorKindInsufficientFunds},
+		{errors.New("Not enough BTC."), "", ErrorKindInsufficientFunds},
+		{errors.New("invalid nonce parameter"), "", ErrorKindInvalidNonce},
+		{errors.New("Invalid API key"), "", ErrorKindAuthFailure},
+		{errors.New("Order not found"), "", ErrorKindOrderNotFound},
+		{errors.New("Market is closed"), "", ErrorKindMarketClosed},
+		{&common.HTTPError{StatusCode: 429}, "", ErrorKindRateLimited},
+		{&common.HTTPError{StatusCode: 401}, "", ErrorKindAuthFailure},
+		{&common.HTTPError{StatusCode: 502}, "", ErrorKindTransport},
+		{&common.HTTPError{StatusCode: 400, Body: `{"message":"Insufficient funds"}`}, "", ErrorKindInsufficientFunds},
+		{errors.New("Something went wrong"), "", ErrorKindUnknown},
+	}
+
+	for _, x := range tests {
+		err := NewExchangeError("TESTNAME", x.err, x.payload)
+		if kind := GetErrorKind(err); kind != x.kind {
+			t.Errorf("Test Failed - NewExchangeError() %s expected %s, got %s", x.err, x.kind, kind)
+		}
+	}
+
+	err := NewExchangeError("TESTNAME", &common.HTTPError{StatusCode: 400, Body: "raw"}, "")
+	exchangeErr, ok := err.(*ExchangeError)
+	if !ok {
+		t.Fatal("Test Failed - NewExchangeError() did not return an ExchangeError")
+	}
+
+	if exchangeErr.Payload != "raw" || exchangeErr.StatusCode != 400 || exchangeErr.Exchange != "TESTNAME" {
+		t.Error("Test Failed - NewExchangeError() incorrect fields")
+	}
+
+	if NewExchangeError("OTHER", err, "") != err {
+		t.Error("Test Failed - NewExchangeError() rewrapped an ExchangeError")
+	}
+
+	err = NewExchangeError("TESTNAME", errors.New("EOrder:Insufficient margin"), "", ErrorRule{Match: "eorder:insufficient margin", Kind: ErrorKindInsufficientFunds})
+	if !IsErrorKind(err, ErrorKindInsufficientFunds) {
+		t.Error("Test Failed - NewExchangeError() ignored the wrapper rules")
+	}
+}
+
+func TestGetErrorKind(t *testing.T) {
+	if GetErrorKind(nil) != "" {
+		t.Error("Test Failed - GetErrorKind() returned a kind for a nil error")
+	}
+
+	if GetErrorKind(&common.HTTPError{StatusCode: 429}) != ErrorKindRateLimited {
+		t.Error("Test Failed - GetErrorKind() did not classify an unwrapped HTTP error")
+	}
+
+	err := NewFundingError("TESTNAME", "BTC", NewExchangeError("TESTNAME", errors.New("Insufficient balance"), ""))
+	if !IsErrorKind(err, ErrorKindInsufficientFunds) {
+		t.Error("Test Failed - GetErrorKind() did not return the funding error kind")
+	}
+
+	if !IsRetryable(&common.HTTPError{StatusCode: 503}) || IsRetryable(errors.New("Insufficient funds")) || IsRetryable(&ExchangeError{Kind: ErrorKindOutcomeUnknown}) {
+		t.Error("Test Failed - IsRetryable() incorrect result")
+	}
+}
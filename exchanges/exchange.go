@@ -1,7 +1,9 @@
 package exchange
 
 import (
+	"context"
 	"log"
+	"regexp"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -9,6 +11,7 @@ import (
 	"github.com/champii/gocryptotrader/currency/pair"
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 const (
@@ -43,6 +46,14 @@ type ExchangeBase struct {
 	EnabledPairs                []string
 	WebsocketURL                string
 	APIUrl                      string
+	pairInfo                    map[string]PairInfo
+	authRateLimiter             *RateLimiter
+	unauthRateLimiter           *RateLimiter
+	requester                   *common.Requester
+	nonce                       *Nonce
+	nonceErrorPattern           *regexp.Regexp
+	websocketCtx                context.Context
+	websocketCancel             context.CancelFunc
 }
 
 //IBotExchange : Enforces standard functions for all exchanges supported in gocryptotrader
@@ -56,6 +67,23 @@ type IBotExchange interface {
 	GetOrderbookEx(currency pair.CurrencyPair) (orderbook.OrderbookBase, error)
 	GetEnabledCurrencies() []string
 	GetExchangeAccountInfo() (ExchangeAccountInfo, error)
+	SubmitOrder(order OrderRequest) (OrderResult, error)
+	CancelOrder(orderID string, currency pair.CurrencyPair) error
+	ModifyOrder(orderID string, order OrderRequest) (OrderResult, error)
+	GetOrderInfo(orderID string, currency pair.CurrencyPair) (OrderDetail, error)
+	GetActiveOrders(filter OrderFilter) ([]OrderDetail, error)
+	GetOrderHistory(filter OrderFilter) ([]OrderDetail, error)
+	GetFills(filter OrderFilter) ([]Fill, error)
+	GetDepositAddress(currency string) (DepositAddress, error)
+	WithdrawCryptocurrency(request WithdrawRequest) (WithdrawResult, error)
+	UpdatePairInfo() error
+	GetPairInfo(currency pair.CurrencyPair) (PairInfo, error)
+	RoundPrice(currency pair.CurrencyPair, price float64) float64
+	RoundAmount(currency pair.CurrencyPair, amount float64) float64
+	GetHistoricCandles(currency pair.CurrencyPair, start, end time.Time, interval time.Duration) (CandleSeries, error)
+	GetRecentTrades(currency pair.CurrencyPair) ([]trades.Trade, error)
+	StopWebsocket()
+	GetHealth(staleTimeout time.Duration) ExchangeHealth
 }
 
 func (e *ExchangeBase) GetName() string {
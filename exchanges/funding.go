@@ -0,0 +1,85 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	ErrCurrencyNotSupported   = "Currency not supported by exchange."
+	ErrDepositAddressNotFound = "Deposit address not found."
+	ErrInvalidWithdrawAmount  = "Withdrawal amount must be greater than zero."
+	ErrInvalidWithdrawAddress = "Withdrawal address must not be empty."
+	ErrWithdrawRejected       = "Withdrawal rejected by exchange."
+	ErrWithdrawTagUnsupported = "Withdrawal destination tag not supported by exchange."
+)
+
+//DepositAddress : Address which funds a currency on an exchange
+type DepositAddress struct {
+	Exchange string
+	Currency string
+	Address  string
+	Tag      string
+}
+
+//WithdrawRequest : Generic cryptocurrency withdrawal parameters accepted by every exchange wrapper
+type WithdrawRequest struct {
+	Currency string
+	Address  string
+	Tag      string
+	Amount   float64
+}
+
+//WithdrawResult : Generic response returned after a withdrawal is requested
+type WithdrawResult struct {
+	Exchange     string
+	WithdrawalID string
+	Currency     string
+	Address      string
+	Amount       float64
+}
+
+//FundingError : Error returned by deposit address and withdrawal calls. Reason
+//holds one of the funding error constants, or the exchange message when the
+//exchange rejected the request for its own reasons. Kind is carried over from
+//an ExchangeError
+type FundingError struct {
+	Exchange string
+	Currency string
+	Reason   string
+	Kind     ErrorKind
+}
+
+//Error returns the formatted funding error
+func (e *FundingError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Exchange, e.Currency, e.Reason)
+}
+
+//NewFundingError wraps err into a FundingError for the exchange and currency
+func NewFundingError(exchange, currency string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if fundingErr, ok := err.(*FundingError); ok {
+		return fundingErr
+	}
+	result := &FundingError{Exchange: exchange, Currency: currency, Reason: err.Error()}
+	if exchangeErr, ok := err.(*ExchangeError); ok {
+		result.Reason = exchangeErr.Message
+		result.Kind = exchangeErr.Kind
+	}
+	return result
+}
+
+//Validate checks the withdrawal request for missing or inconsistent fields
+func (w *WithdrawRequest) Validate() error {
+	if w.Amount <= 0 {
+		return errors.New(ErrInvalidWithdrawAmount)
+	}
+
+	if w.Address == "" {
+		return errors.New(ErrInvalidWithdrawAddress)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithdrawRequestValidate(t *testing.T) {
+	request := WithdrawRequest{
+		Currency: "BTC",
+		Address:  "1BitcoinAddress",
+		Amount:   1,
+	}
+
+	if err := request.Validate(); err != nil {
+		t.Errorf("Test Failed - WithdrawRequest Validate() error: %s", err)
+	}
+
+	request.Amount = 0
+	if err := request.Validate(); err == nil {
+		t.Error("Test Failed - WithdrawRequest Validate() accepted a zero amount")
+	}
+
+	request.Amount = 1
+	request.Address = ""
+	if err := request.Validate(); err == nil {
+		t.Error("Test Failed - WithdrawRequest Validate() accepted an empty address")
+	}
+}
+
+func TestNewFundingError(t *testing.T) {
+	if NewFundingError("TESTNAME", "BTC", nil) != nil {
+		t.Error("Test Failed - NewFundingError() wrapped a nil error")
+	}
+
+	err := NewFundingError("TESTNAME", "BTC", errors.New(ErrCurrencyNotSupported))
+	fundingErr, ok := err.(*FundingError)
+	if !ok {
+		t.Fatal("Test Failed - NewFundingError() did not return a FundingError")
+	}
+
+	if fundingErr.Reason != ErrCurrencyNotSupported || fundingErr.Exchange != "TESTNAME" {
+		t.Error("Test Failed - NewFundingError() incorrect fields")
+	}
+
+	if NewFundingError("OTHER", "LTC", err) != err {
+		t.Error("Test Failed - NewFundingError() rewrapped a FundingError")
+	}
+}
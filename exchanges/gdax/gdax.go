@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"strconv"
 	"time"
@@ -33,8 +34,24 @@ const (
 	GDAX_REPORTS     = "reports"
 )
 
+const (
+	GDAX_FILLS_LIMIT       = 100
+	GDAX_ORDERS_LIMIT      = 100
+	GDAX_HISTORY_LIMIT     = 300
+	GDAX_AUTH_RATE_LIMIT   = 300
+	GDAX_UNAUTH_RATE_LIMIT = 180
+)
+
+var gdaxErrorRules = []exchange.ErrorRule{
+	{Match: "request timestamp expired", Kind: exchange.ErrorKindInvalidNonce},
+	{Match: "invalid passphrase", Kind: exchange.ErrorKindAuthFailure},
+	{Match: "notfound", Kind: exchange.ErrorKindOrderNotFound},
+}
+
 type GDAX struct {
 	exchange.ExchangeBase
+	WebsocketConn *exchange.WebsocketConnection
+	books         map[string]*GDAXBook
 }
 
 func (g *GDAX) SetDefaults() {
@@ -46,6 +63,8 @@ func (g *GDAX) SetDefaults() {
 	g.Verbose = false
 	g.Websocket = false
 	g.RESTPollingDelay = 10
+	g.SetRateLimit(GDAX_AUTH_RATE_LIMIT, GDAX_UNAUTH_RATE_LIMIT)
+	g.WebsocketConn = g.newWebsocketConnection()
 }
 
 func (g *GDAX) Setup(exch config.ExchangeConfig) {
@@ -56,6 +75,11 @@ func (g *GDAX) Setup(exch config.ExchangeConfig) {
 		g.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		g.SetAPIKeys(exch.APIKey, exch.APISecret, exch.ClientID, true)
 		g.RESTPollingDelay = exch.RESTPollingDelay
+		g.UpdateRateLimit(exch)
+		err := g.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", g.GetName(), err)
+		}
 		g.Verbose = exch.Verbose
 		g.Websocket = exch.Websocket
 		g.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -74,7 +98,7 @@ func (g *GDAX) GetFee(maker bool) float64 {
 
 func (g *GDAX) GetProducts() ([]GDAXProduct, error) {
 	products := []GDAXProduct{}
-	err := common.SendHTTPGetRequest(GDAX_API_URL+GDAX_PRODUCTS, true, &products)
+	err := g.SendHTTPGetRequest(g.GetAPIUrl(GDAX_API_URL)+GDAX_PRODUCTS, true, &products)
 
 	if err != nil {
 		return nil, err
@@ -88,12 +112,12 @@ func (g *GDAX) GetOrderbook(symbol string, level int) (interface{}, error) {
 	path := ""
 	if level > 0 {
 		levelStr := strconv.Itoa(level)
-		path = fmt.Sprintf("%s/%s/%s?level=%s", GDAX_API_URL+GDAX_PRODUCTS, symbol, GDAX_ORDERBOOK, levelStr)
+		path = fmt.Sprintf("%s/%s/%s?level=%s", g.GetAPIUrl(GDAX_API_URL)+GDAX_PRODUCTS, symbol, GDAX_ORDERBOOK, levelStr)
 	} else {
-		path = fmt.Sprintf("%s/%s/%s", GDAX_API_URL+GDAX_PRODUCTS, symbol, GDAX_ORDERBOOK)
+		path = fmt.Sprintf("%s/%s/%s", g.GetAPIUrl(GDAX_API_URL)+GDAX_PRODUCTS, symbol, GDAX_ORDERBOOK)
 	}
 
-	err := common.SendHTTPGetRequest(path, true, &orderbook)
+	err := g.SendHTTPGetRequest(path, true, &orderbook)
 	if err != nil {
 		return nil, err
 	}
@@ -159,8 +183,8 @@ func (g *GDAX) GetOrderbook(symbol string, level int) (interface{}, error) {
 
 func (g *GDAX) GetTicker(symbol string) (GDAXTicker, error) {
 	ticker := GDAXTicker{}
-	path := fmt.Sprintf("%s/%s/%s", GDAX_API_URL+GDAX_PRODUCTS, symbol, GDAX_TICKER)
-	err := common.SendHTTPGetRequest(path, true, &ticker)
+	path := fmt.Sprintf("%s/%s/%s", g.GetAPIUrl(GDAX_API_URL)+GDAX_PRODUCTS, symbol, GDAX_TICKER)
+	err := g.SendHTTPGetRequest(path, true, &ticker)
 
 	if err != nil {
 		return ticker, err
@@ -170,8 +194,8 @@ func (g *GDAX) GetTicker(symbol string) (GDAXTicker, error) {
 
 func (g *GDAX) GetTrades(symbol string) ([]GDAXTrade, error) {
 	trades := []GDAXTrade{}
-	path := fmt.Sprintf("%s/%s/%s", GDAX_API_URL+GDAX_PRODUCTS, symbol, GDAX_TRADES)
-	err := common.SendHTTPGetRequest(path, true, &trades)
+	path := fmt.Sprintf("%s/%s/%s", g.GetAPIUrl(GDAX_API_URL)+GDAX_PRODUCTS, symbol, GDAX_TRADES)
+	err := g.SendHTTPGetRequest(path, true, &trades)
 
 	if err != nil {
 		return nil, err
@@ -180,34 +204,42 @@ func (g *GDAX) GetTrades(symbol string) ([]GDAXTrade, error) {
 }
 
 func (g *GDAX) GetHistoricRates(symbol string, start, end, granularity int64) ([]GDAXHistory, error) {
-	history := []GDAXHistory{}
 	values := url.Values{}
 
 	if start > 0 {
-		values.Set("start", strconv.FormatInt(start, 10))
+		values.Set("start", time.Unix(start, 0).UTC().Format(time.RFC3339))
 	}
 
 	if end > 0 {
-		values.Set("end", strconv.FormatInt(end, 10))
+		values.Set("end", time.Unix(end, 0).UTC().Format(time.RFC3339))
 	}
 
 	if granularity > 0 {
 		values.Set("granularity", strconv.FormatInt(granularity, 10))
 	}
 
-	path := common.EncodeURLValues(fmt.Sprintf("%s/%s/%s", GDAX_API_URL+GDAX_PRODUCTS, symbol, GDAX_HISTORY), values)
-	err := common.SendHTTPGetRequest(path, true, &history)
+	resp := [][]float64{}
+	path := common.EncodeURLValues(fmt.Sprintf("%s/%s/%s", g.GetAPIUrl(GDAX_API_URL)+GDAX_PRODUCTS, symbol, GDAX_HISTORY), values)
+	err := g.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return nil, err
 	}
+
+	history := []GDAXHistory{}
+	for _, x := range resp {
+		if len(x) < 6 {
+			continue
+		}
+		history = append(history, GDAXHistory{Time: int64(x[0]), Low: x[1], High: x[2], Open: x[3], Close: x[4], Volume: x[5]})
+	}
 	return history, nil
 }
 
 func (g *GDAX) GetStats(symbol string) (GDAXStats, error) {
 	stats := GDAXStats{}
-	path := fmt.Sprintf("%s/%s/%s", GDAX_API_URL+GDAX_PRODUCTS, symbol, GDAX_STATS)
-	err := common.SendHTTPGetRequest(path, true, &stats)
+	path := fmt.Sprintf("%s/%s/%s", g.GetAPIUrl(GDAX_API_URL)+GDAX_PRODUCTS, symbol, GDAX_STATS)
+	err := g.SendHTTPGetRequest(path, true, &stats)
 
 	if err != nil {
 		return stats, err
@@ -217,7 +249,7 @@ func (g *GDAX) GetStats(symbol string) (GDAXStats, error) {
 
 func (g *GDAX) GetCurrencies() ([]GDAXCurrency, error) {
 	currencies := []GDAXCurrency{}
-	err := common.SendHTTPGetRequest(GDAX_API_URL+GDAX_CURRENCIES, true, &currencies)
+	err := g.SendHTTPGetRequest(g.GetAPIUrl(GDAX_API_URL)+GDAX_CURRENCIES, true, &currencies)
 
 	if err != nil {
 		return nil, err
@@ -264,14 +296,21 @@ func (g *GDAX) GetHolds(accountID string) ([]GDAXAccountHolds, error) {
 	return resp, nil
 }
 
-func (g *GDAX) PlaceOrder(clientRef string, price, amount float64, side string, productID, stp string) (string, error) {
+func (g *GDAX) PlaceOrder(clientRef string, price, amount float64, side, orderType, productID, stp string) (string, error) {
 	request := make(map[string]interface{})
 
 	if clientRef != "" {
 		request["client_oid"] = clientRef
 	}
 
-	request["price"] = strconv.FormatFloat(price, 'f', -1, 64)
+	if orderType != "" {
+		request["type"] = orderType
+	}
+
+	if orderType != "market" {
+		request["price"] = strconv.FormatFloat(price, 'f', -1, 64)
+	}
+
 	request["size"] = strconv.FormatFloat(amount, 'f', -1, 64)
 	request["side"] = side
 	request["product_id"] = productID
@@ -293,7 +332,7 @@ func (g *GDAX) PlaceOrder(clientRef string, price, amount float64, side string,
 	return resp.ID, nil
 }
 
-func (g *GDAX) CancelOrder(orderID string) error {
+func (g *GDAX) CancelExistingOrder(orderID string) error {
 	path := fmt.Sprintf("%s/%s", GDAX_ORDERS, orderID)
 	err := g.SendAuthenticatedHTTPRequest("DELETE", path, nil, nil)
 	if err != nil {
@@ -302,14 +341,21 @@ func (g *GDAX) CancelOrder(orderID string) error {
 	return nil
 }
 
-func (g *GDAX) GetOrders(params url.Values) ([]GDAXOrdersResponse, error) {
-	path := common.EncodeURLValues(GDAX_API_URL+GDAX_ORDERS, params)
-	resp := []GDAXOrdersResponse{}
-	err := g.SendAuthenticatedHTTPRequest("GET", common.GetURIPath(path), nil, &resp)
+func (g *GDAX) GetOrders(params url.Values) ([]GDAXOrderResponse, error) {
+	resp, _, err := g.GetOrdersPage(params)
+	return resp, err
+}
+
+//GetOrdersPage returns a page of orders along with the CB-AFTER cursor, which
+//is passed as the after parameter to request the next, older page
+func (g *GDAX) GetOrdersPage(params url.Values) ([]GDAXOrderResponse, string, error) {
+	path := common.EncodeURLValues(g.GetAPIUrl(GDAX_API_URL)+GDAX_ORDERS, params)
+	resp := []GDAXOrderResponse{}
+	headers, err := g.sendAuthenticatedHTTPRequest("GET", common.GetURIPath(path), nil, &resp)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return resp, nil
+	return resp, headers.Get("CB-AFTER"), nil
 }
 
 func (g *GDAX) GetOrder(orderID string) (GDAXOrderResponse, error) {
@@ -322,8 +368,8 @@ func (g *GDAX) GetOrder(orderID string) (GDAXOrderResponse, error) {
 	return resp, nil
 }
 
-func (g *GDAX) GetFills(params url.Values) ([]GDAXFillResponse, error) {
-	path := common.EncodeURLValues(GDAX_API_URL+GDAX_FILLS, params)
+func (g *GDAX) GetFillHistory(params url.Values) ([]GDAXFillResponse, error) {
+	path := common.EncodeURLValues(g.GetAPIUrl(GDAX_API_URL)+GDAX_FILLS, params)
 	resp := []GDAXFillResponse{}
 	err := g.SendAuthenticatedHTTPRequest("GET", common.GetURIPath(path), nil, &resp)
 	if err != nil {
@@ -369,7 +415,13 @@ func (g *GDAX) GetReportStatus(reportID string) (GDAXReportResponse, error) {
 	return resp, nil
 }
 
-func (g *GDAX) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) (err error) {
+func (g *GDAX) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) error {
+	_, err := g.sendAuthenticatedHTTPRequest(method, path, params, result)
+	return err
+}
+
+func (g *GDAX) sendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) (responseHeaders http.Header, err error) {
+	g.WaitRateLimit(true)
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 
 	payload := []byte("")
@@ -378,7 +430,7 @@ func (g *GDAX) SendAuthenticatedHTTPRequest(method, path string, params map[stri
 		payload, err = common.JSONEncode(params)
 
 		if err != nil {
-			return errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
+			return nil, errors.New("SendAuthenticatedHTTPRequest: Unable to JSON request")
 		}
 
 		if g.Verbose {
@@ -395,17 +447,26 @@ func (g *GDAX) SendAuthenticatedHTTPRequest(method, path string, params map[stri
 	headers["CB-ACCESS-PASSPHRASE"] = g.ClientID
 	headers["Content-Type"] = "application/json"
 
-	resp, err := common.SendHTTPRequest(method, GDAX_API_URL+path, headers, bytes.NewBuffer(payload))
+	resp, responseHeaders, err := g.SendHTTPRequestWithHeaders(method, g.GetAPIUrl(GDAX_API_URL)+path, headers, bytes.NewBuffer(payload))
 
 	if g.Verbose {
 		log.Printf("Recieved raw: \n%s\n", resp)
 	}
 
+	errResponse := GDAXErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.Message != "" {
+		return nil, exchange.NewExchangeError(g.Name, errors.New(errResponse.Message), resp, gdaxErrorRules...)
+	}
+
+	if err != nil {
+		return nil, exchange.NewExchangeError(g.Name, err, resp, gdaxErrorRules...)
+	}
+
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return nil, exchange.NewExchangeError(g.Name, errors.New("Unable to JSON Unmarshal response."), resp)
 	}
 
-	return nil
+	return responseHeaders, nil
 }
@@ -0,0 +1,66 @@
+package gdax
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+func TestGetOrderHistory(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("status") != "done" || r.Form.Get("limit") != strconv.Itoa(GDAX_ORDERS_LIMIT) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"Invalid request"}`))
+			return
+		}
+		requests = append(requests, r.Form.Get("after"))
+
+		order := `{"id":"%d","size":"1","price":"100","status":"done","done_reason":"filled","filled_size":"1","product_id":"BTC-USD","side":"buy","type":"limit","created_at":"%s"}`
+		orders := []string{}
+		switch r.Form.Get("after") {
+		case "":
+			for i := 1; i <= GDAX_ORDERS_LIMIT; i++ {
+				orders = append(orders, fmt.Sprintf(order, i, "2017-01-02T00:00:00Z"))
+			}
+			w.Header().Set("CB-AFTER", "100")
+		case "100":
+			for i := 101; i <= 100+GDAX_ORDERS_LIMIT; i++ {
+				orders = append(orders, fmt.Sprintf(order, i, "2017-01-01T00:00:00Z"))
+			}
+			w.Header().Set("CB-AFTER", "200")
+		default:
+			orders = append(orders, fmt.Sprintf(order, 201, "2016-12-31T00:00:00Z"))
+		}
+		w.Write([]byte(`[` + strings.Join(orders, ",") + `]`))
+	}))
+	defer server.Close()
+
+	g := GDAX{}
+	g.SetDefaults()
+	g.APIUrl = server.URL + "/"
+	g.SetRateLimit(0, 0)
+
+	orders, err := g.GetOrderHistory(exchange.OrderFilter{})
+	if err != nil || len(orders) != 2*GDAX_ORDERS_LIMIT+1 {
+		t.Fatalf("Test Failed - GetOrderHistory() expected %d orders, received %d %v", 2*GDAX_ORDERS_LIMIT+1, len(orders), err)
+	}
+
+	expected := []string{"", "100", "200"}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("Test Failed - GetOrderHistory() expected cursors %v, received %v", expected, requests)
+	}
+
+	requests = nil
+	_, err = g.GetOrderHistory(exchange.OrderFilter{StartTime: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)})
+	if err != nil || strings.Join(requests, ",") != ",100" {
+		t.Errorf("Test Failed - GetOrderHistory() expected paging to stop at the start time, received cursors %v %v", requests, err)
+	}
+}
@@ -104,19 +104,6 @@ type GDAXAccountHolds struct {
 	Reference string  `json:"ref"`
 }
 
-type GDAXOrdersResponse struct {
-	ID         string  `json:"id"`
-	Size       float64 `json:"size,string"`
-	Price      float64 `json:"price,string"`
-	ProductID  string  `json:"product_id"`
-	Status     string  `json:"status"`
-	FilledSize float64 `json:"filled_size,string"`
-	FillFees   float64 `json:"fill_fees,string"`
-	Settled    bool    `json:"settled"`
-	Side       string  `json:"side"`
-	CreatedAt  string  `json:"created_at"`
-}
-
 type GDAXOrderResponse struct {
 	ID         string  `json:"id"`
 	Size       float64 `json:"size,string"`
@@ -128,6 +115,7 @@ type GDAXOrderResponse struct {
 	ProductID  string  `json:"product_id"`
 	FillFees   float64 `json:"fill_fees,string"`
 	Side       string  `json:"side"`
+	Type       string  `json:"type"`
 	CreatedAt  string  `json:"created_at"`
 	DoneAt     string  `json:"done_at"`
 }
@@ -165,19 +153,21 @@ type GDAXWebsocketSubscribe struct {
 }
 
 type GDAXWebsocketReceived struct {
-	Type     string  `json:"type"`
-	Time     string  `json:"time"`
-	Sequence int     `json:"sequence"`
-	OrderID  string  `json:"order_id"`
-	Size     float64 `json:"size,string"`
-	Price    float64 `json:"price,string"`
-	Side     string  `json:"side"`
+	Type      string  `json:"type"`
+	Time      string  `json:"time"`
+	Sequence  int     `json:"sequence"`
+	ProductID string  `json:"product_id"`
+	OrderID   string  `json:"order_id"`
+	Size      float64 `json:"size,string"`
+	Price     float64 `json:"price,string"`
+	Side      string  `json:"side"`
 }
 
 type GDAXWebsocketOpen struct {
 	Type          string  `json:"type"`
 	Time          string  `json:"time"`
 	Sequence      int     `json:"sequence"`
+	ProductID     string  `json:"product_id"`
 	OrderID       string  `json:"order_id"`
 	Price         float64 `json:"price,string"`
 	RemainingSize float64 `json:"remaining_size,string"`
@@ -188,6 +178,7 @@ type GDAXWebsocketDone struct {
 	Type          string  `json:"type"`
 	Time          string  `json:"time"`
 	Sequence      int     `json:"sequence"`
+	ProductID     string  `json:"product_id"`
 	Price         float64 `json:"price,string"`
 	OrderID       string  `json:"order_id"`
 	Reason        string  `json:"reason"`
@@ -199,6 +190,7 @@ type GDAXWebsocketMatch struct {
 	Type         string  `json:"type"`
 	TradeID      int     `json:"trade_id"`
 	Sequence     int     `json:"sequence"`
+	ProductID    string  `json:"product_id"`
 	MakerOrderID string  `json:"maker_order_id"`
 	TakerOrderID string  `json:"taker_order_id"`
 	Time         string  `json:"time"`
@@ -208,12 +200,17 @@ type GDAXWebsocketMatch struct {
 }
 
 type GDAXWebsocketChange struct {
-	Type     string  `json:"type"`
-	Time     string  `json:"time"`
-	Sequence int     `json:"sequence"`
-	OrderID  string  `json:"order_id"`
-	NewSize  float64 `json:"new_size,string"`
-	OldSize  float64 `json:"old_size,string"`
-	Price    float64 `json:"price,string"`
-	Side     string  `json:"side"`
+	Type      string  `json:"type"`
+	Time      string  `json:"time"`
+	Sequence  int     `json:"sequence"`
+	ProductID string  `json:"product_id"`
+	OrderID   string  `json:"order_id"`
+	NewSize   float64 `json:"new_size,string"`
+	OldSize   float64 `json:"old_size,string"`
+	Price     float64 `json:"price,string"`
+	Side      string  `json:"side"`
+}
+
+type GDAXErrorResponse struct {
+	Message string `json:"message"`
 }
@@ -2,124 +2,129 @@ package gdax
 
 import (
 	"log"
-	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 const (
 	GDAX_WEBSOCKET_URL = "wss://ws-feed.gdax.com"
 )
 
-func (g *GDAX) WebsocketSubscribe(product string, conn *websocket.Conn) error {
+func (g *GDAX) WebsocketSubscribe(product string) error {
 	subscribe := GDAXWebsocketSubscribe{"subscribe", product}
-	json, err := common.JSONEncode(subscribe)
-	if err != nil {
-		return err
-	}
-
-	err = conn.WriteMessage(websocket.TextMessage, json)
+	return g.WebsocketConn.SendJSON(subscribe)
+}
 
-	if err != nil {
-		return err
+//WebsocketProcessMatch stores a full channel match as a trade. The match side
+//is the maker side, so the taker side is the opposite
+func (g *GDAX) WebsocketProcessMatch(match GDAXWebsocketMatch) {
+	trade := trades.Trade{
+		Pair:    pair.NewCurrencyPairDelimiter(match.ProductID, "-"),
+		TradeID: strconv.Itoa(match.TradeID),
+		Side:    trades.TRADE_SIDE_SELL,
+		Price:   match.Price,
+		Amount:  match.Size,
+	}
+	if match.Side == "sell" {
+		trade.Side = trades.TRADE_SIDE_BUY
 	}
-	return nil
+	trade.Timestamp, _ = time.Parse(time.RFC3339Nano, match.Time)
+	trades.ProcessTrades(g.GetName(), trade.Pair, []trades.Trade{trade})
 }
 
 func (g *GDAX) WebsocketClient() {
-	for g.Enabled && g.Websocket {
-		var Dialer websocket.Dialer
-		conn, _, err := Dialer.Dial(GDAX_WEBSOCKET_URL, http.Header{})
+	g.WebsocketConn.URL = g.GetWebsocketURL(GDAX_WEBSOCKET_URL)
+	g.WebsocketConn.Verbose = g.Verbose
 
+	for _, x := range g.EnabledPairs {
+		err := g.WebsocketConn.Subscribe(exchange.WebsocketSubscription{Channel: x[0:3] + "-" + x[3:]})
 		if err != nil {
-			log.Printf("%s Unable to connect to Websocket. Error: %s\n", g.GetName(), err)
-			continue
+			log.Printf("%s Websocket subscription error: %s\n", g.GetName(), err)
 		}
+	}
+	g.WebsocketConn.Run(g.GetWebsocketContext())
+}
 
-		log.Printf("%s Connected to Websocket.\n", g.GetName())
+//WebsocketHandleMessage routes a full channel message to the level 3 orderbook
+//and trade handlers
+func (g *GDAX) WebsocketHandleMessage(msgType int, resp []byte) {
+	switch msgType {
+	case websocket.TextMessage:
+		type MsgType struct {
+			Type string `json:"type"`
+		}
 
-		currencies := []string{}
-		for _, x := range g.EnabledPairs {
-			currency := x[0:3] + "-" + x[3:]
-			currencies = append(currencies, currency)
+		msgType := MsgType{}
+		err := common.JSONDecode(resp, &msgType)
+		if err != nil {
+			log.Println(err)
+			return
 		}
 
-		for _, x := range currencies {
-			err = g.WebsocketSubscribe(x, conn)
+		switch msgType.Type {
+		case "error":
+			log.Println(string(resp))
+			break
+		case "received":
+			received := GDAXWebsocketReceived{}
+			err := common.JSONDecode(resp, &received)
 			if err != nil {
-				log.Printf("%s Websocket subscription error: %s\n", g.GetName(), err)
-				continue
+				log.Println(err)
+				return
 			}
-		}
-
-		if g.Verbose {
-			log.Printf("%s Subscribed to product messages.", g.GetName())
-		}
-
-		for g.Enabled && g.Websocket {
-			msgType, resp, err := conn.ReadMessage()
+			g.WebsocketProcessBook(received.ProductID, GDAXBookMessage{Type: msgType.Type, Sequence: int64(received.Sequence), OrderID: received.OrderID})
+		case "open":
+			open := GDAXWebsocketOpen{}
+			err := common.JSONDecode(resp, &open)
 			if err != nil {
 				log.Println(err)
-				break
+				return
 			}
-
-			switch msgType {
-			case websocket.TextMessage:
-				type MsgType struct {
-					Type string `json:"type"`
-				}
-
-				msgType := MsgType{}
-				err := common.JSONDecode(resp, &msgType)
-				if err != nil {
-					log.Println(err)
-					continue
-				}
-
-				switch msgType.Type {
-				case "error":
-					log.Println(string(resp))
-					break
-				case "received":
-					received := GDAXWebsocketReceived{}
-					err := common.JSONDecode(resp, &received)
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-				case "open":
-					open := GDAXWebsocketOpen{}
-					err := common.JSONDecode(resp, &open)
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-				case "done":
-					done := GDAXWebsocketDone{}
-					err := common.JSONDecode(resp, &done)
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-				case "match":
-					match := GDAXWebsocketMatch{}
-					err := common.JSONDecode(resp, &match)
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-				case "change":
-					change := GDAXWebsocketChange{}
-					err := common.JSONDecode(resp, &change)
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-				}
+			g.WebsocketProcessBook(open.ProductID, GDAXBookMessage{Type: msgType.Type, Sequence: int64(open.Sequence), OrderID: open.OrderID,
+				Side: open.Side, Price: open.Price, Size: open.RemainingSize})
+		case "done":
+			done := GDAXWebsocketDone{}
+			err := common.JSONDecode(resp, &done)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			g.WebsocketProcessBook(done.ProductID, GDAXBookMessage{Type: msgType.Type, Sequence: int64(done.Sequence), OrderID: done.OrderID})
+		case "match":
+			match := GDAXWebsocketMatch{}
+			err := common.JSONDecode(resp, &match)
+			if err != nil {
+				log.Println(err)
+				return
 			}
+			g.WebsocketProcessBook(match.ProductID, GDAXBookMessage{Type: msgType.Type, Sequence: int64(match.Sequence), OrderID: match.MakerOrderID,
+				Size: match.Size})
+			g.WebsocketProcessMatch(match)
+		case "change":
+			change := GDAXWebsocketChange{}
+			err := common.JSONDecode(resp, &change)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			g.WebsocketProcessBook(change.ProductID, GDAXBookMessage{Type: msgType.Type, Sequence: int64(change.Sequence), OrderID: change.OrderID,
+				Size: change.NewSize})
 		}
-		conn.Close()
-		log.Printf("%s Websocket client disconnected.", g.GetName())
 	}
 }
+
+func (g *GDAX) newWebsocketConnection() *exchange.WebsocketConnection {
+	conn := exchange.NewWebsocketConnection(g.GetName(), GDAX_WEBSOCKET_URL)
+	conn.OnSubscribe = func(subscription exchange.WebsocketSubscription) error {
+		return g.WebsocketSubscribe(subscription.Channel)
+	}
+	conn.OnMessage = g.WebsocketHandleMessage
+	conn.OnDisconnect = g.WebsocketResetBooks
+	return conn
+}
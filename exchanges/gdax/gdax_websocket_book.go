@@ -0,0 +1,261 @@
+package gdax
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+)
+
+const (
+	GDAX_WEBSOCKET_MAX_PENDING = 10000
+)
+
+const (
+	ErrGDAXBookSequenceGap = "%s %s level 3 orderbook sequence gap, expected %d received %d."
+	ErrGDAXBookNotSynced   = "Level 3 orderbook is not synced."
+)
+
+var bookMtx sync.Mutex
+
+//GDAXBookMessage : Full channel message reduced to its effect on a resting
+//order. Size is the opened size for open messages, the matched size for match
+//messages and the new size for change messages
+type GDAXBookMessage struct {
+	Type     string
+	Sequence int64
+	OrderID  string
+	Side     string
+	Price    float64
+	Size     float64
+}
+
+//GDAXBook : Order by order level 3 orderbook of a product, seeded from the REST
+//level 3 orderbook and kept up to date by the full channel
+type GDAXBook struct {
+	ProductID string
+	Sequence  int64
+	orders    map[string]gdaxBookOrder
+	synced    bool
+	fetching  bool
+	pending   []GDAXBookMessage
+	depth     *orderbook.Depth
+}
+
+type gdaxBookOrder struct {
+	GDAXOrderL3
+	Bid bool
+}
+
+//GDAXOrdersByPrice : Sorts level 3 orders by ascending price
+type GDAXOrdersByPrice []GDAXOrderL3
+
+func (o GDAXOrdersByPrice) Len() int {
+	return len(o)
+}
+
+func (o GDAXOrdersByPrice) Less(i, j int) bool {
+	return o[i].Price < o[j].Price
+}
+
+func (o GDAXOrdersByPrice) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+}
+
+//WebsocketGetBook returns the level 3 orderbook of the product as sorted bids
+//and asks
+func (g *GDAX) WebsocketGetBook(productID string) (GDAXOrderbookL3, error) {
+	bookMtx.Lock()
+	defer bookMtx.Unlock()
+
+	book, ok := g.books[productID]
+	if !ok || !book.synced {
+		return GDAXOrderbookL3{}, errors.New(ErrGDAXBookNotSynced)
+	}
+
+	result := GDAXOrderbookL3{Sequence: book.Sequence}
+	for _, x := range book.orders {
+		if x.Bid {
+			result.Bids = append(result.Bids, x.GDAXOrderL3)
+		} else {
+			result.Asks = append(result.Asks, x.GDAXOrderL3)
+		}
+	}
+	sort.Stable(sort.Reverse(GDAXOrdersByPrice(result.Bids)))
+	sort.Stable(GDAXOrdersByPrice(result.Asks))
+	return result, nil
+}
+
+//WebsocketProcessBook applies a full channel message to the level 3 orderbook of
+//its product. Messages received while the orderbook is fetched are queued, and
+//a sequence gap drops the orderbook and fetches it again
+func (g *GDAX) WebsocketProcessBook(productID string, message GDAXBookMessage) {
+	bookMtx.Lock()
+	defer bookMtx.Unlock()
+
+	book := g.getBook(productID)
+	if !book.synced {
+		g.queueBookMessage(book, message)
+		return
+	}
+
+	if message.Sequence <= book.Sequence {
+		return
+	}
+
+	if message.Sequence != book.Sequence+1 {
+		log.Printf(ErrGDAXBookSequenceGap, g.GetName(), productID, book.Sequence+1, message.Sequence)
+		g.desyncBook(book)
+		g.queueBookMessage(book, message)
+		return
+	}
+	g.applyBookMessage(book, message)
+}
+
+//WebsocketResetBooks drops every level 3 orderbook, so they are fetched again
+//once the websocket reconnects
+func (g *GDAX) WebsocketResetBooks() {
+	bookMtx.Lock()
+	defer bookMtx.Unlock()
+
+	for _, x := range g.books {
+		g.desyncBook(x)
+		x.pending = nil
+	}
+}
+
+func (g *GDAX) getBook(productID string) *GDAXBook {
+	if g.books == nil {
+		g.books = make(map[string]*GDAXBook)
+	}
+
+	book, ok := g.books[productID]
+	if !ok {
+		p := pair.NewCurrencyPairDelimiter(productID, "-")
+		book = &GDAXBook{ProductID: productID, depth: orderbook.GetDepth(g.GetName(), p, nil)}
+		g.books[productID] = book
+	}
+	return book
+}
+
+func (g *GDAX) desyncBook(book *GDAXBook) {
+	book.synced = false
+	orderbook.RemoveOrderbook(g.GetName(), book.depth.Pair)
+}
+
+func (g *GDAX) queueBookMessage(book *GDAXBook, message GDAXBookMessage) {
+	if len(book.pending) >= GDAX_WEBSOCKET_MAX_PENDING {
+		book.pending = book.pending[1:]
+	}
+	book.pending = append(book.pending, message)
+
+	if book.fetching {
+		return
+	}
+	book.fetching = true
+
+	go func() {
+		response, err := g.GetOrderbook(book.ProductID, 3)
+
+		bookMtx.Lock()
+		defer bookMtx.Unlock()
+		book.fetching = false
+
+		if err != nil {
+			log.Printf("%s %s unable to fetch level 3 orderbook. Error: %s\n", g.GetName(), book.ProductID, err)
+			return
+		}
+		g.loadBook(book, response.(GDAXOrderbookL3))
+	}()
+}
+
+func (g *GDAX) loadBook(book *GDAXBook, snapshot GDAXOrderbookL3) {
+	book.orders = make(map[string]gdaxBookOrder)
+	book.Sequence = snapshot.Sequence
+
+	bids := make(map[float64]float64)
+	for _, x := range snapshot.Bids {
+		book.orders[x.OrderID] = gdaxBookOrder{GDAXOrderL3: x, Bid: true}
+		bids[x.Price] += x.Amount
+	}
+
+	asks := make(map[float64]float64)
+	for _, x := range snapshot.Asks {
+		book.orders[x.OrderID] = gdaxBookOrder{GDAXOrderL3: x}
+		asks[x.Price] += x.Amount
+	}
+
+	levels := orderbook.OrderbookBase{Sequence: snapshot.Sequence}
+	for price, amount := range bids {
+		levels.Bids = append(levels.Bids, orderbook.OrderbookItem{Price: price, Amount: amount})
+	}
+	for price, amount := range asks {
+		levels.Asks = append(levels.Asks, orderbook.OrderbookItem{Price: price, Amount: amount})
+	}
+
+	err := book.depth.LoadSnapshot(levels)
+	if err != nil {
+		log.Println(err)
+	}
+	book.synced = true
+
+	pending := book.pending
+	book.pending = nil
+	for _, x := range pending {
+		if x.Sequence <= book.Sequence {
+			continue
+		}
+
+		if x.Sequence != book.Sequence+1 {
+			log.Printf(ErrGDAXBookSequenceGap, g.GetName(), book.ProductID, book.Sequence+1, x.Sequence)
+			g.desyncBook(book)
+			g.queueBookMessage(book, x)
+			return
+		}
+		g.applyBookMessage(book, x)
+	}
+}
+
+func (g *GDAX) applyBookMessage(book *GDAXBook, message GDAXBookMessage) {
+	book.Sequence = message.Sequence
+	order, ok := book.orders[message.OrderID]
+	delta := float64(0)
+
+	switch message.Type {
+	case "open":
+		order = gdaxBookOrder{GDAXOrderL3: GDAXOrderL3{OrderID: message.OrderID, Price: message.Price, Amount: message.Size}, Bid: message.Side == "buy"}
+		book.orders[message.OrderID] = order
+		ok = true
+		delta = message.Size
+	case "done":
+		if ok {
+			delete(book.orders, message.OrderID)
+			delta = -order.Amount
+		}
+	case "match":
+		if ok {
+			order.Amount -= message.Size
+			book.orders[message.OrderID] = order
+			delta = -message.Size
+		}
+	case "change":
+		if ok {
+			delta = message.Size - order.Amount
+			order.Amount = message.Size
+			book.orders[message.OrderID] = order
+		}
+	}
+
+	updates := []orderbook.DepthUpdate{}
+	if ok && delta != 0 {
+		updates = append(updates, orderbook.DepthUpdate{Bid: order.Bid, Action: orderbook.DEPTH_ACTION_CHANGE, Price: order.Price, Amount: delta})
+	}
+
+	err := book.depth.Apply(message.Sequence, updates)
+	if err != nil {
+		log.Printf("%s %s aggregated orderbook error: %s\n", g.GetName(), book.ProductID, err)
+	}
+}
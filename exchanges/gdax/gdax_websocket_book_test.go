@@ -0,0 +1,45 @@
+package gdax
+
+import (
+	"testing"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+)
+
+func TestWebsocketProcessBook(t *testing.T) {
+	g := GDAX{}
+	g.SetDefaults()
+
+	book := g.getBook("BTC-USD")
+	g.loadBook(book, GDAXOrderbookL3{
+		Sequence: 100,
+		Bids:     []GDAXOrderL3{{OrderID: "a", Price: 99, Amount: 1}, {OrderID: "b", Price: 100, Amount: 2}, {OrderID: "c", Price: 99, Amount: 3}},
+		Asks:     []GDAXOrderL3{{OrderID: "d", Price: 101, Amount: 1}},
+	})
+
+	g.WebsocketProcessBook("BTC-USD", GDAXBookMessage{Type: "received", Sequence: 101, OrderID: "e"})
+	g.WebsocketProcessBook("BTC-USD", GDAXBookMessage{Type: "open", Sequence: 102, OrderID: "e", Side: "sell", Price: 102, Size: 4})
+	g.WebsocketProcessBook("BTC-USD", GDAXBookMessage{Type: "match", Sequence: 103, OrderID: "b", Size: 0.5})
+	g.WebsocketProcessBook("BTC-USD", GDAXBookMessage{Type: "change", Sequence: 104, OrderID: "c", Size: 2})
+	g.WebsocketProcessBook("BTC-USD", GDAXBookMessage{Type: "done", Sequence: 105, OrderID: "d"})
+	g.WebsocketProcessBook("BTC-USD", GDAXBookMessage{Type: "done", Sequence: 104, OrderID: "e"})
+
+	result, err := g.WebsocketGetBook("BTC-USD")
+	if err != nil {
+		t.Fatalf("Test Failed - GDAX WebsocketGetBook error: %s", err)
+	}
+
+	if result.Sequence != 105 || len(result.Bids) != 3 || len(result.Asks) != 1 || result.Bids[0].Amount != 1.5 || result.Asks[0].OrderID != "e" {
+		t.Errorf("Test Failed - GDAX level 3 orderbook is incorrect: %+v", result)
+	}
+
+	aggregated, err := orderbook.GetOrderbook(g.GetName(), pair.NewCurrencyPairDelimiter("BTC-USD", "-"))
+	if err != nil {
+		t.Fatalf("Test Failed - GDAX aggregated orderbook error: %s", err)
+	}
+
+	if len(aggregated.Bids) != 2 || aggregated.Bids[1].Amount != 3 || len(aggregated.Asks) != 1 || aggregated.Asks[0].Price != 102 {
+		t.Errorf("Test Failed - GDAX aggregated orderbook is incorrect: %+v", aggregated)
+	}
+}
@@ -1,7 +1,11 @@
 package gdax
 
 import (
+	"errors"
 	"log"
+	"net/url"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -10,6 +14,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (g *GDAX) Start() {
@@ -18,7 +23,7 @@ func (g *GDAX) Start() {
 
 func (g *GDAX) Run() {
 	if g.Verbose {
-		log.Printf("%s Websocket: %s. (url: %s).\n", g.GetName(), common.IsEnabled(g.Websocket), GDAX_WEBSOCKET_URL)
+		log.Printf("%s Websocket: %s. (url: %s).\n", g.GetName(), common.IsEnabled(g.Websocket), g.GetWebsocketURL(GDAX_WEBSOCKET_URL))
 		log.Printf("%s polling delay: %ds.\n", g.GetName(), g.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", g.GetName(), len(g.EnabledPairs), g.EnabledPairs)
 	}
@@ -43,11 +48,16 @@ func (g *GDAX) Run() {
 		}
 	}
 
+	err = g.UpdatePairInfo()
+	if err != nil {
+		log.Printf("%s Failed to get pair info.\n", g.GetName())
+	}
+
 	for g.Enabled {
 		for _, x := range g.EnabledPairs {
 			currency := pair.NewCurrencyPair(x[0:3], x[3:])
 			currency.Delimiter = "-"
-			go func() {
+			g.Poll(x, func() {
 				ticker, err := g.GetTickerPrice(currency)
 
 				if err != nil {
@@ -56,7 +66,7 @@ func (g *GDAX) Run() {
 				}
 				log.Printf("GDAX %s: Last %f High %f Low %f Volume %f\n", currency.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(g.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * g.RESTPollingDelay)
 	}
@@ -127,9 +137,302 @@ func (g *GDAX) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase, err
 	}
 
 	for x, _ := range obNew.Asks {
-		orderBook.Asks = append(orderBook.Asks, orderbook.OrderbookItem{Amount: obNew.Bids[x].Amount, Price: obNew.Bids[x].Price})
+		orderBook.Asks = append(orderBook.Asks, orderbook.OrderbookItem{Amount: obNew.Asks[x].Amount, Price: obNew.Asks[x].Price})
 	}
 	orderBook.Pair = p
+	orderBook.Sequence = obNew.Sequence
 	orderbook.ProcessOrderbook(g.GetName(), p, orderBook)
 	return orderBook, nil
 }
+
+//SubmitOrder : Places a new order on GDAX
+func (g *GDAX) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := g.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	orderID, err := g.PlaceOrder(order.ClientID, order.Price, order.Amount, common.StringToLower(string(order.Side)),
+		common.StringToLower(string(order.Type)), g.formatProductID(order.Pair), "")
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = g.GetName()
+	result.OrderID = orderID
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (g *GDAX) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	return g.CancelExistingOrder(orderID)
+}
+
+//ModifyOrder : GDAX does not support amending orders
+func (g *GDAX) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (g *GDAX) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	response, err := g.GetOrder(orderID)
+	if err != nil {
+		return detail, err
+	}
+
+	return g.getOrderDetail(response), nil
+}
+
+//GetActiveOrders : Retrieves all open and pending orders matching the filter
+func (g *GDAX) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return g.getOrders([]string{"open", "pending", "active"}, filter)
+}
+
+//GetOrderHistory : Retrieves completed orders matching the filter
+func (g *GDAX) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return g.getOrders([]string{"done"}, filter)
+}
+
+func (g *GDAX) getOrders(status []string, filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	params := url.Values{}
+	for _, x := range status {
+		params.Add("status", x)
+	}
+
+	if len(filter.Pairs) == 1 {
+		params.Set("product_id", g.formatProductID(filter.Pairs[0]))
+	}
+	params.Set("limit", strconv.Itoa(GDAX_ORDERS_LIMIT))
+
+	var orders []exchange.OrderDetail
+	for {
+		response, after, err := g.GetOrdersPage(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, x := range response {
+			orders = append(orders, g.getOrderDetail(x))
+		}
+
+		if after == "" || len(response) < GDAX_ORDERS_LIMIT {
+			break
+		}
+
+		// orders are returned newest first, so the page is older than the
+		// filter range once its last order is
+		oldest := orders[len(orders)-1]
+		if !filter.StartTime.IsZero() && oldest.CreatedAt.Before(filter.StartTime) {
+			break
+		}
+		params.Set("after", after)
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetFills : Retrieves the account's executions matching the filter, following
+//the fills cursor of each product back to the start of the filter range
+func (g *GDAX) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	pairs := filter.Pairs
+	if len(pairs) == 0 {
+		for _, x := range g.EnabledPairs {
+			pairs = append(pairs, pair.NewCurrencyPair(x[0:3], x[3:]))
+		}
+	}
+
+	var fills []exchange.Fill
+	for _, x := range pairs {
+		params := url.Values{}
+		params.Set("product_id", g.formatProductID(x))
+		params.Set("limit", strconv.Itoa(GDAX_FILLS_LIMIT))
+
+		for {
+			response, err := g.GetFillHistory(params)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, y := range response {
+				fills = append(fills, g.getFill(y))
+			}
+
+			if len(response) < GDAX_FILLS_LIMIT {
+				break
+			}
+
+			// fills are returned newest first, so the last entry is the
+			// cursor for the next page
+			oldest := g.getFill(response[len(response)-1])
+			if !filter.StartTime.IsZero() && oldest.Timestamp.Before(filter.StartTime) {
+				break
+			}
+			params.Set("after", oldest.TradeID)
+		}
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+func (g *GDAX) getFill(response GDAXFillResponse) exchange.Fill {
+	var fill exchange.Fill
+	fill.Exchange = g.GetName()
+	fill.TradeID = strconv.Itoa(response.TradeID)
+	fill.OrderID = response.OrderID
+	if common.StringContains(response.ProductID, "-") {
+		fill.Pair = pair.NewCurrencyPairDelimiter(response.ProductID, "-")
+		fill.FeeCurrency = fill.Pair.GetSecondCurrency().String()
+	}
+	fill.Side = exchange.OrderSide(common.StringToUpper(response.Side))
+	fill.Price = response.Price
+	fill.Amount = response.Size
+	fill.Fee = response.Fee
+	fill.Timestamp, _ = time.Parse(time.RFC3339Nano, response.CreatedAt)
+	return fill
+}
+
+func (g *GDAX) getOrderDetail(response GDAXOrderResponse) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = g.GetName()
+	detail.OrderID = response.ID
+	if common.StringContains(response.ProductID, "-") {
+		detail.Pair = pair.NewCurrencyPairDelimiter(response.ProductID, "-")
+	}
+	detail.Side = exchange.OrderSide(common.StringToUpper(response.Side))
+	detail.Type = exchange.OrderTypeLimit
+	if response.Type == "market" {
+		detail.Type = exchange.OrderTypeMarket
+	}
+	detail.Price = response.Price
+	detail.Amount = response.Size
+	detail.FilledAmount = response.FilledSize
+	detail.CreatedAt, _ = time.Parse(time.RFC3339Nano, response.CreatedAt)
+	detail.UpdatedAt, _ = time.Parse(time.RFC3339Nano, response.DoneAt)
+
+	switch response.Status {
+	case "pending", "open", "active":
+		detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, true, false)
+	case "done":
+		if response.DoneReason == "canceled" {
+			detail.Status = exchange.OrderStatusCancelled
+		} else {
+			detail.Status = exchange.OrderStatusFilled
+		}
+	case "rejected":
+		detail.Status = exchange.OrderStatusRejected
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail
+}
+
+func (g *GDAX) formatProductID(p pair.CurrencyPair) string {
+	return common.StringToUpper(p.GetFirstCurrency().String()) + "-" + common.StringToUpper(p.GetSecondCurrency().String())
+}
+
+//GetDepositAddress : Not supported, GDAX only funds accounts through linked Coinbase accounts
+func (g *GDAX) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(g.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Not supported, GDAX only withdraws to linked Coinbase accounts
+func (g *GDAX) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	return exchange.WithdrawResult{}, exchange.NewFundingError(g.GetName(), request.Currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//UpdatePairInfo : Populates the pair metadata table from the GDAX products
+func (g *GDAX) UpdatePairInfo() error {
+	products, err := g.GetProducts()
+	if err != nil {
+		return err
+	}
+
+	var info []exchange.PairInfo
+	for _, x := range products {
+		if x.BaseCurrency == "" || x.QuoteCurrency == "" {
+			continue
+		}
+		info = append(info, exchange.PairInfo{
+			Pair:       pair.NewCurrencyPair(x.BaseCurrency, x.QuoteCurrency),
+			MinAmount:  x.BaseMinSize,
+			MaxAmount:  float64(x.BaseMaxSize),
+			AmountStep: exchange.GetPrecisionStep(8),
+			PriceTick:  x.QuoteIncrement,
+			Status:     exchange.PairStatusTrading,
+		})
+	}
+	g.SetPairInfo(info)
+	return nil
+}
+
+//GetHistoricCandles : Retrieves candles for a product, splitting long ranges
+//into windows of at most GDAX_HISTORY_LIMIT candles
+func (g *GDAX) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	err := exchange.ValidateCandleRange(start, end, interval)
+	if err != nil {
+		return exchange.CandleSeries{}, err
+	}
+
+	granularity := int64(interval / time.Second)
+	switch granularity {
+	case 60, 300, 900, 3600, 21600, 86400:
+	default:
+		return exchange.CandleSeries{}, errors.New(exchange.ErrCandleIntervalNotSupported)
+	}
+
+	var candles []exchange.Candle
+	window := interval * GDAX_HISTORY_LIMIT
+	for windowStart := start; windowStart.Before(end); windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		history, err := g.GetHistoricRates(g.formatProductID(p), windowStart.Unix(), windowEnd.Unix(), granularity)
+		if err != nil {
+			return exchange.CandleSeries{}, err
+		}
+
+		for _, x := range history {
+			candles = append(candles, exchange.Candle{
+				Time:   time.Unix(x.Time, 0),
+				Open:   x.Open,
+				High:   x.High,
+				Low:    x.Low,
+				Close:  x.Close,
+				Volume: x.Volume,
+			})
+		}
+	}
+	return exchange.NewCandleSeries(g.GetName(), p, start, end, interval, candles), nil
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a product. GDAX
+//reports the maker side, so the taker side is the opposite
+func (g *GDAX) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := g.GetTrades(g.formatProductID(p))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:    p,
+			TradeID: strconv.FormatInt(x.TradeID, 10),
+			Side:    trades.TRADE_SIDE_SELL,
+			Price:   x.Price,
+			Amount:  x.Size,
+		}
+		if x.Side == "sell" {
+			trade.Side = trades.TRADE_SIDE_BUY
+		}
+		trade.Timestamp, _ = time.Parse(time.RFC3339Nano, x.Time)
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(g.GetName(), p, result)
+	return result, nil
+}
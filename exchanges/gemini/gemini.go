@@ -7,7 +7,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
@@ -33,8 +32,20 @@ const (
 	GEMINI_MYTRADES             = "mytrades"
 	GEMINI_BALANCES             = "balances"
 	GEMINI_HEARTBEAT            = "heartbeat"
+
+	GEMINI_MYTRADES_LIMIT = 500
+)
+
+const (
+	GEMINI_AUTH_RATE_LIMIT   = 600
+	GEMINI_UNAUTH_RATE_LIMIT = 120
 )
 
+var geminiErrorRules = []exchange.ErrorRule{
+	{Match: "ordernotfound", Kind: exchange.ErrorKindOrderNotFound},
+	{Match: "marketnotopen", Kind: exchange.ErrorKindMarketClosed},
+}
+
 type Gemini struct {
 	exchange.ExchangeBase
 }
@@ -45,6 +56,7 @@ func (g *Gemini) SetDefaults() {
 	g.Verbose = false
 	g.Websocket = false
 	g.RESTPollingDelay = 10
+	g.SetRateLimit(GEMINI_AUTH_RATE_LIMIT, GEMINI_UNAUTH_RATE_LIMIT)
 }
 
 func (g *Gemini) Setup(exch config.ExchangeConfig) {
@@ -55,6 +67,11 @@ func (g *Gemini) Setup(exch config.ExchangeConfig) {
 		g.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		g.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		g.RESTPollingDelay = exch.RESTPollingDelay
+		g.UpdateRateLimit(exch)
+		err := g.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", g.GetName(), err)
+		}
 		g.Verbose = exch.Verbose
 		g.Websocket = exch.Websocket
 		g.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -74,9 +91,9 @@ func (g *Gemini) GetTicker(currency string) (GeminiTicker, error) {
 
 	ticker := GeminiTicker{}
 	resp := TickerResponse{}
-	path := fmt.Sprintf("%s/v%s/%s/%s", GEMINI_API_URL, GEMINI_API_VERSION, GEMINI_TICKER, currency)
+	path := fmt.Sprintf("%s/v%s/%s/%s", g.GetAPIUrl(GEMINI_API_URL), GEMINI_API_VERSION, GEMINI_TICKER, currency)
 
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := g.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return ticker, err
 	}
@@ -96,8 +113,8 @@ func (g *Gemini) GetTicker(currency string) (GeminiTicker, error) {
 
 func (g *Gemini) GetSymbols() ([]string, error) {
 	symbols := []string{}
-	path := fmt.Sprintf("%s/v%s/%s", GEMINI_API_URL, GEMINI_API_VERSION, GEMINI_SYMBOLS)
-	err := common.SendHTTPGetRequest(path, true, &symbols)
+	path := fmt.Sprintf("%s/v%s/%s", g.GetAPIUrl(GEMINI_API_URL), GEMINI_API_VERSION, GEMINI_SYMBOLS)
+	err := g.SendHTTPGetRequest(path, true, &symbols)
 	if err != nil {
 		return nil, err
 	}
@@ -105,9 +122,9 @@ func (g *Gemini) GetSymbols() ([]string, error) {
 }
 
 func (g *Gemini) GetAuction(currency string) (GeminiAuction, error) {
-	path := fmt.Sprintf("%s/v%s/%s/%s", GEMINI_API_URL, GEMINI_API_VERSION, GEMINI_AUCTION, currency)
+	path := fmt.Sprintf("%s/v%s/%s/%s", g.GetAPIUrl(GEMINI_API_URL), GEMINI_API_VERSION, GEMINI_AUCTION, currency)
 	auction := GeminiAuction{}
-	err := common.SendHTTPGetRequest(path, true, &auction)
+	err := g.SendHTTPGetRequest(path, true, &auction)
 	if err != nil {
 		return auction, err
 	}
@@ -115,9 +132,9 @@ func (g *Gemini) GetAuction(currency string) (GeminiAuction, error) {
 }
 
 func (g *Gemini) GetAuctionHistory(currency string, params url.Values) ([]GeminiAuctionHistory, error) {
-	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s/%s", GEMINI_API_URL, GEMINI_API_VERSION, GEMINI_AUCTION, currency, GEMINI_AUCTION_HISTORY), params)
+	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s/%s", g.GetAPIUrl(GEMINI_API_URL), GEMINI_API_VERSION, GEMINI_AUCTION, currency, GEMINI_AUCTION_HISTORY), params)
 	auctionHist := []GeminiAuctionHistory{}
-	err := common.SendHTTPGetRequest(path, true, &auctionHist)
+	err := g.SendHTTPGetRequest(path, true, &auctionHist)
 	if err != nil {
 		return nil, err
 	}
@@ -125,9 +142,9 @@ func (g *Gemini) GetAuctionHistory(currency string, params url.Values) ([]Gemini
 }
 
 func (g *Gemini) GetOrderbook(currency string, params url.Values) (GeminiOrderbook, error) {
-	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s", GEMINI_API_URL, GEMINI_API_VERSION, GEMINI_ORDERBOOK, currency), params)
+	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s", g.GetAPIUrl(GEMINI_API_URL), GEMINI_API_VERSION, GEMINI_ORDERBOOK, currency), params)
 	orderbook := GeminiOrderbook{}
-	err := common.SendHTTPGetRequest(path, true, &orderbook)
+	err := g.SendHTTPGetRequest(path, true, &orderbook)
 	if err != nil {
 		return GeminiOrderbook{}, err
 	}
@@ -136,9 +153,9 @@ func (g *Gemini) GetOrderbook(currency string, params url.Values) (GeminiOrderbo
 }
 
 func (g *Gemini) GetTrades(currency string, params url.Values) ([]GeminiTrade, error) {
-	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s", GEMINI_API_URL, GEMINI_API_VERSION, GEMINI_TRADES, currency), params)
+	path := common.EncodeURLValues(fmt.Sprintf("%s/v%s/%s/%s", g.GetAPIUrl(GEMINI_API_URL), GEMINI_API_VERSION, GEMINI_TRADES, currency), params)
 	trades := []GeminiTrade{}
-	err := common.SendHTTPGetRequest(path, true, &trades)
+	err := g.SendHTTPGetRequest(path, true, &trades)
 	if err != nil {
 		return []GeminiTrade{}, err
 	}
@@ -162,7 +179,7 @@ func (g *Gemini) NewOrder(symbol string, amount, price float64, side, orderType
 	return response.OrderID, nil
 }
 
-func (g *Gemini) CancelOrder(OrderID int64) (GeminiOrder, error) {
+func (g *Gemini) CancelExistingOrder(OrderID int64) (GeminiOrder, error) {
 	request := make(map[string]interface{})
 	request["order_id"] = OrderID
 
@@ -208,11 +225,15 @@ func (g *Gemini) GetOrders() ([]GeminiOrder, error) {
 	return response, nil
 }
 
-func (g *Gemini) GetTradeHistory(symbol string, timestamp int64) ([]GeminiTradeHistory, error) {
+func (g *Gemini) GetTradeHistory(symbol string, timestamp int64, limit int) ([]GeminiTradeHistory, error) {
 	request := make(map[string]interface{})
 	request["symbol"] = symbol
 	request["timestamp"] = timestamp
 
+	if limit > 0 {
+		request["limit_trades"] = limit
+	}
+
 	response := []GeminiTradeHistory{}
 	err := g.SendAuthenticatedHTTPRequest("POST", GEMINI_MYTRADES, request, &response)
 	if err != nil {
@@ -245,9 +266,10 @@ func (g *Gemini) PostHeartbeat() (bool, error) {
 }
 
 func (g *Gemini) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) (err error) {
+	g.WaitRateLimit(true)
 	request := make(map[string]interface{})
 	request["request"] = fmt.Sprintf("/v%s/%s", GEMINI_API_VERSION, path)
-	request["nonce"] = time.Now().UnixNano()
+	request["nonce"] = g.GetNonce().Get()
 
 	if params != nil {
 		for key, value := range params {
@@ -272,16 +294,25 @@ func (g *Gemini) SendAuthenticatedHTTPRequest(method, path string, params map[st
 	headers["X-GEMINI-PAYLOAD"] = PayloadBase64
 	headers["X-GEMINI-SIGNATURE"] = common.HexEncodeToString(hmac)
 
-	resp, err := common.SendHTTPRequest(method, GEMINI_API_URL+path, headers, strings.NewReader(""))
+	resp, err := g.SendHTTPRequest(method, g.GetAPIUrl(GEMINI_API_URL)+path, headers, strings.NewReader(""))
 
 	if g.Verbose {
 		log.Printf("Recieved raw: \n%s\n", resp)
 	}
 
+	errResponse := GeminiErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.Result == "error" {
+		return g.CheckNonceError(exchange.NewExchangeError(g.Name, fmt.Errorf("%s: %s", errResponse.Reason, errResponse.Message), resp, geminiErrorRules...))
+	}
+
+	if err != nil {
+		return g.CheckNonceError(exchange.NewExchangeError(g.Name, err, resp, geminiErrorRules...))
+	}
+
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return g.CheckNonceError(exchange.NewExchangeError(g.Name, errors.New("Unable to JSON Unmarshal response."), resp))
 	}
 
 	return nil
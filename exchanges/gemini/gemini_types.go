@@ -15,7 +15,7 @@ type GeminiTrade struct {
 	TID       int64   `json:"tid"`
 	Price     float64 `json:"price"`
 	Amount    float64 `json:"amount"`
-	Side      string  `json:"taker"`
+	Side      string  `json:"type"`
 }
 
 type GeminiOrder struct {
@@ -42,15 +42,15 @@ type GeminiOrderResult struct {
 }
 
 type GeminiTradeHistory struct {
-	Price         float64 `json:"price"`
-	Amount        float64 `json:"amount"`
+	Price         float64 `json:"price,string"`
+	Amount        float64 `json:"amount,string"`
 	Timestamp     int64   `json:"timestamp"`
 	TimestampMS   int64   `json:"timestampms"`
 	Type          string  `json:"type"`
 	FeeCurrency   string  `json:"fee_currency"`
-	FeeAmount     float64 `json:"fee_amount"`
+	FeeAmount     float64 `json:"fee_amount,string"`
 	TID           int64   `json:"tid"`
-	OrderID       int64   `json:"order_id"`
+	OrderID       int64   `json:"order_id,string"`
 	ClientOrderID string  `json:"client_order_id"`
 }
 
@@ -92,3 +92,9 @@ type GeminiAuctionHistory struct {
 	TimestampMS     int64   `json:"timestampms"`
 	EventType       string  `json:"event_type"`
 }
+
+type GeminiErrorResponse struct {
+	Result  string `json:"result"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
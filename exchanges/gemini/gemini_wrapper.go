@@ -1,15 +1,20 @@
 package gemini
 
 import (
+	"errors"
 	"log"
 	"net/url"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/currency/pair"
 	"github.com/champii/gocryptotrader/exchanges"
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (g *Gemini) Start() {
@@ -35,7 +40,7 @@ func (g *Gemini) Run() {
 	for g.Enabled {
 		for _, x := range g.EnabledPairs {
 			currency := pair.NewCurrencyPair(x[0:3], x[3:])
-			go func() {
+			g.Poll(x, func() {
 				ticker, err := g.GetTickerPrice(currency)
 				if err != nil {
 					log.Println(err)
@@ -43,7 +48,7 @@ func (g *Gemini) Run() {
 				}
 				log.Printf("Gemini %s Last %f Bid %f Ask %f Volume %f\n", currency.Pair().String(), ticker.Last, ticker.Bid, ticker.Ask, ticker.Volume)
 				stats.AddExchangeInfo(g.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * g.RESTPollingDelay)
 	}
@@ -112,3 +117,200 @@ func (g *Gemini) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase, e
 	orderbook.ProcessOrderbook(g.GetName(), p, orderBook)
 	return orderBook, nil
 }
+
+//SubmitOrder : Places a new limit order on Gemini
+func (g *Gemini) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := g.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	orderID, err := g.NewOrder(order.Pair.Pair().Lower().String(), order.Amount, order.Price, common.StringToLower(string(order.Side)), "exchange limit")
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = g.GetName()
+	result.OrderID = strconv.FormatInt(orderID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (g *Gemini) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = g.CancelExistingOrder(id)
+	return err
+}
+
+//ModifyOrder : Gemini does not support amending orders
+func (g *Gemini) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (g *Gemini) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	response, err := g.GetOrderStatus(id)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+	return g.getOrderDetail(response), nil
+}
+
+func (g *Gemini) getOrderDetail(order GeminiOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = g.GetName()
+	detail.OrderID = strconv.FormatInt(order.OrderID, 10)
+	detail.ClientID = order.ClientOrderID
+	if len(order.Symbol) == 6 {
+		detail.Pair = pair.NewCurrencyPair(common.StringToUpper(order.Symbol[0:3]), common.StringToUpper(order.Symbol[3:]))
+	}
+	detail.Side = exchange.OrderSide(common.StringToUpper(order.Side))
+	detail.Type = exchange.OrderTypeLimit
+	detail.Status = exchange.GetOrderStatus(order.OriginalAmount, order.ExecutedAmount, order.IsLive, order.IsCancelled)
+	detail.Price = order.Price
+	detail.Amount = order.OriginalAmount
+	detail.FilledAmount = order.ExecutedAmount
+	detail.AveragePrice = order.AvgExecutionPrice
+	detail.CreatedAt = time.Unix(0, order.TimestampMS*int64(time.Millisecond))
+	return detail
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (g *Gemini) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := g.GetOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range response {
+		orders = append(orders, g.getOrderDetail(x))
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Not supported, Gemini only exposes fills through its trade history
+func (g *Gemini) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetFills : Retrieves the account's executions matching the filter, paging
+//forward through the trade history of each pair
+func (g *Gemini) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	pairs := filter.Pairs
+	if len(pairs) == 0 {
+		for _, x := range g.EnabledPairs {
+			pairs = append(pairs, pair.NewCurrencyPair(x[0:3], x[3:]))
+		}
+	}
+
+	var fills []exchange.Fill
+	for _, x := range pairs {
+		var timestamp int64
+		if !filter.StartTime.IsZero() {
+			timestamp = filter.StartTime.Unix()
+		}
+
+		seen := make(map[int64]bool)
+		for {
+			response, err := g.GetTradeHistory(common.StringToLower(x.GetFirstCurrency().String()+x.GetSecondCurrency().String()), timestamp, GEMINI_MYTRADES_LIMIT)
+			if err != nil {
+				return nil, err
+			}
+
+			added := 0
+			for _, y := range response {
+				if seen[y.TID] {
+					continue
+				}
+				seen[y.TID] = true
+				added++
+
+				if y.Timestamp > timestamp {
+					timestamp = y.Timestamp
+				}
+
+				var fill exchange.Fill
+				fill.Exchange = g.GetName()
+				fill.TradeID = strconv.FormatInt(y.TID, 10)
+				fill.OrderID = strconv.FormatInt(y.OrderID, 10)
+				fill.Pair = x
+				fill.Side = exchange.OrderSide(common.StringToUpper(y.Type))
+				fill.Price = y.Price
+				fill.Amount = y.Amount
+				fill.Fee = y.FeeAmount
+				fill.FeeCurrency = y.FeeCurrency
+				fill.Timestamp = time.Unix(0, y.TimestampMS*int64(time.Millisecond))
+				fills = append(fills, fill)
+			}
+
+			if added == 0 || len(response) < GEMINI_MYTRADES_LIMIT {
+				break
+			}
+		}
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+//GetDepositAddress : Not supported, the Gemini client has no funding endpoints
+func (g *Gemini) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(g.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Not supported, the Gemini client has no funding endpoints
+func (g *Gemini) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	return exchange.WithdrawResult{}, exchange.NewFundingError(g.GetName(), request.Currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//UpdatePairInfo : Not supported, the Gemini symbols endpoint only returns symbol names
+func (g *Gemini) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, Gemini has no candle endpoint
+func (g *Gemini) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair
+func (g *Gemini) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := g.GetTrades(p.Pair().Lower().String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(x.Timestamp, 0),
+		}
+		if x.Side == "sell" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(g.GetName(), p, result)
+	return result, nil
+}
@@ -0,0 +1,195 @@
+package exchange
+
+import (
+	"sync"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+)
+
+const (
+	HEALTH_REST_WINDOW           = 100
+	HEALTH_DEFAULT_STALE_TIMEOUT = 5 * time.Minute
+	HEALTH_MAX_REST_ERROR_RATE   = 0.5
+)
+
+var (
+	healthRecords = make(map[string]*healthRecord)
+	healthMtx     sync.Mutex
+)
+
+//PairHealth : Age of the market data stored for an exchange pair, measured
+//from its latest ticker or orderbook update. Pairs never updated are stale
+type PairHealth struct {
+	Pair             string    `json:"pair"`
+	LastTicker       time.Time `json:"lastTicker"`
+	LastOrderbook    time.Time `json:"lastOrderbook"`
+	StalenessSeconds float64   `json:"stalenessSeconds"`
+	Stale            bool      `json:"stale"`
+}
+
+//ExchangeHealth : REST, websocket and market data health of an exchange. The
+//REST error rate covers the last HEALTH_REST_WINDOW requests
+type ExchangeHealth struct {
+	ExchangeName         string       `json:"exchangeName"`
+	Healthy              bool         `json:"healthy"`
+	LastRESTSuccess      time.Time    `json:"lastRESTSuccess"`
+	LastRESTError        time.Time    `json:"lastRESTError"`
+	LastRESTErrorMessage string       `json:"lastRESTErrorMessage,omitempty"`
+	RESTRequests         int64        `json:"restRequests"`
+	RESTErrors           int64        `json:"restErrors"`
+	RESTErrorRate        float64      `json:"restErrorRate"`
+	Websocket            bool         `json:"websocket"`
+	WebsocketState       string       `json:"websocketState,omitempty"`
+	LastWebsocketMessage time.Time    `json:"lastWebsocketMessage"`
+	WebsocketReconnects  int64        `json:"websocketReconnects"`
+	Pairs                []PairHealth `json:"pairs"`
+}
+
+type healthRecord struct {
+	lastRESTSuccess      time.Time
+	lastRESTError        time.Time
+	lastRESTErrorMessage string
+	restRequests         int64
+	restErrors           int64
+	recentErrors         []bool
+	websocketState       string
+	websocketConnects    int64
+	lastWebsocketMessage time.Time
+}
+
+func getHealthRecord(exchangeName string) *healthRecord {
+	record, ok := healthRecords[exchangeName]
+	if !ok {
+		record = &healthRecord{}
+		healthRecords[exchangeName] = record
+	}
+	return record
+}
+
+//RecordRESTResult records the outcome of a REST request sent by the exchange
+func RecordRESTResult(exchangeName string, err error) {
+	healthMtx.Lock()
+	defer healthMtx.Unlock()
+
+	record := getHealthRecord(exchangeName)
+	record.restRequests++
+	if err != nil {
+		record.restErrors++
+		record.lastRESTError = time.Now()
+		record.lastRESTErrorMessage = err.Error()
+	} else {
+		record.lastRESTSuccess = time.Now()
+	}
+
+	if len(record.recentErrors) >= HEALTH_REST_WINDOW {
+		record.recentErrors = record.recentErrors[1:]
+	}
+	record.recentErrors = append(record.recentErrors, err != nil)
+}
+
+//RecordWebsocketMessage records the arrival of a websocket message
+func RecordWebsocketMessage(exchangeName string) {
+	healthMtx.Lock()
+	getHealthRecord(exchangeName).lastWebsocketMessage = time.Now()
+	healthMtx.Unlock()
+}
+
+//RecordWebsocketState records a websocket state change, writing it to the
+//stream recording of the exchange as well. Every connection after the first
+//one counts as a reconnect
+func RecordWebsocketState(exchangeName, state string) {
+	healthMtx.Lock()
+	record := getHealthRecord(exchangeName)
+	record.websocketState = state
+	if state == WEBSOCKET_STATE_CONNECTED {
+		record.websocketConnects++
+	}
+	healthMtx.Unlock()
+
+	GetStreamRecorder(exchangeName).RecordWebsocketState(state)
+}
+
+//ResetHealth drops the recorded health of the exchange
+func ResetHealth(exchangeName string) {
+	healthMtx.Lock()
+	delete(healthRecords, exchangeName)
+	healthMtx.Unlock()
+}
+
+//GetHealth returns the health of the exchange. Enabled pairs whose ticker and
+//orderbook were not updated within staleTimeout are stale, HEALTH_DEFAULT_STALE_TIMEOUT
+//is used when it is not positive. The exchange is healthy when no pair is
+//stale, its REST error rate is acceptable and its websocket, if enabled, is
+//connected
+func (e *ExchangeBase) GetHealth(staleTimeout time.Duration) ExchangeHealth {
+	if staleTimeout <= 0 {
+		staleTimeout = HEALTH_DEFAULT_STALE_TIMEOUT
+	}
+
+	result := ExchangeHealth{ExchangeName: e.Name, Websocket: e.Websocket, Pairs: []PairHealth{}}
+
+	healthMtx.Lock()
+	if record, ok := healthRecords[e.Name]; ok {
+		result.LastRESTSuccess = record.lastRESTSuccess
+		result.LastRESTError = record.lastRESTError
+		result.LastRESTErrorMessage = record.lastRESTErrorMessage
+		result.RESTRequests = record.restRequests
+		result.RESTErrors = record.restErrors
+		result.WebsocketState = record.websocketState
+		result.LastWebsocketMessage = record.lastWebsocketMessage
+		if record.websocketConnects > 1 {
+			result.WebsocketReconnects = record.websocketConnects - 1
+		}
+
+		errors := 0
+		for _, x := range record.recentErrors {
+			if x {
+				errors++
+			}
+		}
+		if len(record.recentErrors) > 0 {
+			result.RESTErrorRate = float64(errors) / float64(len(record.recentErrors))
+		}
+	}
+	healthMtx.Unlock()
+
+	now := time.Now()
+	stale := false
+	for _, x := range e.EnabledPairs {
+		if len(x) < 6 {
+			continue
+		}
+
+		p := pair.NewCurrencyPairFromString(x)
+		health := PairHealth{Pair: x}
+		if price, err := ticker.GetTicker(e.Name, p); err == nil {
+			health.LastTicker = price.LastUpdated
+		}
+		if book, err := orderbook.GetOrderbook(e.Name, p); err == nil {
+			health.LastOrderbook = book.LastUpdated
+		}
+
+		latest := health.LastTicker
+		if health.LastOrderbook.After(latest) {
+			latest = health.LastOrderbook
+		}
+
+		health.Stale = true
+		if !latest.IsZero() {
+			age := now.Sub(latest)
+			health.StalenessSeconds = age.Seconds()
+			health.Stale = age > staleTimeout
+		}
+		stale = stale || health.Stale
+		result.Pairs = append(result.Pairs, health)
+	}
+
+	result.Healthy = !stale && result.RESTErrorRate <= HEALTH_MAX_REST_ERROR_RATE
+	if e.Websocket && result.WebsocketState != WEBSOCKET_STATE_CONNECTED {
+		result.Healthy = false
+	}
+	return result
+}
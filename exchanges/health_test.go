@@ -0,0 +1,70 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+)
+
+func TestGetHealth(t *testing.T) {
+	e := ExchangeBase{Name: "healthtest", EnabledPairs: []string{"BTCUSD", "LTCUSD"}}
+	ResetHealth(e.Name)
+	defer ResetHealth(e.Name)
+
+	ticker.ProcessTicker(e.Name, pair.NewCurrencyPair("BTC", "USD"), ticker.TickerPrice{Last: 1000})
+	orderbook.ProcessOrderbook(e.Name, pair.NewCurrencyPair("LTC", "USD"), orderbook.OrderbookBase{LastUpdated: time.Now().Add(-time.Hour)})
+
+	health := e.GetHealth(time.Minute)
+	if len(health.Pairs) != 2 {
+		t.Fatalf("Test Failed - GetHealth() expected 2 pairs, received %d", len(health.Pairs))
+	}
+	if health.Pairs[0].Stale || health.Pairs[0].LastTicker.IsZero() {
+		t.Errorf("Test Failed - GetHealth() BTCUSD expected fresh, received %+v", health.Pairs[0])
+	}
+	if !health.Pairs[1].Stale || health.Pairs[1].StalenessSeconds < 3600 {
+		t.Errorf("Test Failed - GetHealth() LTCUSD expected stale, received %+v", health.Pairs[1])
+	}
+	if health.Healthy {
+		t.Error("Test Failed - GetHealth() expected unhealthy with a stale pair")
+	}
+
+	if health = e.GetHealth(2 * time.Hour); !health.Healthy {
+		t.Errorf("Test Failed - GetHealth() expected healthy, received %+v", health)
+	}
+
+	RecordRESTResult(e.Name, nil)
+	RecordRESTResult(e.Name, errors.New("timeout"))
+	RecordRESTResult(e.Name, errors.New("timeout"))
+	health = e.GetHealth(2 * time.Hour)
+	if health.RESTRequests != 3 || health.RESTErrors != 2 || health.LastRESTErrorMessage != "timeout" {
+		t.Errorf("Test Failed - GetHealth() REST results incorrect: %+v", health)
+	}
+	if health.Healthy || health.LastRESTSuccess.IsZero() {
+		t.Errorf("Test Failed - GetHealth() expected unhealthy with a %f error rate", health.RESTErrorRate)
+	}
+
+	for i := 0; i < HEALTH_REST_WINDOW; i++ {
+		RecordRESTResult(e.Name, nil)
+	}
+	if health = e.GetHealth(2 * time.Hour); health.RESTErrorRate != 0 || !health.Healthy {
+		t.Errorf("Test Failed - GetHealth() expected errors outside the window to be dropped, received %f", health.RESTErrorRate)
+	}
+
+	e.Websocket = true
+	if health = e.GetHealth(2 * time.Hour); health.Healthy {
+		t.Error("Test Failed - GetHealth() expected unhealthy without a websocket connection")
+	}
+
+	RecordWebsocketState(e.Name, WEBSOCKET_STATE_CONNECTED)
+	RecordWebsocketState(e.Name, WEBSOCKET_STATE_DISCONNECTED)
+	RecordWebsocketState(e.Name, WEBSOCKET_STATE_CONNECTED)
+	RecordWebsocketMessage(e.Name)
+	health = e.GetHealth(2 * time.Hour)
+	if !health.Healthy || health.WebsocketReconnects != 1 || health.LastWebsocketMessage.IsZero() {
+		t.Errorf("Test Failed - GetHealth() websocket health incorrect: %+v", health)
+	}
+}
@@ -1,6 +1,7 @@
 package huobi
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
@@ -14,8 +15,42 @@ import (
 )
 
 const (
-	HUOBI_API_URL     = "https://api.huobi.com/apiv2.php"
-	HUOBI_API_VERSION = "2"
+	HUOBI_API_URL           = "https://api.huobi.com"
+	HUOBI_API_VERSION       = "2"
+	HUOBI_API_AUTHENTICATED = "apiv2.php"
+	HUOBI_API_STATICMARKET  = "staticmarket"
+)
+
+const (
+	HUOBI_AUTH_RATE_LIMIT   = 60
+	HUOBI_UNAUTH_RATE_LIMIT = 60
+)
+
+const (
+	HUOBI_COIN_TYPE_BTC = 1
+	HUOBI_COIN_TYPE_LTC = 2
+)
+
+const (
+	HUOBI_ORDER_TYPE_BUY         = 1
+	HUOBI_ORDER_TYPE_SELL        = 2
+	HUOBI_ORDER_TYPE_MARKET_BUY  = 3
+	HUOBI_ORDER_TYPE_MARKET_SELL = 4
+)
+
+const (
+	HUOBI_ORDER_STATUS_UNFILLED            = 0
+	HUOBI_ORDER_STATUS_PARTIALLY_FILLED    = 1
+	HUOBI_ORDER_STATUS_FILLED              = 2
+	HUOBI_ORDER_STATUS_CANCELLED           = 3
+	HUOBI_ORDER_STATUS_ABANDONED           = 4
+	HUOBI_ORDER_STATUS_ABNORMAL            = 5
+	HUOBI_ORDER_STATUS_PARTIALLY_CANCELLED = 6
+	HUOBI_ORDER_STATUS_QUEUEING            = 7
+)
+
+const (
+	ErrHuobiRequestFailed = "Huobi %s request failed: %s."
 )
 
 type HUOBI struct {
@@ -29,6 +64,7 @@ func (h *HUOBI) SetDefaults() {
 	h.Verbose = false
 	h.Websocket = false
 	h.RESTPollingDelay = 10
+	h.SetRateLimit(HUOBI_AUTH_RATE_LIMIT, HUOBI_UNAUTH_RATE_LIMIT)
 }
 
 func (h *HUOBI) Setup(exch config.ExchangeConfig) {
@@ -39,6 +75,11 @@ func (h *HUOBI) Setup(exch config.ExchangeConfig) {
 		h.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		h.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		h.RESTPollingDelay = exch.RESTPollingDelay
+		h.UpdateRateLimit(exch)
+		err := h.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", h.GetName(), err)
+		}
 		h.Verbose = exch.Verbose
 		h.Websocket = exch.Websocket
 		h.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -53,8 +94,8 @@ func (h *HUOBI) GetFee() float64 {
 
 func (h *HUOBI) GetTicker(symbol string) (HuobiTicker, error) {
 	resp := HuobiTickerResponse{}
-	path := fmt.Sprintf("http://api.huobi.com/staticmarket/ticker_%s_json.js", symbol)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	path := fmt.Sprintf("%s/%s/ticker_%s_json.js", h.GetAPIUrl(HUOBI_API_URL), HUOBI_API_STATICMARKET, symbol)
+	err := h.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return HuobiTicker{}, err
@@ -63,45 +104,43 @@ func (h *HUOBI) GetTicker(symbol string) (HuobiTicker, error) {
 }
 
 func (h *HUOBI) GetOrderBook(symbol string) (HuobiOrderbook, error) {
-	path := fmt.Sprintf("http://api.huobi.com/staticmarket/depth_%s_json.js", symbol)
+	path := fmt.Sprintf("%s/%s/depth_%s_json.js", h.GetAPIUrl(HUOBI_API_URL), HUOBI_API_STATICMARKET, symbol)
 	resp := HuobiOrderbook{}
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := h.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return resp, err
 	}
 	return resp, nil
 }
 
-func (h *HUOBI) GetAccountInfo() {
-	err := h.SendAuthenticatedRequest("get_account_info", url.Values{})
-
-	if err != nil {
-		log.Println(err)
-	}
+func (h *HUOBI) GetAccountInfo() (HuobiAccountInfo, error) {
+	result := HuobiAccountInfo{}
+	err := h.SendAuthenticatedRequest("get_account_info", url.Values{}, &result)
+	return result, err
 }
 
-func (h *HUOBI) GetOrders(coinType int) {
+//GetOrders returns the unfilled orders of a coin type
+func (h *HUOBI) GetOrders(coinType int) ([]HuobiOrder, error) {
 	values := url.Values{}
 	values.Set("coin_type", strconv.Itoa(coinType))
-	err := h.SendAuthenticatedRequest("get_orders", values)
 
-	if err != nil {
-		log.Println(err)
-	}
+	var result []HuobiOrder
+	err := h.SendAuthenticatedRequest("get_orders", values, &result)
+	return result, err
 }
 
-func (h *HUOBI) GetOrderInfo(orderID, coinType int) {
+func (h *HUOBI) OrderInfo(orderID int64, coinType int) (HuobiOrderInfo, error) {
 	values := url.Values{}
-	values.Set("id", strconv.Itoa(orderID))
+	values.Set("id", strconv.FormatInt(orderID, 10))
 	values.Set("coin_type", strconv.Itoa(coinType))
-	err := h.SendAuthenticatedRequest("order_info", values)
 
-	if err != nil {
-		log.Println(err)
-	}
+	result := HuobiOrderInfo{}
+	err := h.SendAuthenticatedRequest("order_info", values, &result)
+	return result, err
 }
 
-func (h *HUOBI) Trade(orderType string, coinType int, price, amount float64) {
+//Trade places a limit order and returns its ID
+func (h *HUOBI) Trade(orderType string, coinType int, price, amount float64) (int64, error) {
 	values := url.Values{}
 	if orderType != "buy" {
 		orderType = "sell"
@@ -109,97 +148,122 @@ func (h *HUOBI) Trade(orderType string, coinType int, price, amount float64) {
 	values.Set("coin_type", strconv.Itoa(coinType))
 	values.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
 	values.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
-	err := h.SendAuthenticatedRequest(orderType, values)
-
-	if err != nil {
-		log.Println(err)
-	}
+	return h.sendTradeRequest(orderType, values)
 }
 
-func (h *HUOBI) MarketTrade(orderType string, coinType int, price, amount float64) {
+//MarketTrade places a market order and returns its ID. Market buy orders are
+//sized by amount in CNY
+func (h *HUOBI) MarketTrade(orderType string, coinType int, price, amount float64) (int64, error) {
 	values := url.Values{}
 	if orderType != "buy_market" {
 		orderType = "sell_market"
 	}
 	values.Set("coin_type", strconv.Itoa(coinType))
 	values.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
-	values.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
-	err := h.SendAuthenticatedRequest(orderType, values)
-
-	if err != nil {
-		log.Println(err)
+	if price > 0 {
+		values.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
 	}
+	return h.sendTradeRequest(orderType, values)
 }
 
-func (h *HUOBI) CancelOrder(orderID, coinType int) {
+func (h *HUOBI) CancelExistingOrder(orderID int64, coinType int) error {
 	values := url.Values{}
 	values.Set("coin_type", strconv.Itoa(coinType))
-	values.Set("id", strconv.Itoa(orderID))
-	err := h.SendAuthenticatedRequest("cancel_order", values)
-
-	if err != nil {
-		log.Println(err)
-	}
+	values.Set("id", strconv.FormatInt(orderID, 10))
+	_, err := h.sendTradeRequest("cancel_order", values)
+	return err
 }
 
-func (h *HUOBI) ModifyOrder(orderType string, coinType, orderID int, price, amount float64) {
+//ModifyExistingOrder replaces the price and amount of an order and returns
+//the ID of the order
+func (h *HUOBI) ModifyExistingOrder(orderType string, coinType int, orderID int64, price, amount float64) (int64, error) {
 	values := url.Values{}
 	values.Set("coin_type", strconv.Itoa(coinType))
-	values.Set("id", strconv.Itoa(orderID))
+	values.Set("id", strconv.FormatInt(orderID, 10))
 	values.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
 	values.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
-	err := h.SendAuthenticatedRequest("modify_order", values)
-
-	if err != nil {
-		log.Println(err)
-	}
+	return h.sendTradeRequest("modify_order", values)
 }
 
-func (h *HUOBI) GetNewDealOrders(coinType int) {
+//GetNewDealOrders returns the most recently filled orders of a coin type
+func (h *HUOBI) GetNewDealOrders(coinType int) ([]HuobiDealOrder, error) {
 	values := url.Values{}
 	values.Set("coin_type", strconv.Itoa(coinType))
-	err := h.SendAuthenticatedRequest("get_new_deal_orders", values)
 
-	if err != nil {
-		log.Println(err)
-	}
+	var result []HuobiDealOrder
+	err := h.SendAuthenticatedRequest("get_new_deal_orders", values, &result)
+	return result, err
 }
 
-func (h *HUOBI) GetOrderIDByTradeID(coinType, orderID int) {
+func (h *HUOBI) GetOrderIDByTradeID(coinType int, tradeID int64) (int64, error) {
 	values := url.Values{}
 	values.Set("coin_type", strconv.Itoa(coinType))
-	values.Set("trade_id", strconv.Itoa(orderID))
-	err := h.SendAuthenticatedRequest("get_order_id_by_trade_id", values)
+	values.Set("trade_id", strconv.FormatInt(tradeID, 10))
 
+	type Response struct {
+		OrderID int64 `json:"order_id"`
+	}
+
+	result := Response{}
+	err := h.SendAuthenticatedRequest("get_order_id_by_trade_id", values, &result)
+	return result.OrderID, err
+}
+
+//sendTradeRequest sends an order request and returns the order ID, failing
+//unless Huobi reports success
+func (h *HUOBI) sendTradeRequest(method string, values url.Values) (int64, error) {
+	result := HuobiTradeResponse{}
+	err := h.SendAuthenticatedRequest(method, values, &result)
 	if err != nil {
-		log.Println(err)
+		return 0, err
+	}
+
+	if result.Result != "success" {
+		return 0, exchange.NewExchangeError(h.Name, fmt.Errorf(ErrHuobiRequestFailed, method, result.Result), "")
 	}
+	return result.ID, nil
 }
 
-func (h *HUOBI) SendAuthenticatedRequest(method string, v url.Values) error {
+//SendAuthenticatedRequest calls a trade API method and decodes its response
+//into result
+func (h *HUOBI) SendAuthenticatedRequest(method string, v url.Values, result interface{}) error {
+	h.WaitRateLimit(true)
 	v.Set("access_key", h.APIKey)
 	v.Set("created", strconv.FormatInt(time.Now().Unix(), 10))
 	v.Set("method", method)
 	hash := common.GetMD5([]byte(v.Encode() + "&secret_key=" + h.APISecret))
 	v.Set("sign", common.StringToLower(common.HexEncodeToString(hash)))
 	encoded := v.Encode()
+	path := fmt.Sprintf("%s/%s", h.GetAPIUrl(HUOBI_API_URL), HUOBI_API_AUTHENTICATED)
 
 	if h.Verbose {
-		log.Printf("Sending POST request to %s with params %s\n", HUOBI_API_URL, encoded)
+		log.Printf("Sending POST request to %s with params %s\n", path, encoded)
 	}
 
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	resp, err := common.SendHTTPRequest("POST", HUOBI_API_URL, headers, strings.NewReader(encoded))
+	resp, err := h.SendHTTPRequest("POST", path, headers, strings.NewReader(encoded))
 
 	if err != nil {
-		return err
+		return exchange.NewExchangeError(h.Name, err, resp)
 	}
 
 	if h.Verbose {
 		log.Printf("Recieved raw: %s\n", resp)
 	}
 
+	errResponse := HuobiErrorResponse{}
+	err = common.JSONDecode([]byte(resp), &errResponse)
+
+	if err == nil && errResponse.Code != 0 {
+		return exchange.NewExchangeError(h.Name, fmt.Errorf("Huobi error %d: %s", errResponse.Code, errResponse.Message), resp)
+	}
+
+	err = common.JSONDecode([]byte(resp), result)
+	if err != nil {
+		return exchange.NewExchangeError(h.Name, errors.New("Unable to JSON Unmarshal response."), resp)
+	}
+
 	return nil
 }
@@ -0,0 +1,93 @@
+package huobi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+func TestGetCoinType(t *testing.T) {
+	if coinType, err := getCoinType(pair.NewCurrencyPair("ltc", "cny")); err != nil || coinType != HUOBI_COIN_TYPE_LTC {
+		t.Errorf("Test Failed - getCoinType() expected %d, received %d %v", HUOBI_COIN_TYPE_LTC, coinType, err)
+	}
+
+	if _, err := getCoinType(pair.NewCurrencyPair("ETH", "CNY")); err == nil {
+		t.Error("Test Failed - getCoinType() accepted ETH")
+	}
+}
+
+func TestAuthenticatedEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.URL.Path != "/"+HUOBI_API_AUTHENTICATED || r.Form.Get("access_key") != "key" || r.Form.Get("sign") == "" {
+			w.Write([]byte(`{"code":71,"msg":"Invalid signature"}`))
+			return
+		}
+
+		switch r.Form.Get("method") {
+		case "get_account_info":
+			w.Write([]byte(`{"total":"1000","net_asset":"1000","available_cny_display":"500.5","available_btc_display":"1.25","available_ltc_display":"0","frozen_cny_display":"100","frozen_btc_display":"0.5","frozen_ltc_display":"0"}`))
+		case "buy":
+			if r.Form.Get("price") != "900" || r.Form.Get("amount") != "1" || r.Form.Get("coin_type") != "1" {
+				w.Write([]byte(`{"code":1,"msg":"Invalid arguments"}`))
+				return
+			}
+			w.Write([]byte(`{"result":"success","id":42}`))
+		case "cancel_order":
+			w.Write([]byte(`{"result":"fail"}`))
+		case "order_info":
+			w.Write([]byte(`{"id":42,"type":1,"order_price":"900","order_amount":"1","processed_price":"899","processed_amount":"0.25","vot":"224.75","fee":"0","total":"224.75","status":1}`))
+		case "get_orders":
+			w.Write([]byte(`[{"id":42,"type":2,"order_price":"900","order_amount":"1","processed_amount":"0","order_time":1500000000}]`))
+		case "get_new_deal_orders":
+			w.Write([]byte(`[{"id":41,"type":4,"order_price":"0","order_amount":"2","processed_amount":"2","last_processed_time":1500000100}]`))
+		}
+	}))
+	defer server.Close()
+
+	h := HUOBI{}
+	h.SetDefaults()
+	h.Name = "Huobi Test"
+	h.APIUrl = server.URL
+	h.SetRateLimit(0, 0)
+	h.APIKey = "key"
+	h.APISecret = "secret"
+	p := pair.NewCurrencyPair("BTC", "CNY")
+
+	info, err := h.GetExchangeAccountInfo()
+	if err != nil || len(info.Currencies) != 3 || info.Currencies[0].TotalValue != 500.5 || info.Currencies[1].Hold != 0.5 {
+		t.Errorf("Test Failed - GetExchangeAccountInfo() incorrect: %+v %v", info, err)
+	}
+
+	result, err := h.SubmitOrder(exchange.OrderRequest{Pair: p, Side: exchange.OrderSideBuy, Type: exchange.OrderTypeLimit, Amount: 1, Price: 900})
+	if err != nil || result.OrderID != "42" {
+		t.Errorf("Test Failed - SubmitOrder() incorrect: %+v %v", result, err)
+	}
+
+	_, err = h.SubmitOrder(exchange.OrderRequest{Pair: p, Side: exchange.OrderSideBuy, Type: exchange.OrderTypeMarket, Amount: 1})
+	if err == nil || err.Error() != exchange.ErrOrderTypeNotSupported {
+		t.Errorf("Test Failed - SubmitOrder() expected market buys to be refused, received %v", err)
+	}
+
+	if err = h.CancelOrder("42", p); err == nil {
+		t.Error("Test Failed - CancelOrder() accepted a failed cancellation")
+	}
+
+	order, err := h.GetOrderInfo("42", p)
+	if err != nil || order.Status != exchange.OrderStatusPartiallyFilled || order.AveragePrice != 899 || order.Side != exchange.OrderSideBuy {
+		t.Errorf("Test Failed - GetOrderInfo() incorrect: %+v %v", order, err)
+	}
+
+	orders, err := h.GetActiveOrders(exchange.OrderFilter{Pairs: []pair.CurrencyPair{p}})
+	if err != nil || len(orders) != 1 || orders[0].Side != exchange.OrderSideSell || orders[0].Status != exchange.OrderStatusActive {
+		t.Errorf("Test Failed - GetActiveOrders() incorrect: %+v %v", orders, err)
+	}
+
+	orders, err = h.GetOrderHistory(exchange.OrderFilter{Pairs: []pair.CurrencyPair{p}})
+	if err != nil || len(orders) != 1 || orders[0].Type != exchange.OrderTypeMarket || orders[0].Status != exchange.OrderStatusFilled {
+		t.Errorf("Test Failed - GetOrderHistory() incorrect: %+v %v", orders, err)
+	}
+}
@@ -21,3 +21,63 @@ type HuobiOrderbook struct {
 	Asks   [][]float64 `json:"asks"`
 	Symbol string      `json:"string"`
 }
+
+type HuobiErrorResponse struct {
+	Code    int64  `json:"code"`
+	Message string `json:"msg"`
+}
+
+//HuobiAccountInfo : Balances of the CNY account, available balances exclude
+//frozen and loaned funds
+type HuobiAccountInfo struct {
+	Total        float64 `json:"total,string"`
+	NetAsset     float64 `json:"net_asset,string"`
+	AvailableCNY float64 `json:"available_cny_display,string"`
+	AvailableBTC float64 `json:"available_btc_display,string"`
+	AvailableLTC float64 `json:"available_ltc_display,string"`
+	FrozenCNY    float64 `json:"frozen_cny_display,string"`
+	FrozenBTC    float64 `json:"frozen_btc_display,string"`
+	FrozenLTC    float64 `json:"frozen_ltc_display,string"`
+	LoanCNY      float64 `json:"loan_cny_display,string"`
+	LoanBTC      float64 `json:"loan_btc_display,string"`
+	LoanLTC      float64 `json:"loan_ltc_display,string"`
+}
+
+//HuobiOrder : Unfilled order. Type is 1 for buy and 2 for sell
+type HuobiOrder struct {
+	ID              int64   `json:"id"`
+	Type            int     `json:"type"`
+	OrderPrice      float64 `json:"order_price,string"`
+	OrderAmount     float64 `json:"order_amount,string"`
+	ProcessedAmount float64 `json:"processed_amount,string"`
+	OrderTime       int64   `json:"order_time"`
+}
+
+//HuobiOrderInfo : Order detail. Types 3 and 4 are market buy and sell orders,
+//status is one of the HUOBI_ORDER_STATUS values
+type HuobiOrderInfo struct {
+	ID              int64   `json:"id"`
+	Type            int     `json:"type"`
+	OrderPrice      float64 `json:"order_price,string"`
+	OrderAmount     float64 `json:"order_amount,string"`
+	ProcessedPrice  float64 `json:"processed_price,string"`
+	ProcessedAmount float64 `json:"processed_amount,string"`
+	Vot             float64 `json:"vot,string"`
+	Fee             float64 `json:"fee,string"`
+	Total           float64 `json:"total,string"`
+	Status          int     `json:"status"`
+}
+
+type HuobiDealOrder struct {
+	ID                int64   `json:"id"`
+	Type              int     `json:"type"`
+	OrderPrice        float64 `json:"order_price,string"`
+	OrderAmount       float64 `json:"order_amount,string"`
+	ProcessedAmount   float64 `json:"processed_amount,string"`
+	LastProcessedTime int64   `json:"last_processed_time"`
+}
+
+type HuobiTradeResponse struct {
+	Result string `json:"result"`
+	ID     int64  `json:"id"`
+}
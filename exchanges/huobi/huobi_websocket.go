@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/exchanges"
 	"github.com/thrasher-/socketio"
 )
 
@@ -161,6 +162,7 @@ func (h *HUOBI) BuildHuobiWebsocketParamsList(objectName, currency, pushType, pe
 }
 
 func (h *HUOBI) OnConnect(output chan socketio.Message) {
+	exchange.RecordWebsocketState(h.GetName(), exchange.WEBSOCKET_STATE_CONNECTED)
 	if h.Verbose {
 		log.Printf("%s Connected to Websocket.", h.GetName())
 	}
@@ -177,19 +179,20 @@ func (h *HUOBI) OnConnect(output chan socketio.Message) {
 }
 
 func (h *HUOBI) OnDisconnect(output chan socketio.Message) {
+	exchange.RecordWebsocketState(h.GetName(), exchange.WEBSOCKET_STATE_DISCONNECTED)
 	log.Printf("%s Disconnected from websocket server.. Reconnecting.\n", h.GetName())
-	h.WebsocketClient()
 }
 
 func (h *HUOBI) OnError() {
 	log.Printf("%s Error with Websocket connection.. Reconnecting.\n", h.GetName())
-	h.WebsocketClient()
 }
 
 func (h *HUOBI) OnMessage(message []byte, output chan socketio.Message) {
+	exchange.RecordWebsocketMessage(h.GetName())
 }
 
 func (h *HUOBI) OnRequest(message []byte, output chan socketio.Message) {
+	exchange.RecordWebsocketMessage(h.GetName())
 	response := HuobiResponse{}
 	err := common.JSONDecode(message, &response)
 	if err != nil {
@@ -197,11 +200,38 @@ func (h *HUOBI) OnRequest(message []byte, output chan socketio.Message) {
 	}
 }
 
+//websocketHandler returns handler, writing the events to the stream recording
+//first when the exchange is recorded
+func (h *HUOBI) websocketHandler(event string, handler func(message []byte, output chan socketio.Message)) func(message []byte, output chan socketio.Message) {
+	recorder := exchange.GetStreamRecorder(h.GetName())
+	if recorder == nil {
+		return handler
+	}
+
+	return func(message []byte, output chan socketio.Message) {
+		recorder.RecordWebsocketEvent(event, message)
+		handler(message, output)
+	}
+}
+
 func (h *HUOBI) WebsocketClient() {
 	events := make(map[string]func(message []byte, output chan socketio.Message))
 	events["request"] = h.OnRequest
 	events["message"] = h.OnMessage
 
+	if replayer := exchange.GetStreamReplayer(h.GetName()); replayer != nil {
+		replayer.Replay(h.GetWebsocketContext(), func(entry exchange.StreamEntry) {
+			if handler, ok := events[entry.Channel]; ok {
+				handler(entry.Data, nil)
+			}
+		})
+		return
+	}
+
+	for event, handler := range events {
+		events[event] = h.websocketHandler(event, handler)
+	}
+
 	HuobiSocket = &socketio.SocketIO{
 		Version:      0.9,
 		OnConnect:    h.OnConnect,
@@ -210,12 +240,18 @@ func (h *HUOBI) WebsocketClient() {
 		OnDisconnect: h.OnDisconnect,
 	}
 
+	ctx := h.GetWebsocketContext()
+	backoff := exchange.WebsocketBackoff{}
 	for h.Enabled && h.Websocket {
-		err := socketio.ConnectToSocket(HUOBI_SOCKETIO_ADDRESS, HuobiSocket)
+		err := socketio.ConnectToSocket(h.GetWebsocketURL(HUOBI_SOCKETIO_ADDRESS), HuobiSocket)
 		if err != nil {
 			log.Printf("%s Unable to connect to Websocket. Err: %s\n", h.GetName(), err)
-			continue
+		} else {
+			log.Printf("%s Disconnected from Websocket.\n", h.GetName())
+		}
+
+		if !backoff.Wait(ctx) {
+			return
 		}
-		log.Printf("%s Disconnected from Websocket.\n", h.GetName())
 	}
 }
@@ -1,7 +1,9 @@
 package huobi
 
 import (
+	"errors"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -11,6 +13,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (h *HUOBI) Start() {
@@ -19,7 +22,7 @@ func (h *HUOBI) Start() {
 
 func (h *HUOBI) Run() {
 	if h.Verbose {
-		log.Printf("%s Websocket: %s (url: %s).\n", h.GetName(), common.IsEnabled(h.Websocket), HUOBI_SOCKETIO_ADDRESS)
+		log.Printf("%s Websocket: %s (url: %s).\n", h.GetName(), common.IsEnabled(h.Websocket), h.GetWebsocketURL(HUOBI_SOCKETIO_ADDRESS))
 		log.Printf("%s polling delay: %ds.\n", h.GetName(), h.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", h.GetName(), len(h.EnabledPairs), h.EnabledPairs)
 	}
@@ -31,7 +34,7 @@ func (h *HUOBI) Run() {
 	for h.Enabled {
 		for _, x := range h.EnabledPairs {
 			curr := pair.NewCurrencyPair(x[0:3], x[3:])
-			go func() {
+			h.Poll(x, func() {
 				ticker, err := h.GetTickerPrice(curr)
 				if err != nil {
 					log.Println(err)
@@ -43,7 +46,7 @@ func (h *HUOBI) Run() {
 				log.Printf("Huobi %s: Last %f (%f) High %f (%f) Low %f (%f) Volume %f\n", curr.Pair().String(), HuobiLastUSD, ticker.Last, HuobiHighUSD, ticker.High, HuobiLowUSD, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(h.GetName(), curr.GetFirstCurrency().String(), curr.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
 				stats.AddExchangeInfo(h.GetName(), curr.GetFirstCurrency().String(), "USD", HuobiLastUSD, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * h.RESTPollingDelay)
 	}
@@ -97,10 +100,262 @@ func (h *HUOBI) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase, er
 	return orderBook, nil
 }
 
-//TODO: retrieve HUOBI balance info
-//GetExchangeAccountInfo : Retrieves balances for all enabled currencies for the HUOBI exchange
+//GetExchangeAccountInfo : Retrieves the CNY, BTC and LTC balances for the HUOBI exchange
 func (e *HUOBI) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	var response exchange.ExchangeAccountInfo
 	response.ExchangeName = e.GetName()
+
+	info, err := e.GetAccountInfo()
+	if err != nil {
+		return response, err
+	}
+
+	response.Currencies = []exchange.ExchangeAccountCurrencyInfo{
+		{CurrencyName: "CNY", TotalValue: info.AvailableCNY, Hold: info.FrozenCNY},
+		{CurrencyName: "BTC", TotalValue: info.AvailableBTC, Hold: info.FrozenBTC},
+		{CurrencyName: "LTC", TotalValue: info.AvailableLTC, Hold: info.FrozenLTC},
+	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new order on Huobi. Market buy orders are sized in CNY
+//by Huobi, so only limit buy orders are accepted
+func (h *HUOBI) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := h.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	coinType, err := getCoinType(order.Pair)
+	if err != nil {
+		return result, err
+	}
+
+	var orderID int64
+	switch {
+	case order.IsMarket() && order.IsBuy():
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	case order.IsMarket():
+		orderID, err = h.MarketTrade("sell_market", coinType, 0, order.Amount)
+	case order.IsBuy():
+		orderID, err = h.Trade("buy", coinType, order.Price, order.Amount)
+	default:
+		orderID, err = h.Trade("sell", coinType, order.Price, order.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = h.GetName()
+	result.OrderID = strconv.FormatInt(orderID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (h *HUOBI) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	coinType, err := getCoinType(p)
+	if err != nil {
+		return err
+	}
+	return h.CancelExistingOrder(id, coinType)
+}
+
+//ModifyOrder : Replaces the price and amount of a limit order
+func (h *HUOBI) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := h.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return result, err
+	}
+
+	coinType, err := getCoinType(order.Pair)
+	if err != nil {
+		return result, err
+	}
+
+	newID, err := h.ModifyExistingOrder(common.StringToLower(string(order.Side)), coinType, id, order.Price, order.Amount)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = h.GetName()
+	result.OrderID = strconv.FormatInt(newID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (h *HUOBI) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	coinType, err := getCoinType(p)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	order, err := h.OrderInfo(id, coinType)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	if order.ID == 0 {
+		return exchange.OrderDetail{}, errors.New(exchange.ErrOrderNotFound)
+	}
+	return h.getOrderInfoDetail(p, order), nil
+}
+
+//GetActiveOrders : Retrieves all unfilled orders matching the filter
+func (h *HUOBI) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, x := range h.getFilterPairs(filter) {
+		coinType, err := getCoinType(x)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := h.GetOrders(coinType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, y := range response {
+			detail := h.getOrderDetail(x, y.ID, y.Type, y.OrderPrice, y.OrderAmount, y.ProcessedAmount)
+			detail.Status = exchange.GetOrderStatus(y.OrderAmount, y.ProcessedAmount, true, false)
+			detail.CreatedAt = time.Unix(y.OrderTime, 0)
+			orders = append(orders, detail)
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Retrieves the filled orders matching the filter. Huobi only
+//returns the most recently filled orders of each coin
+func (h *HUOBI) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, x := range h.getFilterPairs(filter) {
+		coinType, err := getCoinType(x)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := h.GetNewDealOrders(coinType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, y := range response {
+			detail := h.getOrderDetail(x, y.ID, y.Type, y.OrderPrice, y.OrderAmount, y.ProcessedAmount)
+			detail.Status = exchange.GetOrderStatus(y.OrderAmount, y.ProcessedAmount, false, false)
+			detail.CreatedAt = time.Unix(y.LastProcessedTime, 0)
+			detail.UpdatedAt = detail.CreatedAt
+			orders = append(orders, detail)
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetFills : Not supported, the Huobi trade API does not list executions
+func (h *HUOBI) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+func (h *HUOBI) getFilterPairs(filter exchange.OrderFilter) []pair.CurrencyPair {
+	if len(filter.Pairs) > 0 {
+		return filter.Pairs
+	}
+
+	var pairs []pair.CurrencyPair
+	for _, x := range h.EnabledPairs {
+		pairs = append(pairs, pair.NewCurrencyPair(x[0:3], x[3:]))
+	}
+	return pairs
+}
+
+//getCoinType returns the trade API coin type of the base currency of a pair
+func getCoinType(p pair.CurrencyPair) (int, error) {
+	switch p.GetFirstCurrency().Upper().String() {
+	case "BTC":
+		return HUOBI_COIN_TYPE_BTC, nil
+	case "LTC":
+		return HUOBI_COIN_TYPE_LTC, nil
+	}
+	return 0, errors.New(exchange.ErrCurrencyNotSupported)
+}
+
+func (h *HUOBI) getOrderDetail(p pair.CurrencyPair, orderID int64, orderType int, price, amount, filled float64) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = h.GetName()
+	detail.OrderID = strconv.FormatInt(orderID, 10)
+	detail.Pair = p
+	detail.Side = exchange.OrderSideBuy
+	if orderType == HUOBI_ORDER_TYPE_SELL || orderType == HUOBI_ORDER_TYPE_MARKET_SELL {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	if orderType == HUOBI_ORDER_TYPE_MARKET_BUY || orderType == HUOBI_ORDER_TYPE_MARKET_SELL {
+		detail.Type = exchange.OrderTypeMarket
+	}
+	detail.Price = price
+	detail.Amount = amount
+	detail.FilledAmount = filled
+	return detail
+}
+
+func (h *HUOBI) getOrderInfoDetail(p pair.CurrencyPair, order HuobiOrderInfo) exchange.OrderDetail {
+	detail := h.getOrderDetail(p, order.ID, order.Type, order.OrderPrice, order.OrderAmount, order.ProcessedAmount)
+	detail.AveragePrice = order.ProcessedPrice
+
+	switch order.Status {
+	case HUOBI_ORDER_STATUS_ABANDONED, HUOBI_ORDER_STATUS_ABNORMAL:
+		detail.Status = exchange.OrderStatusRejected
+	default:
+		active := order.Status == HUOBI_ORDER_STATUS_UNFILLED || order.Status == HUOBI_ORDER_STATUS_PARTIALLY_FILLED || order.Status == HUOBI_ORDER_STATUS_QUEUEING
+		cancelled := order.Status == HUOBI_ORDER_STATUS_CANCELLED || order.Status == HUOBI_ORDER_STATUS_PARTIALLY_CANCELLED
+		detail.Status = exchange.GetOrderStatus(order.OrderAmount, order.ProcessedAmount, active, cancelled)
+	}
+	return detail
+}
+
+//GetDepositAddress : Not supported, the Huobi trade API does not return deposit addresses
+func (h *HUOBI) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(h.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Not supported, the Huobi trade API has no withdrawal method
+func (h *HUOBI) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	return exchange.WithdrawResult{}, exchange.NewFundingError(h.GetName(), request.Currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//UpdatePairInfo : Not supported, Huobi does not publish pair constraints
+func (h *HUOBI) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, the Huobi REST client has no candle endpoint
+func (h *HUOBI) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Not supported, the Huobi REST client has no public trades endpoint
+func (h *HUOBI) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
@@ -3,6 +3,7 @@ package itbit
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"log"
 	"net/url"
 	"strconv"
@@ -18,8 +19,21 @@ const (
 	ITBIT_API_VERSION = "1"
 )
 
+const (
+	ITBIT_AUTH_RATE_LIMIT   = 60
+	ITBIT_UNAUTH_RATE_LIMIT = 60
+	ITBIT_RECORDS_PER_PAGE  = 50
+)
+
+const (
+	ErrItBitNoWallet = "ItBit account has no wallet."
+)
+
+//ItBit : Orders and balances are kept per wallet. The wrapper trades on
+//WalletID, or on the first wallet of the account when it is empty
 type ItBit struct {
 	exchange.ExchangeBase
+	WalletID string
 }
 
 func (i *ItBit) SetDefaults() {
@@ -30,6 +44,8 @@ func (i *ItBit) SetDefaults() {
 	i.Verbose = false
 	i.Websocket = false
 	i.RESTPollingDelay = 10
+	i.SetRateLimit(ITBIT_AUTH_RATE_LIMIT, ITBIT_UNAUTH_RATE_LIMIT)
+	i.SetNonceResolution(time.Millisecond)
 }
 
 func (i *ItBit) Setup(exch config.ExchangeConfig) {
@@ -40,6 +56,11 @@ func (i *ItBit) Setup(exch config.ExchangeConfig) {
 		i.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		i.SetAPIKeys(exch.APIKey, exch.APISecret, exch.ClientID, false)
 		i.RESTPollingDelay = exch.RESTPollingDelay
+		i.UpdateRateLimit(exch)
+		err := i.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", i.GetName(), err)
+		}
 		i.Verbose = exch.Verbose
 		i.Websocket = exch.Websocket
 		i.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -57,9 +78,9 @@ func (i *ItBit) GetFee(maker bool) float64 {
 }
 
 func (i *ItBit) GetTicker(currency string) (ItBitTicker, error) {
-	path := ITBIT_API_URL + "/markets/" + currency + "/ticker"
+	path := i.GetAPIUrl(ITBIT_API_URL) + "/markets/" + currency + "/ticker"
 	var itbitTicker ItBitTicker
-	err := common.SendHTTPGetRequest(path, true, &itbitTicker)
+	err := i.SendHTTPGetRequest(path, true, &itbitTicker)
 	if err != nil {
 		return ItBitTicker{}, err
 	}
@@ -68,8 +89,8 @@ func (i *ItBit) GetTicker(currency string) (ItBitTicker, error) {
 
 func (i *ItBit) GetOrderbook(currency string) (ItBitOrderbookResponse, error) {
 	response := ItBitOrderbookResponse{}
-	path := ITBIT_API_URL + "/markets/" + currency + "/order_book"
-	err := common.SendHTTPGetRequest(path, true, &response)
+	path := i.GetAPIUrl(ITBIT_API_URL) + "/markets/" + currency + "/order_book"
+	err := i.SendHTTPGetRequest(path, true, &response)
 	if err != nil {
 		return ItBitOrderbookResponse{}, err
 	}
@@ -78,7 +99,7 @@ func (i *ItBit) GetOrderbook(currency string) (ItBitOrderbookResponse, error) {
 
 func (i *ItBit) GetTradeHistory(currency, timestamp string) bool {
 	req := "/trades?since=" + timestamp
-	err := common.SendHTTPGetRequest(ITBIT_API_URL+"markets/"+currency+req, true, nil)
+	err := i.SendHTTPGetRequest(i.GetAPIUrl(ITBIT_API_URL)+"/markets/"+currency+req, true, nil)
 	if err != nil {
 		log.Println(err)
 		return false
@@ -86,67 +107,59 @@ func (i *ItBit) GetTradeHistory(currency, timestamp string) bool {
 	return true
 }
 
-func (i *ItBit) GetWallets(params url.Values) {
+func (i *ItBit) GetWallets(params url.Values) ([]ItBitWallet, error) {
 	params.Set("userId", i.ClientID)
 	path := "/wallets?" + params.Encode()
 
-	err := i.SendAuthenticatedHTTPRequest("GET", path, nil)
-
-	if err != nil {
-		log.Println(err)
-	}
+	var result []ItBitWallet
+	err := i.SendAuthenticatedHTTPRequest("GET", path, nil, &result)
+	return result, err
 }
 
-func (i *ItBit) CreateWallet(walletName string) {
+func (i *ItBit) CreateWallet(walletName string) (ItBitWallet, error) {
 	path := "/wallets"
 	params := make(map[string]interface{})
 	params["userId"] = i.ClientID
 	params["name"] = walletName
 
-	err := i.SendAuthenticatedHTTPRequest("POST", path, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitWallet{}
+	err := i.SendAuthenticatedHTTPRequest("POST", path, params, &result)
+	return result, err
 }
 
-func (i *ItBit) GetWallet(walletID string) {
+func (i *ItBit) GetWallet(walletID string) (ItBitWallet, error) {
 	path := "/wallets/" + walletID
-	err := i.SendAuthenticatedHTTPRequest("GET", path, nil)
 
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitWallet{}
+	err := i.SendAuthenticatedHTTPRequest("GET", path, nil, &result)
+	return result, err
 }
 
-func (i *ItBit) GetWalletBalance(walletID, currency string) {
-	path := "/wallets/ " + walletID + "/balances/" + currency
-	err := i.SendAuthenticatedHTTPRequest("GET", path, nil)
+func (i *ItBit) GetWalletBalance(walletID, currency string) (ItBitBalance, error) {
+	path := "/wallets/" + walletID + "/balances/" + currency
 
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitBalance{}
+	err := i.SendAuthenticatedHTTPRequest("GET", path, nil, &result)
+	return result, err
 }
 
-func (i *ItBit) GetWalletTrades(walletID string, params url.Values) {
+func (i *ItBit) GetWalletTrades(walletID string, params url.Values) (ItBitTradeHistory, error) {
 	path := common.EncodeURLValues("/wallets/"+walletID+"/trades", params)
-	err := i.SendAuthenticatedHTTPRequest("GET", path, nil)
 
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitTradeHistory{}
+	err := i.SendAuthenticatedHTTPRequest("GET", path, nil, &result)
+	return result, err
 }
 
-func (i *ItBit) GetWalletOrders(walletID string, params url.Values) {
+func (i *ItBit) GetWalletOrders(walletID string, params url.Values) ([]ItBitOrder, error) {
 	path := common.EncodeURLValues("/wallets/"+walletID+"/orders", params)
-	err := i.SendAuthenticatedHTTPRequest("GET", path, nil)
 
-	if err != nil {
-		log.Println(err)
-	}
+	var result []ItBitOrder
+	err := i.SendAuthenticatedHTTPRequest("GET", path, nil, &result)
+	return result, err
 }
 
-func (i *ItBit) PlaceWalletOrder(walletID, side, orderType, currency string, amount, price float64, instrument string, clientRef string) {
+func (i *ItBit) PlaceWalletOrder(walletID, side, orderType, currency string, amount, price float64, instrument string, clientRef string) (ItBitOrder, error) {
 	path := "/wallets/" + walletID + "/orders"
 	params := make(map[string]interface{})
 	params["side"] = side
@@ -160,58 +173,51 @@ func (i *ItBit) PlaceWalletOrder(walletID, side, orderType, currency string, amo
 		params["clientOrderIdentifier"] = clientRef
 	}
 
-	err := i.SendAuthenticatedHTTPRequest("POST", path, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitOrder{}
+	err := i.SendAuthenticatedHTTPRequest("POST", path, params, &result)
+	return result, err
 }
 
-func (i *ItBit) GetWalletOrder(walletID, orderID string) {
+func (i *ItBit) GetWalletOrder(walletID, orderID string) (ItBitOrder, error) {
 	path := "/wallets/" + walletID + "/orders/" + orderID
-	err := i.SendAuthenticatedHTTPRequest("GET", path, nil)
 
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitOrder{}
+	err := i.SendAuthenticatedHTTPRequest("GET", path, nil, &result)
+	return result, err
 }
 
-func (i *ItBit) CancelWalletOrder(walletID, orderID string) {
+//CancelWalletOrder requests the cancellation of an order. ItBit accepts the
+//request without a response body, the order is cancelled asynchronously
+func (i *ItBit) CancelWalletOrder(walletID, orderID string) error {
 	path := "/wallets/" + walletID + "/orders/" + orderID
-	err := i.SendAuthenticatedHTTPRequest("DELETE", path, nil)
-
-	if err != nil {
-		log.Println(err)
-	}
+	return i.SendAuthenticatedHTTPRequest("DELETE", path, nil, nil)
 }
 
-func (i *ItBit) PlaceWithdrawalRequest(walletID, currency, address string, amount float64) {
+//PlaceWithdrawalRequest withdraws a cryptocurrency and returns the withdrawal
+//ID
+func (i *ItBit) PlaceWithdrawalRequest(walletID, currency, address string, amount float64) (int64, error) {
 	path := "/wallets/" + walletID + "/cryptocurrency_withdrawals"
 	params := make(map[string]interface{})
 	params["currency"] = currency
 	params["amount"] = amount
 	params["address"] = address
 
-	err := i.SendAuthenticatedHTTPRequest("POST", path, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitWithdrawal{}
+	err := i.SendAuthenticatedHTTPRequest("POST", path, params, &result)
+	return result.WithdrawalID, err
 }
 
-func (i *ItBit) GetDepositAddress(walletID, currency string) {
+func (i *ItBit) GetCryptoDepositAddress(walletID, currency string) (ItBitDepositAddress, error) {
 	path := "/wallets/" + walletID + "/cryptocurrency_deposits"
 	params := make(map[string]interface{})
 	params["currency"] = currency
 
-	err := i.SendAuthenticatedHTTPRequest("POST", path, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitDepositAddress{}
+	err := i.SendAuthenticatedHTTPRequest("POST", path, params, &result)
+	return result, err
 }
 
-func (i *ItBit) WalletTransfer(walletID, sourceWallet, destWallet string, amount float64, currency string) {
+func (i *ItBit) WalletTransfer(walletID, sourceWallet, destWallet string, amount float64, currency string) (ItBitWalletTransfer, error) {
 	path := "/wallets/" + walletID + "/wallet_transfers"
 	params := make(map[string]interface{})
 	params["sourceWalletId"] = sourceWallet
@@ -219,24 +225,19 @@ func (i *ItBit) WalletTransfer(walletID, sourceWallet, destWallet string, amount
 	params["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
 	params["currencyCode"] = currency
 
-	err := i.SendAuthenticatedHTTPRequest("POST", path, params)
-
-	if err != nil {
-		log.Println(err)
-	}
+	result := ItBitWalletTransfer{}
+	err := i.SendAuthenticatedHTTPRequest("POST", path, params, &result)
+	return result, err
 }
 
-func (i *ItBit) SendAuthenticatedHTTPRequest(method string, path string, params map[string]interface{}) (err error) {
+//SendAuthenticatedHTTPRequest sends a signed request and decodes the response
+//into result. Empty responses are not decoded
+func (i *ItBit) SendAuthenticatedHTTPRequest(method string, path string, params map[string]interface{}, result interface{}) (err error) {
+	i.WaitRateLimit(true)
 	timestamp := strconv.FormatInt(time.Now().UnixNano(), 10)[0:13]
-	nonce, err := strconv.Atoi(timestamp)
-
-	if err != nil {
-		return err
-	}
-
-	nonce -= 1
+	nonceStr := i.GetNonce().GetString()
 	request := make(map[string]interface{})
-	url := ITBIT_API_URL + path
+	url := i.GetAPIUrl(ITBIT_API_URL) + path
 
 	if params != nil {
 		for key, value := range params {
@@ -258,11 +259,9 @@ func (i *ItBit) SendAuthenticatedHTTPRequest(method string, path string, params
 		}
 	}
 
-	nonceStr := strconv.Itoa(nonce)
 	message, err := common.JSONEncode([]string{method, url, string(PayloadJson), nonceStr, timestamp})
 	if err != nil {
-		log.Println(err)
-		return
+		return err
 	}
 
 	hash := common.GetSHA256([]byte(nonceStr + string(message)))
@@ -275,10 +274,28 @@ func (i *ItBit) SendAuthenticatedHTTPRequest(method string, path string, params
 	headers["X-Auth-Nonce"] = nonceStr
 	headers["Content-Type"] = "application/json"
 
-	resp, err := common.SendHTTPRequest(method, url, headers, bytes.NewBuffer([]byte(PayloadJson)))
+	resp, err := i.SendHTTPRequest(method, url, headers, bytes.NewBuffer([]byte(PayloadJson)))
 
 	if i.Verbose {
 		log.Printf("Recieved raw: \n%s\n", resp)
 	}
+
+	errResponse := ItBitErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.Code != 0 {
+		return i.CheckNonceError(exchange.NewExchangeError(i.Name, fmt.Errorf("ItBit error %d: %s", errResponse.Code, errResponse.Description), resp))
+	}
+
+	if err != nil {
+		return i.CheckNonceError(exchange.NewExchangeError(i.Name, err, resp))
+	}
+
+	if result == nil || len(resp) == 0 {
+		return nil
+	}
+
+	err = common.JSONDecode([]byte(resp), result)
+	if err != nil {
+		return exchange.NewExchangeError(i.Name, errors.New("Unable to JSON Unmarshal response."), resp)
+	}
 	return nil
 }
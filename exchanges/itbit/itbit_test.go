@@ -0,0 +1,124 @@
+package itbit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+func TestGetInstrument(t *testing.T) {
+	if result := getInstrument(pair.NewCurrencyPair("btc", "usd")); result != "XBTUSD" {
+		t.Errorf("Test Failed - getInstrument() expected XBTUSD, received %s", result)
+	}
+
+	p := getInstrumentPair("XBTSGD")
+	if p.GetFirstCurrency().String() != "BTC" || p.GetSecondCurrency().String() != "SGD" {
+		t.Errorf("Test Failed - getInstrumentPair() incorrect: %s", p.Pair())
+	}
+
+	if result := getCurrencyName("xbt"); result != "BTC" {
+		t.Errorf("Test Failed - getCurrencyName() expected BTC, received %s", result)
+	}
+}
+
+func TestWalletEndpoints(t *testing.T) {
+	var orderPages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" || r.Header.Get("X-Auth-Nonce") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":10002,"description":"Invalid signature"}`))
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /wallets":
+			if r.URL.Query().Get("userId") != "user" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[{"id":"w1","userId":"user","name":"Main","balances":[{"currency":"XBT","availableBalance":"1.25","totalBalance":"1.75"},{"currency":"USD","availableBalance":"500","totalBalance":"500"}]},{"id":"w2","userId":"user","name":"Other","balances":[{"currency":"XBT","availableBalance":"0.75","totalBalance":"0.75"}]}]`))
+		case "POST /wallets/w1/orders":
+			request := make(map[string]string)
+			body := make([]byte, r.ContentLength)
+			r.Body.Read(body)
+			common.JSONDecode(body, &request)
+			if request["instrument"] != "XBTUSD" || request["currency"] != "XBT" || request["price"] != "900" || request["clientOrderIdentifier"] != "ref" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"code":10001,"description":"Invalid order"}`))
+				return
+			}
+			w.Write([]byte(`{"id":"o1","walletId":"w1","side":"buy","instrument":"XBTUSD","type":"limit","currency":"XBT","amount":"1","price":"900","amountFilled":"0","status":"submitted","clientOrderIdentifier":"ref"}`))
+		case "DELETE /wallets/w1/orders/o1":
+			w.WriteHeader(http.StatusAccepted)
+		case "GET /wallets/w1/orders/o1":
+			w.Write([]byte(`{"id":"o1","side":"buy","instrument":"XBTUSD","amount":"1","price":"900","amountFilled":"0.25","volumeWeightedAveragePrice":"899","createdTime":"2017-03-20T16:30:00.1234Z","status":"open"}`))
+		case "GET /wallets/w1/orders":
+			orderPages = append(orderPages, r.URL.Query().Get("status")+r.URL.Query().Get("page"))
+			if r.URL.Query().Get("status") != "filled" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[{"id":"o0","side":"sell","instrument":"XBTUSD","amount":"2","price":"950","amountFilled":"2","createdTime":"2017-03-20T16:00:00Z","status":"filled"}]`))
+		case "GET /wallets/w1/trades":
+			w.Write([]byte(`{"totalNumberOfRecords":"1","currentPageNumber":"1","recordsPerPage":"50","tradingHistory":[{"orderId":"o0","timestamp":"2017-03-20T16:00:01Z","instrument":"XBTUSD","direction":"sell","currency1":"XBT","currency1Amount":"2","currency2":"USD","currency2Amount":"1900","rate":"950","commissionPaid":"1.5","commissionCurrency":"USD","rebatesApplied":"0.5","executionId":"e1"}]}`))
+		case "POST /wallets/w1/cryptocurrency_deposits":
+			w.Write([]byte(`{"id":1,"walletID":"w1","depositAddress":"1deposit"}`))
+		}
+	}))
+	defer server.Close()
+
+	i := ItBit{}
+	i.SetDefaults()
+	i.Name = "ItBit Test"
+	i.APIUrl = server.URL
+	i.SetRateLimit(0, 0)
+	i.ClientID = "user"
+	i.APISecret = "secret"
+	p := pair.NewCurrencyPair("XBT", "USD")
+
+	info, err := i.GetExchangeAccountInfo()
+	if err != nil || len(info.Currencies) != 2 || info.Currencies[0].CurrencyName != "BTC" || info.Currencies[0].TotalValue != 2 || info.Currencies[0].Hold != 0.5 {
+		t.Errorf("Test Failed - GetExchangeAccountInfo() incorrect: %+v %v", info, err)
+	}
+
+	result, err := i.SubmitOrder(exchange.OrderRequest{Pair: pair.NewCurrencyPair("BTC", "USD"), Side: exchange.OrderSideBuy, Type: exchange.OrderTypeLimit, Amount: 1, Price: 900, ClientID: "ref"})
+	if err != nil || result.OrderID != "o1" {
+		t.Errorf("Test Failed - SubmitOrder() incorrect: %+v %v", result, err)
+	}
+
+	_, err = i.SubmitOrder(exchange.OrderRequest{Pair: p, Side: exchange.OrderSideBuy, Type: exchange.OrderTypeMarket, Amount: 1})
+	if err == nil || err.Error() != exchange.ErrOrderTypeNotSupported {
+		t.Errorf("Test Failed - SubmitOrder() expected market orders to be refused, received %v", err)
+	}
+
+	if err = i.CancelOrder("o1", p); err != nil {
+		t.Errorf("Test Failed - CancelOrder() error: %s", err)
+	}
+
+	order, err := i.GetOrderInfo("o1", p)
+	if err != nil || order.Status != exchange.OrderStatusPartiallyFilled || order.AveragePrice != 899 || order.CreatedAt.Nanosecond() != 123400000 {
+		t.Errorf("Test Failed - GetOrderInfo() incorrect: %+v %v", order, err)
+	}
+
+	orders, err := i.GetOrderHistory(exchange.OrderFilter{Pairs: []pair.CurrencyPair{p}})
+	if err != nil || len(orders) != 1 || orders[0].Side != exchange.OrderSideSell || orders[0].Status != exchange.OrderStatusFilled {
+		t.Errorf("Test Failed - GetOrderHistory() incorrect: %+v %v", orders, err)
+	}
+	if len(orderPages) != 2 || orderPages[0] != "filled1" || orderPages[1] != "cancelled1" {
+		t.Errorf("Test Failed - GetOrderHistory() requested %v", orderPages)
+	}
+
+	fills, err := i.GetFills(exchange.OrderFilter{Pairs: []pair.CurrencyPair{pair.NewCurrencyPair("BTC", "USD")}})
+	if err != nil || len(fills) != 1 || fills[0].TradeID != "e1" || fills[0].Pair.Pair().String() != "BTCUSD" || fills[0].Fee != 1 || fills[0].FeeCurrency != "USD" {
+		t.Errorf("Test Failed - GetFills() incorrect: %+v %v", fills, err)
+	}
+
+	address, err := i.GetDepositAddress("BTC")
+	if err != nil || address.Address != "1deposit" {
+		t.Errorf("Test Failed - GetDepositAddress() incorrect: %+v %v", address, err)
+	}
+}
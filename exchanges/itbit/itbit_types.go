@@ -24,3 +24,83 @@ type ItBitOrderbookResponse struct {
 	Bids [][]string `json:"bids"`
 	Asks [][]string `json:"asks"`
 }
+
+type ItBitErrorResponse struct {
+	Code        int64  `json:"code"`
+	Description string `json:"description"`
+}
+
+type ItBitBalance struct {
+	Currency         string  `json:"currency"`
+	AvailableBalance float64 `json:"availableBalance,string"`
+	TotalBalance     float64 `json:"totalBalance,string"`
+}
+
+type ItBitWallet struct {
+	ID       string         `json:"id"`
+	UserID   string         `json:"userId"`
+	Name     string         `json:"name"`
+	Balances []ItBitBalance `json:"balances"`
+}
+
+//ItBitOrder : Wallet order. Status is submitted, open, filled, cancelled or
+//rejected
+type ItBitOrder struct {
+	ID                         string  `json:"id"`
+	WalletID                   string  `json:"walletId"`
+	Side                       string  `json:"side"`
+	Instrument                 string  `json:"instrument"`
+	Type                       string  `json:"type"`
+	Currency                   string  `json:"currency"`
+	Amount                     float64 `json:"amount,string"`
+	Price                      float64 `json:"price,string"`
+	AmountFilled               float64 `json:"amountFilled,string"`
+	VolumeWeightedAveragePrice float64 `json:"volumeWeightedAveragePrice,string"`
+	CreatedTime                string  `json:"createdTime"`
+	Status                     string  `json:"status"`
+	ClientOrderIdentifier      string  `json:"clientOrderIdentifier"`
+}
+
+//ItBitTrade : Execution of one of the wallet's orders. Currency1 is the base
+//currency of the instrument
+type ItBitTrade struct {
+	OrderID            string  `json:"orderId"`
+	Timestamp          string  `json:"timestamp"`
+	Instrument         string  `json:"instrument"`
+	Direction          string  `json:"direction"`
+	Currency1          string  `json:"currency1"`
+	Currency1Amount    float64 `json:"currency1Amount,string"`
+	Currency2          string  `json:"currency2"`
+	Currency2Amount    float64 `json:"currency2Amount,string"`
+	Rate               float64 `json:"rate,string"`
+	CommissionPaid     float64 `json:"commissionPaid,string"`
+	CommissionCurrency string  `json:"commissionCurrency"`
+	RebatesApplied     float64 `json:"rebatesApplied,string"`
+	RebateCurrency     string  `json:"rebateCurrency"`
+	ExecutionID        string  `json:"executionId"`
+}
+
+type ItBitTradeHistory struct {
+	TotalNumberOfRecords int64        `json:"totalNumberOfRecords,string"`
+	CurrentPageNumber    int64        `json:"currentPageNumber,string"`
+	LatestExecutionID    string       `json:"latestExecutionId"`
+	RecordsPerPage       int64        `json:"recordsPerPage,string"`
+	TradingHistory       []ItBitTrade `json:"tradingHistory"`
+}
+
+type ItBitWithdrawal struct {
+	WithdrawalID int64 `json:"withdrawalId"`
+}
+
+type ItBitDepositAddress struct {
+	ID             int64  `json:"id"`
+	WalletID       string `json:"walletID"`
+	DepositAddress string `json:"depositAddress"`
+}
+
+type ItBitWalletTransfer struct {
+	SourceWalletID      string  `json:"sourceWalletId"`
+	DestinationWalletID string  `json:"destinationWalletId"`
+	Amount              float64 `json:"amount,string"`
+	CurrencyCode        string  `json:"currencyCode"`
+}
@@ -1,15 +1,20 @@
 package itbit
 
 import (
+	"errors"
 	"log"
+	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/currency/pair"
 	"github.com/champii/gocryptotrader/exchanges"
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (i *ItBit) Start() {
@@ -24,7 +29,7 @@ func (i *ItBit) Run() {
 	for i.Enabled {
 		for _, x := range i.EnabledPairs {
 			currency := pair.NewCurrencyPair(x[0:3], x[3:])
-			go func() {
+			i.Poll(x, func() {
 				ticker, err := i.GetTickerPrice(currency)
 				if err != nil {
 					log.Println(err)
@@ -32,7 +37,7 @@ func (i *ItBit) Run() {
 				}
 				log.Printf("ItBit %s: Last %f High %f Low %f Volume %f\n", currency.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(i.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * i.RESTPollingDelay)
 	}
@@ -103,10 +108,371 @@ func (i *ItBit) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase, er
 	return orderBook, nil
 }
 
-//TODO Get current holdings from ItBit
-//GetExchangeAccountInfo : Retrieves balances for all enabled currencies for the ItBit exchange
+//GetExchangeAccountInfo : Retrieves the balances of every wallet for the ItBit exchange
 func (e *ItBit) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	var response exchange.ExchangeAccountInfo
 	response.ExchangeName = e.GetName()
+
+	wallets, err := e.GetWallets(url.Values{})
+	if err != nil {
+		return response, err
+	}
+
+	available := make(map[string]float64)
+	total := make(map[string]float64)
+	for _, x := range wallets {
+		for _, y := range x.Balances {
+			currency := getCurrencyName(y.Currency)
+			available[currency] += y.AvailableBalance
+			total[currency] += y.TotalBalance
+		}
+	}
+
+	var currencies []string
+	for x := range total {
+		currencies = append(currencies, x)
+	}
+	sort.Strings(currencies)
+
+	for _, x := range currencies {
+		var exchangeCurrency exchange.ExchangeAccountCurrencyInfo
+		exchangeCurrency.CurrencyName = x
+		exchangeCurrency.TotalValue = available[x]
+		exchangeCurrency.Hold = total[x] - available[x]
+		response.Currencies = append(response.Currencies, exchangeCurrency)
+	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new order on the wallet. ItBit only accepts limit orders
+func (i *ItBit) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := i.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	walletID, err := i.getWalletID()
+	if err != nil {
+		return result, err
+	}
+
+	side := "sell"
+	if order.IsBuy() {
+		side = "buy"
+	}
+
+	currency := getItBitCurrency(order.Pair.GetFirstCurrency().Upper().String())
+	response, err := i.PlaceWalletOrder(walletID, side, "limit", currency, order.Amount, order.Price, getInstrument(order.Pair), order.ClientID)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = i.GetName()
+	result.OrderID = response.ID
+	result.ClientID = order.ClientID
+	result.FilledAmount = response.AmountFilled
+	return result, nil
+}
+
+//CancelOrder : Requests the cancellation of an order by its ID
+func (i *ItBit) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	walletID, err := i.getWalletID()
+	if err != nil {
+		return err
+	}
+	return i.CancelWalletOrder(walletID, orderID)
+}
+
+//ModifyOrder : Not supported, ItBit orders can only be cancelled and placed again
+func (i *ItBit) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (i *ItBit) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	walletID, err := i.getWalletID()
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	order, err := i.GetWalletOrder(walletID, orderID)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	if order.ID == "" {
+		return exchange.OrderDetail{}, errors.New(exchange.ErrOrderNotFound)
+	}
+	return i.getOrderDetail(order), nil
+}
+
+//GetActiveOrders : Retrieves all open orders of the wallet matching the filter
+func (i *ItBit) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	orders, err := i.getWalletOrders(filter, "open")
+	if err != nil {
+		return nil, err
+	}
+	return exchange.FilterOrders(orders, getItBitFilter(filter)), nil
+}
+
+//GetOrderHistory : Retrieves the filled and cancelled orders of the wallet
+//matching the filter
+func (i *ItBit) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, x := range []string{"filled", "cancelled"} {
+		result, err := i.getWalletOrders(filter, x)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, result...)
+	}
+	return exchange.FilterOrders(orders, getItBitFilter(filter)), nil
+}
+
+//GetFills : Retrieves the executions of the wallet's orders matching the filter
+func (i *ItBit) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	walletID, err := i.getWalletID()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("perPage", strconv.Itoa(ITBIT_RECORDS_PER_PAGE))
+	if !filter.StartTime.IsZero() {
+		params.Set("rangeStart", filter.StartTime.UTC().Format(time.RFC3339))
+	}
+	if !filter.EndTime.IsZero() {
+		params.Set("rangeEnd", filter.EndTime.UTC().Format(time.RFC3339))
+	}
+
+	var fills []exchange.Fill
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		response, err := i.GetWalletTrades(walletID, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, x := range response.TradingHistory {
+			var fill exchange.Fill
+			fill.Exchange = i.GetName()
+			fill.TradeID = x.ExecutionID
+			fill.OrderID = x.OrderID
+			fill.Pair = getInstrumentPair(x.Instrument)
+			fill.Side = exchange.OrderSideBuy
+			if x.Direction == "sell" {
+				fill.Side = exchange.OrderSideSell
+			}
+			fill.Price = x.Rate
+			fill.Amount = x.Currency1Amount
+			fill.Fee = x.CommissionPaid - x.RebatesApplied
+			fill.FeeCurrency = getCurrencyName(x.CommissionCurrency)
+			fill.Timestamp, _ = time.Parse(time.RFC3339Nano, x.Timestamp)
+			fills = append(fills, fill)
+		}
+
+		if len(response.TradingHistory) < ITBIT_RECORDS_PER_PAGE || int64(page*ITBIT_RECORDS_PER_PAGE) >= response.TotalNumberOfRecords {
+			break
+		}
+	}
+	return exchange.FilterFills(fills, getItBitFilter(filter)), nil
+}
+
+//getWalletOrders returns every order of the wallet with the status, paging
+//through each filter pair's instrument
+func (i *ItBit) getWalletOrders(filter exchange.OrderFilter, status string) ([]exchange.OrderDetail, error) {
+	walletID, err := i.getWalletID()
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range i.getFilterPairs(filter) {
+		params := url.Values{}
+		params.Set("instrument", getInstrument(x))
+		params.Set("status", status)
+		params.Set("perPage", strconv.Itoa(ITBIT_RECORDS_PER_PAGE))
+
+		for page := 1; ; page++ {
+			params.Set("page", strconv.Itoa(page))
+			response, err := i.GetWalletOrders(walletID, params)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, y := range response {
+				orders = append(orders, i.getOrderDetail(y))
+			}
+
+			if len(response) < ITBIT_RECORDS_PER_PAGE {
+				break
+			}
+		}
+	}
+	return orders, nil
+}
+
+//getWalletID returns the configured wallet, or the first wallet of the account
+func (i *ItBit) getWalletID() (string, error) {
+	if i.WalletID != "" {
+		return i.WalletID, nil
+	}
+
+	wallets, err := i.GetWallets(url.Values{})
+	if err != nil {
+		return "", err
+	}
+
+	if len(wallets) == 0 {
+		return "", errors.New(ErrItBitNoWallet)
+	}
+	return wallets[0].ID, nil
+}
+
+func (i *ItBit) getFilterPairs(filter exchange.OrderFilter) []pair.CurrencyPair {
+	if len(filter.Pairs) > 0 {
+		return filter.Pairs
+	}
+
+	var pairs []pair.CurrencyPair
+	for _, x := range i.EnabledPairs {
+		pairs = append(pairs, pair.NewCurrencyPair(x[0:3], x[3:]))
+	}
+	return pairs
+}
+
+func (i *ItBit) getOrderDetail(order ItBitOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = i.GetName()
+	detail.OrderID = order.ID
+	detail.ClientID = order.ClientOrderIdentifier
+	detail.Pair = getInstrumentPair(order.Instrument)
+	detail.Side = exchange.OrderSideBuy
+	if order.Side == "sell" {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	detail.Price = order.Price
+	detail.Amount = order.Amount
+	detail.FilledAmount = order.AmountFilled
+	detail.AveragePrice = order.VolumeWeightedAveragePrice
+	detail.CreatedAt, _ = time.Parse(time.RFC3339Nano, order.CreatedTime)
+
+	if order.Status == "rejected" {
+		detail.Status = exchange.OrderStatusRejected
+	} else {
+		active := order.Status == "submitted" || order.Status == "open"
+		detail.Status = exchange.GetOrderStatus(order.Amount, order.AmountFilled, active, order.Status == "cancelled")
+	}
+	return detail
+}
+
+//getInstrument returns the ItBit instrument of a pair, such as XBTUSD
+func getInstrument(p pair.CurrencyPair) string {
+	return getItBitCurrency(p.GetFirstCurrency().Upper().String()) + getItBitCurrency(p.GetSecondCurrency().Upper().String())
+}
+
+//getInstrumentPair returns the pair of an ItBit instrument with its currencies
+//named as elsewhere in the bot, so XBTUSD becomes BTC/USD
+func getInstrumentPair(instrument string) pair.CurrencyPair {
+	if len(instrument) < 6 {
+		return pair.NewCurrencyPair(getCurrencyName(instrument), "")
+	}
+	return pair.NewCurrencyPair(getCurrencyName(instrument[0:3]), getCurrencyName(instrument[3:]))
+}
+
+//getItBitFilter returns the filter with its pairs named as the pairs of the
+//orders and fills, so filters using XBT still match
+func getItBitFilter(filter exchange.OrderFilter) exchange.OrderFilter {
+	pairs := filter.Pairs
+	filter.Pairs = nil
+	for _, x := range pairs {
+		filter.Pairs = append(filter.Pairs, pair.NewCurrencyPair(getCurrencyName(x.GetFirstCurrency().String()), getCurrencyName(x.GetSecondCurrency().String())))
+	}
+	return filter
+}
+
+//getItBitCurrency returns the ItBit code of a currency, ItBit lists bitcoin as
+//XBT
+func getItBitCurrency(currency string) string {
+	if currency == "BTC" {
+		return "XBT"
+	}
+	return currency
+}
+
+func getCurrencyName(currency string) string {
+	if common.StringToUpper(currency) == "XBT" {
+		return "BTC"
+	}
+	return common.StringToUpper(currency)
+}
+
+//GetDepositAddress : Creates a deposit address of the currency for the wallet
+func (i *ItBit) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	walletID, err := i.getWalletID()
+	if err != nil {
+		return exchange.DepositAddress{}, exchange.NewFundingError(i.GetName(), currency, err)
+	}
+
+	response, err := i.GetCryptoDepositAddress(walletID, getItBitCurrency(common.StringToUpper(currency)))
+	if err != nil {
+		return exchange.DepositAddress{}, exchange.NewFundingError(i.GetName(), currency, err)
+	}
+
+	var result exchange.DepositAddress
+	result.Exchange = i.GetName()
+	result.Currency = currency
+	result.Address = response.DepositAddress
+	return result, nil
+}
+
+//WithdrawCryptocurrency : Withdraws a cryptocurrency from the wallet
+func (i *ItBit) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	err := request.Validate()
+	if err != nil {
+		return exchange.WithdrawResult{}, exchange.NewFundingError(i.GetName(), request.Currency, err)
+	}
+
+	if request.Tag != "" {
+		return exchange.WithdrawResult{}, exchange.NewFundingError(i.GetName(), request.Currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	walletID, err := i.getWalletID()
+	if err != nil {
+		return exchange.WithdrawResult{}, exchange.NewFundingError(i.GetName(), request.Currency, err)
+	}
+
+	withdrawalID, err := i.PlaceWithdrawalRequest(walletID, getItBitCurrency(common.StringToUpper(request.Currency)), request.Address, request.Amount)
+	if err != nil {
+		return exchange.WithdrawResult{}, exchange.NewFundingError(i.GetName(), request.Currency, err)
+	}
+
+	var result exchange.WithdrawResult
+	result.Exchange = i.GetName()
+	result.WithdrawalID = strconv.FormatInt(withdrawalID, 10)
+	result.Currency = request.Currency
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//UpdatePairInfo : Not supported, ItBit does not publish pair constraints
+func (i *ItBit) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, ItBit has no candle endpoint
+func (i *ItBit) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Not supported, the ItBit REST client does not parse trade responses yet
+func (i *ItBit) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
@@ -1,16 +1,15 @@
 package kraken
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
+	"github.com/champii/gocryptotrader/currency/pair"
 	"github.com/champii/gocryptotrader/exchanges"
 )
 
@@ -40,10 +39,27 @@ const (
 	KRAKEN_ORDER_PLACE    = "AddOrder"
 )
 
+const (
+	KRAKEN_AUTH_RATE_LIMIT   = 20
+	KRAKEN_UNAUTH_RATE_LIMIT = 60
+	KRAKEN_RESULTS_LIMIT     = 50
+)
+
+const (
+	ErrKrakenPairNotFound    = "Kraken returned no data for %s."
+	ErrKrakenNoTransactionID = "Kraken returned no transaction ID for the order."
+)
+
+var krakenErrorRules = []exchange.ErrorRule{
+	{Match: "eservice:unavailable", Kind: exchange.ErrorKindMarketClosed},
+	{Match: "eservice:busy", Kind: exchange.ErrorKindTransport},
+	{Match: "egeneral:temporary lockout", Kind: exchange.ErrorKindRateLimited},
+	{Match: "eorder:cannot open position", Kind: exchange.ErrorKindInsufficientFunds},
+}
+
 type Kraken struct {
 	exchange.ExchangeBase
 	CryptoFee, FiatFee float64
-	Ticker             map[string]KrakenTicker
 }
 
 func (k *Kraken) SetDefaults() {
@@ -54,7 +70,7 @@ func (k *Kraken) SetDefaults() {
 	k.Verbose = false
 	k.Websocket = false
 	k.RESTPollingDelay = 10
-	k.Ticker = make(map[string]KrakenTicker)
+	k.SetRateLimit(KRAKEN_AUTH_RATE_LIMIT, KRAKEN_UNAUTH_RATE_LIMIT)
 }
 
 func (k *Kraken) Setup(exch config.ExchangeConfig) {
@@ -65,6 +81,11 @@ func (k *Kraken) Setup(exch config.ExchangeConfig) {
 		k.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		k.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		k.RESTPollingDelay = exch.RESTPollingDelay
+		k.UpdateRateLimit(exch)
+		err := k.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", k.GetName(), err)
+		}
 		k.Verbose = exch.Verbose
 		k.Websocket = exch.Websocket
 		k.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -81,161 +102,335 @@ func (k *Kraken) GetFee(cryptoTrade bool) float64 {
 	}
 }
 
-func (k *Kraken) GetServerTime() error {
-	var result interface{}
-	path := fmt.Sprintf("%s/%s/public/%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_SERVER_TIME)
-	err := common.SendHTTPGetRequest(path, true, &result)
+//GetKrakenAssetName returns the common name of a Kraken asset. Kraken prefixes
+//the older crypto assets with X and fiat with Z, and names bitcoin XBT
+func GetKrakenAssetName(asset string) string {
+	asset = common.StringToUpper(asset)
+	if len(asset) == 4 && (asset[0] == 'X' || asset[0] == 'Z') {
+		asset = asset[1:]
+	}
 
-	if err != nil {
-		return err
+	switch asset {
+	case "XBT":
+		return "BTC"
+	case "XDG":
+		return "DOGE"
 	}
+	return asset
+}
 
-	log.Println(result)
-	return nil
+//GetKrakenSymbol returns the Kraken altname of a currency
+func GetKrakenSymbol(currency string) string {
+	currency = common.StringToUpper(currency)
+	switch currency {
+	case "BTC":
+		return "XBT"
+	case "DOGE":
+		return "XDG"
+	}
+	return currency
 }
 
-func (k *Kraken) GetAssets() error {
-	var result interface{}
-	path := fmt.Sprintf("%s/%s/public/%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_ASSETS)
-	err := common.SendHTTPGetRequest(path, true, &result)
+//GetKrakenPairAltname converts a Kraken pair name such as XXBTZUSD to the
+//altname XBTUSD used in the config. Dark pool suffixes are dropped
+func GetKrakenPairAltname(name string) string {
+	name = strings.TrimSuffix(common.StringToUpper(name), ".D")
+	if len(name) == 8 && (name[0] == 'X' || name[0] == 'Z') && (name[4] == 'X' || name[4] == 'Z') {
+		return name[1:4] + name[5:]
+	}
+	return name
+}
 
-	if err != nil {
-		return err
+//GetKrakenPair splits a Kraken pair name into its currencies. Every Kraken
+//quote currency has a three letter altname
+func GetKrakenPair(name string) pair.CurrencyPair {
+	altname := GetKrakenPairAltname(name)
+	if len(altname) < 6 {
+		return pair.NewCurrencyPair(altname, "")
 	}
+	return pair.NewCurrencyPair(altname[:len(altname)-3], altname[len(altname)-3:])
+}
 
-	log.Println(result)
-	return nil
+//getKrakenPairSymbol returns the Kraken altname of a pair
+func getKrakenPairSymbol(p pair.CurrencyPair) string {
+	return GetKrakenSymbol(p.GetFirstCurrency().String()) + GetKrakenSymbol(p.GetSecondCurrency().String())
+}
+
+func (k *Kraken) GetServerTime() (KrakenServerTime, error) {
+	result := KrakenServerTime{}
+	err := k.SendPublicHTTPRequest(KRAKEN_SERVER_TIME, nil, &result)
+	return result, err
+}
+
+func (k *Kraken) GetAssets() (map[string]KrakenAsset, error) {
+	result := make(map[string]KrakenAsset)
+	err := k.SendPublicHTTPRequest(KRAKEN_ASSETS, nil, &result)
+	return result, err
 }
 
 func (k *Kraken) GetAssetPairs() (map[string]KrakenAssetPairs, error) {
-	type Response struct {
-		Result map[string]KrakenAssetPairs `json:"result"`
-		Error  []interface{}               `json:"error"`
+	result := make(map[string]KrakenAssetPairs)
+	err := k.SendPublicHTTPRequest(KRAKEN_ASSET_PAIRS, nil, &result)
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	response := Response{}
-	path := fmt.Sprintf("%s/%s/public/%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_ASSET_PAIRS)
-	err := common.SendHTTPGetRequest(path, true, &response)
+//GetTicker returns the tickers of a comma separated list of pairs keyed by
+//their altname
+func (k *Kraken) GetTicker(symbol string) (map[string]KrakenTicker, error) {
+	values := url.Values{}
+	values.Set("pair", symbol)
 
+	resp := make(map[string]KrakenTickerResponse)
+	err := k.SendPublicHTTPRequest(KRAKEN_TICKER, values, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	return response.Result, nil
+	result := make(map[string]KrakenTicker)
+	for x, y := range resp {
+		ticker := KrakenTicker{}
+		ticker.Ask = getKrakenStringsFloat(y.Ask, 0)
+		ticker.Bid = getKrakenStringsFloat(y.Bid, 0)
+		ticker.Last = getKrakenStringsFloat(y.Last, 0)
+		ticker.Volume = getKrakenStringsFloat(y.Volume, 1)
+		ticker.VWAP = getKrakenStringsFloat(y.VWAP, 1)
+		if len(y.Trades) > 1 {
+			ticker.Trades = y.Trades[1]
+		}
+		ticker.Low = getKrakenStringsFloat(y.Low, 1)
+		ticker.High = getKrakenStringsFloat(y.High, 1)
+		ticker.Open, _ = strconv.ParseFloat(y.Open, 64)
+
+		altname := GetKrakenPairAltname(x)
+		result[altname] = ticker
+	}
+	return result, nil
 }
 
-func (k *Kraken) GetTicker(symbol string) error {
+func (k *Kraken) GetOHLC(symbol string, interval int, since int64) ([]KrakenOHLC, int64, error) {
 	values := url.Values{}
 	values.Set("pair", symbol)
 
-	type Response struct {
-		Error []interface{}                   `json:"error"`
-		Data  map[string]KrakenTickerResponse `json:"result"`
+	if interval > 0 {
+		values.Set("interval", strconv.Itoa(interval))
 	}
 
-	resp := Response{}
-	path := fmt.Sprintf("%s/%s/public/%s?%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_TICKER, values.Encode())
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	if since > 0 {
+		values.Set("since", strconv.FormatInt(since, 10))
+	}
 
+	resp := make(map[string]interface{})
+	err := k.SendPublicHTTPRequest(KRAKEN_OHLC, values, &resp)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
-	if len(resp.Error) > 0 {
-		return errors.New(fmt.Sprintf("Kraken error: %s", resp.Error))
+	var last int64
+	if cursor, ok := resp["last"].(float64); ok {
+		last = int64(cursor)
 	}
 
-	for x, y := range resp.Data {
-		x = x[1:4] + x[5:]
-		ticker := KrakenTicker{}
-		ticker.Ask, _ = strconv.ParseFloat(y.Ask[0], 64)
-		ticker.Bid, _ = strconv.ParseFloat(y.Bid[0], 64)
-		ticker.Last, _ = strconv.ParseFloat(y.Last[0], 64)
-		ticker.Volume, _ = strconv.ParseFloat(y.Volume[1], 64)
-		ticker.VWAP, _ = strconv.ParseFloat(y.VWAP[1], 64)
-		ticker.Trades = y.Trades[1]
-		ticker.Low, _ = strconv.ParseFloat(y.Low[1], 64)
-		ticker.High, _ = strconv.ParseFloat(y.High[1], 64)
-		ticker.Open, _ = strconv.ParseFloat(y.Open, 64)
-		k.Ticker[x] = ticker
-	}
-	return nil
+	rows, err := getKrakenPairResult(resp, symbol)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ohlc := []KrakenOHLC{}
+	for _, fields := range rows {
+		if len(fields) < 8 {
+			continue
+		}
+
+		var candle KrakenOHLC
+		candle.Time = int64(getKrakenFloat(fields[0]))
+		candle.Open = getKrakenFloat(fields[1])
+		candle.High = getKrakenFloat(fields[2])
+		candle.Low = getKrakenFloat(fields[3])
+		candle.Close = getKrakenFloat(fields[4])
+		candle.VWAP = getKrakenFloat(fields[5])
+		candle.Volume = getKrakenFloat(fields[6])
+		candle.Count = int64(getKrakenFloat(fields[7]))
+		ohlc = append(ohlc, candle)
+	}
+	return ohlc, last, nil
 }
 
-func (k *Kraken) GetOHLC(symbol string) error {
-	values := url.Values{}
-	values.Set("pair", symbol)
-
-	var result interface{}
-	path := fmt.Sprintf("%s/%s/public/%s?%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_OHLC, values.Encode())
-	err := common.SendHTTPGetRequest(path, true, &result)
+func getKrakenFloat(value interface{}) float64 {
+	switch x := value.(type) {
+	case string:
+		result, _ := strconv.ParseFloat(x, 64)
+		return result
+	case float64:
+		return x
+	}
+	return 0
+}
 
-	if err != nil {
-		return err
+func getKrakenStringsFloat(values []string, index int) float64 {
+	if index >= len(values) {
+		return 0
 	}
+	result, _ := strconv.ParseFloat(values[index], 64)
+	return result
+}
 
-	log.Println(result)
-	return nil
+//getKrakenPairResult returns the rows of the pair keyed result of a single
+//pair request. Kraken keys the result by its own pair name, which may differ
+//from the requested altname, next to the last cursor
+func getKrakenPairResult(result map[string]interface{}, symbol string) ([][]interface{}, error) {
+	for key, value := range result {
+		if key == "last" {
+			continue
+		}
+
+		rows, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+
+		var fields [][]interface{}
+		for _, row := range rows {
+			if x, ok := row.([]interface{}); ok {
+				fields = append(fields, x)
+			}
+		}
+		return fields, nil
+	}
+	return nil, fmt.Errorf(ErrKrakenPairNotFound, symbol)
 }
 
-func (k *Kraken) GetDepth(symbol string) error {
+func (k *Kraken) GetDepth(symbol string, count int) (KrakenOrderbook, error) {
 	values := url.Values{}
 	values.Set("pair", symbol)
 
-	var result interface{}
-	path := fmt.Sprintf("%s/%s/public/%s?%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_DEPTH, values.Encode())
-	err := common.SendHTTPGetRequest(path, true, &result)
+	if count > 0 {
+		values.Set("count", strconv.Itoa(count))
+	}
+
+	type Response struct {
+		Asks [][]interface{} `json:"asks"`
+		Bids [][]interface{} `json:"bids"`
+	}
 
+	orderbook := KrakenOrderbook{}
+	resp := make(map[string]Response)
+	err := k.SendPublicHTTPRequest(KRAKEN_DEPTH, values, &resp)
 	if err != nil {
-		return err
+		return orderbook, err
 	}
 
-	log.Println(result)
-	return nil
+	if len(resp) == 0 {
+		return orderbook, fmt.Errorf(ErrKrakenPairNotFound, symbol)
+	}
+
+	for _, x := range resp {
+		orderbook.Asks = getKrakenOrderbookItems(x.Asks)
+		orderbook.Bids = getKrakenOrderbookItems(x.Bids)
+	}
+	return orderbook, nil
+}
+
+func getKrakenOrderbookItems(rows [][]interface{}) []KrakenOrderbookItem {
+	var items []KrakenOrderbookItem
+	for _, x := range rows {
+		if len(x) < 3 {
+			continue
+		}
+		items = append(items, KrakenOrderbookItem{
+			Price:     getKrakenFloat(x[0]),
+			Amount:    getKrakenFloat(x[1]),
+			Timestamp: int64(getKrakenFloat(x[2])),
+		})
+	}
+	return items
 }
 
-func (k *Kraken) GetTrades(symbol string) error {
+func (k *Kraken) GetTrades(symbol string, since int64) ([]KrakenTrade, error) {
 	values := url.Values{}
 	values.Set("pair", symbol)
 
-	var result interface{}
-	path := fmt.Sprintf("%s/%s/public/%s?%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_TRADES, values.Encode())
-	err := common.SendHTTPGetRequest(path, true, &result)
+	if since > 0 {
+		values.Set("since", strconv.FormatInt(since, 10))
+	}
+
+	resp := make(map[string]interface{})
+	err := k.SendPublicHTTPRequest(KRAKEN_TRADES, values, &resp)
+	if err != nil {
+		return nil, err
+	}
 
+	rows, err := getKrakenPairResult(resp, symbol)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	log.Println(result)
-	return nil
+	var result []KrakenTrade
+	for _, x := range rows {
+		if len(x) < 6 {
+			continue
+		}
+
+		trade := KrakenTrade{
+			Price:  getKrakenFloat(x[0]),
+			Volume: getKrakenFloat(x[1]),
+			Time:   getKrakenFloat(x[2]),
+		}
+		trade.BuyOrSell, _ = x[3].(string)
+		trade.MarketOrLimit, _ = x[4].(string)
+		trade.Misc, _ = x[5].(string)
+		result = append(result, trade)
+	}
+	return result, nil
 }
 
-func (k *Kraken) GetSpread(symbol string) {
+func (k *Kraken) GetSpread(symbol string) ([]KrakenSpread, error) {
 	values := url.Values{}
 	values.Set("pair", symbol)
 
-	var result interface{}
-	path := fmt.Sprintf("%s/%s/public/%s?%s", KRAKEN_API_URL, KRAKEN_API_VERSION, KRAKEN_SPREAD, values.Encode())
-	err := common.SendHTTPGetRequest(path, true, &result)
+	resp := make(map[string]interface{})
+	err := k.SendPublicHTTPRequest(KRAKEN_SPREAD, values, &resp)
+	if err != nil {
+		return nil, err
+	}
 
+	rows, err := getKrakenPairResult(resp, symbol)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-}
 
-func (k *Kraken) GetBalance() {
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_BALANCE, url.Values{})
+	var result []KrakenSpread
+	for _, x := range rows {
+		if len(x) < 3 {
+			continue
+		}
+		result = append(result, KrakenSpread{
+			Time: int64(getKrakenFloat(x[0])),
+			Bid:  getKrakenFloat(x[1]),
+			Ask:  getKrakenFloat(x[2]),
+		})
+	}
+	return result, nil
+}
 
+//GetBalance returns the account balances keyed by the Kraken asset name
+func (k *Kraken) GetBalance() (map[string]float64, error) {
+	resp := make(map[string]string)
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_BALANCE, url.Values{}, &resp)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
 
-	log.Println(result)
+	result := make(map[string]float64)
+	for x, y := range resp {
+		result[x], _ = strconv.ParseFloat(y, 64)
+	}
+	return result, nil
 }
 
-func (k *Kraken) GetTradeBalance(symbol, asset string) {
+func (k *Kraken) GetTradeBalance(symbol, asset string) (KrakenTradeBalance, error) {
 	values := url.Values{}
 
 	if len(symbol) > 0 {
@@ -246,17 +441,13 @@ func (k *Kraken) GetTradeBalance(symbol, asset string) {
 		values.Set("asset", asset)
 	}
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_TRADE_BALANCE, values)
-
-	if err != nil {
-		log.Println(err)
-		return
-	}
-
-	log.Println(result)
+	result := KrakenTradeBalance{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_TRADE_BALANCE, values, &result)
+	return result, err
 }
 
-func (k *Kraken) GetOpenOrders(showTrades bool, userref int64) {
+//GetOpenOrders returns the open orders keyed by their transaction ID
+func (k *Kraken) GetOpenOrders(showTrades bool, userref int64) (map[string]KrakenOrder, error) {
 	values := url.Values{}
 
 	if showTrades {
@@ -267,17 +458,17 @@ func (k *Kraken) GetOpenOrders(showTrades bool, userref int64) {
 		values.Set("userref", strconv.FormatInt(userref, 10))
 	}
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_OPEN_ORDERS, values)
-
+	result := KrakenOpenOrders{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_OPEN_ORDERS, values, &result)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-
-	log.Println(result)
+	return result.Open, nil
 }
 
-func (k *Kraken) GetClosedOrders(showTrades bool, userref, start, end, offset int64, closetime string) {
+//GetClosedOrders returns a page of at most KRAKEN_RESULTS_LIMIT closed orders,
+//newest first, and the number of orders matching the range
+func (k *Kraken) GetClosedOrders(showTrades bool, userref, start, end, offset int64, closetime string) (KrakenClosedOrders, error) {
 	values := url.Values{}
 
 	if showTrades {
@@ -304,17 +495,14 @@ func (k *Kraken) GetClosedOrders(showTrades bool, userref, start, end, offset in
 		values.Set("closetime", closetime)
 	}
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_CLOSED_ORDERS, values)
-
-	if err != nil {
-		log.Println(err)
-		return
-	}
-
-	log.Println(result)
+	result := KrakenClosedOrders{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_CLOSED_ORDERS, values, &result)
+	return result, err
 }
 
-func (k *Kraken) QueryOrdersInfo(showTrades bool, userref, txid int64) {
+//QueryOrdersInfo returns the orders of a comma separated list of transaction
+//IDs
+func (k *Kraken) QueryOrdersInfo(showTrades bool, userref int64, txid string) (map[string]KrakenOrder, error) {
 	values := url.Values{}
 
 	if showTrades {
@@ -325,25 +513,25 @@ func (k *Kraken) QueryOrdersInfo(showTrades bool, userref, txid int64) {
 		values.Set("userref", strconv.FormatInt(userref, 10))
 	}
 
-	if txid != 0 {
-		values.Set("txid", strconv.FormatInt(userref, 10))
+	if len(txid) > 0 {
+		values.Set("txid", txid)
 	}
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_QUERY_ORDERS, values)
-
+	result := make(map[string]KrakenOrder)
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_QUERY_ORDERS, values, &result)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-
-	log.Println(result)
+	return result, nil
 }
 
-func (k *Kraken) GetTradesHistory(tradeType string, showRelatedTrades bool, start, end, offset int64) {
+//GetTradesHistory returns a page of at most KRAKEN_RESULTS_LIMIT trades,
+//newest first, and the number of trades matching the range
+func (k *Kraken) GetTradesHistory(tradeType string, showRelatedTrades bool, start, end, offset int64) (KrakenTradesHistory, error) {
 	values := url.Values{}
 
 	if len(tradeType) > 0 {
-		values.Set("aclass", tradeType)
+		values.Set("type", tradeType)
 	}
 
 	if showRelatedTrades {
@@ -359,56 +547,50 @@ func (k *Kraken) GetTradesHistory(tradeType string, showRelatedTrades bool, star
 	}
 
 	if offset != 0 {
-		values.Set("offset", strconv.FormatInt(offset, 10))
-	}
-
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_TRADES_HISTORY, values)
-
-	if err != nil {
-		log.Println(err)
-		return
+		values.Set("ofs", strconv.FormatInt(offset, 10))
 	}
 
-	log.Println(result)
+	result := KrakenTradesHistory{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_TRADES_HISTORY, values, &result)
+	return result, err
 }
 
-func (k *Kraken) QueryTrades(txid int64, showRelatedTrades bool) {
+func (k *Kraken) QueryTrades(txid string, showRelatedTrades bool) (map[string]KrakenTradeInfo, error) {
 	values := url.Values{}
-	values.Set("txid", strconv.FormatInt(txid, 10))
+	values.Set("txid", txid)
 
 	if showRelatedTrades {
 		values.Set("trades", "true")
 	}
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_QUERY_TRADES, values)
-
+	result := make(map[string]KrakenTradeInfo)
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_QUERY_TRADES, values, &result)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-
-	log.Println(result)
+	return result, nil
 }
 
-func (k *Kraken) OpenPositions(txid int64, showPL bool) {
+func (k *Kraken) OpenPositions(txid string, showPL bool) (map[string]KrakenPosition, error) {
 	values := url.Values{}
-	values.Set("txid", strconv.FormatInt(txid, 10))
+
+	if len(txid) > 0 {
+		values.Set("txid", txid)
+	}
 
 	if showPL {
 		values.Set("docalcs", "true")
 	}
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_OPEN_POSITIONS, values)
-
+	result := make(map[string]KrakenPosition)
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_OPEN_POSITIONS, values, &result)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-
-	log.Println(result)
+	return result, nil
 }
 
-func (k *Kraken) GetLedgers(symbol, asset, ledgerType string, start, end, offset int64) {
+func (k *Kraken) GetLedgers(symbol, asset, ledgerType string, start, end, offset int64) (KrakenLedgers, error) {
 	values := url.Values{}
 
 	if len(symbol) > 0 {
@@ -432,111 +614,142 @@ func (k *Kraken) GetLedgers(symbol, asset, ledgerType string, start, end, offset
 	}
 
 	if offset != 0 {
-		values.Set("offset", strconv.FormatInt(offset, 10))
-	}
-
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_LEDGERS, values)
-
-	if err != nil {
-		log.Println(err)
-		return
+		values.Set("ofs", strconv.FormatInt(offset, 10))
 	}
 
-	log.Println(result)
+	result := KrakenLedgers{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_LEDGERS, values, &result)
+	return result, err
 }
 
-func (k *Kraken) QueryLedgers(id string) {
+func (k *Kraken) QueryLedgers(id string) (map[string]KrakenLedger, error) {
 	values := url.Values{}
 	values.Set("id", id)
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_QUERY_LEDGERS, values)
-
+	result := make(map[string]KrakenLedger)
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_QUERY_LEDGERS, values, &result)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-
-	log.Println(result)
+	return result, nil
 }
 
-func (k *Kraken) GetTradeVolume(symbol string) {
+//GetTradeVolume returns the 30 day volume and, when pairs are given, their
+//fee tiers
+func (k *Kraken) GetTradeVolume(symbol string) (KrakenTradeVolume, error) {
 	values := url.Values{}
-	values.Set("pair", symbol)
-
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_TRADE_VOLUME, values)
 
-	if err != nil {
-		log.Println(err)
-		return
+	if len(symbol) > 0 {
+		values.Set("pair", symbol)
+		values.Set("fee-info", "true")
 	}
 
-	log.Println(result)
+	result := KrakenTradeVolume{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_TRADE_VOLUME, values, &result)
+	return result, err
 }
 
-func (k *Kraken) AddOrder(symbol, side, orderType string, price, price2, volume, leverage, position float64) {
+//AddOrder places an order. Price2, leverage and userref are only sent when set
+func (k *Kraken) AddOrder(symbol, side, orderType string, price, price2, volume, leverage float64, userref int64) (KrakenAddOrderResponse, error) {
 	values := url.Values{}
-	values.Set("pairs", symbol)
+	values.Set("pair", symbol)
 	values.Set("type", side)
 	values.Set("ordertype", orderType)
-	values.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
-	values.Set("price2", strconv.FormatFloat(price, 'f', -1, 64))
 	values.Set("volume", strconv.FormatFloat(volume, 'f', -1, 64))
-	values.Set("leverage", strconv.FormatFloat(leverage, 'f', -1, 64))
-	values.Set("position", strconv.FormatFloat(position, 'f', -1, 64))
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_ORDER_PLACE, values)
+	if price != 0 {
+		values.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
+	}
 
-	if err != nil {
-		log.Println(err)
-		return
+	if price2 != 0 {
+		values.Set("price2", strconv.FormatFloat(price2, 'f', -1, 64))
+	}
+
+	if leverage != 0 {
+		values.Set("leverage", strconv.FormatFloat(leverage, 'f', -1, 64))
+	}
+
+	if userref != 0 {
+		values.Set("userref", strconv.FormatInt(userref, 10))
 	}
 
-	log.Println(result)
+	result := KrakenAddOrderResponse{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_ORDER_PLACE, values, &result)
+	return result, err
 }
 
-func (k *Kraken) CancelOrder(orderID int64) {
+func (k *Kraken) CancelExistingOrder(txid string) (KrakenCancelOrderResponse, error) {
 	values := url.Values{}
-	values.Set("txid", strconv.FormatInt(orderID, 10))
+	values.Set("txid", txid)
 
-	result, err := k.SendAuthenticatedHTTPRequest(KRAKEN_ORDER_CANCEL, values)
+	result := KrakenCancelOrderResponse{}
+	err := k.SendAuthenticatedHTTPRequest(KRAKEN_ORDER_CANCEL, values, &result)
+	return result, err
+}
+
+//SendPublicHTTPRequest sends a public request and decodes its result into
+//result
+func (k *Kraken) SendPublicHTTPRequest(method string, values url.Values, result interface{}) error {
+	path := fmt.Sprintf("%s/%s/public/%s", k.GetAPIUrl(KRAKEN_API_URL), KRAKEN_API_VERSION, method)
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
 
+	resp := KrakenResponse{Result: result}
+	err := k.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
-		log.Println(err)
-		return
+		return err
 	}
 
-	log.Println(result)
+	if len(resp.Error) > 0 {
+		return exchange.NewExchangeError(k.Name, fmt.Errorf("Kraken error: %s", strings.Join(resp.Error, ", ")), "", krakenErrorRules...)
+	}
+	return nil
 }
 
-func (k *Kraken) SendAuthenticatedHTTPRequest(method string, values url.Values) (interface{}, error) {
+//SendAuthenticatedHTTPRequest sends a signed request and decodes its result
+//into result
+func (k *Kraken) SendAuthenticatedHTTPRequest(method string, values url.Values, result interface{}) error {
+	k.WaitRateLimit(true)
 	path := fmt.Sprintf("/%s/private/%s", KRAKEN_API_VERSION, method)
-	values.Set("nonce", strconv.FormatInt(time.Now().UnixNano(), 10))
+	values.Set("nonce", k.GetNonce().GetString())
 	secret, err := common.Base64Decode(k.APISecret)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	shasum := common.GetSHA256([]byte(values.Get("nonce") + values.Encode()))
 	signature := common.Base64Encode(common.GetHMAC(common.HASH_SHA512, append([]byte(path), shasum...), secret))
 
 	if k.Verbose {
-		log.Printf("Sending POST request to %s, path: %s.", KRAKEN_API_URL, path)
+		log.Printf("Sending POST request to %s, path: %s.", k.GetAPIUrl(KRAKEN_API_URL), path)
 	}
 
 	headers := make(map[string]string)
 	headers["API-Key"] = k.APIKey
 	headers["API-Sign"] = signature
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	resp, err := common.SendHTTPRequest("POST", KRAKEN_API_URL+path, headers, strings.NewReader(values.Encode()))
+	resp, err := k.SendHTTPRequest("POST", k.GetAPIUrl(KRAKEN_API_URL)+path, headers, strings.NewReader(values.Encode()))
 
 	if err != nil {
-		return nil, err
+		return k.CheckNonceError(exchange.NewExchangeError(k.Name, err, resp, krakenErrorRules...))
 	}
 
 	if k.Verbose {
 		log.Printf("Recieved raw: \n%s\n", resp)
 	}
 
-	return resp, nil
+	response := KrakenResponse{Result: result}
+	err = common.JSONDecode([]byte(resp), &response)
+
+	if err != nil {
+		return k.CheckNonceError(exchange.NewExchangeError(k.Name, fmt.Errorf("Unable to JSON Unmarshal response: %s", err), resp))
+	}
+
+	if len(response.Error) > 0 {
+		return k.CheckNonceError(exchange.NewExchangeError(k.Name, fmt.Errorf("Kraken error: %s", strings.Join(response.Error, ", ")), resp, krakenErrorRules...))
+	}
+	return nil
 }
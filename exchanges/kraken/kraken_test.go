@@ -0,0 +1,203 @@
+package kraken
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+)
+
+func TestGetKrakenAssetName(t *testing.T) {
+	names := map[string]string{
+		"XXBT": "BTC",
+		"ZUSD": "USD",
+		"XXDG": "DOGE",
+		"XETC": "ETC",
+		"DASH": "DASH",
+		"USDT": "USDT",
+		"EOS":  "EOS",
+	}
+	for x, y := range names {
+		if result := GetKrakenAssetName(x); result != y {
+			t.Errorf("Test Failed - GetKrakenAssetName(%s) expected %s, received %s", x, y, result)
+		}
+	}
+
+	if result := GetKrakenSymbol("btc"); result != "XBT" {
+		t.Errorf("Test Failed - GetKrakenSymbol() expected XBT, received %s", result)
+	}
+}
+
+func TestGetKrakenPair(t *testing.T) {
+	names := map[string]string{
+		"XXBTZUSD":   "XBTUSD",
+		"XETHXXBT.d": "ETHXBT",
+		"DASHEUR":    "DASHEUR",
+		"XBTUSD":     "XBTUSD",
+	}
+	for x, y := range names {
+		if result := GetKrakenPairAltname(x); result != y {
+			t.Errorf("Test Failed - GetKrakenPairAltname(%s) expected %s, received %s", x, y, result)
+		}
+	}
+
+	p := GetKrakenPair("DASHXBT")
+	if p.GetFirstCurrency().String() != "DASH" || p.GetSecondCurrency().String() != "XBT" {
+		t.Errorf("Test Failed - GetKrakenPair() incorrect: %s", p.Pair())
+	}
+
+	if symbol := getKrakenPairSymbol(pair.NewCurrencyPair("BTC", "EUR")); symbol != "XBTEUR" {
+		t.Errorf("Test Failed - getKrakenPairSymbol() expected XBTEUR, received %s", symbol)
+	}
+}
+
+func TestPublicEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/0/public/Ticker":
+			w.Write([]byte(`{"error":[],"result":{"XXBTZEUR":{"a":["1001.5","1","1.000"],"b":["1000.5","2","2.000"],"c":["1001.0","0.1"],"v":["10","150.5"],"p":["999","998"],"t":[5,60],"l":["990","980"],"h":["1010","1020"],"o":"995"}}}`))
+		case "/0/public/Depth":
+			w.Write([]byte(`{"error":[],"result":{"XXBTZEUR":{"asks":[["1001.5","1.5",1500000000]],"bids":[["1000.5","2.5",1500000001],["1000.0","3",1500000002]]}}}`))
+		case "/0/public/Trades":
+			w.Write([]byte(`{"error":[],"result":{"XXBTZEUR":[["1000.1","0.5",1500000000.25,"s","l",""],["1000.2","0.75",1500000001.5,"b","m",""]],"last":"1500000001500000000"}}`))
+		case "/0/public/Spread":
+			w.Write([]byte(`{"error":["EQuery:Unknown asset pair"]}`))
+		}
+	}))
+	defer server.Close()
+
+	k := Kraken{}
+	k.SetDefaults()
+	k.Name = "Kraken Public Test"
+	k.APIUrl = server.URL
+	k.SetRateLimit(0, 0)
+	p := pair.NewCurrencyPair("XBT", "EUR")
+
+	price, err := k.GetTickerPrice(p)
+	if err != nil || price.Ask != 1001.5 || price.Bid != 1000.5 || price.Last != 1001 || price.Volume != 150.5 || price.High != 1020 {
+		t.Errorf("Test Failed - GetTickerPrice() incorrect: %+v %v", price, err)
+	}
+	tickers, err := k.GetTicker("XBTEUR")
+	if err != nil || tickers["XBTEUR"].Trades != 60 {
+		t.Errorf("Test Failed - GetTicker() did not key the ticker by altname: %+v %v", tickers, err)
+	}
+
+	book, err := k.GetOrderbookEx(p)
+	if err != nil || len(book.Asks) != 1 || len(book.Bids) != 2 || book.Bids[1].Amount != 3 {
+		t.Errorf("Test Failed - GetOrderbookEx() incorrect: %+v %v", book, err)
+	}
+	if _, err = orderbook.GetOrderbook(k.GetName(), p); err != nil {
+		t.Errorf("Test Failed - GetOrderbookEx() did not store the orderbook: %s", err)
+	}
+
+	result, err := k.GetRecentTrades(p)
+	if err != nil || len(result) != 2 || result[0].Side != "SELL" || result[1].Amount != 0.75 || result[0].Timestamp.UnixNano() != 1500000000250000000 {
+		t.Errorf("Test Failed - GetRecentTrades() incorrect: %+v %v", result, err)
+	}
+
+	_, err = k.GetSpread("XBTEUR")
+	if err == nil || !common.StringContains(err.Error(), "EQuery:Unknown asset pair") {
+		t.Errorf("Test Failed - GetSpread() expected a Kraken error, received %v", err)
+	}
+}
+
+func TestPrivateEndpoints(t *testing.T) {
+	var closedOffsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("API-Key") != "key" || r.Header.Get("API-Sign") == "" {
+			w.Write([]byte(`{"error":["EAPI:Invalid key"]}`))
+			return
+		}
+		r.ParseForm()
+
+		switch r.URL.Path {
+		case "/0/private/Balance":
+			w.Write([]byte(`{"error":[],"result":{"ZUSD":"100.5","XXBT":"1.25","XXDG":"0"}}`))
+		case "/0/private/AddOrder":
+			if r.Form.Get("pair") != "XBTUSD" || r.Form.Get("price") != "900" || r.Form.Get("price2") != "" || r.Form.Get("userref") != "42" {
+				w.Write([]byte(`{"error":["EGeneral:Invalid arguments"]}`))
+				return
+			}
+			w.Write([]byte(`{"error":[],"result":{"descr":{"order":"buy 1.00000000 XBTUSD @ limit 900.0"},"txid":["OABCDE-FGHIJ-KLMNOP"]}}`))
+		case "/0/private/CancelOrder":
+			w.Write([]byte(`{"error":["EOrder:Unknown order"]}`))
+		case "/0/private/QueryOrders":
+			w.Write([]byte(`{"error":[],"result":{}}`))
+		case "/0/private/OpenOrders":
+			w.Write([]byte(`{"error":[],"result":{"open":{"OABCDE-FGHIJ-KLMNOP":{"refid":null,"userref":42,"status":"open","opentm":1500000000.5,"descr":{"pair":"XBTUSD","type":"buy","ordertype":"limit","price":"900.0"},"vol":"1.00000000","vol_exec":"0.25000000","cost":"225","fee":"0.5","price":"900"}}}}`))
+		case "/0/private/ClosedOrders":
+			closedOffsets = append(closedOffsets, r.Form.Get("ofs"))
+			if r.Form.Get("ofs") == "" {
+				w.Write([]byte(`{"error":[],"result":{"closed":{"O1":{"status":"closed","opentm":1500000000,"closetm":1500000100,"descr":{"pair":"ETHXBT","type":"sell","ordertype":"market","price":"0"},"vol":"2","vol_exec":"2","price":"0.05"}},"count":2}}`))
+				return
+			}
+			w.Write([]byte(`{"error":[],"result":{"closed":{"O2":{"status":"canceled","opentm":1500000200,"descr":{"pair":"XBTUSD","type":"buy","ordertype":"limit","price":"800"},"vol":"1","vol_exec":"0","price":"0"}},"count":2}}`))
+		case "/0/private/TradesHistory":
+			w.Write([]byte(`{"error":[],"result":{"trades":{"T1":{"ordertxid":"O1","pair":"XETHXXBT","time":1500000050.5,"type":"sell","ordertype":"market","price":"0.05","cost":"0.1","fee":"0.0002","vol":"2","margin":"0"}},"count":1}}`))
+		}
+	}))
+	defer server.Close()
+
+	k := Kraken{}
+	k.SetDefaults()
+	k.Name = "Kraken Private Test"
+	k.APIUrl = server.URL
+	k.SetRateLimit(0, 0)
+	k.APIKey = "key"
+	k.APISecret = common.Base64Encode([]byte("secret"))
+
+	info, err := k.GetExchangeAccountInfo()
+	if err != nil || len(info.Currencies) != 3 {
+		t.Fatalf("Test Failed - GetExchangeAccountInfo() incorrect: %+v %v", info, err)
+	}
+	if info.Currencies[0].CurrencyName != "BTC" || info.Currencies[0].TotalValue != 1.25 || info.Currencies[2].CurrencyName != "USD" || info.Currencies[2].TotalValue != 100.5 {
+		t.Errorf("Test Failed - GetExchangeAccountInfo() asset names incorrect: %+v", info.Currencies)
+	}
+
+	result, err := k.SubmitOrder(exchange.OrderRequest{Pair: pair.NewCurrencyPair("BTC", "USD"), Side: exchange.OrderSideBuy, Type: exchange.OrderTypeLimit, Amount: 1, Price: 900, ClientID: "42"})
+	if err != nil || result.OrderID != "OABCDE-FGHIJ-KLMNOP" {
+		t.Errorf("Test Failed - SubmitOrder() incorrect: %+v %v", result, err)
+	}
+
+	err = k.CancelOrder("OABCDE-FGHIJ-KLMNOP", pair.NewCurrencyPair("XBT", "USD"))
+	if !exchange.IsErrorKind(err, exchange.ErrorKindOrderNotFound) {
+		t.Errorf("Test Failed - CancelOrder() expected an order not found error, received %v", err)
+	}
+
+	if _, err = k.GetOrderInfo("OMISSING", pair.NewCurrencyPair("XBT", "USD")); err == nil || err.Error() != exchange.ErrOrderNotFound {
+		t.Errorf("Test Failed - GetOrderInfo() expected an order not found error, received %v", err)
+	}
+
+	orders, err := k.GetActiveOrders(exchange.OrderFilter{Pairs: []pair.CurrencyPair{pair.NewCurrencyPair("BTC", "USD")}})
+	if err != nil || len(orders) != 1 {
+		t.Fatalf("Test Failed - GetActiveOrders() incorrect: %+v %v", orders, err)
+	}
+	if orders[0].ClientID != "42" || orders[0].Pair.Pair().String() != "BTCUSD" || orders[0].Status != exchange.OrderStatusPartiallyFilled || orders[0].Price != 900 || orders[0].FilledAmount != 0.25 {
+		t.Errorf("Test Failed - GetActiveOrders() order detail incorrect: %+v", orders[0])
+	}
+
+	orders, err = k.GetActiveOrders(exchange.OrderFilter{Pairs: []pair.CurrencyPair{pair.NewCurrencyPair("XBT", "USD")}})
+	if err != nil || len(orders) != 1 {
+		t.Errorf("Test Failed - GetActiveOrders() did not match a filter using XBT: %+v %v", orders, err)
+	}
+
+	orders, err = k.GetOrderHistory(exchange.OrderFilter{})
+	if err != nil || len(orders) != 2 || len(closedOffsets) != 2 || closedOffsets[1] != "1" {
+		t.Fatalf("Test Failed - GetOrderHistory() did not page: %+v %v %v", orders, closedOffsets, err)
+	}
+	if orders[0].Status != exchange.OrderStatusFilled || orders[0].Type != exchange.OrderTypeMarket || orders[0].AveragePrice != 0.05 || orders[1].Status != exchange.OrderStatusCancelled {
+		t.Errorf("Test Failed - GetOrderHistory() order details incorrect: %+v", orders)
+	}
+
+	fills, err := k.GetFills(exchange.OrderFilter{Pairs: []pair.CurrencyPair{pair.NewCurrencyPair("ETH", "BTC")}})
+	if err != nil || len(fills) != 1 {
+		t.Fatalf("Test Failed - GetFills() incorrect: %+v %v", fills, err)
+	}
+	if fills[0].OrderID != "O1" || fills[0].Pair.GetSecondCurrency().String() != "BTC" || fills[0].Side != exchange.OrderSideSell || fills[0].FeeCurrency != "BTC" || fills[0].Fee != 0.0002 {
+		t.Errorf("Test Failed - GetFills() fill incorrect: %+v", fills[0])
+	}
+}
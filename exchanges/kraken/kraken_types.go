@@ -19,6 +19,17 @@ type KrakenAssetPairs struct {
 	MarginStop        int         `json:"margin_stop"`
 }
 
+type KrakenOHLC struct {
+	Time   int64
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	VWAP   float64
+	Volume float64
+	Count  int64
+}
+
 type KrakenTicker struct {
 	Ask    float64
 	Bid    float64
@@ -42,3 +53,189 @@ type KrakenTickerResponse struct {
 	High   []string `json:"h"`
 	Open   string   `json:"o"`
 }
+
+type KrakenErrorResponse struct {
+	Error []string `json:"error"`
+}
+
+//KrakenResponse : Envelope of every Kraken response. Result is decoded into
+//the value it is set to
+type KrakenResponse struct {
+	Error  []string    `json:"error"`
+	Result interface{} `json:"result"`
+}
+
+type KrakenServerTime struct {
+	Unixtime int64  `json:"unixtime"`
+	Rfc1123  string `json:"rfc1123"`
+}
+
+type KrakenAsset struct {
+	Altname         string `json:"altname"`
+	Aclass          string `json:"aclass"`
+	Decimals        int    `json:"decimals"`
+	DisplayDecimals int    `json:"display_decimals"`
+}
+
+type KrakenOrderbookItem struct {
+	Price     float64
+	Amount    float64
+	Timestamp int64
+}
+
+type KrakenOrderbook struct {
+	Bids []KrakenOrderbookItem
+	Asks []KrakenOrderbookItem
+}
+
+//KrakenTrade : Public trade. BuyOrSell is b or s, MarketOrLimit m or l
+type KrakenTrade struct {
+	Price         float64
+	Volume        float64
+	Time          float64
+	BuyOrSell     string
+	MarketOrLimit string
+	Misc          string
+}
+
+type KrakenSpread struct {
+	Time int64
+	Bid  float64
+	Ask  float64
+}
+
+type KrakenTradeBalance struct {
+	EquivalentBalance float64 `json:"eb,string"`
+	TradeBalance      float64 `json:"tb,string"`
+	MarginAmount      float64 `json:"m,string"`
+	Net               float64 `json:"n,string"`
+	Cost              float64 `json:"c,string"`
+	Valuation         float64 `json:"v,string"`
+	Equity            float64 `json:"e,string"`
+	FreeMargin        float64 `json:"mf,string"`
+	MarginLevel       float64 `json:"ml,string"`
+}
+
+type KrakenOrderDescription struct {
+	Pair      string `json:"pair"`
+	Type      string `json:"type"`
+	OrderType string `json:"ordertype"`
+	Price     string `json:"price"`
+	Price2    string `json:"price2"`
+	Leverage  string `json:"leverage"`
+	Order     string `json:"order"`
+	Close     string `json:"close"`
+}
+
+//KrakenOrder : Open or closed order. Price is the average execution price
+type KrakenOrder struct {
+	RefID          string                 `json:"refid"`
+	UserRef        int64                  `json:"userref"`
+	Status         string                 `json:"status"`
+	OpenTime       float64                `json:"opentm"`
+	StartTime      float64                `json:"starttm"`
+	ExpireTime     float64                `json:"expiretm"`
+	CloseTime      float64                `json:"closetm"`
+	Description    KrakenOrderDescription `json:"descr"`
+	Volume         float64                `json:"vol,string"`
+	VolumeExecuted float64                `json:"vol_exec,string"`
+	Cost           float64                `json:"cost,string"`
+	Fee            float64                `json:"fee,string"`
+	Price          float64                `json:"price,string"`
+	StopPrice      float64                `json:"stopprice,string"`
+	LimitPrice     float64                `json:"limitprice,string"`
+	Misc           string                 `json:"misc"`
+	OrderFlags     string                 `json:"oflags"`
+	Reason         string                 `json:"reason"`
+	Trades         []string               `json:"trades"`
+}
+
+type KrakenOpenOrders struct {
+	Open map[string]KrakenOrder `json:"open"`
+}
+
+type KrakenClosedOrders struct {
+	Closed map[string]KrakenOrder `json:"closed"`
+	Count  int64                  `json:"count"`
+}
+
+type KrakenTradeInfo struct {
+	OrderTxID string  `json:"ordertxid"`
+	Pair      string  `json:"pair"`
+	Time      float64 `json:"time"`
+	Type      string  `json:"type"`
+	OrderType string  `json:"ordertype"`
+	Price     float64 `json:"price,string"`
+	Cost      float64 `json:"cost,string"`
+	Fee       float64 `json:"fee,string"`
+	Volume    float64 `json:"vol,string"`
+	Margin    float64 `json:"margin,string"`
+	Misc      string  `json:"misc"`
+}
+
+type KrakenTradesHistory struct {
+	Trades map[string]KrakenTradeInfo `json:"trades"`
+	Count  int64                      `json:"count"`
+}
+
+type KrakenPosition struct {
+	OrderTxID    string  `json:"ordertxid"`
+	Pair         string  `json:"pair"`
+	Time         float64 `json:"time"`
+	Type         string  `json:"type"`
+	OrderType    string  `json:"ordertype"`
+	Cost         float64 `json:"cost,string"`
+	Fee          float64 `json:"fee,string"`
+	Volume       float64 `json:"vol,string"`
+	VolumeClosed float64 `json:"vol_closed,string"`
+	Margin       float64 `json:"margin,string"`
+	Value        float64 `json:"value,string"`
+	Net          float64 `json:"net,string"`
+	Misc         string  `json:"misc"`
+	OrderFlags   string  `json:"oflags"`
+}
+
+type KrakenLedger struct {
+	RefID   string  `json:"refid"`
+	Time    float64 `json:"time"`
+	Type    string  `json:"type"`
+	Aclass  string  `json:"aclass"`
+	Asset   string  `json:"asset"`
+	Amount  float64 `json:"amount,string"`
+	Fee     float64 `json:"fee,string"`
+	Balance float64 `json:"balance,string"`
+}
+
+type KrakenLedgers struct {
+	Ledger map[string]KrakenLedger `json:"ledger"`
+	Count  int64                   `json:"count"`
+}
+
+type KrakenFeeTier struct {
+	Fee        float64 `json:"fee,string"`
+	MinFee     float64 `json:"minfee,string"`
+	MaxFee     float64 `json:"maxfee,string"`
+	NextFee    float64 `json:"nextfee,string"`
+	NextVolume float64 `json:"nextvolume,string"`
+	TierVolume float64 `json:"tiervolume,string"`
+}
+
+type KrakenTradeVolume struct {
+	Currency  string                   `json:"currency"`
+	Volume    float64                  `json:"volume,string"`
+	Fees      map[string]KrakenFeeTier `json:"fees"`
+	FeesMaker map[string]KrakenFeeTier `json:"fees_maker"`
+}
+
+type KrakenAddOrderResponse struct {
+	Description struct {
+		Order string `json:"order"`
+		Close string `json:"close"`
+	} `json:"descr"`
+	TransactionIDs []string `json:"txid"`
+}
+
+type KrakenCancelOrderResponse struct {
+	Count   int64 `json:"count"`
+	Pending bool  `json:"pending"`
+}
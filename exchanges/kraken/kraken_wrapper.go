@@ -1,7 +1,11 @@
 package kraken
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -10,6 +14,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (k *Kraken) Start() {
@@ -36,44 +41,464 @@ func (k *Kraken) Run() {
 		}
 	}
 
+	err = k.UpdatePairInfo()
+	if err != nil {
+		log.Printf("%s Failed to get pair info.\n", k.GetName())
+	}
+
 	for k.Enabled {
-		err := k.GetTicker(common.JoinStrings(k.EnabledPairs, ","))
+		tickers, err := k.GetTicker(common.JoinStrings(k.EnabledPairs, ","))
 		if err != nil {
 			log.Println(err)
 		} else {
 			for _, x := range k.EnabledPairs {
-				ticker := k.Ticker[x]
-				log.Printf("Kraken %s Last %f High %f Low %f Volume %f\n", x, ticker.Last, ticker.High, ticker.Low, ticker.Volume)
-				stats.AddExchangeInfo(k.GetName(), x[0:3], x[3:], ticker.Last, ticker.Volume)
+				tick, ok := tickers[x]
+				if !ok {
+					continue
+				}
+				currency := GetKrakenPair(x)
+				tickerPrice := k.getTickerPrice(currency, tick)
+				ticker.ProcessTicker(k.GetName(), currency, tickerPrice)
+				log.Printf("Kraken %s Last %f High %f Low %f Volume %f\n", x, tick.Last, tick.High, tick.Low, tick.Volume)
+				stats.AddExchangeInfo(k.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), tick.Last, tick.Volume)
 			}
 		}
 		time.Sleep(time.Second * k.RESTPollingDelay)
 	}
 }
 
-//This will return the TickerPrice struct when tickers are completed here..
 func (k *Kraken) GetTickerPrice(p pair.CurrencyPair) (ticker.TickerPrice, error) {
-	var tickerPrice ticker.TickerPrice
-	/*
-		ticker, err := i.GetTicker(currency)
-		if err != nil {
-			log.Println(err)
-			return tickerPrice
-		}
-		tickerPrice.Ask = ticker.Ask
-		tickerPrice.Bid = ticker.Bid
-	*/
+	tick, err := ticker.GetTicker(k.GetName(), p)
+	if err == nil {
+		return tick, nil
+	}
+
+	symbol := getKrakenPairSymbol(p)
+	tickers, err := k.GetTicker(symbol)
+	if err != nil {
+		return ticker.TickerPrice{}, err
+	}
+
+	tickerNew, ok := tickers[symbol]
+	if !ok {
+		return ticker.TickerPrice{}, fmt.Errorf(ErrKrakenPairNotFound, symbol)
+	}
+
+	tickerPrice := k.getTickerPrice(p, tickerNew)
+	ticker.ProcessTicker(k.GetName(), p, tickerPrice)
 	return tickerPrice, nil
 }
 
+func (k *Kraken) getTickerPrice(p pair.CurrencyPair, tick KrakenTicker) ticker.TickerPrice {
+	var tickerPrice ticker.TickerPrice
+	tickerPrice.Pair = p
+	tickerPrice.Ask = tick.Ask
+	tickerPrice.Bid = tick.Bid
+	tickerPrice.Last = tick.Last
+	tickerPrice.Low = tick.Low
+	tickerPrice.High = tick.High
+	tickerPrice.Volume = tick.Volume
+	return tickerPrice
+}
+
 func (k *Kraken) GetOrderbookEx(p pair.CurrencyPair) (orderbook.OrderbookBase, error) {
-	return orderbook.OrderbookBase{}, nil
+	ob, err := orderbook.GetOrderbook(k.GetName(), p)
+	if err == nil {
+		return ob, nil
+	}
+
+	var orderBook orderbook.OrderbookBase
+	orderbookNew, err := k.GetDepth(getKrakenPairSymbol(p), 0)
+	if err != nil {
+		return orderBook, err
+	}
+
+	for _, x := range orderbookNew.Asks {
+		orderBook.Asks = append(orderBook.Asks, orderbook.OrderbookItem{Price: x.Price, Amount: x.Amount})
+	}
+
+	for _, x := range orderbookNew.Bids {
+		orderBook.Bids = append(orderBook.Bids, orderbook.OrderbookItem{Price: x.Price, Amount: x.Amount})
+	}
+
+	orderBook.Pair = p
+	orderbook.ProcessOrderbook(k.GetName(), p, orderBook)
+	return orderBook, nil
 }
 
-//TODO: Retrieve Kraken info
-//GetExchangeAccountInfo : Retrieves balances for all enabled currencies for the Kraken exchange
+//GetExchangeAccountInfo : Retrieves balances for all currencies held on the
+//Kraken exchange. Kraken asset names such as XXBT and ZUSD are reported as BTC
+//and USD
 func (e *Kraken) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	var response exchange.ExchangeAccountInfo
 	response.ExchangeName = e.GetName()
+
+	balances, err := e.GetBalance()
+	if err != nil {
+		return response, err
+	}
+
+	var assets []string
+	for x := range balances {
+		assets = append(assets, x)
+	}
+	sort.Strings(assets)
+
+	for _, x := range assets {
+		var exchangeCurrency exchange.ExchangeAccountCurrencyInfo
+		exchangeCurrency.CurrencyName = GetKrakenAssetName(x)
+		exchangeCurrency.TotalValue = balances[x]
+		response.Currencies = append(response.Currencies, exchangeCurrency)
+	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new order on Kraken. A numeric client ID is sent as
+//the Kraken user reference
+func (k *Kraken) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := k.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	side := "sell"
+	if order.IsBuy() {
+		side = "buy"
+	}
+
+	orderType := "limit"
+	price := order.Price
+	if order.IsMarket() {
+		orderType = "market"
+		price = 0
+	}
+
+	userref, _ := strconv.ParseInt(order.ClientID, 10, 32)
+	response, err := k.AddOrder(getKrakenPairSymbol(order.Pair), side, orderType, price, 0, order.Amount, 0, userref)
+	if err != nil {
+		return result, err
+	}
+
+	if len(response.TransactionIDs) == 0 {
+		return result, exchange.NewExchangeError(k.GetName(), errors.New(ErrKrakenNoTransactionID), "", krakenErrorRules...)
+	}
+
+	result.Exchange = k.GetName()
+	result.OrderID = response.TransactionIDs[0]
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its transaction ID
+func (k *Kraken) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	_, err := k.CancelExistingOrder(orderID)
+	return err
+}
+
+//ModifyOrder : Not supported, Kraken does not support amending orders
+func (k *Kraken) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (k *Kraken) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	response, err := k.QueryOrdersInfo(false, 0, orderID)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	order, ok := response[orderID]
+	if !ok {
+		return exchange.OrderDetail{}, errors.New(exchange.ErrOrderNotFound)
+	}
+	return k.getOrderDetail(orderID, order), nil
+}
+
+//GetActiveOrders : Retrieves all open orders matching the filter
+func (k *Kraken) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := k.GetOpenOrders(false, 0)
+	if err != nil {
+		return nil, err
+	}
+	return exchange.FilterOrders(k.getOrderDetails(response), getKrakenFilter(filter)), nil
+}
+
+//GetOrderHistory : Retrieves closed, cancelled and expired orders matching the
+//filter, paging through the closed orders in the filter time range
+func (k *Kraken) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	start, end := getKrakenFilterRange(filter)
+
+	var orders []exchange.OrderDetail
+	seen := make(map[string]bool)
+	var offset int64
+	for {
+		response, err := k.GetClosedOrders(false, 0, start, end, offset, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, x := range k.getOrderDetails(response.Closed) {
+			if seen[x.OrderID] {
+				continue
+			}
+			seen[x.OrderID] = true
+			orders = append(orders, x)
+		}
+
+		offset += int64(len(response.Closed))
+		if len(response.Closed) == 0 || offset >= response.Count {
+			break
+		}
+	}
+	return exchange.FilterOrders(orders, getKrakenFilter(filter)), nil
+}
+
+//GetFills : Retrieves the account's executions matching the filter, paging
+//through the trade history in the filter time range
+func (k *Kraken) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	start, end := getKrakenFilterRange(filter)
+
+	var fills []exchange.Fill
+	seen := make(map[string]bool)
+	var offset int64
+	for {
+		response, err := k.GetTradesHistory("", false, start, end, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		var tradeIDs []string
+		for x := range response.Trades {
+			tradeIDs = append(tradeIDs, x)
+		}
+		sort.Strings(tradeIDs)
+
+		for _, x := range tradeIDs {
+			if seen[x] {
+				continue
+			}
+			seen[x] = true
+			fills = append(fills, k.getFill(x, response.Trades[x]))
+		}
+
+		offset += int64(len(response.Trades))
+		if len(response.Trades) == 0 || offset >= response.Count {
+			break
+		}
+	}
+	return exchange.FilterFills(fills, getKrakenFilter(filter)), nil
+}
+
+//getKrakenFilter returns the filter with its pairs named as the pairs of the
+//orders and fills, so filters using Kraken altnames such as XBT still match
+func getKrakenFilter(filter exchange.OrderFilter) exchange.OrderFilter {
+	pairs := filter.Pairs
+	filter.Pairs = nil
+	for _, x := range pairs {
+		filter.Pairs = append(filter.Pairs, pair.NewCurrencyPair(GetKrakenAssetName(x.GetFirstCurrency().String()), GetKrakenAssetName(x.GetSecondCurrency().String())))
+	}
+	return filter
+}
+
+//getKrakenCurrencyPair returns the pair of a Kraken pair name with its
+//currencies named as elsewhere in the bot, so XBTUSD becomes BTC/USD
+func getKrakenCurrencyPair(name string) pair.CurrencyPair {
+	p := GetKrakenPair(name)
+	return pair.NewCurrencyPair(GetKrakenAssetName(p.GetFirstCurrency().String()), GetKrakenAssetName(p.GetSecondCurrency().String()))
+}
+
+//getKrakenFilterRange returns the filter time range as the unix timestamps
+//used by the Kraken history endpoints, zero when unset
+func getKrakenFilterRange(filter exchange.OrderFilter) (int64, int64) {
+	var start, end int64
+	if !filter.StartTime.IsZero() {
+		start = filter.StartTime.Unix()
+	}
+	if !filter.EndTime.IsZero() {
+		end = filter.EndTime.Unix()
+	}
+	return start, end
+}
+
+//getKrakenTime converts a Kraken timestamp in fractional seconds
+func getKrakenTime(timestamp float64) time.Time {
+	seconds := int64(timestamp)
+	return time.Unix(seconds, int64((timestamp-float64(seconds))*1e9))
+}
+
+//getFill converts a trade history entry. Kraken charges fees in the quote
+//currency unless told otherwise when the order is placed
+func (k *Kraken) getFill(tradeID string, trade KrakenTradeInfo) exchange.Fill {
+	var fill exchange.Fill
+	fill.Exchange = k.GetName()
+	fill.TradeID = tradeID
+	fill.OrderID = trade.OrderTxID
+	fill.Pair = getKrakenCurrencyPair(trade.Pair)
+	fill.Side = exchange.OrderSideBuy
+	if trade.Type == "sell" {
+		fill.Side = exchange.OrderSideSell
+	}
+	fill.Price = trade.Price
+	fill.Amount = trade.Volume
+	fill.Fee = trade.Fee
+	fill.FeeCurrency = fill.Pair.GetSecondCurrency().String()
+	fill.Timestamp = getKrakenTime(trade.Time)
+	return fill
+}
+
+func (k *Kraken) getOrderDetails(response map[string]KrakenOrder) []exchange.OrderDetail {
+	var orderIDs []string
+	for x := range response {
+		orderIDs = append(orderIDs, x)
+	}
+	sort.Strings(orderIDs)
+
+	var orders []exchange.OrderDetail
+	for _, x := range orderIDs {
+		orders = append(orders, k.getOrderDetail(x, response[x]))
+	}
+	return orders
+}
+
+func (k *Kraken) getOrderDetail(orderID string, order KrakenOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = k.GetName()
+	detail.OrderID = orderID
+	if order.UserRef != 0 {
+		detail.ClientID = strconv.FormatInt(order.UserRef, 10)
+	}
+	detail.Pair = getKrakenCurrencyPair(order.Description.Pair)
+	detail.Side = exchange.OrderSideBuy
+	if order.Description.Type == "sell" {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	if order.Description.OrderType == "market" {
+		detail.Type = exchange.OrderTypeMarket
+	}
+
+	active := order.Status == "pending" || order.Status == "open"
+	cancelled := order.Status == "canceled" || order.Status == "expired"
+	detail.Status = exchange.GetOrderStatus(order.Volume, order.VolumeExecuted, active, cancelled)
+	detail.Price, _ = strconv.ParseFloat(order.Description.Price, 64)
+	detail.Amount = order.Volume
+	detail.FilledAmount = order.VolumeExecuted
+	detail.AveragePrice = order.Price
+	detail.CreatedAt = getKrakenTime(order.OpenTime)
+	if order.CloseTime > 0 {
+		detail.UpdatedAt = getKrakenTime(order.CloseTime)
+	}
+	return detail
+}
+
+//GetDepositAddress : Not supported, the Kraken REST client does not parse funding responses yet
+func (k *Kraken) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(k.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Not supported, the Kraken REST client does not parse funding responses yet
+func (k *Kraken) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	return exchange.WithdrawResult{}, exchange.NewFundingError(k.GetName(), request.Currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//UpdatePairInfo : Populates the pair metadata table from the Kraken asset
+//pairs, keyed on the altname used in the config. Dark pool pairs are skipped
+func (k *Kraken) UpdatePairInfo() error {
+	assetPairs, err := k.GetAssetPairs()
+	if err != nil {
+		return err
+	}
+
+	var info []exchange.PairInfo
+	for _, x := range assetPairs {
+		if len(x.Altname) != 6 {
+			continue
+		}
+		info = append(info, exchange.PairInfo{
+			Pair:           pair.NewCurrencyPair(x.Altname[0:3], x.Altname[3:]),
+			AmountStep:     exchange.GetPrecisionStep(x.LotDecimals),
+			PriceTick:      exchange.GetPrecisionStep(x.PairDecimals),
+			QuotePrecision: x.PairDecimals,
+			Status:         exchange.PairStatusTrading,
+		})
+	}
+	k.SetPairInfo(info)
+	return nil
+}
+
+//GetHistoricCandles : Retrieves candles for a pair, following the Kraken since
+//cursor until the end of the range. Kraken only serves the most recent 720
+//candles of each interval, older ranges return no data
+func (k *Kraken) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	err := exchange.ValidateCandleRange(start, end, interval)
+	if err != nil {
+		return exchange.CandleSeries{}, err
+	}
+
+	minutes := int(interval / time.Minute)
+	switch minutes {
+	case 1, 5, 15, 30, 60, 240, 1440, 10080, 21600:
+	default:
+		return exchange.CandleSeries{}, errors.New(exchange.ErrCandleIntervalNotSupported)
+	}
+
+	if time.Duration(minutes)*time.Minute != interval {
+		return exchange.CandleSeries{}, errors.New(exchange.ErrCandleIntervalNotSupported)
+	}
+
+	var candles []exchange.Candle
+	symbol := getKrakenPairSymbol(p)
+	since := start.Unix() - 1
+	for since < end.Unix() {
+		ohlc, last, err := k.GetOHLC(symbol, minutes, since)
+		if err != nil {
+			return exchange.CandleSeries{}, err
+		}
+
+		for _, x := range ohlc {
+			candles = append(candles, exchange.Candle{
+				Time:   time.Unix(x.Time, 0),
+				Open:   x.Open,
+				High:   x.High,
+				Low:    x.Low,
+				Close:  x.Close,
+				Volume: x.Volume,
+			})
+		}
+
+		if len(ohlc) == 0 || last <= since {
+			break
+		}
+		since = last
+	}
+	return exchange.NewCandleSeries(k.GetName(), p, start, end, interval, candles), nil
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair. Kraken
+//trades carry no trade ID
+func (k *Kraken) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := k.GetTrades(getKrakenPairSymbol(p), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:      p,
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Volume,
+			Timestamp: getKrakenTime(x.Time),
+		}
+		if x.BuyOrSell == "s" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(k.GetName(), p, result)
+	return result, nil
+}
@@ -6,7 +6,6 @@ import (
 	"log"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
@@ -30,6 +29,11 @@ const (
 	LAKEBTC_CREATE_WITHDRAW       = "createWithdraw"
 )
 
+const (
+	LAKEBTC_AUTH_RATE_LIMIT   = 60
+	LAKEBTC_UNAUTH_RATE_LIMIT = 60
+)
+
 type LakeBTC struct {
 	exchange.ExchangeBase
 }
@@ -42,6 +46,7 @@ func (l *LakeBTC) SetDefaults() {
 	l.Verbose = false
 	l.Websocket = false
 	l.RESTPollingDelay = 10
+	l.SetRateLimit(LAKEBTC_AUTH_RATE_LIMIT, LAKEBTC_UNAUTH_RATE_LIMIT)
 }
 
 func (l *LakeBTC) Setup(exch config.ExchangeConfig) {
@@ -52,6 +57,11 @@ func (l *LakeBTC) Setup(exch config.ExchangeConfig) {
 		l.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		l.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		l.RESTPollingDelay = exch.RESTPollingDelay
+		l.UpdateRateLimit(exch)
+		err := l.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", l.GetName(), err)
+		}
 		l.Verbose = exch.Verbose
 		l.Websocket = exch.Websocket
 		l.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -70,8 +80,8 @@ func (l *LakeBTC) GetFee(maker bool) float64 {
 
 func (l *LakeBTC) GetTicker() (map[string]LakeBTCTicker, error) {
 	response := make(map[string]LakeBTCTickerResponse)
-	path := fmt.Sprintf("%s/%s", LAKEBTC_API_URL, LAKEBTC_TICKER)
-	err := common.SendHTTPGetRequest(path, true, &response)
+	path := fmt.Sprintf("%s/%s", l.GetAPIUrl(LAKEBTC_API_URL), LAKEBTC_TICKER)
+	err := l.SendHTTPGetRequest(path, true, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -110,9 +120,9 @@ func (l *LakeBTC) GetOrderBook(currency string) (LakeBTCOrderbook, error) {
 		Bids [][]string `json:"bids"`
 		Asks [][]string `json:"asks"`
 	}
-	path := fmt.Sprintf("%s/%s?symbol=%s", LAKEBTC_API_URL, LAKEBTC_ORDERBOOK, common.StringToLower(currency))
+	path := fmt.Sprintf("%s/%s?symbol=%s", l.GetAPIUrl(LAKEBTC_API_URL), LAKEBTC_ORDERBOOK, common.StringToLower(currency))
 	resp := Response{}
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := l.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return LakeBTCOrderbook{}, err
 	}
@@ -149,9 +159,9 @@ func (l *LakeBTC) GetOrderBook(currency string) (LakeBTCOrderbook, error) {
 }
 
 func (l *LakeBTC) GetTradeHistory(currency string) ([]LakeBTCTradeHistory, error) {
-	path := fmt.Sprintf("%s/%s?symbol=%s", LAKEBTC_API_URL, LAKEBTC_TRADES, common.StringToLower(currency))
+	path := fmt.Sprintf("%s/%s?symbol=%s", l.GetAPIUrl(LAKEBTC_API_URL), LAKEBTC_TRADES, common.StringToLower(currency))
 	resp := []LakeBTCTradeHistory{}
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := l.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +226,7 @@ func (l *LakeBTC) GetOrders(orders []int64) ([]LakeBTCOrders, error) {
 	return resp, nil
 }
 
-func (l *LakeBTC) CancelOrder(orderID int64) error {
+func (l *LakeBTC) CancelExistingOrder(orderID int64) error {
 	type Response struct {
 		Result bool `json:"Result"`
 	}
@@ -272,12 +282,13 @@ func (l *LakeBTC) CreateWithdraw(amount float64, accountID int64) (LakeBTCWithdr
 }
 
 func (l *LakeBTC) SendAuthenticatedHTTPRequest(method, params string, result interface{}) (err error) {
-	nonce := strconv.FormatInt(time.Now().UnixNano(), 10)
+	l.WaitRateLimit(true)
+	nonce := l.GetNonce().GetString()
 	req := fmt.Sprintf("tonce=%s&accesskey=%s&requestmethod=post&id=1&method=%s&params=%s", nonce, l.APIKey, method, params)
 	hmac := common.GetHMAC(common.HASH_SHA1, []byte(req), []byte(l.APISecret))
 
 	if l.Verbose {
-		log.Printf("Sending POST request to %s calling method %s with params %s\n", LAKEBTC_API_URL, method, req)
+		log.Printf("Sending POST request to %s calling method %s with params %s\n", l.GetAPIUrl(LAKEBTC_API_URL), method, req)
 	}
 
 	postData := make(map[string]interface{})
@@ -295,9 +306,9 @@ func (l *LakeBTC) SendAuthenticatedHTTPRequest(method, params string, result int
 	headers["Authorization"] = "Basic " + common.Base64Encode([]byte(l.APIKey+":"+common.HexEncodeToString(hmac)))
 	headers["Content-Type"] = "application/json-rpc"
 
-	resp, err := common.SendHTTPRequest("POST", LAKEBTC_API_URL, headers, strings.NewReader(string(data)))
+	resp, err := l.SendHTTPRequest("POST", l.GetAPIUrl(LAKEBTC_API_URL), headers, strings.NewReader(string(data)))
 	if err != nil {
-		return err
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, err, resp))
 	}
 
 	if l.Verbose {
@@ -311,17 +322,17 @@ func (l *LakeBTC) SendAuthenticatedHTTPRequest(method, params string, result int
 	errResponse := ErrorResponse{}
 	err = common.JSONDecode([]byte(resp), &errResponse)
 	if err != nil {
-		return errors.New("Unable to check response for error.")
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, errors.New("Unable to check response for error."), resp))
 	}
 
 	if errResponse.Error != "" {
-		return errors.New(errResponse.Error)
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, errors.New(errResponse.Error), resp))
 	}
 
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, errors.New("Unable to JSON Unmarshal response."), resp))
 	}
 
 	return nil
@@ -30,7 +30,7 @@ type LakeBTCTickerResponse struct {
 }
 
 type LakeBTCTradeHistory struct {
-	Date   int64   `json:"data"`
+	Date   int64   `json:"date"`
 	Price  float64 `json:"price,string"`
 	Amount float64 `json:"amount,string"`
 	TID    int64   `json:"tid"`
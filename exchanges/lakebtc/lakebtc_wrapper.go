@@ -1,7 +1,9 @@
 package lakebtc
 
 import (
+	"errors"
 	"log"
+	"sort"
 	"strconv"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (l *LakeBTC) Start() {
@@ -111,3 +114,203 @@ func (l *LakeBTC) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error)
 	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new limit order on LakeBTC
+func (l *LakeBTC) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := l.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	orderType := 0
+	if order.IsBuy() {
+		orderType = 1
+	}
+
+	response, err := l.Trade(orderType, order.Amount, order.Price, order.Pair.Pair().Lower().String())
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = l.GetName()
+	result.OrderID = strconv.FormatInt(response.ID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (l *LakeBTC) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+	return l.CancelExistingOrder(id)
+}
+
+//ModifyOrder : LakeBTC does not support amending orders
+func (l *LakeBTC) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (l *LakeBTC) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return detail, err
+	}
+
+	orders, err := l.GetOrders([]int64{id})
+	if err != nil {
+		return detail, err
+	}
+
+	if len(orders) == 0 {
+		return detail, errors.New(exchange.ErrOrderNotFound)
+	}
+
+	return l.getOrderDetail(orders[0]), nil
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (l *LakeBTC) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	openOrders, err := l.GetOpenOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(openOrders) == 0 {
+		return []exchange.OrderDetail{}, nil
+	}
+
+	// The open orders listing omits the original amount, so look the
+	// orders up again to get their fill state
+	var ids []int64
+	for _, x := range openOrders {
+		ids = append(ids, x.ID)
+	}
+
+	response, err := l.GetOrders(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for _, x := range response {
+		orders = append(orders, l.getOrderDetail(x))
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Not supported, LakeBTC only exposes fills through its trade history
+func (l *LakeBTC) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetFills : Retrieves the account's executions matching the filter. LakeBTC
+//does not return trade or order IDs, and prices are derived from the trade totals
+func (l *LakeBTC) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	var timestamp int64
+	if !filter.StartTime.IsZero() {
+		timestamp = filter.StartTime.Unix()
+	}
+
+	response, err := l.GetTrades(timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	var fills []exchange.Fill
+	for _, x := range response {
+		var fill exchange.Fill
+		fill.Exchange = l.GetName()
+		if len(x.Symbol) == 6 {
+			fill.Pair = pair.NewCurrencyPair(common.StringToUpper(x.Symbol[0:3]), common.StringToUpper(x.Symbol[3:]))
+		}
+		fill.Side = exchange.OrderSide(common.StringToUpper(x.Type))
+		fill.Amount = x.Amount
+		if x.Amount > 0 {
+			fill.Price = x.Total / x.Amount
+		}
+		fill.Timestamp = time.Unix(x.At, 0)
+		fills = append(fills, fill)
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+func (l *LakeBTC) getOrderDetail(order LakeBTCOrders) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = l.GetName()
+	detail.OrderID = strconv.FormatInt(order.ID, 10)
+	if len(order.Symbol) == 6 {
+		detail.Pair = pair.NewCurrencyPair(common.StringToUpper(order.Symbol[0:3]), common.StringToUpper(order.Symbol[3:]))
+	}
+	detail.Side = exchange.OrderSide(common.StringToUpper(order.Type))
+	detail.Type = exchange.OrderTypeLimit
+	detail.Price = order.Price
+	detail.Amount = order.OriginalAmount
+	detail.FilledAmount = order.OriginalAmount - order.Amount
+	detail.CreatedAt = time.Unix(order.At, 0)
+
+	switch order.State {
+	case "active":
+		detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, true, false)
+	case "filled":
+		detail.Status = exchange.OrderStatusFilled
+	case "cancelled":
+		detail.Status = exchange.OrderStatusCancelled
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail
+}
+
+//GetDepositAddress : Not supported, LakeBTC has no deposit address endpoint
+func (l *LakeBTC) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(l.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Not supported, LakeBTC only withdraws to preregistered withdrawal accounts
+func (l *LakeBTC) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	return exchange.WithdrawResult{}, exchange.NewFundingError(l.GetName(), request.Currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//UpdatePairInfo : Not supported, LakeBTC does not publish pair constraints
+func (l *LakeBTC) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, LakeBTC has no candle endpoint
+func (l *LakeBTC) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair. LakeBTC does
+//not report the trade side
+func (l *LakeBTC) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := l.GetTradeHistory(p.Pair().Lower().String())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		result = append(result, trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TID, 10),
+			Side:      trades.TRADE_SIDE_UNKNOWN,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(x.Date, 0),
+		})
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(l.GetName(), p, result)
+	return result, nil
+}
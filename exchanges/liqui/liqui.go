@@ -15,8 +15,9 @@ import (
 )
 
 const (
-	LIQUI_API_PUBLIC_URL      = "https://api.Liqui.io/api"
-	LIQUI_API_PRIVATE_URL     = "https://api.Liqui.io/tapi"
+	LIQUI_API_URL             = "https://api.Liqui.io"
+	LIQUI_API_PUBLIC          = "api"
+	LIQUI_API_PRIVATE         = "tapi"
 	LIQUI_API_PUBLIC_VERSION  = "3"
 	LIQUI_API_PRIVATE_VERSION = "1"
 	LIQUI_INFO                = "info"
@@ -30,6 +31,13 @@ const (
 	LIQUI_CANCEL_ORDER        = "CancelOrder"
 	LIQUI_TRADE_HISTORY       = "TradeHistory"
 	LIQUI_WITHDRAW_COIN       = "WithdrawCoin"
+	LIQUI_TRADE_HISTORY_LIMIT = 1000
+	LIQUI_NONCE_ERROR_PATTERN = `you should send:\s*(\d+)`
+)
+
+const (
+	LIQUI_AUTH_RATE_LIMIT   = 60
+	LIQUI_UNAUTH_RATE_LIMIT = 60
 )
 
 type Liqui struct {
@@ -45,6 +53,9 @@ func (l *Liqui) SetDefaults() {
 	l.Verbose = false
 	l.Websocket = false
 	l.RESTPollingDelay = 10
+	l.SetRateLimit(LIQUI_AUTH_RATE_LIMIT, LIQUI_UNAUTH_RATE_LIMIT)
+	l.SetNonceResolution(time.Second)
+	l.SetNonceErrorPattern(LIQUI_NONCE_ERROR_PATTERN)
 	l.Ticker = make(map[string]LiquiTicker)
 }
 
@@ -56,6 +67,17 @@ func (l *Liqui) Setup(exch config.ExchangeConfig) {
 		l.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		l.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		l.RESTPollingDelay = exch.RESTPollingDelay
+		l.UpdateRateLimit(exch)
+		err := l.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", l.GetName(), err)
+		}
+		if l.AuthenticatedAPISupport {
+			err = l.EnableNoncePersistence()
+			if err != nil {
+				log.Printf("%s Failed to load the stored nonce: %s.\n", l.GetName(), err)
+			}
+		}
 		l.Verbose = exch.Verbose
 		l.Websocket = exch.Websocket
 		l.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -85,9 +107,9 @@ func (l *Liqui) GetAvailablePairs(nonHidden bool) []string {
 }
 
 func (l *Liqui) GetInfo() (LiquiInfo, error) {
-	req := fmt.Sprintf("%s/%s/%s/", LIQUI_API_PUBLIC_URL, LIQUI_API_PUBLIC_VERSION, LIQUI_INFO)
+	req := fmt.Sprintf("%s/%s/%s/%s/", l.GetAPIUrl(LIQUI_API_URL), LIQUI_API_PUBLIC, LIQUI_API_PUBLIC_VERSION, LIQUI_INFO)
 	resp := LiquiInfo{}
-	err := common.SendHTTPGetRequest(req, true, &resp)
+	err := l.SendHTTPGetRequest(req, true, &resp)
 
 	if err != nil {
 		return resp, err
@@ -102,8 +124,8 @@ func (l *Liqui) GetTicker(symbol string) (map[string]LiquiTicker, error) {
 	}
 
 	response := Response{}
-	req := fmt.Sprintf("%s/%s/%s/%s", LIQUI_API_PUBLIC_URL, LIQUI_API_PUBLIC_VERSION, LIQUI_TICKER, symbol)
-	err := common.SendHTTPGetRequest(req, true, &response.Data)
+	req := fmt.Sprintf("%s/%s/%s/%s/%s", l.GetAPIUrl(LIQUI_API_URL), LIQUI_API_PUBLIC, LIQUI_API_PUBLIC_VERSION, LIQUI_TICKER, symbol)
+	err := l.SendHTTPGetRequest(req, true, &response.Data)
 
 	if err != nil {
 		return nil, err
@@ -117,9 +139,9 @@ func (l *Liqui) GetDepth(symbol string) (LiquiOrderbook, error) {
 	}
 
 	response := Response{}
-	req := fmt.Sprintf("%s/%s/%s/%s", LIQUI_API_PUBLIC_URL, LIQUI_API_PUBLIC_VERSION, LIQUI_DEPTH, symbol)
+	req := fmt.Sprintf("%s/%s/%s/%s/%s", l.GetAPIUrl(LIQUI_API_URL), LIQUI_API_PUBLIC, LIQUI_API_PUBLIC_VERSION, LIQUI_DEPTH, symbol)
 
-	err := common.SendHTTPGetRequest(req, true, &response.Data)
+	err := l.SendHTTPGetRequest(req, true, &response.Data)
 	if err != nil {
 		return LiquiOrderbook{}, err
 	}
@@ -134,9 +156,9 @@ func (l *Liqui) GetTrades(symbol string) ([]LiquiTrades, error) {
 	}
 
 	response := Response{}
-	req := fmt.Sprintf("%s/%s/%s/%s", LIQUI_API_PUBLIC_URL, LIQUI_API_PUBLIC_VERSION, LIQUI_TRADES, symbol)
+	req := fmt.Sprintf("%s/%s/%s/%s/%s", l.GetAPIUrl(LIQUI_API_URL), LIQUI_API_PUBLIC, LIQUI_API_PUBLIC_VERSION, LIQUI_TRADES, symbol)
 
-	err := common.SendHTTPGetRequest(req, true, &response.Data)
+	err := l.SendHTTPGetRequest(req, true, &response.Data)
 	if err != nil {
 		return []LiquiTrades{}, err
 	}
@@ -174,9 +196,11 @@ func (l *Liqui) Trade(pair, orderType string, amount, price float64) (float64, e
 	return result.OrderID, nil
 }
 
-func (l *Liqui) GetActiveOrders(pair string) (map[string]LiquiActiveOrders, error) {
+func (l *Liqui) GetOpenOrders(pair string) (map[string]LiquiActiveOrders, error) {
 	req := url.Values{}
-	req.Add("pair", pair)
+	if pair != "" {
+		req.Add("pair", pair)
+	}
 
 	var result map[string]LiquiActiveOrders
 	err := l.SendAuthenticatedHTTPRequest(LIQUI_ACTIVE_ORDERS, req, &result)
@@ -188,7 +212,7 @@ func (l *Liqui) GetActiveOrders(pair string) (map[string]LiquiActiveOrders, erro
 	return result, nil
 }
 
-func (l *Liqui) GetOrderInfo(OrderID int64) (map[string]LiquiOrderInfo, error) {
+func (l *Liqui) OrderInfo(OrderID int64) (map[string]LiquiOrderInfo, error) {
 	req := url.Values{}
 	req.Add("order_id", strconv.FormatInt(OrderID, 10))
 
@@ -202,7 +226,7 @@ func (l *Liqui) GetOrderInfo(OrderID int64) (map[string]LiquiOrderInfo, error) {
 	return result, nil
 }
 
-func (l *Liqui) CancelOrder(OrderID int64) (bool, error) {
+func (l *Liqui) CancelExistingOrder(OrderID int64) (bool, error) {
 	req := url.Values{}
 	req.Add("order_id", strconv.FormatInt(OrderID, 10))
 
@@ -249,15 +273,18 @@ func (l *Liqui) WithdrawCoins(coin string, amount float64, address string) (Liqu
 }
 
 func (l *Liqui) SendAuthenticatedHTTPRequest(method string, values url.Values, result interface{}) (err error) {
-	nonce := strconv.FormatInt(time.Now().Unix(), 10)
+	l.WaitRateLimit(true)
+	nonce := l.GetNonce().GetString()
 	values.Set("nonce", nonce)
 	values.Set("method", method)
 
 	encoded := values.Encode()
 	hmac := common.GetHMAC(common.HASH_SHA512, []byte(encoded), []byte(l.APISecret))
 
+	path := fmt.Sprintf("%s/%s", l.GetAPIUrl(LIQUI_API_URL), LIQUI_API_PRIVATE)
+
 	if l.Verbose {
-		log.Printf("Sending POST request to %s calling method %s with params %s\n", LIQUI_API_PRIVATE_URL, method, encoded)
+		log.Printf("Sending POST request to %s calling method %s with params %s\n", path, method, encoded)
 	}
 
 	headers := make(map[string]string)
@@ -265,21 +292,21 @@ func (l *Liqui) SendAuthenticatedHTTPRequest(method string, values url.Values, r
 	headers["Sign"] = common.HexEncodeToString(hmac)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	resp, err := common.SendHTTPRequest("POST", LIQUI_API_PRIVATE_URL, headers, strings.NewReader(encoded))
+	resp, err := l.SendHTTPRequest("POST", path, headers, strings.NewReader(encoded))
 
 	if err != nil {
-		return err
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, err, resp))
 	}
 
 	response := LiquiResponse{}
 	err = common.JSONDecode([]byte(resp), &response)
 
 	if err != nil {
-		return err
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, err, resp))
 	}
 
 	if response.Success != 1 {
-		return errors.New(response.Error)
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, errors.New(response.Error), resp))
 	}
 
 	jsonEncoded, err := common.JSONEncode(response.Return)
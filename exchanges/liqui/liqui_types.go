@@ -19,7 +19,7 @@ type LiquiOrderbook struct {
 
 type LiquiTrades struct {
 	Type      string  `json:"type"`
-	Price     float64 `json:"bid"`
+	Price     float64 `json:"price"`
 	Amount    float64 `json:"amount"`
 	TID       int64   `json:"tid"`
 	Timestamp int64   `json:"timestamp"`
@@ -66,7 +66,7 @@ type LiquiTrade struct {
 
 type LiquiActiveOrders struct {
 	Pair             string  `json:"pair"`
-	Type             string  `json:"sell"`
+	Type             string  `json:"type"`
 	Amount           float64 `json:"amount"`
 	Rate             float64 `json:"rate"`
 	TimestampCreated float64 `json:"time_created"`
@@ -75,7 +75,7 @@ type LiquiActiveOrders struct {
 
 type LiquiOrderInfo struct {
 	Pair             string  `json:"pair"`
-	Type             string  `json:"sell"`
+	Type             string  `json:"type"`
 	StartAmount      float64 `json:"start_amount"`
 	Amount           float64 `json:"amount"`
 	Rate             float64 `json:"rate"`
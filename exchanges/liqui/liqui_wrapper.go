@@ -3,6 +3,9 @@ package liqui
 import (
 	"errors"
 	"log"
+	"net/url"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -11,6 +14,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (l *Liqui) Start() {
@@ -33,6 +37,7 @@ func (l *Liqui) Run() {
 		if err != nil {
 			log.Printf("%s Failed to get config.\n", l.GetName())
 		}
+		l.setPairInfo(l.Info)
 	}
 
 	pairs := []string{}
@@ -44,7 +49,7 @@ func (l *Liqui) Run() {
 	pairsString := common.JoinStrings(pairs, "-")
 
 	for l.Enabled {
-		go func() {
+		l.Poll("tickers", func() {
 			ticker, err := l.GetTicker(pairsString)
 			if err != nil {
 				log.Println(err)
@@ -56,7 +61,7 @@ func (l *Liqui) Run() {
 				l.Ticker[x] = y
 				stats.AddExchangeInfo(l.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), y.Last, y.Vol_cur)
 			}
-		}()
+		})
 		time.Sleep(time.Second * l.RESTPollingDelay)
 	}
 }
@@ -123,3 +128,278 @@ func (e *Liqui) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 
 	return response, nil
 }
+
+//SubmitOrder : Places a new limit order on Liqui
+func (l *Liqui) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := l.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	orderID, err := l.Trade(l.formatOrderPair(order.Pair), common.StringToLower(string(order.Side)), order.Amount, order.Price)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = l.GetName()
+	result.OrderID = strconv.FormatInt(int64(orderID), 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (l *Liqui) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.CancelExistingOrder(id)
+	return err
+}
+
+//ModifyOrder : Liqui does not support amending orders
+func (l *Liqui) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (l *Liqui) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return detail, err
+	}
+
+	response, err := l.OrderInfo(id)
+	if err != nil {
+		return detail, err
+	}
+
+	order, ok := response[orderID]
+	if !ok {
+		return detail, errors.New(exchange.ErrOrderNotFound)
+	}
+
+	detail.Exchange = l.GetName()
+	detail.OrderID = orderID
+	detail.Pair = pair.NewCurrencyPairDelimiter(common.StringToUpper(order.Pair), "_")
+	detail.Side = exchange.OrderSide(common.StringToUpper(order.Type))
+	detail.Type = exchange.OrderTypeLimit
+	detail.Price = order.Rate
+	detail.Amount = order.StartAmount
+	detail.FilledAmount = order.StartAmount - order.Amount
+	detail.CreatedAt = time.Unix(int64(order.TimestampCreated), 0)
+
+	switch order.Status {
+	case 0:
+		detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, true, false)
+	case 1:
+		detail.Status = exchange.OrderStatusFilled
+	case 2, 3:
+		detail.Status = exchange.OrderStatusCancelled
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail, nil
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (l *Liqui) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := l.GetOpenOrders("")
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for id, x := range response {
+		var detail exchange.OrderDetail
+		detail.Exchange = l.GetName()
+		detail.OrderID = id
+		detail.Pair = pair.NewCurrencyPairDelimiter(common.StringToUpper(x.Pair), "_")
+		detail.Side = exchange.OrderSide(common.StringToUpper(x.Type))
+		detail.Type = exchange.OrderTypeLimit
+		detail.Status = exchange.OrderStatusActive
+		detail.Price = x.Rate
+		detail.Amount = x.Amount
+		detail.CreatedAt = time.Unix(int64(x.TimestampCreated), 0)
+		orders = append(orders, detail)
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Not supported, Liqui only exposes fills through its trade history
+func (l *Liqui) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetFills : Retrieves the account's executions matching the filter, paging
+//forward by trade ID. Liqui does not report fees on its trade history
+func (l *Liqui) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	var since, end, currencyPair string
+	if !filter.StartTime.IsZero() {
+		since = strconv.FormatInt(filter.StartTime.Unix(), 10)
+	}
+
+	if !filter.EndTime.IsZero() {
+		end = strconv.FormatInt(filter.EndTime.Unix(), 10)
+	}
+
+	if len(filter.Pairs) == 1 {
+		currencyPair = l.formatOrderPair(filter.Pairs[0])
+	}
+
+	var fills []exchange.Fill
+	var fromID int64
+	for {
+		vals := url.Values{}
+		vals.Set("count", strconv.Itoa(LIQUI_TRADE_HISTORY_LIMIT))
+		vals.Set("order", "ASC")
+		if fromID > 0 {
+			vals.Set("from_id", strconv.FormatInt(fromID, 10))
+		}
+
+		if since != "" {
+			vals.Set("since", since)
+		}
+
+		if end != "" {
+			vals.Set("end", end)
+		}
+
+		response, err := l.GetTradeHistory(vals, currencyPair)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, x := range response {
+			tradeID, err := strconv.ParseInt(id, 10, 64)
+			if err == nil && tradeID >= fromID {
+				fromID = tradeID + 1
+			}
+
+			var fill exchange.Fill
+			fill.Exchange = l.GetName()
+			fill.TradeID = id
+			fill.OrderID = strconv.FormatInt(int64(x.OrderID), 10)
+			fill.Pair = pair.NewCurrencyPairDelimiter(common.StringToUpper(x.Pair), "_")
+			fill.Side = exchange.OrderSide(common.StringToUpper(x.Type))
+			fill.Price = x.Rate
+			fill.Amount = x.Amount
+			fill.Timestamp = time.Unix(int64(x.Timestamp), 0)
+			fills = append(fills, fill)
+		}
+
+		if len(response) < LIQUI_TRADE_HISTORY_LIMIT {
+			break
+		}
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+func (l *Liqui) formatOrderPair(p pair.CurrencyPair) string {
+	return common.StringToLower(p.GetFirstCurrency().String()) + "_" + common.StringToLower(p.GetSecondCurrency().String())
+}
+
+//GetDepositAddress : Not supported, Liqui has no deposit address endpoint
+func (l *Liqui) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(l.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Withdraws a currency to an external address
+func (l *Liqui) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	currency := common.StringToUpper(request.Currency)
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(l.GetName(), currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(l.GetName(), currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	response, err := l.WithdrawCoins(currency, request.Amount, request.Address)
+	if err != nil {
+		return result, exchange.NewFundingError(l.GetName(), currency, err)
+	}
+
+	result.Exchange = l.GetName()
+	result.WithdrawalID = strconv.FormatInt(response.TID, 10)
+	result.Currency = currency
+	result.Address = request.Address
+	result.Amount = response.AmountSent
+	return result, nil
+}
+
+//UpdatePairInfo : Populates the pair metadata table from the Liqui info
+//endpoint. Hidden pairs are marked as halted
+func (l *Liqui) UpdatePairInfo() error {
+	response, err := l.GetInfo()
+	if err != nil {
+		return err
+	}
+
+	l.setPairInfo(response)
+	return nil
+}
+
+func (l *Liqui) setPairInfo(response LiquiInfo) {
+	var info []exchange.PairInfo
+	for symbol, x := range response.Pairs {
+		if !common.StringContains(symbol, "_") {
+			continue
+		}
+		status := exchange.PairStatusTrading
+		if x.Hidden == 1 {
+			status = exchange.PairStatusHalted
+		}
+		info = append(info, exchange.PairInfo{
+			Pair:           pair.NewCurrencyPairDelimiter(common.StringToUpper(symbol), "_"),
+			MinAmount:      x.MinAmount,
+			AmountStep:     exchange.GetPrecisionStep(8),
+			PriceTick:      exchange.GetPrecisionStep(x.DecimalPlaces),
+			QuotePrecision: x.DecimalPlaces,
+			Status:         status,
+		})
+	}
+	l.SetPairInfo(info)
+}
+
+//GetHistoricCandles : Not supported, Liqui has no candle endpoint
+func (l *Liqui) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair
+func (l *Liqui) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := l.GetTrades(l.formatOrderPair(p))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(x.Timestamp, 0),
+		}
+		if x.Type == "ask" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(l.GetName(), p, result)
+	return result, nil
+}
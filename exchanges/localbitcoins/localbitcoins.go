@@ -7,7 +7,6 @@ import (
 	"log"
 	"net/url"
 	"strconv"
-	"time"
 
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
@@ -27,6 +26,11 @@ const (
 	LOCALBITCOINS_API_WALLET_ADDRESS  = "wallet-addr/"
 )
 
+const (
+	LOCALBITCOINS_AUTH_RATE_LIMIT   = 60
+	LOCALBITCOINS_UNAUTH_RATE_LIMIT = 60
+)
+
 type LocalBitcoins struct {
 	exchange.ExchangeBase
 }
@@ -38,6 +42,7 @@ func (l *LocalBitcoins) SetDefaults() {
 	l.Verbose = false
 	l.Websocket = false
 	l.RESTPollingDelay = 10
+	l.SetRateLimit(LOCALBITCOINS_AUTH_RATE_LIMIT, LOCALBITCOINS_UNAUTH_RATE_LIMIT)
 }
 
 func (l *LocalBitcoins) Setup(exch config.ExchangeConfig) {
@@ -48,6 +53,11 @@ func (l *LocalBitcoins) Setup(exch config.ExchangeConfig) {
 		l.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		l.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		l.RESTPollingDelay = exch.RESTPollingDelay
+		l.UpdateRateLimit(exch)
+		err := l.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", l.GetName(), err)
+		}
 		l.Verbose = exch.Verbose
 		l.Websocket = exch.Websocket
 		l.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -66,7 +76,7 @@ func (l *LocalBitcoins) GetFee(maker bool) float64 {
 
 func (l *LocalBitcoins) GetTicker() (map[string]LocalBitcoinsTicker, error) {
 	result := make(map[string]LocalBitcoinsTicker)
-	err := common.SendHTTPGetRequest(LOCALBITCOINS_API_URL+LOCALBITCOINS_API_TICKER, true, &result)
+	err := l.SendHTTPGetRequest(l.GetAPIUrl(LOCALBITCOINS_API_URL)+LOCALBITCOINS_API_TICKER, true, &result)
 
 	if err != nil {
 		return result, err
@@ -76,9 +86,9 @@ func (l *LocalBitcoins) GetTicker() (map[string]LocalBitcoinsTicker, error) {
 }
 
 func (l *LocalBitcoins) GetTrades(currency string, values url.Values) ([]LocalBitcoinsTrade, error) {
-	path := common.EncodeURLValues(fmt.Sprintf("%s/%s/trades.json", LOCALBITCOINS_API_URL+LOCALBITCOINS_API_BITCOINCHARTS, currency), values)
+	path := common.EncodeURLValues(fmt.Sprintf("%s/%s/trades.json", l.GetAPIUrl(LOCALBITCOINS_API_URL)+LOCALBITCOINS_API_BITCOINCHARTS, currency), values)
 	result := []LocalBitcoinsTrade{}
-	err := common.SendHTTPGetRequest(path, true, &result)
+	err := l.SendHTTPGetRequest(path, true, &result)
 
 	if err != nil {
 		return result, err
@@ -93,9 +103,9 @@ func (l *LocalBitcoins) GetOrderbook(currency string) (LocalBitcoinsOrderbook, e
 		Asks [][]string `json:"asks"`
 	}
 
-	path := fmt.Sprintf("%s/%s/orderbook.json", LOCALBITCOINS_API_URL+LOCALBITCOINS_API_BITCOINCHARTS, currency)
+	path := fmt.Sprintf("%s/%s/orderbook.json", l.GetAPIUrl(LOCALBITCOINS_API_URL)+LOCALBITCOINS_API_BITCOINCHARTS, currency)
 	resp := response{}
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := l.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return LocalBitcoinsOrderbook{}, err
@@ -147,8 +157,8 @@ func (l *LocalBitcoins) GetAccountInfo(username string, self bool) (LocalBitcoin
 			return resp.Data, err
 		}
 	} else {
-		path := fmt.Sprintf("%s/api/account_info/%s/", LOCALBITCOINS_API_URL, username)
-		err := common.SendHTTPGetRequest(path, true, &resp)
+		path := fmt.Sprintf("%s/api/account_info/%s/", l.GetAPIUrl(LOCALBITCOINS_API_URL), username)
+		err := l.SendHTTPGetRequest(path, true, &resp)
 
 		if err != nil {
 			return resp.Data, err
@@ -267,7 +277,8 @@ func (l *LocalBitcoins) GetWalletAddress() (string, error) {
 }
 
 func (l *LocalBitcoins) SendAuthenticatedHTTPRequest(method, path string, values url.Values, result interface{}) (err error) {
-	nonce := strconv.FormatInt(time.Now().UnixNano(), 10)
+	l.WaitRateLimit(true)
+	nonce := l.GetNonce().GetString()
 	payload := ""
 	path = "/api/" + path
 
@@ -283,16 +294,25 @@ func (l *LocalBitcoins) SendAuthenticatedHTTPRequest(method, path string, values
 	headers["Apiauth-Signature"] = common.StringToUpper(common.HexEncodeToString(hmac))
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	resp, err := common.SendHTTPRequest(method, LOCALBITCOINS_API_URL+path, headers, bytes.NewBuffer([]byte(payload)))
+	resp, err := l.SendHTTPRequest(method, l.GetAPIUrl(LOCALBITCOINS_API_URL)+path, headers, bytes.NewBuffer([]byte(payload)))
 
 	if l.Verbose {
 		log.Printf("Recieved raw: \n%s\n", resp)
 	}
 
+	errResponse := LocalBitcoinsErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.Error != nil {
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, fmt.Errorf("LocalBitcoins error %d: %s", errResponse.Error.ErrorCode, errResponse.Error.Message), resp))
+	}
+
+	if err != nil {
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, err, resp))
+	}
+
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return l.CheckNonceError(exchange.NewExchangeError(l.Name, errors.New("Unable to JSON Unmarshal response."), resp))
 	}
 
 	return nil
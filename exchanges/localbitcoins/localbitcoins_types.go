@@ -82,3 +82,10 @@ type LocalBitcoinsWalletBalanceInfo struct {
 	ReceivingAddressCount int                              `json:"receiving_address_count"` // always 1
 	ReceivingAddressList  []LocalBitcoinsWalletAddressList `json:"receiving_address_list"`
 }
+
+type LocalBitcoinsErrorResponse struct {
+	Error *struct {
+		Message   string `json:"message"`
+		ErrorCode int64  `json:"error_code"`
+	} `json:"error"`
+}
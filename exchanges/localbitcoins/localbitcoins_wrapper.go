@@ -1,14 +1,19 @@
 package localbitcoins
 
 import (
+	"errors"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/currency/pair"
 	"github.com/champii/gocryptotrader/exchanges"
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (l *LocalBitcoins) Start() {
@@ -79,3 +84,130 @@ func (e *LocalBitcoins) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo,
 	response.Currencies = append(response.Currencies, exchangeCurrency)
 	return response, nil
 }
+
+//SubmitOrder : Not supported, LocalBitcoins is a peer to peer marketplace and has no order API
+func (l *LocalBitcoins) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//CancelOrder : Not supported, LocalBitcoins is a peer to peer marketplace and has no order API
+func (l *LocalBitcoins) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//ModifyOrder : Not supported, LocalBitcoins is a peer to peer marketplace and has no order API
+func (l *LocalBitcoins) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Not supported, LocalBitcoins is a peer to peer marketplace and has no order API
+func (l *LocalBitcoins) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	return exchange.OrderDetail{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetActiveOrders : Not supported, LocalBitcoins is a peer to peer marketplace and has no order API
+func (l *LocalBitcoins) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderHistory : Not supported, LocalBitcoins is a peer to peer marketplace and has no order API
+func (l *LocalBitcoins) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetFills : Not supported, LocalBitcoins is a peer to peer marketplace and has no order API
+func (l *LocalBitcoins) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetDepositAddress : Retrieves the wallet receive address. Only BTC is supported
+func (l *LocalBitcoins) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	var address exchange.DepositAddress
+	currency = common.StringToUpper(currency)
+	if currency != "BTC" {
+		return address, exchange.NewFundingError(l.GetName(), currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	response, err := l.GetWalletAddress()
+	if err != nil {
+		return address, exchange.NewFundingError(l.GetName(), currency, err)
+	}
+
+	if response == "" {
+		return address, exchange.NewFundingError(l.GetName(), currency, errors.New(exchange.ErrDepositAddressNotFound))
+	}
+
+	address.Exchange = l.GetName()
+	address.Currency = currency
+	address.Address = response
+	return address, nil
+}
+
+//WithdrawCryptocurrency : Sends BTC from the wallet. LocalBitcoins does not
+//return an ID for the transaction
+func (l *LocalBitcoins) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	currency := common.StringToUpper(request.Currency)
+	if currency != "BTC" {
+		return result, exchange.NewFundingError(l.GetName(), currency, errors.New(exchange.ErrCurrencyNotSupported))
+	}
+
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(l.GetName(), currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(l.GetName(), currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	_, err = l.WalletSend(request.Address, request.Amount, 0)
+	if err != nil {
+		return result, exchange.NewFundingError(l.GetName(), currency, err)
+	}
+
+	result.Exchange = l.GetName()
+	result.Currency = currency
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//UpdatePairInfo : Not supported, LocalBitcoins is a peer to peer marketplace without order constraints
+func (l *LocalBitcoins) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Not supported, LocalBitcoins has no candle endpoint
+func (l *LocalBitcoins) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	return exchange.CandleSeries{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetRecentTrades : Retrieves the latest completed BTC trades for a fiat
+//currency. LocalBitcoins does not report the trade side
+func (l *LocalBitcoins) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	if p.GetFirstCurrency().Upper().String() != "BTC" {
+		return nil, errors.New(exchange.ErrCurrencyNotSupported)
+	}
+
+	response, err := l.GetTrades(p.GetSecondCurrency().Upper().String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		result = append(result, trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TID, 10),
+			Side:      trades.TRADE_SIDE_UNKNOWN,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(x.Date, 0),
+		})
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(l.GetName(), p, result)
+	return result, nil
+}
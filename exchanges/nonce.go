@@ -0,0 +1,168 @@
+package exchange
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/champii/gocryptotrader/common"
+)
+
+const (
+	NONCE_FILE_EXTENSION = ".nonce"
+	ErrNonceFileInvalid  = "Nonce file %s does not hold a valid nonce."
+)
+
+var nonceMtx sync.Mutex
+
+//Nonce : Strictly increasing nonce shared by the authenticated requests of an
+//exchange. Values follow the clock at the nonce resolution and are bumped past
+//the last value when requests are issued faster than the resolution
+type Nonce struct {
+	mtx        sync.Mutex
+	value      int64
+	resolution time.Duration
+	file       string
+}
+
+//NewNonce returns a nonce counting in units of resolution since the Unix epoch
+func NewNonce(resolution time.Duration) *Nonce {
+	if resolution <= 0 {
+		resolution = time.Nanosecond
+	}
+	return &Nonce{resolution: resolution}
+}
+
+//Get returns the next nonce, which is always greater than the previous one.
+//When the nonce is persisted the new value is written before it is returned
+func (n *Nonce) Get() int64 {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	next := time.Now().UnixNano() / int64(n.resolution)
+	if next <= n.value {
+		next = n.value + 1
+	}
+	n.value = next
+
+	if n.file != "" {
+		err := common.WriteFile(n.file, []byte(strconv.FormatInt(n.value, 10)))
+		if err != nil {
+			log.Printf("Unable to persist nonce to %s. Error: %s\n", n.file, err)
+		}
+	}
+	return n.value
+}
+
+//GetString returns the next nonce as a string
+func (n *Nonce) GetString() string {
+	return strconv.FormatInt(n.Get(), 10)
+}
+
+//GetValue returns the last nonce returned by Get
+func (n *Nonce) GetValue() int64 {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.value
+}
+
+//Resync moves the nonce forward so the next value is greater than minimum. The
+//nonce is never moved backwards
+func (n *Nonce) Resync(minimum int64) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if minimum > n.value {
+		n.value = minimum
+	}
+}
+
+//SetPersistence loads the last nonce stored in file and stores every following
+//nonce there, so the nonce keeps increasing across restarts
+func (n *Nonce) SetPersistence(file string) error {
+	data, err := common.ReadFile(file)
+	if err == nil {
+		stored, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return fmt.Errorf(ErrNonceFileInvalid, file)
+		}
+		n.Resync(stored)
+	}
+
+	n.mtx.Lock()
+	n.file = file
+	n.mtx.Unlock()
+	return nil
+}
+
+//SetNonceResolution sets the unit the exchange nonce counts in, for example
+//time.Second for exchanges limiting the nonce to 32 bits
+func (e *ExchangeBase) SetNonceResolution(resolution time.Duration) {
+	nonceMtx.Lock()
+	e.nonce = NewNonce(resolution)
+	nonceMtx.Unlock()
+}
+
+//GetNonce returns the nonce generator of the exchange. Exchanges which did not
+//set a resolution count in nanoseconds
+func (e *ExchangeBase) GetNonce() *Nonce {
+	nonceMtx.Lock()
+	defer nonceMtx.Unlock()
+
+	if e.nonce == nil {
+		e.nonce = NewNonce(time.Nanosecond)
+	}
+	return e.nonce
+}
+
+//EnableNoncePersistence stores the exchange nonce in a file named after the
+//exchange in the working directory
+func (e *ExchangeBase) EnableNoncePersistence() error {
+	return e.GetNonce().SetPersistence(common.StringToLower(e.Name) + NONCE_FILE_EXTENSION)
+}
+
+//SetNonceErrorPattern sets the format in which the exchange reports the nonce
+//it expects in an invalid nonce error. The first group of pattern matches the
+//nonce, and the pattern is matched case insensitively
+func (e *ExchangeBase) SetNonceErrorPattern(pattern string) {
+	nonceMtx.Lock()
+	e.nonceErrorPattern = regexp.MustCompile("(?i)" + pattern)
+	nonceMtx.Unlock()
+}
+
+//CheckNonceError resyncs the nonce when err reports an invalid nonce and
+//returns err unchanged. Exchanges with a nonce error pattern are resynced past
+//the nonce they report, other numbers in the error are ignored
+func (e *ExchangeBase) CheckNonceError(err error) error {
+	if !IsErrorKind(err, ErrorKindInvalidNonce) {
+		return err
+	}
+
+	message := err.Error()
+	if exchangeErr, ok := err.(*ExchangeError); ok {
+		message += " " + exchangeErr.Payload
+	}
+
+	nonceMtx.Lock()
+	pattern := e.nonceErrorPattern
+	nonceMtx.Unlock()
+
+	nonce := e.GetNonce()
+	if pattern != nil {
+		if match := pattern.FindStringSubmatch(message); len(match) > 1 {
+			value, parseErr := strconv.ParseInt(match[1], 10, 64)
+			if parseErr == nil && value > nonce.GetValue() {
+				nonce.Resync(value)
+			}
+		}
+	}
+
+	if e.Verbose {
+		log.Printf("%s invalid nonce reported, nonce resynced to %d.\n", e.Name, nonce.GetValue())
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package exchange
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNonceGet(t *testing.T) {
+	nonce := NewNonce(time.Second)
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	seen := make(map[int64]bool)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			value := nonce.Get()
+			mtx.Lock()
+			if seen[value] {
+				t.Errorf("Test Failed - Get() returned %d twice", value)
+			}
+			seen[value] = true
+			mtx.Unlock()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if nonce.GetValue() < time.Now().Unix() {
+		t.Error("Test Failed - Get() did not follow the clock at the nonce resolution")
+	}
+
+	last := nonce.GetValue()
+	nonce.Resync(last - 100)
+	if nonce.Get() != last+1 {
+		t.Error("Test Failed - Resync() moved the nonce backwards")
+	}
+
+	nonce.Resync(last + 100)
+	if nonce.Get() != last+101 {
+		t.Error("Test Failed - Resync() did not move the nonce forward")
+	}
+}
+
+func TestNoncePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "test"+NONCE_FILE_EXTENSION)
+
+	nonce := NewNonce(time.Second)
+	err = nonce.SetPersistence(file)
+	if err != nil {
+		t.Fatalf("Test Failed - SetPersistence() error: %s", err)
+	}
+	nonce.Resync(time.Now().Unix() + 1000)
+	last := nonce.Get()
+
+	restarted := NewNonce(time.Second)
+	err = restarted.SetPersistence(file)
+	if err != nil || restarted.Get() != last+1 {
+		t.Error("Test Failed - SetPersistence() did not restore the stored nonce")
+	}
+
+	ioutil.WriteFile(file, []byte("nonce"), 0644)
+	err = NewNonce(time.Second).SetPersistence(file)
+	if err == nil {
+		t.Error("Test Failed - SetPersistence() accepted an invalid nonce file")
+	}
+}
+
+func TestCheckNonceError(t *testing.T) {
+	var e ExchangeBase
+	e.SetNonceResolution(time.Second)
+	e.SetNonceErrorPattern(`you should send:\s*(\d+)`)
+	e.GetNonce().Get()
+
+	err := NewExchangeError("BTCE", errors.New("invalid nonce parameter; on key:4000000000, you sent:'1', you should send:4000000001"), "")
+	if e.CheckNonceError(err) != err {
+		t.Error("Test Failed - CheckNonceError() did not return the error")
+	}
+
+	if e.GetNonce().Get() != 4000000002 {
+		t.Error("Test Failed - CheckNonceError() did not resync the nonce")
+	}
+
+	e.CheckNonceError(NewExchangeError("BTCE", errors.New("insufficient funds 5000000000"), ""))
+	if e.GetNonce().GetValue() != 4000000002 {
+		t.Error("Test Failed - CheckNonceError() resynced on an unrelated error")
+	}
+
+	e.CheckNonceError(NewExchangeError("BTCE", errors.New("invalid nonce parameter; on key:9000000000, you sent:'1'"), `{"success":0,"balance":5000000000}`))
+	if e.GetNonce().GetValue() != 4000000002 {
+		t.Error("Test Failed - CheckNonceError() resynced to a number outside the expected nonce format")
+	}
+}
@@ -2,12 +2,13 @@ package okcoin
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/config"
 	"github.com/champii/gocryptotrader/exchanges"
@@ -65,16 +66,41 @@ const (
 	OKCOIN_FUTURES_DEVOLVE         = "future_devolve.do"
 )
 
+const (
+	OKCOIN_ORDER_HISTORY_PAGE_LENGTH = 200
+	OKCOIN_KLINE_LIMIT               = 1000
+	OKCOIN_AUTH_RATE_LIMIT           = 300
+	OKCOIN_UNAUTH_RATE_LIMIT         = 300
+)
+
 var (
 	okcoinDefaultsSet = false
+	okcoinErrorRules  = []exchange.ErrorRule{
+		{Match: "frequency too high", Kind: exchange.ErrorKindRateLimited},
+		{Match: "secretkey", Kind: exchange.ErrorKindAuthFailure},
+		{Match: "authorization error", Kind: exchange.ErrorKindAuthFailure},
+		{Match: "ip not allowed", Kind: exchange.ErrorKindAuthFailure},
+		{Match: "ip restricted", Kind: exchange.ErrorKindAuthFailure},
+		{Match: "not sufficient", Kind: exchange.ErrorKindInsufficientFunds},
+		{Match: "balance is too low", Kind: exchange.ErrorKindInsufficientFunds},
+		{Match: "no response on channel", Kind: exchange.ErrorKindOutcomeUnknown},
+		{Match: "websocket responses desynced", Kind: exchange.ErrorKindOutcomeUnknown},
+		{Match: "websocket disconnected", Kind: exchange.ErrorKindOutcomeUnknown},
+		{Match: "websocket trading stopped", Kind: exchange.ErrorKindTransport},
+	}
 )
 
 type OKCoin struct {
 	exchange.ExchangeBase
-	RESTErrors      map[string]string
-	WebsocketErrors map[string]string
-	FuturesValues   []string
-	WebsocketConn   *websocket.Conn
+	RESTErrors              map[string]string
+	WebsocketErrors         map[string]string
+	FuturesValues           []string
+	WebsocketConn           *exchange.WebsocketConnection
+	WebsocketAccount        *OKCoinWebsocketAccount
+	WebsocketCandles        *OKCoinWebsocketCandles
+	WebsocketRequestTimeout time.Duration
+	International           bool
+	websocketRequests       *okcoinWebsocketRequests
 }
 
 func (o *OKCoin) SetDefaults() {
@@ -84,18 +110,26 @@ func (o *OKCoin) SetDefaults() {
 	o.Verbose = false
 	o.Websocket = false
 	o.RESTPollingDelay = 10
+	o.SetRateLimit(OKCOIN_AUTH_RATE_LIMIT, OKCOIN_UNAUTH_RATE_LIMIT)
 	o.FuturesValues = []string{"this_week", "next_week", "quarter"}
+	o.WebsocketAccount = NewOKCoinWebsocketAccount()
+	o.WebsocketCandles = NewOKCoinWebsocketCandles()
+	o.WebsocketRequestTimeout = OKCOIN_WEBSOCKET_REQUEST_TIMEOUT
+	o.websocketRequests = newOKCoinWebsocketRequests()
 
 	if !okcoinDefaultsSet {
 		o.APIUrl = OKCOIN_API_URL
 		o.Name = "OKCOIN International"
 		o.WebsocketURL = OKCOIN_WEBSOCKET_URL
+		o.International = true
 		okcoinDefaultsSet = true
 	} else {
 		o.APIUrl = OKCOIN_API_URL_CHINA
 		o.Name = "OKCOIN China"
 		o.WebsocketURL = OKCOIN_WEBSOCKET_URL_CHINA
+		o.International = false
 	}
+	o.WebsocketConn = o.newWebsocketConnection()
 }
 
 func (o *OKCoin) Setup(exch config.ExchangeConfig) {
@@ -106,6 +140,11 @@ func (o *OKCoin) Setup(exch config.ExchangeConfig) {
 		o.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		o.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		o.RESTPollingDelay = exch.RESTPollingDelay
+		o.UpdateRateLimit(exch)
+		err := o.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", o.GetName(), err)
+		}
 		o.Verbose = exch.Verbose
 		o.Websocket = exch.Websocket
 		o.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -115,7 +154,7 @@ func (o *OKCoin) Setup(exch config.ExchangeConfig) {
 }
 
 func (o *OKCoin) GetFee(maker bool) float64 {
-	if o.APIUrl == OKCOIN_API_URL {
+	if o.International {
 		if maker {
 			return o.MakerFee
 		} else {
@@ -131,7 +170,7 @@ func (o *OKCoin) GetTicker(symbol string) (OKCoinTicker, error) {
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_TICKER, vals)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := o.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return OKCoinTicker{}, err
 	}
@@ -150,7 +189,7 @@ func (o *OKCoin) GetOrderBook(symbol string, size int64, merge bool) (OKCoinOrde
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_DEPTH, vals)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := o.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return resp, err
 	}
@@ -166,15 +205,15 @@ func (o *OKCoin) GetTrades(symbol string, since int64) ([]OKCoinTrades, error) {
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_TRADES, vals)
-	err := common.SendHTTPGetRequest(path, true, &result)
+	err := o.SendHTTPGetRequest(path, true, &result)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (o *OKCoin) GetKline(symbol, klineType string, size, since int64) ([]interface{}, error) {
-	resp := []interface{}{}
+func (o *OKCoin) GetKline(symbol, klineType string, size, since int64) ([]OKCoinKline, error) {
+	resp := [][]interface{}{}
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 	vals.Set("type", klineType)
@@ -188,12 +227,36 @@ func (o *OKCoin) GetKline(symbol, klineType string, size, since int64) ([]interf
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_KLINE, vals)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := o.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
+	klines := []OKCoinKline{}
+	for _, x := range resp {
+		if len(x) < 6 {
+			continue
+		}
+
+		var values [6]float64
+		for i := range values {
+			switch value := x[i].(type) {
+			case float64:
+				values[i] = value
+			case string:
+				values[i], _ = strconv.ParseFloat(value, 64)
+			}
+		}
+		klines = append(klines, OKCoinKline{
+			Timestamp: int64(values[0]),
+			Open:      values[1],
+			High:      values[2],
+			Low:       values[3],
+			Close:     values[4],
+			Volume:    values[5],
+		})
+	}
+	return klines, nil
 }
 
 func (o *OKCoin) GetFuturesTicker(symbol, contractType string) (OKCoinFuturesTicker, error) {
@@ -202,7 +265,7 @@ func (o *OKCoin) GetFuturesTicker(symbol, contractType string) (OKCoinFuturesTic
 	vals.Set("symbol", symbol)
 	vals.Set("contract_type", contractType)
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_TICKER, vals)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := o.SendHTTPGetRequest(path, true, &resp)
 	if err != nil {
 		return OKCoinFuturesTicker{}, err
 	}
@@ -223,7 +286,7 @@ func (o *OKCoin) GetFuturesDepth(symbol, contractType string, size int64, merge
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_DEPTH, vals)
-	err := common.SendHTTPGetRequest(path, true, &result)
+	err := o.SendHTTPGetRequest(path, true, &result)
 	if err != nil {
 		return result, err
 	}
@@ -237,7 +300,7 @@ func (o *OKCoin) GetFuturesTrades(symbol, contractType string) ([]OKCoinFuturesT
 	vals.Set("contract_type", contractType)
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_TRADES, vals)
-	err := common.SendHTTPGetRequest(path, true, &result)
+	err := o.SendHTTPGetRequest(path, true, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -254,7 +317,7 @@ func (o *OKCoin) GetFuturesIndex(symbol string) (float64, error) {
 	vals.Set("symbol", symbol)
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_INDEX, vals)
-	err := common.SendHTTPGetRequest(path, true, &result)
+	err := o.SendHTTPGetRequest(path, true, &result)
 	if err != nil {
 		return 0, err
 	}
@@ -267,7 +330,7 @@ func (o *OKCoin) GetFuturesExchangeRate() (float64, error) {
 	}
 
 	result := Response{}
-	err := common.SendHTTPGetRequest(o.APIUrl+OKCOIN_EXCHANGE_RATE, true, &result)
+	err := o.SendHTTPGetRequest(o.APIUrl+OKCOIN_EXCHANGE_RATE, true, &result)
 	if err != nil {
 		return result.Rate, err
 	}
@@ -283,7 +346,7 @@ func (o *OKCoin) GetFuturesEstimatedPrice(symbol string) (float64, error) {
 	vals := url.Values{}
 	vals.Set("symbol", symbol)
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_ESTIMATED_PRICE, vals)
-	err := common.SendHTTPGetRequest(path, true, &result)
+	err := o.SendHTTPGetRequest(path, true, &result)
 	if err != nil {
 		return result.Price, err
 	}
@@ -305,7 +368,7 @@ func (o *OKCoin) GetFuturesKline(symbol, klineType, contractType string, size, s
 	}
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_KLINE, vals)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := o.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return nil, err
@@ -320,7 +383,7 @@ func (o *OKCoin) GetFuturesHoldAmount(symbol, contractType string) ([]OKCoinFutu
 	vals.Set("contract_type", contractType)
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_HOLD_AMOUNT, vals)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := o.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return nil, err
@@ -341,7 +404,7 @@ func (o *OKCoin) GetFuturesExplosive(symbol, contractType string, status, curren
 	vals.Set("page_length", strconv.FormatInt(pageLength, 10))
 
 	path := common.EncodeURLValues(o.APIUrl+OKCOIN_FUTURES_EXPLOSIVE, vals)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	err := o.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return nil, err
@@ -367,8 +430,12 @@ func (o *OKCoin) Trade(amount, price float64, symbol, orderType string) (int64,
 		OrderID int64 `json:"order_id"`
 	}
 	v := url.Values{}
-	v.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
-	v.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
+	if amount != 0 {
+		v.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+	}
+	if price != 0 {
+		v.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
+	}
 	v.Set("symbol", symbol)
 	v.Set("type", orderType)
 
@@ -418,7 +485,7 @@ func (o *OKCoin) BatchTrade(orderData string, symbol, orderType string) (OKCoinB
 	return result, nil
 }
 
-func (o *OKCoin) CancelOrder(orderID []int64, symbol string) (OKCoinCancelOrderResponse, error) {
+func (o *OKCoin) CancelExistingOrder(orderID []int64, symbol string) (OKCoinCancelOrderResponse, error) {
 	v := url.Values{}
 	orders := []string{}
 	orderStr := ""
@@ -445,7 +512,7 @@ func (o *OKCoin) CancelOrder(orderID []int64, symbol string) (OKCoinCancelOrderR
 	return result, nil
 }
 
-func (o *OKCoin) GetOrderInfo(orderID int64, symbol string) ([]OKCoinOrderInfo, error) {
+func (o *OKCoin) OrderInfo(orderID int64, symbol string) ([]OKCoinOrderInfo, error) {
 	type Response struct {
 		Result bool              `json:"result"`
 		Orders []OKCoinOrderInfo `json:"orders"`
@@ -497,7 +564,7 @@ func (o *OKCoin) GetOrderInfoBatch(orderID []int64, symbol string) ([]OKCoinOrde
 	return result.Orders, nil
 }
 
-func (o *OKCoin) GetOrderHistory(pageLength, currentPage int64, status, symbol string) (OKCoinOrderHistory, error) {
+func (o *OKCoin) GetOrderHistoryPage(pageLength, currentPage int64, status, symbol string) (OKCoinOrderHistory, error) {
 	v := url.Values{}
 	v.Set("symbol", symbol)
 	v.Set("status", status)
@@ -877,6 +944,7 @@ func (o *OKCoin) GetFuturesUserPosition4Fix(symbol, contractType string) {
 }
 
 func (o *OKCoin) SendAuthenticatedHTTPRequest(method string, v url.Values, result interface{}) (err error) {
+	o.WaitRateLimit(true)
 	v.Set("api_key", o.APIKey)
 	hasher := common.GetMD5([]byte(v.Encode() + "&secret_key=" + o.APISecret))
 	v.Set("sign", strings.ToUpper(common.HexEncodeToString(hasher)))
@@ -891,20 +959,25 @@ func (o *OKCoin) SendAuthenticatedHTTPRequest(method string, v url.Values, resul
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 
-	resp, err := common.SendHTTPRequest("POST", path, headers, strings.NewReader(encoded))
+	resp, err := o.SendHTTPRequest("POST", path, headers, strings.NewReader(encoded))
 
 	if err != nil {
-		return err
+		return exchange.NewExchangeError(o.Name, err, resp, okcoinErrorRules...)
 	}
 
 	if o.Verbose {
 		log.Printf("Recieved raw: \n%s\n", resp)
 	}
 
+	errResponse := OKCoinErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.ErrorCode != 0 {
+		return exchange.NewExchangeError(o.Name, o.getRESTError(errResponse.ErrorCode), resp, okcoinErrorRules...)
+	}
+
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return exchange.NewExchangeError(o.Name, errors.New("Unable to JSON Unmarshal response."), resp)
 	}
 
 	return nil
@@ -986,3 +1059,11 @@ func (o *OKCoin) SetErrorDefaults() {
 		"20028": "No such contract",
 	}
 }
+
+func (o *OKCoin) getRESTError(code int64) error {
+	codeStr := strconv.FormatInt(code, 10)
+	if message, ok := o.RESTErrors[codeStr]; ok {
+		return fmt.Errorf("OKCoin error %s: %s", codeStr, message)
+	}
+	return fmt.Errorf("OKCoin error %s", codeStr)
+}
@@ -1,5 +1,11 @@
 package okcoin
 
+import (
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
 type OKCoinTicker struct {
 	Buy  float64 `json:",string"`
 	High float64 `json:",string"`
@@ -13,6 +19,15 @@ type OKCoinTickerResponse struct {
 	Date   string
 	Ticker OKCoinTicker
 }
+
+type OKCoinKline struct {
+	Timestamp int64
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+}
 type OKCoinFuturesTicker struct {
 	Last        float64
 	Buy         float64
@@ -225,9 +240,11 @@ type OKCoinWebsocketFutureIndex struct {
 	Timestamp   int64   `json:"timestamp,string"`
 }
 
+//OKCoinWebsocketTicker : Spot ticker channel frame. OKCoin sends its values
+//either as numbers or as strings, with the volume using thousands separators
 type OKCoinWebsocketTicker struct {
 	Timestamp float64
-	Vol       string
+	Vol       float64
 	Buy       float64
 	High      float64
 	Last      float64
@@ -389,6 +406,31 @@ type OKCoinWebsocketEventAuthRemove struct {
 }
 
 type OKCoinWebsocketTradeOrderResponse struct {
-	OrderID int64 `json:"order_id,string"`
-	Result  bool  `json:"result,string"`
+	OrderID   int64 `json:"order_id,string"`
+	Result    bool  `json:"result,string"`
+	ErrorCode int64 `json:"error_code"`
+}
+
+//OKCoinWebsocketTrade : Public trade pushed on the spot and futures trade
+//channels
+type OKCoinWebsocketTrade struct {
+	TradeID   int64
+	Price     float64
+	Amount    float64
+	Timestamp time.Time
+	Type      string
+}
+
+//OKCoinWebsocketChannel : Market data channel subscribed for an enabled pair.
+//ContractType is only set for futures channels and Interval for kline channels
+type OKCoinWebsocketChannel struct {
+	Name         string
+	Pair         pair.CurrencyPair
+	ContractType string
+	Interval     time.Duration
+}
+
+type OKCoinErrorResponse struct {
+	Result    bool  `json:"result"`
+	ErrorCode int64 `json:"error_code"`
 }
@@ -1,17 +1,21 @@
 package okcoin
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"net/url"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 const (
@@ -32,25 +36,52 @@ const (
 	OKCOIN_WEBSOCKET_FUTURES_ORDER_INFO   = "ok_futureusd_order_info"
 )
 
-func (o *OKCoin) PingHandler(message string) error {
-	err := o.WebsocketConn.WriteControl(websocket.PingMessage, []byte("{'event':'ping'}"), time.Now().Add(time.Second))
+const (
+	OKCOIN_WEBSOCKET_CHANNEL_TICKER = "ticker"
+	OKCOIN_WEBSOCKET_CHANNEL_DEPTH  = "depth"
+	OKCOIN_WEBSOCKET_CHANNEL_TRADES = "trades"
+	OKCOIN_WEBSOCKET_CHANNEL_KLINE  = "kline"
+	OKCOIN_WEBSOCKET_CHANNEL_INDEX  = "index"
+)
 
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-	return nil
+const (
+	OKCOIN_WEBSOCKET_REQUEST_TIMEOUT  = 10 * time.Second
+	OKCOIN_WEBSOCKET_CANDLES_LIMIT    = 1000
+	OKCOIN_WEBSOCKET_TRADE_UTC_OFFSET = 8 * 60 * 60
+)
+
+const (
+	ErrOKCoinWebsocketRequestTimeout = "%s Websocket: no response on channel %s within %s, the order outcome is unknown."
+	ErrOKCoinWebsocketRequestFailed  = "Websocket request failed."
+	ErrOKCoinWebsocketDisconnected   = "Websocket disconnected before the response was received, the order outcome is unknown."
+	ErrOKCoinWebsocketDesynced       = "Websocket responses desynced by a request timeout, the order outcome is unknown."
+	ErrOKCoinWebsocketTradingStopped = "Websocket trading stopped until the connection is reestablished."
+)
+
+var okcoinWebsocketKlineIntervals = map[string]time.Duration{
+	"1min":   time.Minute,
+	"3min":   time.Minute * 3,
+	"5min":   time.Minute * 5,
+	"15min":  time.Minute * 15,
+	"30min":  time.Minute * 30,
+	"1hour":  time.Hour,
+	"2hour":  time.Hour * 2,
+	"4hour":  time.Hour * 4,
+	"6hour":  time.Hour * 6,
+	"12hour": time.Hour * 12,
+	"day":    time.Hour * 24,
+	"3day":   time.Hour * 24 * 3,
+	"week":   time.Hour * 24 * 7,
+}
+
+//PingHandler sends the ping message OKCoin expects at least every 30 seconds
+func (o *OKCoin) PingHandler() error {
+	return o.WebsocketConn.SendJSON(OKCoinWebsocketEvent{Event: "ping"})
 }
 
 func (o *OKCoin) AddChannel(channel string) {
 	event := OKCoinWebsocketEvent{"addChannel", channel}
-	json, err := common.JSONEncode(event)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	err = o.WebsocketConn.WriteMessage(websocket.TextMessage, json)
-
+	err := o.WebsocketConn.SendJSON(event)
 	if err != nil {
 		log.Println(err)
 		return
@@ -63,13 +94,7 @@ func (o *OKCoin) AddChannel(channel string) {
 
 func (o *OKCoin) RemoveChannel(channel string) {
 	event := OKCoinWebsocketEvent{"removeChannel", channel}
-	json, err := common.JSONEncode(event)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	err = o.WebsocketConn.WriteMessage(websocket.TextMessage, json)
-
+	err := o.WebsocketConn.SendJSON(event)
 	if err != nil {
 		log.Println(err)
 		return
@@ -80,24 +105,36 @@ func (o *OKCoin) RemoveChannel(channel string) {
 	}
 }
 
-func (o *OKCoin) WebsocketSpotTrade(symbol, orderType string, price, amount float64) {
+//WebsocketSpotTrade places a spot order over the websocket and waits for its
+//order ID. Market orders leave the price or amount zero as with Trade
+func (o *OKCoin) WebsocketSpotTrade(symbol, orderType string, price, amount float64) (int64, error) {
 	values := make(map[string]string)
 	values["symbol"] = symbol
 	values["type"] = orderType
-	values["price"] = strconv.FormatFloat(price, 'f', -1, 64)
-	values["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	if price != 0 {
+		values["price"] = strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	if amount != 0 {
+		values["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	}
 	channel := ""
 
-	if o.WebsocketURL == OKCOIN_WEBSOCKET_URL_CHINA {
+	if !o.International {
 		channel = OKCOIN_WEBSOCKET_SPOTCNY_TRADE
 	} else {
 		channel = OKCOIN_WEBSOCKET_SPOTUSD_TRADE
 	}
 
-	o.AddChannelAuthenticated(channel, values)
+	response, err := o.WebsocketRequest(channel, values)
+	if err != nil {
+		return 0, err
+	}
+	return response.OrderID, nil
 }
 
-func (o *OKCoin) WebsocketFuturesTrade(symbol, contractType string, price, amount float64, orderType, matchPrice, leverage int) {
+//WebsocketFuturesTrade places a futures order over the websocket and waits for
+//its order ID
+func (o *OKCoin) WebsocketFuturesTrade(symbol, contractType string, price, amount float64, orderType, matchPrice, leverage int) (int64, error) {
 	values := make(map[string]string)
 	values["symbol"] = symbol
 	values["contract_type"] = contractType
@@ -105,49 +142,79 @@ func (o *OKCoin) WebsocketFuturesTrade(symbol, contractType string, price, amoun
 	values["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
 	values["type"] = strconv.Itoa(orderType)
 	values["match_price"] = strconv.Itoa(matchPrice)
-	values["lever_rate"] = strconv.Itoa(orderType)
-	o.AddChannelAuthenticated(OKCOIN_WEBSOCKET_FUTURES_TRADE, values)
+	values["lever_rate"] = strconv.Itoa(leverage)
+
+	response, err := o.WebsocketRequest(OKCOIN_WEBSOCKET_FUTURES_TRADE, values)
+	if err != nil {
+		return 0, err
+	}
+	return response.OrderID, nil
 }
 
-func (o *OKCoin) WebsocketSpotCancel(symbol string, orderID int64) {
+//WebsocketSpotCancel cancels a spot order over the websocket and waits for the
+//result
+func (o *OKCoin) WebsocketSpotCancel(symbol string, orderID int64) error {
 	values := make(map[string]string)
 	values["symbol"] = symbol
 	values["order_id"] = strconv.FormatInt(orderID, 10)
 	channel := ""
 
-	if o.WebsocketURL == OKCOIN_WEBSOCKET_URL_CHINA {
+	if !o.International {
 		channel = OKCOIN_WEBSOCKET_SPOTCNY_CANCEL_ORDER
 	} else {
 		channel = OKCOIN_WEBSOCKET_SPOTUSD_CANCEL_ORDER
 	}
 
-	o.AddChannelAuthenticated(channel, values)
+	_, err := o.WebsocketRequest(channel, values)
+	return err
 }
 
-func (o *OKCoin) WebsocketFuturesCancel(symbol, contractType string, orderID int64) {
+//WebsocketFuturesCancel cancels a futures order over the websocket and waits
+//for the result
+func (o *OKCoin) WebsocketFuturesCancel(symbol, contractType string, orderID int64) error {
 	values := make(map[string]string)
 	values["symbol"] = symbol
 	values["order_id"] = strconv.FormatInt(orderID, 10)
 	values["contract_type"] = contractType
-	o.AddChannelAuthenticated(OKCOIN_WEBSOCKET_FUTURES_CANCEL_ORDER, values)
+
+	_, err := o.WebsocketRequest(OKCOIN_WEBSOCKET_FUTURES_CANCEL_ORDER, values)
+	return err
 }
 
-func (o *OKCoin) WebsocketSpotOrderInfo(symbol string, orderID int64) {
+//WebsocketSpotUserinfo requests the spot balances, which are pushed on the
+//userinfo channel
+func (o *OKCoin) WebsocketSpotUserinfo() error {
+	channel := ""
+
+	if !o.International {
+		channel = OKCOIN_WEBSOCKET_SPOTCNY_USERINFO
+	} else {
+		channel = OKCOIN_WEBSOCKET_SPOTUSD_USERINFO
+	}
+
+	return o.AddChannelAuthenticated(channel, make(map[string]string))
+}
+
+//WebsocketSpotOrderInfo requests spot orders, which are pushed on the order
+//info channel. An order ID of -1 requests every unfilled order
+func (o *OKCoin) WebsocketSpotOrderInfo(symbol string, orderID int64) error {
 	values := make(map[string]string)
 	values["symbol"] = symbol
 	values["order_id"] = strconv.FormatInt(orderID, 10)
 	channel := ""
 
-	if o.WebsocketURL == OKCOIN_WEBSOCKET_URL_CHINA {
+	if !o.International {
 		channel = OKCOIN_WEBSOCKET_SPOTCNY_ORDER_INFO
 	} else {
 		channel = OKCOIN_WEBSOCKET_SPOTUSD_ORDER_INFO
 	}
 
-	o.AddChannelAuthenticated(channel, values)
+	return o.AddChannelAuthenticated(channel, values)
 }
 
-func (o *OKCoin) WebsocketFuturesOrderInfo(symbol, contractType string, orderID int64, orderStatus, currentPage, pageLength int) {
+//WebsocketFuturesOrderInfo requests futures orders, which are pushed on the
+//futures order info channel
+func (o *OKCoin) WebsocketFuturesOrderInfo(symbol, contractType string, orderID int64, orderStatus, currentPage, pageLength int) error {
 	values := make(map[string]string)
 	values["symbol"] = symbol
 	values["order_id"] = strconv.FormatInt(orderID, 10)
@@ -155,7 +222,32 @@ func (o *OKCoin) WebsocketFuturesOrderInfo(symbol, contractType string, orderID
 	values["status"] = strconv.Itoa(orderStatus)
 	values["current_page"] = strconv.Itoa(currentPage)
 	values["page_length"] = strconv.Itoa(pageLength)
-	o.AddChannelAuthenticated(OKCOIN_WEBSOCKET_FUTURES_ORDER_INFO, values)
+	return o.AddChannelAuthenticated(OKCOIN_WEBSOCKET_FUTURES_ORDER_INFO, values)
+}
+
+//WebsocketRequest sends an authenticated trade or cancel request and waits up
+//to WebsocketRequestTimeout for the response on the same channel. A timeout
+//leaves the outcome of the request unknown and desyncs the responses of the
+//connection, so websocket trading stops and the connection is dropped
+func (o *OKCoin) WebsocketRequest(channel string, values map[string]string) (OKCoinWebsocketTradeOrderResponse, error) {
+	reply, err := o.websocketRequests.send(channel, func() error {
+		return o.AddChannelAuthenticated(channel, values)
+	})
+	if err != nil {
+		return OKCoinWebsocketTradeOrderResponse{}, exchange.NewExchangeError(o.Name, err, "", okcoinErrorRules...)
+	}
+
+	result, ok := o.websocketRequests.wait(reply, o.WebsocketRequestTimeout)
+	if !ok {
+		timeoutErr := exchange.NewExchangeError(o.Name, fmt.Errorf(ErrOKCoinWebsocketRequestTimeout, o.Name, channel, o.WebsocketRequestTimeout), "", okcoinErrorRules...)
+		desyncErr := exchange.NewExchangeError(o.Name, errors.New(ErrOKCoinWebsocketDesynced), "", okcoinErrorRules...)
+		result, ok = o.websocketRequests.desync(reply, timeoutErr, desyncErr)
+		if ok {
+			log.Printf("%s Websocket: responses desynced on channel %s, reconnecting.\n", o.GetName(), channel)
+			o.WebsocketConn.Close()
+		}
+	}
+	return result.response, result.err
 }
 
 func (o *OKCoin) ConvertToURLValues(values map[string]string) url.Values {
@@ -172,51 +264,447 @@ func (o *OKCoin) WebsocketSign(values map[string]string) string {
 	return strings.ToUpper(common.HexEncodeToString(common.GetMD5([]byte(urlVals.Encode() + "&secret_key=" + o.APISecret))))
 }
 
-func (o *OKCoin) AddChannelAuthenticated(channel string, values map[string]string) {
+func (o *OKCoin) AddChannelAuthenticated(channel string, values map[string]string) error {
 	values["sign"] = o.WebsocketSign(values)
 	event := OKCoinWebsocketEventAuth{"addChannel", channel, values}
-	json, err := common.JSONEncode(event)
+	err := o.WebsocketConn.SendJSON(event)
 	if err != nil {
-		log.Println(err)
-		return
-	}
-	err = o.WebsocketConn.WriteMessage(websocket.TextMessage, json)
-
-	if err != nil {
-		log.Println(err)
-		return
+		return err
 	}
 
 	if o.Verbose {
 		log.Printf("%s Adding authenticated channel: %s\n", o.GetName(), channel)
 	}
+	return nil
 }
 
-func (o *OKCoin) RemoveChannelAuthenticated(conn *websocket.Conn, channel string, values map[string]string) {
+func (o *OKCoin) RemoveChannelAuthenticated(channel string, values map[string]string) {
 	values["sign"] = o.WebsocketSign(values)
 	event := OKCoinWebsocketEventAuthRemove{"removeChannel", channel, values}
-	json, err := common.JSONEncode(event)
+	err := o.WebsocketConn.SendJSON(event)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	err = o.WebsocketConn.WriteMessage(websocket.TextMessage, json)
 
+	if o.Verbose {
+		log.Printf("%s Removing authenticated channel: %s\n", o.GetName(), channel)
+	}
+}
+
+//GetFuturesContractPair returns the pair futures market data of a contract is
+//stored under, such as BTC/USD_THIS_WEEK, keeping it apart from the spot pair
+func GetFuturesContractPair(p pair.CurrencyPair, contractType string) pair.CurrencyPair {
+	return pair.NewCurrencyPair(p.GetFirstCurrency().Upper().String(), p.GetSecondCurrency().Upper().String()+"_"+strings.ToUpper(contractType))
+}
+
+//GetWebsocketChannelName returns the name of a market data channel
+func GetWebsocketChannelName(channel OKCoinWebsocketChannel) string {
+	currency := strings.ToLower(channel.Pair.GetFirstCurrency().String() + channel.Pair.GetSecondCurrency().String())
+	kline := ""
+	for k, v := range okcoinWebsocketKlineIntervals {
+		if v == channel.Interval {
+			kline = k
+		}
+	}
+
+	if channel.ContractType == "" {
+		switch channel.Name {
+		case OKCOIN_WEBSOCKET_CHANNEL_TICKER:
+			return fmt.Sprintf("ok_%s_ticker", currency)
+		case OKCOIN_WEBSOCKET_CHANNEL_DEPTH:
+			return fmt.Sprintf("ok_%s_depth60", currency)
+		case OKCOIN_WEBSOCKET_CHANNEL_TRADES:
+			return fmt.Sprintf("ok_%s_trades_v1", currency)
+		case OKCOIN_WEBSOCKET_CHANNEL_KLINE:
+			return fmt.Sprintf("ok_%s_kline_%s", currency, kline)
+		case OKCOIN_WEBSOCKET_CHANNEL_INDEX:
+			return fmt.Sprintf("ok_%s_future_index", currency)
+		}
+		return ""
+	}
+
+	switch channel.Name {
+	case OKCOIN_WEBSOCKET_CHANNEL_TICKER:
+		return fmt.Sprintf("ok_%s_future_ticker_%s", currency, channel.ContractType)
+	case OKCOIN_WEBSOCKET_CHANNEL_DEPTH:
+		return fmt.Sprintf("ok_%s_future_depth_%s_60", currency, channel.ContractType)
+	case OKCOIN_WEBSOCKET_CHANNEL_TRADES:
+		return fmt.Sprintf("ok_%s_future_trade_v1_%s", currency, channel.ContractType)
+	case OKCOIN_WEBSOCKET_CHANNEL_KLINE:
+		return fmt.Sprintf("ok_future_%s_kline_%s_%s", currency, channel.ContractType, kline)
+	}
+	return ""
+}
+
+//ParseWebsocketChannel returns the market data channel a channel name refers
+//to. Names which are not market data channels of a pair return false
+func ParseWebsocketChannel(name string) (OKCoinWebsocketChannel, bool) {
+	result := OKCoinWebsocketChannel{}
+	futuresKline := strings.HasPrefix(name, "ok_future_")
+	rest := strings.TrimPrefix(strings.TrimPrefix(name, "ok_future_"), "ok_")
+	if rest == name || len(rest) < 7 {
+		return result, false
+	}
+
+	currency := strings.ToUpper(rest[0:6])
+	result.Pair = pair.NewCurrencyPair(currency[0:3], currency[3:])
+	rest = rest[6:]
+
+	if futuresKline {
+		if !strings.HasPrefix(rest, "_kline_") {
+			return result, false
+		}
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_KLINE
+		result.ContractType, result.Interval = getWebsocketKlineInterval(rest[7:])
+		return result, result.ContractType != "" && result.Interval != 0
+	}
+
+	switch {
+	case rest == "_ticker":
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_TICKER
+	case rest == "_depth60":
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_DEPTH
+	case rest == "_trades_v1":
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_TRADES
+	case rest == "_future_index":
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_INDEX
+	case strings.HasPrefix(rest, "_kline_"):
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_KLINE
+		result.Interval = okcoinWebsocketKlineIntervals[rest[7:]]
+		return result, result.Interval != 0
+	case strings.HasPrefix(rest, "_future_ticker_"):
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_TICKER
+		result.ContractType = rest[15:]
+		return result, result.ContractType != ""
+	case strings.HasPrefix(rest, "_future_depth_") && strings.HasSuffix(rest, "_60"):
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_DEPTH
+		result.ContractType = strings.TrimSuffix(rest[14:], "_60")
+		return result, result.ContractType != ""
+	case strings.HasPrefix(rest, "_future_trade_v1_"):
+		result.Name = OKCOIN_WEBSOCKET_CHANNEL_TRADES
+		result.ContractType = rest[17:]
+		return result, result.ContractType != ""
+	default:
+		return result, false
+	}
+	return result, true
+}
+
+//getWebsocketKlineInterval splits the contract type and interval of a futures
+//kline channel, such as this_week_1min
+func getWebsocketKlineInterval(value string) (string, time.Duration) {
+	for k, v := range okcoinWebsocketKlineIntervals {
+		if strings.HasSuffix(value, "_"+k) {
+			return strings.TrimSuffix(value, "_"+k), v
+		}
+	}
+	return "", 0
+}
+
+//GetWebsocketChannels returns the market data channels of the enabled pairs.
+//The international instance subscribes to the futures contracts, the China
+//instance to the spot markets
+func (o *OKCoin) GetWebsocketChannels() []OKCoinWebsocketChannel {
+	klineValues := []string{"1min", "3min", "5min", "15min", "30min", "1hour", "2hour", "4hour", "6hour", "12hour", "day", "3day", "week"}
+	intervals := []time.Duration{}
+	for _, x := range klineValues {
+		intervals = append(intervals, okcoinWebsocketKlineIntervals[x])
+	}
+
+	channels := []OKCoinWebsocketChannel{}
+	for _, x := range o.EnabledPairs {
+		p := pair.NewCurrencyPair(x[0:3], x[3:])
+		if o.International {
+			channels = append(channels, OKCoinWebsocketChannel{Name: OKCOIN_WEBSOCKET_CHANNEL_INDEX, Pair: p})
+			for _, y := range o.FuturesValues {
+				for _, z := range []string{OKCOIN_WEBSOCKET_CHANNEL_TICKER, OKCOIN_WEBSOCKET_CHANNEL_DEPTH, OKCOIN_WEBSOCKET_CHANNEL_TRADES} {
+					channels = append(channels, OKCoinWebsocketChannel{Name: z, Pair: p, ContractType: y})
+				}
+				for _, z := range intervals {
+					channels = append(channels, OKCoinWebsocketChannel{Name: OKCOIN_WEBSOCKET_CHANNEL_KLINE, Pair: p, ContractType: y, Interval: z})
+				}
+			}
+			continue
+		}
+
+		for _, y := range []string{OKCOIN_WEBSOCKET_CHANNEL_TICKER, OKCOIN_WEBSOCKET_CHANNEL_DEPTH, OKCOIN_WEBSOCKET_CHANNEL_TRADES} {
+			channels = append(channels, OKCoinWebsocketChannel{Name: y, Pair: p})
+		}
+		for _, y := range intervals {
+			channels = append(channels, OKCoinWebsocketChannel{Name: OKCOIN_WEBSOCKET_CHANNEL_KLINE, Pair: p, Interval: y})
+		}
+	}
+	return channels
+}
+
+//WebsocketGetDepth returns the orderbook of a spot pair or futures contract.
+//The depth channels push the top 60 levels in full, so every frame is loaded
+//as a snapshot and the REST orderbook is only fetched on a resync
+func (o *OKCoin) WebsocketGetDepth(p pair.CurrencyPair, contractType string) *orderbook.Depth {
+	if contractType == "" {
+		return orderbook.GetDepth(o.GetName(), p, func() (orderbook.OrderbookBase, error) {
+			return o.GetOrderbookEx(p)
+		})
+	}
+
+	return orderbook.GetDepth(o.GetName(), GetFuturesContractPair(p, contractType), func() (orderbook.OrderbookBase, error) {
+		book, err := o.GetFuturesDepth(o.formatOrderSymbol(p), contractType, 200, false)
+		if err != nil {
+			return orderbook.OrderbookBase{}, err
+		}
+		return getWebsocketOrderbook(book.Bids, book.Asks), nil
+	})
+}
+
+//WebsocketProcessTicker stores a spot ticker channel frame in the ticker store
+func (o *OKCoin) WebsocketProcessTicker(p pair.CurrencyPair, tick OKCoinWebsocketTicker) {
+	ticker.ProcessTicker(o.GetName(), p, ticker.TickerPrice{
+		Last:   tick.Last,
+		High:   tick.High,
+		Low:    tick.Low,
+		Bid:    tick.Buy,
+		Ask:    tick.Sell,
+		Volume: tick.Vol,
+	})
+}
+
+//WebsocketProcessFuturesTicker stores a futures ticker channel frame in the
+//ticker store under the contract pair
+func (o *OKCoin) WebsocketProcessFuturesTicker(p pair.CurrencyPair, contractType string, tick OKCoinWebsocketFuturesTicker) {
+	ticker.ProcessTicker(o.GetName(), GetFuturesContractPair(p, contractType), ticker.TickerPrice{
+		Last:   tick.Last,
+		High:   tick.High,
+		Low:    tick.Low,
+		Bid:    tick.Buy,
+		Ask:    tick.Sell,
+		Volume: tick.Volume,
+	})
+}
+
+//WebsocketProcessOrderbook loads a depth channel frame. Futures levels are
+//sized in contracts
+func (o *OKCoin) WebsocketProcessOrderbook(p pair.CurrencyPair, contractType string, book OKCoinWebsocketOrderbook) {
+	err := o.WebsocketGetDepth(p, contractType).LoadSnapshot(getWebsocketOrderbook(book.Bids, book.Asks))
 	if err != nil {
 		log.Println(err)
-		return
 	}
+}
 
-	if o.Verbose {
-		log.Printf("%s Removing authenticated channel: %s\n", o.GetName(), channel)
+//WebsocketProcessTrades stores trade channel prints in the trade store,
+//futures prints under the contract pair. Bid trades were taken by a buyer
+func (o *OKCoin) WebsocketProcessTrades(p pair.CurrencyPair, contractType string, executed []OKCoinWebsocketTrade) {
+	if contractType != "" {
+		p = GetFuturesContractPair(p, contractType)
+	}
+
+	result := []trades.Trade{}
+	for _, x := range executed {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TradeID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: x.Timestamp,
+		}
+		if x.Type == "ask" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	if len(result) > 0 {
+		trades.ProcessTrades(o.GetName(), p, result)
 	}
 }
 
+//WebsocketProcessKline stores kline channel candles, futures candles under the
+//contract pair
+func (o *OKCoin) WebsocketProcessKline(p pair.CurrencyPair, contractType string, interval time.Duration, candles []exchange.Candle) {
+	if contractType != "" {
+		p = GetFuturesContractPair(p, contractType)
+	}
+	o.WebsocketCandles.Update(p.Pair().String(), interval, candles)
+}
+
+//GetWebsocketCandles returns the candles pushed on the kline channel of the
+//pair, or futures contract pair, oldest first
+func (o *OKCoin) GetWebsocketCandles(p pair.CurrencyPair, interval time.Duration) []exchange.Candle {
+	return o.WebsocketCandles.Get(p.Pair().String(), interval)
+}
+
+//WebsocketProcessRealtrades updates an order of the account from a spot
+//realtrades push. The push carries no balances, so they are requested again
+func (o *OKCoin) WebsocketProcessRealtrades(trade OKCoinWebsocketRealtrades) {
+	o.WebsocketAccount.UpdateOrder(OKCoinWebsocketOrder{
+		Amount:      trade.TradeAmount,
+		AvgPrice:    trade.AveragePrice,
+		DateCreated: trade.DateCreated,
+		TradeAmount: trade.CompletedTradeAmount,
+		OrderID:     trade.OrderID,
+		Price:       trade.TradeUnitPrice,
+		Status:      trade.Status,
+		Symbol:      trade.Symbol,
+		OrderType:   trade.TradeType,
+	})
+
+	o.WebsocketAccount.InvalidateBalances()
+	err := o.WebsocketSpotUserinfo()
+	if err != nil {
+		log.Printf("%s Websocket: unable to refresh balances: %s\n", o.GetName(), err)
+	}
+}
+
+//WebsocketProcessFuturesRealtrades updates a futures order of the account from
+//a futures realtrades push
+func (o *OKCoin) WebsocketProcessFuturesRealtrades(trade OKCoinWebsocketFuturesRealtrades) {
+	o.WebsocketAccount.UpdateFuturesOrder(OKCoinWebsocketFuturesOrder{
+		Amount:         trade.Amount,
+		ContractName:   trade.ContractName,
+		TradeAmount:    trade.TradeAmount,
+		Fee:            trade.Fee,
+		LeverageAmount: trade.LeverageAmount,
+		OrderID:        trade.OrderID,
+		Price:          trade.Price,
+		AvgPrice:       trade.AvgPrice,
+		Status:         trade.Status,
+		TradeType:      trade.TradeType,
+		UnitAmount:     trade.UnitAmount,
+	})
+}
+
+//UnmarshalJSON decodes a spot ticker whose values are numbers or strings
+func (t *OKCoinWebsocketTicker) UnmarshalJSON(data []byte) error {
+	values := make(map[string]interface{})
+	err := common.JSONDecode(data, &values)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]*float64{"timestamp": &t.Timestamp, "vol": &t.Vol, "buy": &t.Buy, "high": &t.High, "last": &t.Last, "low": &t.Low, "sell": &t.Sell}
+	for k, v := range fields {
+		if values[k] == nil {
+			continue
+		}
+
+		*v, err = getWebsocketFloat(values[k])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//getWebsocketFloat parses a number sent either as a JSON number or as a string,
+//which may hold thousands separators
+func getWebsocketFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(strings.Replace(v, ",", "", -1), 64)
+	}
+	return 0, fmt.Errorf("Unable to parse %v as a number", value)
+}
+
+func getWebsocketOrderbook(bids, asks [][]float64) orderbook.OrderbookBase {
+	book := orderbook.OrderbookBase{}
+	for _, x := range bids {
+		if len(x) >= 2 {
+			book.Bids = append(book.Bids, orderbook.OrderbookItem{Price: x[0], Amount: x[1]})
+		}
+	}
+
+	for _, x := range asks {
+		if len(x) >= 2 {
+			book.Asks = append(book.Asks, orderbook.OrderbookItem{Price: x[0], Amount: x[1]})
+		}
+	}
+	return book
+}
+
+//getWebsocketTrades parses trade channel prints of the form [id, price, amount,
+//time, type]. The time of day is given in China Standard Time, so prints from
+//before midnight belong to the previous day
+func getWebsocketTrades(data [][]string, now time.Time) ([]OKCoinWebsocketTrade, error) {
+	location := time.FixedZone("CST", OKCOIN_WEBSOCKET_TRADE_UTC_OFFSET)
+	now = now.In(location)
+
+	result := []OKCoinWebsocketTrade{}
+	for _, x := range data {
+		if len(x) < 5 {
+			return nil, fmt.Errorf("Unable to parse trade %v", x)
+		}
+
+		id, err := strconv.ParseInt(x[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := strconv.ParseFloat(x[1], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		amount, err := strconv.ParseFloat(x[2], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		clock, err := time.Parse("15:04:05", x[3])
+		if err != nil {
+			return nil, err
+		}
+
+		timestamp := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, location)
+		if timestamp.After(now.Add(time.Hour)) {
+			timestamp = timestamp.AddDate(0, 0, -1)
+		}
+		result = append(result, OKCoinWebsocketTrade{TradeID: id, Price: price, Amount: amount, Timestamp: timestamp.UTC(), Type: x[4]})
+	}
+	return result, nil
+}
+
+//getWebsocketCandles parses kline channel candles of the form [time, open,
+//high, low, close, volume] with the time in milliseconds
+func getWebsocketCandles(data [][]interface{}) ([]exchange.Candle, error) {
+	result := []exchange.Candle{}
+	for _, x := range data {
+		if len(x) < 6 {
+			return nil, fmt.Errorf("Unable to parse candle %v", x)
+		}
+
+		values := make([]float64, 6)
+		for i := range values {
+			value, err := getWebsocketFloat(x[i])
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+
+		result = append(result, exchange.Candle{
+			Time:   time.Unix(0, int64(values[0])*int64(time.Millisecond)),
+			Open:   values[1],
+			High:   values[2],
+			Low:    values[3],
+			Close:  values[4],
+			Volume: values[5],
+		})
+	}
+	return result, nil
+}
+
 func (o *OKCoin) WebsocketClient() {
-	klineValues := []string{"1min", "3min", "5min", "15min", "30min", "1hour", "2hour", "4hour", "6hour", "12hour", "day", "3day", "week"}
+	o.WebsocketConn.ExchangeName = o.GetName()
+	o.WebsocketConn.URL = o.WebsocketURL
+	o.WebsocketConn.Verbose = o.Verbose
+
+	channels := []exchange.WebsocketSubscription{}
 	currencyChan, userinfoChan := "", ""
 
-	if o.WebsocketURL == OKCOIN_WEBSOCKET_URL_CHINA {
+	if !o.International {
 		currencyChan = OKCOIN_WEBSOCKET_CNY_REALTRADES
 		userinfoChan = OKCOIN_WEBSOCKET_SPOTCNY_USERINFO
 	} else {
@@ -224,292 +712,287 @@ func (o *OKCoin) WebsocketClient() {
 		userinfoChan = OKCOIN_WEBSOCKET_SPOTUSD_USERINFO
 	}
 
-	for o.Enabled && o.Websocket {
-		var Dialer websocket.Dialer
-		var err error
-		o.WebsocketConn, _, err = Dialer.Dial(o.WebsocketURL, http.Header{})
+	if o.AuthenticatedAPISupport {
+		authenticated := []string{currencyChan, userinfoChan}
+		if o.International {
+			authenticated = append(authenticated, OKCOIN_WEBSOCKET_FUTURES_REALTRADES, OKCOIN_WEBSOCKET_FUTURES_USERINFO)
+		}
+		for _, x := range authenticated {
+			channels = append(channels, exchange.WebsocketSubscription{Channel: x, Params: map[string]string{}})
+		}
+	}
+
+	for _, x := range o.GetWebsocketChannels() {
+		channels = append(channels, exchange.WebsocketSubscription{Channel: GetWebsocketChannelName(x)})
+	}
 
+	for _, x := range channels {
+		err := o.WebsocketConn.Subscribe(x)
 		if err != nil {
-			log.Printf("%s Unable to connect to Websocket. Error: %s\n", o.GetName(), err)
+			log.Printf("%s Websocket subscription error: %s\n", o.GetName(), err)
+		}
+	}
+	o.WebsocketConn.Run(o.GetWebsocketContext())
+}
+
+//WebsocketHandleMessage decodes a batch of channel messages and routes each to
+//its handler
+func (o *OKCoin) WebsocketHandleMessage(msgType int, resp []byte) {
+	event := OKCoinWebsocketEvent{}
+	if common.JSONDecode(resp, &event) == nil && event.Event == "pong" {
+		return
+	}
+
+	if msgType != websocket.TextMessage {
+		return
+	}
+
+	response := []interface{}{}
+	err := common.JSONDecode(resp, &response)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, y := range response {
+		z, ok := y.(map[string]interface{})
+		if !ok {
 			continue
 		}
 
-		if o.Verbose {
-			log.Printf("%s Connected to Websocket.\n", o.GetName())
+		channelStr, ok := z["channel"].(string)
+		if !ok {
+			log.Println("Unable to convert channel to string")
+			continue
 		}
 
-		o.WebsocketConn.SetPingHandler(o.PingHandler)
+		success := z["success"]
+		if success != "true" && success != true && success != nil {
+			err = o.getWebsocketError(z["errorcode"])
+			log.Printf("%s Websocket: channel %s error: %s.\n", o.GetName(), channelStr, err)
+			o.websocketRequests.deliver(channelStr, OKCoinWebsocketTradeOrderResponse{}, err)
+			continue
+		}
 
-		if o.AuthenticatedAPISupport {
-			if o.WebsocketURL == OKCOIN_WEBSOCKET_URL {
-				o.AddChannelAuthenticated(OKCOIN_WEBSOCKET_FUTURES_REALTRADES, map[string]string{})
-				o.AddChannelAuthenticated(OKCOIN_WEBSOCKET_FUTURES_USERINFO, map[string]string{})
-			}
-			o.AddChannelAuthenticated(currencyChan, map[string]string{})
-			o.AddChannelAuthenticated(userinfoChan, map[string]string{})
+		data := z["data"]
+		if data == nil {
+			continue
 		}
 
-		for _, x := range o.EnabledPairs {
-			currency := common.StringToLower(x)
-			currencyUL := currency[0:3] + "_" + currency[3:]
-			if o.AuthenticatedAPISupport {
-				o.WebsocketSpotOrderInfo(currencyUL, -1)
-			}
-			if o.WebsocketURL == OKCOIN_WEBSOCKET_URL {
-				o.AddChannel(fmt.Sprintf("ok_%s_future_index", currency))
-				for _, y := range o.FuturesValues {
-					if o.AuthenticatedAPISupport {
-						o.WebsocketFuturesOrderInfo(currencyUL, y, -1, 1, 1, 50)
-					}
-					o.AddChannel(fmt.Sprintf("ok_%s_future_ticker_%s", currency, y))
-					o.AddChannel(fmt.Sprintf("ok_%s_future_depth_%s_60", currency, y))
-					o.AddChannel(fmt.Sprintf("ok_%s_future_trade_v1_%s", currency, y))
-					for _, z := range klineValues {
-						o.AddChannel(fmt.Sprintf("ok_future_%s_kline_%s_%s", currency, y, z))
-					}
-				}
-			} else {
-				o.AddChannel(fmt.Sprintf("ok_%s_ticker", currency))
-				o.AddChannel(fmt.Sprintf("ok_%s_depth60", currency))
-				o.AddChannel(fmt.Sprintf("ok_%s_trades_v1", currency))
+		dataJSON, err := common.JSONEncode(data)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
 
-				for _, y := range klineValues {
-					o.AddChannel(fmt.Sprintf("ok_%s_kline_%s", currency, y))
-				}
-			}
+		err = o.websocketHandleChannel(channelStr, dataJSON)
+		if err != nil {
+			log.Printf("%s Websocket: channel %s error: %s.\n", o.GetName(), channelStr, err)
 		}
+	}
+}
 
-		for o.Enabled && o.Websocket {
-			msgType, resp, err := o.WebsocketConn.ReadMessage()
+//websocketHandleChannel routes the data of a single channel message
+func (o *OKCoin) websocketHandleChannel(channel string, data []byte) error {
+	switch channel {
+	case OKCOIN_WEBSOCKET_SPOTUSD_TRADE, OKCOIN_WEBSOCKET_SPOTCNY_TRADE, OKCOIN_WEBSOCKET_FUTURES_TRADE,
+		OKCOIN_WEBSOCKET_SPOTUSD_CANCEL_ORDER, OKCOIN_WEBSOCKET_SPOTCNY_CANCEL_ORDER, OKCOIN_WEBSOCKET_FUTURES_CANCEL_ORDER:
+		response := OKCoinWebsocketTradeOrderResponse{}
+		err := common.JSONDecode(data, &response)
+		if err == nil && response.ErrorCode != 0 {
+			err = o.getWebsocketError(strconv.FormatInt(response.ErrorCode, 10))
+		} else if err == nil && !response.Result {
+			err = exchange.NewExchangeError(o.Name, errors.New(ErrOKCoinWebsocketRequestFailed), string(data), okcoinErrorRules...)
+		}
+		o.websocketRequests.deliver(channel, response, err)
+		return err
+	case OKCOIN_WEBSOCKET_USD_REALTRADES, OKCOIN_WEBSOCKET_CNY_REALTRADES:
+		realtrades := OKCoinWebsocketRealtrades{}
+		err := common.JSONDecode(data, &realtrades)
+		if err != nil {
+			return err
+		}
+		o.WebsocketProcessRealtrades(realtrades)
+		return nil
+	case OKCOIN_WEBSOCKET_FUTURES_REALTRADES:
+		realtrades := OKCoinWebsocketFuturesRealtrades{}
+		err := common.JSONDecode(data, &realtrades)
+		if err != nil {
+			return err
+		}
+		o.WebsocketProcessFuturesRealtrades(realtrades)
+		return nil
+	case OKCOIN_WEBSOCKET_SPOTUSD_USERINFO, OKCOIN_WEBSOCKET_SPOTCNY_USERINFO:
+		userinfo := OKCoinWebsocketUserinfo{}
+		err := common.JSONDecode(data, &userinfo)
+		if err != nil {
+			return err
+		}
+		o.WebsocketAccount.SetBalances(userinfo)
+		return nil
+	case OKCOIN_WEBSOCKET_FUTURES_USERINFO:
+		userinfo := OKCoinWebsocketFuturesUserInfo{}
+		return common.JSONDecode(data, &userinfo)
+	case OKCOIN_WEBSOCKET_SPOTUSD_ORDER_INFO, OKCOIN_WEBSOCKET_SPOTCNY_ORDER_INFO:
+		type OrderInfoResponse struct {
+			Result bool                   `json:"result"`
+			Orders []OKCoinWebsocketOrder `json:"orders"`
+		}
+		var orders OrderInfoResponse
+		err := common.JSONDecode(data, &orders)
+		if err != nil {
+			return err
+		}
+		o.WebsocketAccount.SetOrders(orders.Orders)
+		return nil
+	case OKCOIN_WEBSOCKET_FUTURES_ORDER_INFO:
+		type OrderInfoResponse struct {
+			Result bool                          `json:"result"`
+			Orders []OKCoinWebsocketFuturesOrder `json:"orders"`
+		}
+		var orders OrderInfoResponse
+		err := common.JSONDecode(data, &orders)
+		if err != nil {
+			return err
+		}
+		o.WebsocketAccount.SetFuturesOrders(orders.Orders)
+		return nil
+	}
+
+	result, ok := ParseWebsocketChannel(channel)
+	if !ok {
+		return nil
+	}
+
+	switch result.Name {
+	case OKCOIN_WEBSOCKET_CHANNEL_TICKER:
+		if result.ContractType == "" {
+			tick := OKCoinWebsocketTicker{}
+			err := common.JSONDecode(data, &tick)
 			if err != nil {
-				log.Println(err)
-				break
+				return err
 			}
-			switch msgType {
-			case websocket.TextMessage:
-				response := []interface{}{}
-				err = common.JSONDecode(resp, &response)
+			o.WebsocketProcessTicker(result.Pair, tick)
+			return nil
+		}
 
-				if err != nil {
-					log.Println(err)
-					continue
-				}
+		tick := OKCoinWebsocketFuturesTicker{}
+		err := common.JSONDecode(data, &tick)
+		if err != nil {
+			return err
+		}
+		o.WebsocketProcessFuturesTicker(result.Pair, result.ContractType, tick)
+	case OKCOIN_WEBSOCKET_CHANNEL_DEPTH:
+		book := OKCoinWebsocketOrderbook{}
+		err := common.JSONDecode(data, &book)
+		if err != nil {
+			return err
+		}
+		o.WebsocketProcessOrderbook(result.Pair, result.ContractType, book)
+	case OKCOIN_WEBSOCKET_CHANNEL_TRADES:
+		prints := [][]string{}
+		err := common.JSONDecode(data, &prints)
+		if err != nil {
+			return err
+		}
+
+		executed, err := getWebsocketTrades(prints, time.Now())
+		if err != nil {
+			return err
+		}
+		o.WebsocketProcessTrades(result.Pair, result.ContractType, executed)
+	case OKCOIN_WEBSOCKET_CHANNEL_KLINE:
+		klines := [][]interface{}{}
+		err := common.JSONDecode(data, &klines)
+		if err != nil {
+			return err
+		}
+
+		candles, err := getWebsocketCandles(klines)
+		if err != nil {
+			return err
+		}
+		o.WebsocketProcessKline(result.Pair, result.ContractType, result.Interval, candles)
+	case OKCOIN_WEBSOCKET_CHANNEL_INDEX:
+		index := OKCoinWebsocketFutureIndex{}
+		return common.JSONDecode(data, &index)
+	}
+	return nil
+}
+
+//getWebsocketError returns the error of a websocket error code
+func (o *OKCoin) getWebsocketError(code interface{}) error {
+	codeStr := fmt.Sprintf("%v", code)
+	if message, ok := o.WebsocketErrors[codeStr]; ok {
+		return exchange.NewExchangeError(o.Name, fmt.Errorf("OKCoin websocket error %s: %s", codeStr, message), "", okcoinErrorRules...)
+	}
+	return exchange.NewExchangeError(o.Name, fmt.Errorf("OKCoin websocket error %s", codeStr), "", okcoinErrorRules...)
+}
+
+//websocketConnect requests the orders of the enabled pairs, which are pushed
+//on the order info channels. Orders are synced once every symbol and contract
+//has answered
+func (o *OKCoin) websocketConnect() error {
+	if !o.AuthenticatedAPISupport {
+		return nil
+	}
 
-				for _, y := range response {
-					z := y.(map[string]interface{})
-					channel := z["channel"]
-					data := z["data"]
-					success := z["success"]
-					errorcode := z["errorcode"]
-					channelStr, ok := channel.(string)
-
-					if !ok {
-						log.Println("Unable to convert channel to string")
-						continue
-					}
-
-					if success != "true" && success != nil {
-						errorCodeStr, ok := errorcode.(string)
-						if !ok {
-							log.Printf("%s Websocket: Unable to convert errorcode to string.\n", o.GetName())
-							log.Printf("%s Websocket: channel %s error code: %s.\n", o.GetName(), channelStr, errorcode)
-						} else {
-							log.Printf("%s Websocket: channel %s error: %s.\n", o.GetName(), channelStr, o.WebsocketErrors[errorCodeStr])
-						}
-						continue
-					}
-
-					if success == "true" {
-						if data == nil {
-							continue
-						}
-					}
-
-					dataJSON, err := common.JSONEncode(data)
-
-					if err != nil {
-						log.Println(err)
-						continue
-					}
-
-					switch true {
-					case common.StringContains(channelStr, "ticker") && !common.StringContains(channelStr, "future"):
-						tickerValues := []string{"buy", "high", "last", "low", "sell", "timestamp"}
-						tickerMap := data.(map[string]interface{})
-						ticker := OKCoinWebsocketTicker{}
-						ticker.Vol = tickerMap["vol"].(string)
-
-						for _, z := range tickerValues {
-							result := reflect.TypeOf(tickerMap[z]).String()
-							if result == "string" {
-								value, err := strconv.ParseFloat(tickerMap[z].(string), 64)
-								if err != nil {
-									log.Println(err)
-									continue
-								}
-
-								switch z {
-								case "buy":
-									ticker.Buy = value
-								case "high":
-									ticker.High = value
-								case "last":
-									ticker.Last = value
-								case "low":
-									ticker.Low = value
-								case "sell":
-									ticker.Sell = value
-								case "timestamp":
-									ticker.Timestamp = value
-								}
-
-							} else if result == "float64" {
-								switch z {
-								case "buy":
-									ticker.Buy = tickerMap[z].(float64)
-								case "high":
-									ticker.High = tickerMap[z].(float64)
-								case "last":
-									ticker.Last = tickerMap[z].(float64)
-								case "low":
-									ticker.Low = tickerMap[z].(float64)
-								case "sell":
-									ticker.Sell = tickerMap[z].(float64)
-								case "timestamp":
-									ticker.Timestamp = tickerMap[z].(float64)
-								}
-							}
-						}
-					case common.StringContains(channelStr, "ticker") && common.StringContains(channelStr, "future"):
-						ticker := OKCoinWebsocketFuturesTicker{}
-						err = common.JSONDecode(dataJSON, &ticker)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "depth"):
-						orderbook := OKCoinWebsocketOrderbook{}
-						err = common.JSONDecode(dataJSON, &orderbook)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "trades_v1") || common.StringContains(channelStr, "trade_v1"):
-						type TradeResponse struct {
-							Data [][]string
-						}
-
-						trades := TradeResponse{}
-						err = common.JSONDecode(dataJSON, &trades.Data)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-						// to-do: convert from string array to trade struct
-					case common.StringContains(channelStr, "kline"):
-						klines := []interface{}{}
-						err := common.JSONDecode(dataJSON, &klines)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "spot") && common.StringContains(channelStr, "realtrades"):
-						if string(dataJSON) == "null" {
-							continue
-						}
-						realtrades := OKCoinWebsocketRealtrades{}
-						err := common.JSONDecode(dataJSON, &realtrades)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "future") && common.StringContains(channelStr, "realtrades"):
-						if string(dataJSON) == "null" {
-							continue
-						}
-						realtrades := OKCoinWebsocketFuturesRealtrades{}
-						err := common.JSONDecode(dataJSON, &realtrades)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "spot") && common.StringContains(channelStr, "trade") || common.StringContains(channelStr, "futures") && common.StringContains(channelStr, "trade"):
-						tradeOrder := OKCoinWebsocketTradeOrderResponse{}
-						err := common.JSONDecode(dataJSON, &tradeOrder)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "cancel_order"):
-						cancelOrder := OKCoinWebsocketTradeOrderResponse{}
-						err := common.JSONDecode(dataJSON, &cancelOrder)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "spot") && common.StringContains(channelStr, "userinfo"):
-						userinfo := OKCoinWebsocketUserinfo{}
-						err = common.JSONDecode(dataJSON, &userinfo)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "futureusd_userinfo"):
-						userinfo := OKCoinWebsocketFuturesUserInfo{}
-						err = common.JSONDecode(dataJSON, &userinfo)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "spot") && common.StringContains(channelStr, "order_info"):
-						type OrderInfoResponse struct {
-							Result bool                   `json:"result"`
-							Orders []OKCoinWebsocketOrder `json:"orders"`
-						}
-						var orders OrderInfoResponse
-						err := common.JSONDecode(dataJSON, &orders)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "futureusd_order_info"):
-						type OrderInfoResponse struct {
-							Result bool                          `json:"result"`
-							Orders []OKCoinWebsocketFuturesOrder `json:"orders"`
-						}
-						var orders OrderInfoResponse
-						err := common.JSONDecode(dataJSON, &orders)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					case common.StringContains(channelStr, "future_index"):
-						index := OKCoinWebsocketFutureIndex{}
-						err = common.JSONDecode(dataJSON, &index)
-
-						if err != nil {
-							log.Println(err)
-							continue
-						}
-					}
+	requests := len(o.EnabledPairs)
+	if o.International {
+		requests += len(o.EnabledPairs) * len(o.FuturesValues)
+	}
+	o.WebsocketAccount.ExpectOrders(requests)
+
+	for _, x := range o.EnabledPairs {
+		currency := common.StringToLower(x)
+		currencyUL := currency[0:3] + "_" + currency[3:]
+		err := o.WebsocketSpotOrderInfo(currencyUL, -1)
+		if err != nil {
+			return err
+		}
+
+		if o.International {
+			for _, y := range o.FuturesValues {
+				err = o.WebsocketFuturesOrderInfo(currencyUL, y, -1, 1, 1, 50)
+				if err != nil {
+					return err
 				}
 			}
 		}
-		o.WebsocketConn.Close()
-		log.Printf("%s Websocket client disconnected.", o.GetName())
 	}
+	return nil
+}
+
+//websocketDisconnect drops the account state and fails the requests awaiting
+//a response, which are lost with the connection
+func (o *OKCoin) websocketDisconnect() {
+	o.WebsocketAccount.Reset()
+	o.websocketRequests.fail(exchange.NewExchangeError(o.Name, errors.New(ErrOKCoinWebsocketDisconnected), "", okcoinErrorRules...))
+}
+
+//websocketSubscribe adds the channel, signing it when Params are set. The
+//params are copied as signing adds the key and signature to them
+func (o *OKCoin) websocketSubscribe(subscription exchange.WebsocketSubscription) error {
+	if subscription.Params == nil {
+		o.AddChannel(subscription.Channel)
+		return nil
+	}
+
+	values := make(map[string]string)
+	for k, v := range subscription.Params {
+		values[k] = v
+	}
+	return o.AddChannelAuthenticated(subscription.Channel, values)
+}
+
+func (o *OKCoin) newWebsocketConnection() *exchange.WebsocketConnection {
+	conn := exchange.NewWebsocketConnection(o.GetName(), o.WebsocketURL)
+	conn.OnConnect = o.websocketConnect
+	conn.OnDisconnect = o.websocketDisconnect
+	conn.OnSubscribe = o.websocketSubscribe
+	conn.OnMessage = o.WebsocketHandleMessage
+	conn.Ping = o.PingHandler
+	return conn
 }
 
 func (o *OKCoin) SetWebsocketErrorDefaults() {
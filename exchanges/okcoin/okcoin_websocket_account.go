@@ -0,0 +1,353 @@
+package okcoin
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+//OKCoinWebsocketAccount : Account state pushed over the authenticated
+//websocket channels. Balances are synced once the userinfo response has been
+//received and orders once every expected order info response has
+type OKCoinWebsocketAccount struct {
+	mtx            sync.RWMutex
+	balancesSynced bool
+	ordersSynced   bool
+	ordersPending  int
+	balances       OKCoinWebsocketUserinfo
+	orders         map[int64]OKCoinWebsocketOrder
+	futuresOrders  map[int64]OKCoinWebsocketFuturesOrder
+}
+
+//NewOKCoinWebsocketAccount returns an empty account awaiting its responses
+func NewOKCoinWebsocketAccount() *OKCoinWebsocketAccount {
+	return &OKCoinWebsocketAccount{
+		orders:        make(map[int64]OKCoinWebsocketOrder),
+		futuresOrders: make(map[int64]OKCoinWebsocketFuturesOrder),
+	}
+}
+
+//IsBalancesSynced returns whether the account holds the userinfo balances
+func (a *OKCoinWebsocketAccount) IsBalancesSynced() bool {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.balancesSynced
+}
+
+//IsOrdersSynced returns whether the account holds the unfilled spot orders
+func (a *OKCoinWebsocketAccount) IsOrdersSynced() bool {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.ordersSynced
+}
+
+//Reset drops the account state, so it is only used again once it is requested
+//on the next connection
+func (a *OKCoinWebsocketAccount) Reset() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.balancesSynced = false
+	a.ordersSynced = false
+	a.ordersPending = 0
+	a.balances = OKCoinWebsocketUserinfo{}
+	a.orders = make(map[int64]OKCoinWebsocketOrder)
+	a.futuresOrders = make(map[int64]OKCoinWebsocketFuturesOrder)
+}
+
+//SetBalances replaces the spot balances
+func (a *OKCoinWebsocketAccount) SetBalances(balances OKCoinWebsocketUserinfo) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.balances = balances
+	a.balancesSynced = true
+}
+
+//InvalidateBalances marks the balances stale until the next userinfo response
+func (a *OKCoinWebsocketAccount) InvalidateBalances() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.balancesSynced = false
+}
+
+//ExpectOrders marks the orders unsynced until count order info responses have
+//been received
+func (a *OKCoinWebsocketAccount) ExpectOrders(count int) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.ordersPending = count
+	a.ordersSynced = count == 0
+}
+
+//SetOrders stores the unfilled orders of an order info response. Each response
+//only holds the orders of one symbol, so the stored orders are kept
+func (a *OKCoinWebsocketAccount) SetOrders(orders []OKCoinWebsocketOrder) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	for _, x := range orders {
+		if isOKCoinOrderActive(x.Status) {
+			a.orders[int64(x.OrderID)] = x
+		}
+	}
+	a.receivedOrdersLocked()
+}
+
+//SetFuturesOrders stores the unfilled orders of a futures order info response,
+//which only holds the orders of one contract
+func (a *OKCoinWebsocketAccount) SetFuturesOrders(orders []OKCoinWebsocketFuturesOrder) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	for _, x := range orders {
+		if isOKCoinOrderActive(int64(x.Status)) {
+			a.futuresOrders[int64(x.OrderID)] = x
+		}
+	}
+	a.receivedOrdersLocked()
+}
+
+func (a *OKCoinWebsocketAccount) receivedOrdersLocked() {
+	if a.ordersPending > 0 {
+		a.ordersPending--
+	}
+	a.ordersSynced = a.ordersPending == 0
+}
+
+//UpdateOrder stores a spot order, removing it once it is cancelled or filled
+func (a *OKCoinWebsocketAccount) UpdateOrder(order OKCoinWebsocketOrder) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if !isOKCoinOrderActive(order.Status) {
+		delete(a.orders, int64(order.OrderID))
+		return
+	}
+	a.orders[int64(order.OrderID)] = order
+}
+
+//UpdateFuturesOrder stores a futures order, removing it once it is cancelled
+//or filled
+func (a *OKCoinWebsocketAccount) UpdateFuturesOrder(order OKCoinWebsocketFuturesOrder) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if !isOKCoinOrderActive(int64(order.Status)) {
+		delete(a.futuresOrders, int64(order.OrderID))
+		return
+	}
+	a.futuresOrders[int64(order.OrderID)] = order
+}
+
+//GetBalances returns the spot balances
+func (a *OKCoinWebsocketAccount) GetBalances() OKCoinWebsocketUserinfo {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.balances
+}
+
+//GetOrders returns a copy of the unfilled spot orders
+func (a *OKCoinWebsocketAccount) GetOrders() []OKCoinWebsocketOrder {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	var result []OKCoinWebsocketOrder
+	for _, x := range a.orders {
+		result = append(result, x)
+	}
+	return result
+}
+
+//GetFuturesOrders returns a copy of the unfilled futures orders
+func (a *OKCoinWebsocketAccount) GetFuturesOrders() []OKCoinWebsocketFuturesOrder {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	var result []OKCoinWebsocketFuturesOrder
+	for _, x := range a.futuresOrders {
+		result = append(result, x)
+	}
+	return result
+}
+
+//isOKCoinOrderActive returns whether an order status is unfilled, partially
+//filled or being cancelled
+func isOKCoinOrderActive(status int64) bool {
+	return status == 0 || status == 1 || status == 4
+}
+
+//OKCoinWebsocketCandles : Latest candles pushed on the kline channels, keyed by
+//pair and interval
+type OKCoinWebsocketCandles struct {
+	mtx     sync.RWMutex
+	candles map[string][]exchange.Candle
+}
+
+//NewOKCoinWebsocketCandles returns an empty candle store
+func NewOKCoinWebsocketCandles() *OKCoinWebsocketCandles {
+	return &OKCoinWebsocketCandles{candles: make(map[string][]exchange.Candle)}
+}
+
+//Update merges candles into the series of the pair and interval. The candle
+//in progress is pushed again on every change, so candles replace the stored
+//candle opening at the same time
+func (c *OKCoinWebsocketCandles) Update(pairName string, interval time.Duration, candles []exchange.Candle) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	key := fmt.Sprintf("%s_%s", pairName, interval)
+	series := c.candles[key]
+	for _, x := range candles {
+		i := len(series)
+		for i > 0 && series[i-1].Time.After(x.Time) {
+			i--
+		}
+
+		if i > 0 && series[i-1].Time.Equal(x.Time) {
+			series[i-1] = x
+			continue
+		}
+		series = append(series, exchange.Candle{})
+		copy(series[i+1:], series[i:])
+		series[i] = x
+	}
+
+	if len(series) > OKCOIN_WEBSOCKET_CANDLES_LIMIT {
+		series = series[len(series)-OKCOIN_WEBSOCKET_CANDLES_LIMIT:]
+	}
+	c.candles[key] = series
+}
+
+//Get returns a copy of the candles of the pair and interval, oldest first
+func (c *OKCoinWebsocketCandles) Get(pairName string, interval time.Duration) []exchange.Candle {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return append([]exchange.Candle(nil), c.candles[fmt.Sprintf("%s_%s", pairName, interval)]...)
+}
+
+type okcoinWebsocketReply struct {
+	response OKCoinWebsocketTradeOrderResponse
+	err      error
+}
+
+//okcoinWebsocketRequests correlates trade and cancel requests with their
+//responses. OKCoin responses carry no request ID but arrive in the order the
+//requests were sent on each channel, so waiters are queued per channel. Once a
+//request times out the queues can no longer be matched, so they stay desynced
+//until the connection is dropped
+type okcoinWebsocketRequests struct {
+	mtx      sync.Mutex
+	desynced bool
+	pending  map[string][]chan okcoinWebsocketReply
+}
+
+func newOKCoinWebsocketRequests() *okcoinWebsocketRequests {
+	return &okcoinWebsocketRequests{pending: make(map[string][]chan okcoinWebsocketReply)}
+}
+
+//send queues a waiter for the channel and sends the request while holding the
+//queue, so concurrent requests are queued in the order they are sent. Requests
+//are refused while the queues are desynced
+func (r *okcoinWebsocketRequests) send(channel string, send func() error) (chan okcoinWebsocketReply, error) {
+	reply := make(chan okcoinWebsocketReply, 1)
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.desynced {
+		return nil, errors.New(ErrOKCoinWebsocketTradingStopped)
+	}
+
+	err := send()
+	if err != nil {
+		return nil, err
+	}
+	r.pending[channel] = append(r.pending[channel], reply)
+	return reply, nil
+}
+
+//deliver passes a response to the oldest waiter of the channel. Responses are
+//dropped while the queues are desynced
+func (r *okcoinWebsocketRequests) deliver(channel string, response OKCoinWebsocketTradeOrderResponse, err error) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	queue := r.pending[channel]
+	if r.desynced || len(queue) == 0 {
+		return false
+	}
+
+	r.pending[channel] = queue[1:]
+	queue[0] <- okcoinWebsocketReply{response, err}
+	return true
+}
+
+//fail passes err to every waiter, as responses to requests sent on a lost
+//connection never arrive. The next connection starts with empty queues
+func (r *okcoinWebsocketRequests) fail(err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.failLocked(err)
+	r.desynced = false
+}
+
+//desync handles a reply which timed out. Unless its response arrived in the
+//meantime the queues are desynced, the reply receives timeoutErr and every
+//other waiter desyncErr
+func (r *okcoinWebsocketRequests) desync(reply chan okcoinWebsocketReply, timeoutErr, desyncErr error) (okcoinWebsocketReply, bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	select {
+	case result := <-reply:
+		return result, false
+	default:
+	}
+
+	for channel, queue := range r.pending {
+		for i, x := range queue {
+			if x == reply {
+				r.pending[channel] = append(queue[:i:i], queue[i+1:]...)
+				break
+			}
+		}
+	}
+	r.failLocked(desyncErr)
+	r.desynced = true
+	return okcoinWebsocketReply{err: timeoutErr}, true
+}
+
+//isDesynced returns whether a request timed out on the current connection
+func (r *okcoinWebsocketRequests) isDesynced() bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.desynced
+}
+
+func (r *okcoinWebsocketRequests) failLocked(err error) {
+	for channel, queue := range r.pending {
+		for _, x := range queue {
+			x <- okcoinWebsocketReply{err: err}
+		}
+		delete(r.pending, channel)
+	}
+}
+
+//wait returns the response passed to reply, or false once timeout passes
+func (r *okcoinWebsocketRequests) wait(reply chan okcoinWebsocketReply, timeout time.Duration) (okcoinWebsocketReply, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-reply:
+		return result, true
+	case <-timer.C:
+		return okcoinWebsocketReply{}, false
+	}
+}
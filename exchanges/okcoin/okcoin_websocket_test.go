@@ -0,0 +1,250 @@
+package okcoin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
+	"github.com/gorilla/websocket"
+)
+
+func TestParseWebsocketChannel(t *testing.T) {
+	o := OKCoin{}
+	o.SetDefaults()
+	o.EnabledPairs = []string{"BTCUSD", "LTCCNY"}
+
+	for _, international := range []bool{true, false} {
+		o.International = international
+		for _, x := range o.GetWebsocketChannels() {
+			name := GetWebsocketChannelName(x)
+			result, ok := ParseWebsocketChannel(name)
+			if !ok || result != x {
+				t.Errorf("Test Failed - ParseWebsocketChannel(%s) expected %+v, received %+v", name, x, result)
+			}
+		}
+	}
+
+	result, ok := ParseWebsocketChannel("ok_future_btcusd_kline_this_week_3day")
+	if !ok || result.ContractType != "this_week" || result.Interval != time.Hour*24*3 {
+		t.Errorf("Test Failed - ParseWebsocketChannel() futures kline incorrect: %+v", result)
+	}
+
+	for _, x := range []string{OKCOIN_WEBSOCKET_USD_REALTRADES, "ok_btcusd_kline_2min", "ok_btcusd_future_ticker_", "addChannel"} {
+		if _, ok = ParseWebsocketChannel(x); ok {
+			t.Errorf("Test Failed - ParseWebsocketChannel() accepted %s", x)
+		}
+	}
+}
+
+func TestWebsocketHandleMessage(t *testing.T) {
+	o := OKCoin{}
+	o.SetDefaults()
+	o.Name = "OKCOIN Websocket Test"
+	p := pair.NewCurrencyPair("BTC", "USD")
+
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_btcusd_ticker","data":{"buy":"1000.5","high":1010,"last":"1001","low":990,"sell":1001.5,"timestamp":1490000000000,"vol":"1,234.5"}}]`))
+	price, err := ticker.GetTicker(o.GetName(), p)
+	if err != nil || price.Bid != 1000.5 || price.Ask != 1001.5 || price.Last != 1001 || price.Volume != 1234.5 {
+		t.Errorf("Test Failed - spot ticker incorrect: %+v %v", price, err)
+	}
+
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_btcusd_future_ticker_this_week","data":{"buy":995,"high":1005,"last":"1000","low":985,"sell":996,"vol":"5000"}}]`))
+	price, err = ticker.GetTicker(o.GetName(), GetFuturesContractPair(p, "this_week"))
+	if err != nil || price.Last != 1000 || price.Bid != 995 || price.Volume != 5000 {
+		t.Errorf("Test Failed - futures ticker incorrect: %+v %v", price, err)
+	}
+
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_btcusd_depth60","data":{"asks":[[1003,1],[1002,2]],"bids":[[1000,3],[999,4]],"timestamp":"1490000000000"}}]`))
+	book, err := orderbook.GetOrderbook(o.GetName(), p)
+	if err != nil || len(book.Asks) != 2 || book.Asks[0].Price != 1002 || book.Bids[0].Price != 1000 {
+		t.Errorf("Test Failed - spot depth incorrect: %+v %v", book, err)
+	}
+
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_btcusd_trades_v1","data":[["101","1001","0.5","00:00:01","ask"],["102","1002","0.25","00:00:02","bid"]]}]`))
+	result, err := trades.GetTrades(o.GetName(), p)
+	if err != nil || len(result) != 2 {
+		t.Fatalf("Test Failed - spot trades incorrect: %+v %v", result, err)
+	}
+	if result[0].Side != trades.TRADE_SIDE_SELL || result[1].Side != trades.TRADE_SIDE_BUY || result[1].Price != 1002 {
+		t.Errorf("Test Failed - spot trades incorrect: %+v", result)
+	}
+
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_btcusd_kline_1min","data":[["1490000000000","1000","1010","990","1005","12.5"],[1490000060000,1005,1006,1004,1006,1]]}]`))
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_btcusd_kline_1min","data":[[1490000060000,1005,1008,1004,1007,2]]}]`))
+	candles := o.GetWebsocketCandles(p, time.Minute)
+	if len(candles) != 2 || candles[0].Open != 1000 || candles[1].Close != 1007 || candles[1].Volume != 2 {
+		t.Errorf("Test Failed - spot kline incorrect: %+v", candles)
+	}
+
+	o.Websocket = true
+	o.WebsocketAccount.ExpectOrders(2)
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_spotusd_order_info","data":{"result":true,"orders":[{"amount":1,"avg_price":0,"create_date":1490000000000,"deal_amount":0,"order_id":7,"price":900,"status":0,"symbol":"btc_usd","type":"buy"}]}}]`))
+	if o.WebsocketAccount.IsOrdersSynced() {
+		t.Error("Test Failed - websocket orders synced before every symbol answered")
+	}
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_spotusd_order_info","data":{"result":true,"orders":[]}}]`))
+	if !o.WebsocketAccount.IsOrdersSynced() {
+		t.Error("Test Failed - websocket orders not synced once every symbol answered")
+	}
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_usd_realtrades","data":{"averagePrice":"950","completedTradeAmount":"0.5","createdDate":1490000000000,"id":1,"orderId":8,"sigTradeAmount":"0.5","sigTradePrice":"950","status":1,"symbol":"btc_usd","tradeAmount":"2","tradeType":"sell","tradeUnitPrice":"950","unTrade":"1.5"}}]`))
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_usd_realtrades","data":{"completedTradeAmount":"1","orderId":7,"status":2,"symbol":"btc_usd","tradeAmount":"1","tradeType":"buy","tradeUnitPrice":"900"}}]`))
+	orders, err := o.GetActiveOrders(exchange.OrderFilter{})
+	if err != nil || len(orders) != 1 {
+		t.Fatalf("Test Failed - websocket active orders incorrect: %+v %v", orders, err)
+	}
+	if orders[0].OrderID != "8" || orders[0].Side != exchange.OrderSideSell || orders[0].FilledAmount != 0.5 || orders[0].Status != exchange.OrderStatusPartiallyFilled {
+		t.Errorf("Test Failed - websocket order detail incorrect: %+v", orders[0])
+	}
+
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_spotusd_userinfo","data":{"info":{"funds":{"free":{"btc":"1.5","usd":"100"},"freezed":{"btc":"0.5"}}},"result":true}}]`))
+	info, err := o.GetExchangeAccountInfo()
+	if err != nil || info.Currencies[0].TotalValue != 1.5 || info.Currencies[0].Hold != 0.5 || info.Currencies[2].TotalValue != 100 {
+		t.Errorf("Test Failed - websocket balances incorrect: %+v %v", info, err)
+	}
+
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_usd_realtrades","data":{"completedTradeAmount":"2","orderId":8,"status":2,"symbol":"btc_usd","tradeAmount":"2","tradeType":"sell","tradeUnitPrice":"950"}}]`))
+	if o.WebsocketAccount.IsBalancesSynced() {
+		t.Error("Test Failed - websocket balances still synced after a trade")
+	}
+	o.WebsocketHandleMessage(websocket.TextMessage, []byte(`[{"channel":"ok_spotusd_userinfo","data":{"info":{"funds":{"free":{"btc":"0.5","usd":"1000"},"freezed":{"btc":"0"}}},"result":true}}]`))
+	info, err = o.GetExchangeAccountInfo()
+	if err != nil || info.Currencies[0].TotalValue != 0.5 || info.Currencies[2].TotalValue != 1000 {
+		t.Errorf("Test Failed - refreshed websocket balances incorrect: %+v %v", info, err)
+	}
+
+	o.websocketDisconnect()
+	if o.WebsocketAccount.IsBalancesSynced() || o.WebsocketAccount.IsOrdersSynced() {
+		t.Error("Test Failed - websocket account is synced after a disconnect")
+	}
+}
+
+func TestGetWebsocketTrades(t *testing.T) {
+	now := time.Date(2017, 3, 20, 16, 30, 0, 0, time.UTC)
+	result, err := getWebsocketTrades([][]string{{"1", "1000", "1", "00:29:59", "bid"}, {"2", "1000", "1", "23:59:59", "ask"}}, now)
+	if err != nil || len(result) != 2 {
+		t.Fatalf("Test Failed - getWebsocketTrades() error: %v", err)
+	}
+
+	if !result[0].Timestamp.Equal(time.Date(2017, 3, 20, 16, 29, 59, 0, time.UTC)) {
+		t.Errorf("Test Failed - getWebsocketTrades() expected today, received %s", result[0].Timestamp)
+	}
+	if !result[1].Timestamp.Equal(time.Date(2017, 3, 20, 15, 59, 59, 0, time.UTC)) {
+		t.Errorf("Test Failed - getWebsocketTrades() expected yesterday, received %s", result[1].Timestamp)
+	}
+
+	if _, err = getWebsocketTrades([][]string{{"1", "1000"}}, now); err == nil {
+		t.Error("Test Failed - getWebsocketTrades() accepted an incomplete trade")
+	}
+}
+
+func TestWebsocketRequest(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			event := OKCoinWebsocketEventAuth{}
+			if common.JSONDecode(data, &event) != nil || event.Parameters["sign"] == "" {
+				continue
+			}
+
+			switch {
+			case event.Parameters["symbol"] == "ltc_usd":
+				time.Sleep(300 * time.Millisecond)
+				conn.WriteMessage(websocket.TextMessage, []byte(`[{"channel":"ok_spotusd_trade","data":{"order_id":"999","result":"true"}}]`))
+			case event.Channel == OKCOIN_WEBSOCKET_SPOTUSD_TRADE:
+				conn.WriteMessage(websocket.TextMessage, []byte(`[{"channel":"ok_spotusd_trade","data":{"order_id":"`+event.Parameters["price"]+`","result":"true"}}]`))
+			case event.Channel == OKCOIN_WEBSOCKET_SPOTUSD_CANCEL_ORDER:
+				conn.WriteMessage(websocket.TextMessage, []byte(`[{"channel":"ok_spotusd_cancel_order","success":"false","errorcode":"10009"}]`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	o := OKCoin{}
+	o.SetDefaults()
+	o.Name = "OKCOIN Websocket Request Test"
+	o.International = true
+	o.Websocket = true
+	o.AuthenticatedAPISupport = true
+	o.WebsocketRequestTimeout = 200 * time.Millisecond
+	o.WebsocketConn.ExchangeName = o.Name
+	o.WebsocketConn.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	o.WebsocketConn.Backoff.Min = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan bool)
+	go func() {
+		o.WebsocketConn.Run(ctx)
+		close(stopped)
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	for i := 0; i < 100 && !o.WebsocketConn.IsConnected(); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	results := make(chan int64, 5)
+	for i := int64(1); i <= 5; i++ {
+		go func(price int64) {
+			orderID, err := o.WebsocketSpotTrade("btc_usd", "buy", float64(price), 1)
+			if err != nil || orderID != price {
+				t.Errorf("Test Failed - WebsocketSpotTrade() expected order %d, received %d %v", price, orderID, err)
+			}
+			results <- orderID
+		}(i)
+	}
+	for i := 0; i < 5; i++ {
+		<-results
+	}
+
+	result, err := o.SubmitOrder(exchange.OrderRequest{Pair: pair.NewCurrencyPair("BTC", "USD"), Side: exchange.OrderSideBuy, Type: exchange.OrderTypeLimit, Amount: 1, Price: 42})
+	if err != nil || result.OrderID != "42" {
+		t.Errorf("Test Failed - SubmitOrder() over the websocket expected order 42, received %+v %v", result, err)
+	}
+
+	err = o.CancelOrder("7", pair.NewCurrencyPair("BTC", "USD"))
+	if !exchange.IsErrorKind(err, exchange.ErrorKindOrderNotFound) {
+		t.Errorf("Test Failed - CancelOrder() over the websocket expected an order not found error, received %v", err)
+	}
+
+	reconnects := o.WebsocketConn.GetReconnects()
+	_, err = o.WebsocketSpotTrade("ltc_usd", "buy", 10, 1)
+	if !exchange.IsErrorKind(err, exchange.ErrorKindOutcomeUnknown) || exchange.IsRetryable(err) {
+		t.Errorf("Test Failed - WebsocketSpotTrade() expected a timeout with an unknown outcome, received %v", err)
+	}
+
+	if o.isWebsocketTrading() {
+		t.Error("Test Failed - isWebsocketTrading() trading continued on desynced responses")
+	}
+
+	for i := 0; i < 100 && (o.WebsocketConn.GetReconnects() == reconnects || !o.isWebsocketTrading()); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	orderID, err := o.WebsocketSpotTrade("btc_usd", "buy", 43, 1)
+	if err != nil || orderID != 43 {
+		t.Errorf("Test Failed - WebsocketSpotTrade() after a timeout expected order 43, received %d %v", orderID, err)
+	}
+}
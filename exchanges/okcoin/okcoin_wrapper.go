@@ -1,7 +1,10 @@
 package okcoin
 
 import (
+	"errors"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -11,6 +14,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (o *OKCoin) Start() {
@@ -32,10 +36,10 @@ func (o *OKCoin) Run() {
 		for _, x := range o.EnabledPairs {
 			curr := pair.NewCurrencyPair(x[0:3], x[3:])
 			curr.Delimiter = "_"
-			if o.APIUrl == OKCOIN_API_URL {
+			if o.International {
 				for _, y := range o.FuturesValues {
 					futuresValue := y
-					go func() {
+					o.Poll(x+futuresValue, func() {
 						ticker, err := o.GetFuturesTicker(curr.Pair().Lower().String(), futuresValue)
 						if err != nil {
 							log.Println(err)
@@ -43,9 +47,9 @@ func (o *OKCoin) Run() {
 						}
 						log.Printf("OKCoin Intl Futures %s (%s): Last %f High %f Low %f Volume %f\n", curr.Pair().String(), futuresValue, ticker.Last, ticker.High, ticker.Low, ticker.Vol)
 						stats.AddExchangeInfo(o.GetName(), curr.GetFirstCurrency().String(), curr.GetSecondCurrency().String(), ticker.Last, ticker.Vol)
-					}()
+					})
 				}
-				go func() {
+				o.Poll(x, func() {
 					ticker, err := o.GetTickerPrice(curr)
 					if err != nil {
 						log.Println(err)
@@ -53,9 +57,9 @@ func (o *OKCoin) Run() {
 					}
 					log.Printf("OKCoin Intl Spot %s: Last %f High %f Low %f Volume %f\n", curr.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 					stats.AddExchangeInfo(o.GetName(), curr.GetFirstCurrency().String(), curr.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-				}()
+				})
 			} else {
-				go func() {
+				o.Poll(x, func() {
 					ticker, err := o.GetTickerPrice(curr)
 					if err != nil {
 						log.Println(err)
@@ -67,7 +71,7 @@ func (o *OKCoin) Run() {
 					log.Printf("OKCoin China %s: Last %f (%f) High %f (%f) Low %f (%f) Volume %f\n", curr.Pair().String(), tickerLastUSD, ticker.Last, tickerHighUSD, ticker.High, tickerLowUSD, ticker.Low, ticker.Volume)
 					stats.AddExchangeInfo(o.GetName(), curr.GetFirstCurrency().String(), curr.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
 					stats.AddExchangeInfo(o.GetName(), curr.GetFirstCurrency().String(), "USD", tickerLastUSD, ticker.Volume)
-				}()
+				})
 			}
 		}
 		time.Sleep(time.Second * o.RESTPollingDelay)
@@ -122,9 +126,23 @@ func (o *OKCoin) GetOrderbookEx(currency pair.CurrencyPair) (orderbook.Orderbook
 	return orderBook, nil
 }
 
+//GetExchangeAccountInfo : Retrieves the spot balances of the account. Balances
+//pushed over the authenticated websocket are used once received
 func (e *OKCoin) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error) {
 	var response exchange.ExchangeAccountInfo
 	response.ExchangeName = e.GetName()
+	if e.Websocket && e.WebsocketAccount != nil && e.WebsocketAccount.IsBalancesSynced() {
+		balances := e.WebsocketAccount.GetBalances()
+		free, frozen := balances.Info.Funds.Free, balances.Info.Funds.Frozen
+		response.Currencies = append(response.Currencies,
+			exchange.ExchangeAccountCurrencyInfo{CurrencyName: "BTC", TotalValue: free.BTC, Hold: frozen.BTC},
+			exchange.ExchangeAccountCurrencyInfo{CurrencyName: "LTC", TotalValue: free.LTC, Hold: frozen.LTC},
+			exchange.ExchangeAccountCurrencyInfo{CurrencyName: "USD", TotalValue: free.USD, Hold: frozen.USD},
+			exchange.ExchangeAccountCurrencyInfo{CurrencyName: "CNY", TotalValue: free.CNY, Hold: frozen.CNY},
+		)
+		return response, nil
+	}
+
 	assets, err := e.GetUserInfo()
 	if err != nil {
 		return response, err
@@ -156,3 +174,318 @@ func (e *OKCoin) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error)
 
 	return response, nil
 }
+
+//SubmitOrder : Places a new spot order on OKCoin, over the websocket when it
+//is connected
+func (o *OKCoin) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := o.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	orderType := common.StringToLower(string(order.Side))
+	amount := order.Amount
+	price := order.Price
+	if order.IsMarket() {
+		orderType += "_market"
+		// market buys are sized in the quote currency, so the request price
+		// is used as a reference to convert the base amount
+		if order.IsBuy() {
+			if order.Price <= 0 {
+				return result, errors.New(exchange.ErrInvalidOrderPrice)
+			}
+			price = order.Amount * order.Price
+			amount = 0
+		}
+	}
+
+	var orderID int64
+	if o.isWebsocketTrading() {
+		orderID, err = o.WebsocketSpotTrade(o.formatOrderSymbol(order.Pair), orderType, price, amount)
+	} else {
+		orderID, err = o.Trade(amount, price, o.formatOrderSymbol(order.Pair), orderType)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = o.GetName()
+	result.OrderID = strconv.FormatInt(orderID, 10)
+	result.ClientID = order.ClientID
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID, over the websocket when it is
+//connected
+func (o *OKCoin) CancelOrder(orderID string, p pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if o.isWebsocketTrading() {
+		return o.WebsocketSpotCancel(o.formatOrderSymbol(p), id)
+	}
+
+	response, err := o.CancelExistingOrder([]int64{id}, o.formatOrderSymbol(p))
+	if err != nil {
+		return err
+	}
+
+	if response.Error != "" {
+		return exchange.NewExchangeError(o.GetName(), errors.New(response.Error), "", okcoinErrorRules...)
+	}
+	return nil
+}
+
+//ModifyOrder : OKCoin does not support amending orders
+func (o *OKCoin) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	return exchange.OrderResult{}, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetOrderInfo : Retrieves the current state of an order
+func (o *OKCoin) GetOrderInfo(orderID string, p pair.CurrencyPair) (exchange.OrderDetail, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	orders, err := o.OrderInfo(id, o.formatOrderSymbol(p))
+	if err != nil {
+		return exchange.OrderDetail{}, err
+	}
+
+	if len(orders) == 0 {
+		return exchange.OrderDetail{}, errors.New(exchange.ErrOrderNotFound)
+	}
+	return o.getOrderDetail(orders[0]), nil
+}
+
+//GetActiveOrders : Retrieves all unfilled orders matching the filter. Orders
+//pushed over the authenticated websocket are used once received
+func (o *OKCoin) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	if o.Websocket && o.WebsocketAccount != nil && o.WebsocketAccount.IsOrdersSynced() {
+		for _, x := range o.WebsocketAccount.GetOrders() {
+			orders = append(orders, o.getWebsocketOrderDetail(x))
+		}
+		return exchange.FilterOrders(orders, filter), nil
+	}
+
+	for _, x := range o.getFilterPairs(filter) {
+		// an order ID of -1 returns every unfilled order for the symbol
+		response, err := o.OrderInfo(-1, o.formatOrderSymbol(x))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, y := range response {
+			orders = append(orders, o.getOrderDetail(y))
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Retrieves filled orders matching the filter, OKCoin only
+//keeps the last two days of order history
+func (o *OKCoin) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, x := range o.getFilterPairs(filter) {
+		for page := int64(1); ; page++ {
+			response, err := o.GetOrderHistoryPage(OKCOIN_ORDER_HISTORY_PAGE_LENGTH, page, "1", o.formatOrderSymbol(x))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, y := range response.Orders {
+				orders = append(orders, o.getOrderDetail(y))
+			}
+
+			if len(response.Orders) == 0 || page*OKCOIN_ORDER_HISTORY_PAGE_LENGTH >= int64(response.Total) {
+				break
+			}
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+func (o *OKCoin) getFilterPairs(filter exchange.OrderFilter) []pair.CurrencyPair {
+	if len(filter.Pairs) > 0 {
+		return filter.Pairs
+	}
+
+	var pairs []pair.CurrencyPair
+	for _, x := range o.EnabledPairs {
+		pairs = append(pairs, pair.NewCurrencyPair(x[0:3], x[3:]))
+	}
+	return pairs
+}
+
+func (o *OKCoin) formatOrderSymbol(p pair.CurrencyPair) string {
+	return common.StringToLower(p.GetFirstCurrency().String()) + "_" + common.StringToLower(p.GetSecondCurrency().String())
+}
+
+func (o *OKCoin) getOrderDetail(order OKCoinOrderInfo) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = o.GetName()
+	detail.OrderID = strconv.FormatInt(order.OrderID, 10)
+	detail.Pair = pair.NewCurrencyPairDelimiter(common.StringToUpper(order.Symbol), "_")
+	detail.Side = exchange.OrderSideBuy
+	if common.StringContains(order.Type, "sell") {
+		detail.Side = exchange.OrderSideSell
+	}
+	detail.Type = exchange.OrderTypeLimit
+	if common.StringContains(order.Type, "market") {
+		detail.Type = exchange.OrderTypeMarket
+	}
+	detail.Price = order.Price
+	detail.Amount = order.Amount
+	detail.FilledAmount = order.DealAmount
+	detail.AveragePrice = order.AvgPrice
+	detail.CreatedAt = time.Unix(0, order.Created*int64(time.Millisecond))
+
+	switch order.Status {
+	case -1:
+		detail.Status = exchange.OrderStatusCancelled
+	case 0, 1, 4:
+		detail.Status = exchange.GetOrderStatus(detail.Amount, detail.FilledAmount, true, false)
+	case 2:
+		detail.Status = exchange.OrderStatusFilled
+	default:
+		detail.Status = exchange.OrderStatusUnknown
+	}
+	return detail
+}
+
+//getWebsocketOrderDetail converts an order pushed over the websocket, which
+//holds the same fields as a REST order
+func (o *OKCoin) getWebsocketOrderDetail(order OKCoinWebsocketOrder) exchange.OrderDetail {
+	return o.getOrderDetail(OKCoinOrderInfo{
+		Amount:     order.Amount,
+		AvgPrice:   order.AvgPrice,
+		Created:    int64(order.DateCreated),
+		DealAmount: order.TradeAmount,
+		OrderID:    int64(order.OrderID),
+		OrdersID:   int64(order.OrdersID),
+		Price:      order.Price,
+		Status:     int(order.Status),
+		Symbol:     order.Symbol,
+		Type:       order.OrderType,
+	})
+}
+
+//isWebsocketTrading returns whether orders are sent over the authenticated
+//websocket, which stops while its responses are desynced
+func (o *OKCoin) isWebsocketTrading() bool {
+	return o.Websocket && o.AuthenticatedAPISupport && o.WebsocketConn != nil && o.WebsocketConn.IsConnected() && !o.websocketRequests.isDesynced()
+}
+
+//GetFills : Not supported, OKCoin has no private trade history endpoint
+func (o *OKCoin) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetDepositAddress : Not supported, OKCoin has no deposit address endpoint
+func (o *OKCoin) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	return exchange.DepositAddress{}, exchange.NewFundingError(o.GetName(), currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//WithdrawCryptocurrency : Not supported, OKCoin withdrawals require the account trade password
+func (o *OKCoin) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	return exchange.WithdrawResult{}, exchange.NewFundingError(o.GetName(), request.Currency, errors.New(exchange.ErrFunctionNotSupported))
+}
+
+//UpdatePairInfo : Not supported, OKCoin does not publish pair constraints
+func (o *OKCoin) UpdatePairInfo() error {
+	return errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetHistoricCandles : Retrieves candles for a pair, paging forward from the
+//start time in batches of OKCOIN_KLINE_LIMIT candles
+func (o *OKCoin) GetHistoricCandles(p pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	err := exchange.ValidateCandleRange(start, end, interval)
+	if err != nil {
+		return exchange.CandleSeries{}, err
+	}
+
+	klineType, ok := o.getKlineType(interval)
+	if !ok {
+		return exchange.CandleSeries{}, errors.New(exchange.ErrCandleIntervalNotSupported)
+	}
+
+	var candles []exchange.Candle
+	since := start.UnixNano() / int64(time.Millisecond)
+	endMs := end.UnixNano() / int64(time.Millisecond)
+	for since <= endMs {
+		klines, err := o.GetKline(o.formatOrderSymbol(p), klineType, OKCOIN_KLINE_LIMIT, since)
+		if err != nil {
+			return exchange.CandleSeries{}, err
+		}
+
+		for _, x := range klines {
+			candles = append(candles, exchange.Candle{
+				Time:   time.Unix(0, x.Timestamp*int64(time.Millisecond)),
+				Open:   x.Open,
+				High:   x.High,
+				Low:    x.Low,
+				Close:  x.Close,
+				Volume: x.Volume,
+			})
+		}
+
+		if len(klines) < OKCOIN_KLINE_LIMIT || klines[len(klines)-1].Timestamp < since {
+			break
+		}
+		since = klines[len(klines)-1].Timestamp + int64(interval/time.Millisecond)
+	}
+	return exchange.NewCandleSeries(o.GetName(), p, start, end, interval, candles), nil
+}
+
+func (o *OKCoin) getKlineType(interval time.Duration) (string, bool) {
+	klineTypes := map[time.Duration]string{
+		time.Minute:        "1min",
+		time.Minute * 3:    "3min",
+		time.Minute * 5:    "5min",
+		time.Minute * 15:   "15min",
+		time.Minute * 30:   "30min",
+		time.Hour:          "1hour",
+		time.Hour * 2:      "2hour",
+		time.Hour * 4:      "4hour",
+		time.Hour * 6:      "6hour",
+		time.Hour * 12:     "12hour",
+		time.Hour * 24:     "1day",
+		time.Hour * 24 * 3: "3day",
+		time.Hour * 24 * 7: "1week",
+	}
+	klineType, ok := klineTypes[interval]
+	return klineType, ok
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair
+func (o *OKCoin) GetRecentTrades(p pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := o.GetTrades(o.formatOrderSymbol(p), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:      p,
+			TradeID:   strconv.FormatInt(x.TradeID, 10),
+			Side:      trades.TRADE_SIDE_BUY,
+			Price:     x.Price,
+			Amount:    x.Amount,
+			Timestamp: time.Unix(0, x.DateMS*int64(time.Millisecond)),
+		}
+		if x.Type == "sell" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(o.GetName(), p, result)
+	return result, nil
+}
@@ -0,0 +1,235 @@
+package orderbook
+
+import (
+	"errors"
+	"sort"
+)
+
+const (
+	ErrOrderbookNoBids        = "Orderbook has no bids."
+	ErrOrderbookNoAsks        = "Orderbook has no asks."
+	ErrOrderbookInvalidAmount = "Orderbook fill amount must be greater than zero."
+	ErrOrderbookInvalidRange  = "Orderbook depth percentage must be greater than zero."
+)
+
+//OrderbookFill : Result of walking the orderbook to fill an order. Amount is in
+//the base currency and Notional in the quote currency. Slippage is the
+//percentage the average price is worse than the mid price
+type OrderbookFill struct {
+	Amount       float64
+	Notional     float64
+	AveragePrice float64
+	WorstPrice   float64
+	Slippage     float64
+	FullyFilled  bool
+}
+
+//ByPrice : Sorts orderbook levels by ascending price
+type ByPrice []OrderbookItem
+
+func (o ByPrice) Len() int {
+	return len(o)
+}
+
+func (o ByPrice) Less(i, j int) bool {
+	return o[i].Price < o[j].Price
+}
+
+func (o ByPrice) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+}
+
+//sortedBids returns the bids by descending price. The levels are only copied
+//when an exchange returned them out of order
+func (o *OrderbookBase) sortedBids() []OrderbookItem {
+	if sort.IsSorted(sort.Reverse(ByPrice(o.Bids))) {
+		return o.Bids
+	}
+	bids := append([]OrderbookItem(nil), o.Bids...)
+	sort.Sort(sort.Reverse(ByPrice(bids)))
+	return bids
+}
+
+//sortedAsks returns the asks by ascending price
+func (o *OrderbookBase) sortedAsks() []OrderbookItem {
+	if sort.IsSorted(ByPrice(o.Asks)) {
+		return o.Asks
+	}
+	asks := append([]OrderbookItem(nil), o.Asks...)
+	sort.Sort(ByPrice(asks))
+	return asks
+}
+
+//GetBestBid returns the highest bid price
+func (o *OrderbookBase) GetBestBid() (float64, error) {
+	bids := o.sortedBids()
+	if len(bids) == 0 {
+		return 0, errors.New(ErrOrderbookNoBids)
+	}
+	return bids[0].Price, nil
+}
+
+//GetBestAsk returns the lowest ask price
+func (o *OrderbookBase) GetBestAsk() (float64, error) {
+	asks := o.sortedAsks()
+	if len(asks) == 0 {
+		return 0, errors.New(ErrOrderbookNoAsks)
+	}
+	return asks[0].Price, nil
+}
+
+//GetMidPrice returns the price halfway between the best bid and ask
+func (o *OrderbookBase) GetMidPrice() (float64, error) {
+	bid, err := o.GetBestBid()
+	if err != nil {
+		return 0, err
+	}
+
+	ask, err := o.GetBestAsk()
+	if err != nil {
+		return 0, err
+	}
+	return (bid + ask) / 2, nil
+}
+
+//GetSpread returns the difference between the best ask and bid
+func (o *OrderbookBase) GetSpread() (float64, error) {
+	bid, err := o.GetBestBid()
+	if err != nil {
+		return 0, err
+	}
+
+	ask, err := o.GetBestAsk()
+	if err != nil {
+		return 0, err
+	}
+	return ask - bid, nil
+}
+
+//GetSpreadPercentage returns the spread as a percentage of the mid price
+func (o *OrderbookBase) GetSpreadPercentage() (float64, error) {
+	spread, err := o.GetSpread()
+	if err != nil {
+		return 0, err
+	}
+
+	mid, err := o.GetMidPrice()
+	if err != nil {
+		return 0, err
+	}
+	return spread / mid * 100, nil
+}
+
+//GetDepthWithinPercentage returns the base currency amount bid and offered at
+//prices no further than percentage away from the mid price
+func (o *OrderbookBase) GetDepthWithinPercentage(percentage float64) (float64, float64, error) {
+	if percentage <= 0 {
+		return 0, 0, errors.New(ErrOrderbookInvalidRange)
+	}
+
+	mid, err := o.GetMidPrice()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bidAmount := float64(0)
+	lowest := mid * (1 - percentage/100)
+	for _, x := range o.sortedBids() {
+		if x.Price < lowest {
+			break
+		}
+		bidAmount += x.Amount
+	}
+
+	askAmount := float64(0)
+	highest := mid * (1 + percentage/100)
+	for _, x := range o.sortedAsks() {
+		if x.Price > highest {
+			break
+		}
+		askAmount += x.Amount
+	}
+	return bidAmount, askAmount, nil
+}
+
+//GetImbalance returns the difference between the bid and ask amounts within
+//percentage of the mid price, divided by their sum. It ranges from -1 when
+//only asks are present to 1 when only bids are
+func (o *OrderbookBase) GetImbalance(percentage float64) (float64, error) {
+	bidAmount, askAmount, err := o.GetDepthWithinPercentage(percentage)
+	if err != nil {
+		return 0, err
+	}
+
+	if bidAmount+askAmount == 0 {
+		return 0, nil
+	}
+	return (bidAmount - askAmount) / (bidAmount + askAmount), nil
+}
+
+//GetFillByAmount walks the asks for a buy, or the bids for a sell, until the
+//base currency amount is filled. When the orderbook is too thin the partial
+//fill is returned with FullyFilled unset
+func (o *OrderbookBase) GetFillByAmount(buy bool, amount float64) (OrderbookFill, error) {
+	return o.fill(buy, amount, false)
+}
+
+//GetFillByNotional walks the asks for a buy, or the bids for a sell, until the
+//quote currency notional is spent or received
+func (o *OrderbookBase) GetFillByNotional(buy bool, notional float64) (OrderbookFill, error) {
+	return o.fill(buy, notional, true)
+}
+
+func (o *OrderbookBase) fill(buy bool, target float64, notional bool) (OrderbookFill, error) {
+	result := OrderbookFill{}
+	if target <= 0 {
+		return result, errors.New(ErrOrderbookInvalidAmount)
+	}
+
+	mid, err := o.GetMidPrice()
+	if err != nil {
+		return result, err
+	}
+
+	levels := o.sortedBids()
+	if buy {
+		levels = o.sortedAsks()
+	}
+
+	remaining := target
+	for _, x := range levels {
+		available := x.Amount
+		if notional {
+			available = x.Amount * x.Price
+		}
+
+		result.WorstPrice = x.Price
+		if available >= remaining {
+			if notional {
+				result.Amount += remaining / x.Price
+				result.Notional += remaining
+			} else {
+				result.Amount += remaining
+				result.Notional += remaining * x.Price
+			}
+			result.FullyFilled = true
+			break
+		}
+
+		result.Amount += x.Amount
+		result.Notional += x.Amount * x.Price
+		remaining -= available
+	}
+
+	if result.Amount == 0 {
+		return result, nil
+	}
+
+	result.AveragePrice = result.Notional / result.Amount
+	if buy {
+		result.Slippage = (result.AveragePrice - mid) / mid * 100
+	} else {
+		result.Slippage = (mid - result.AveragePrice) / mid * 100
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package orderbook
+
+import (
+	"math"
+	"testing"
+)
+
+func testOrderbookBase() OrderbookBase {
+	return OrderbookBase{
+		Bids: []OrderbookItem{{Price: 99, Amount: 1}, {Price: 100, Amount: 2}, {Price: 95, Amount: 10}},
+		Asks: []OrderbookItem{{Price: 101, Amount: 1}, {Price: 102, Amount: 3}, {Price: 110, Amount: 10}},
+	}
+}
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestGetMidPriceAndSpread(t *testing.T) {
+	t.Parallel()
+
+	base := testOrderbookBase()
+	mid, err := base.GetMidPrice()
+	if err != nil || mid != 100.5 {
+		t.Error("Test Failed - orderbook GetMidPrice value is incorrect")
+	}
+
+	spread, err := base.GetSpread()
+	if err != nil || spread != 1 {
+		t.Error("Test Failed - orderbook GetSpread value is incorrect")
+	}
+
+	percentage, err := base.GetSpreadPercentage()
+	if err != nil || !floatEquals(percentage, 1/100.5*100) {
+		t.Error("Test Failed - orderbook GetSpreadPercentage value is incorrect")
+	}
+
+	empty := OrderbookBase{Bids: base.Bids}
+	if _, err = empty.GetMidPrice(); err == nil {
+		t.Error("Test Failed - orderbook GetMidPrice returned a price without asks")
+	}
+}
+
+func TestGetDepthWithinPercentage(t *testing.T) {
+	t.Parallel()
+
+	base := testOrderbookBase()
+	bids, asks, err := base.GetDepthWithinPercentage(2)
+	if err != nil || bids != 3 || asks != 4 {
+		t.Errorf("Test Failed - orderbook GetDepthWithinPercentage values are incorrect: %f %f", bids, asks)
+	}
+
+	imbalance, err := base.GetImbalance(2)
+	if err != nil || !floatEquals(imbalance, -1.0/7) {
+		t.Error("Test Failed - orderbook GetImbalance value is incorrect")
+	}
+
+	if _, _, err = base.GetDepthWithinPercentage(0); err == nil {
+		t.Error("Test Failed - orderbook GetDepthWithinPercentage accepted a zero percentage")
+	}
+}
+
+func TestGetFillByAmount(t *testing.T) {
+	t.Parallel()
+
+	base := testOrderbookBase()
+	fill, err := base.GetFillByAmount(true, 2)
+	if err != nil {
+		t.Fatalf("Test Failed - orderbook GetFillByAmount error: %s", err)
+	}
+
+	if !fill.FullyFilled || fill.Amount != 2 || fill.Notional != 203 || fill.AveragePrice != 101.5 || fill.WorstPrice != 102 {
+		t.Errorf("Test Failed - orderbook GetFillByAmount buy values are incorrect: %+v", fill)
+	}
+
+	if !floatEquals(fill.Slippage, 1/100.5*100) {
+		t.Error("Test Failed - orderbook GetFillByAmount buy slippage is incorrect")
+	}
+
+	fill, err = base.GetFillByAmount(false, 4)
+	if err != nil || !fill.FullyFilled || fill.Notional != 394 || fill.WorstPrice != 95 {
+		t.Errorf("Test Failed - orderbook GetFillByAmount sell values are incorrect: %+v", fill)
+	}
+
+	fill, err = base.GetFillByAmount(true, 100)
+	if err != nil || fill.FullyFilled || fill.Amount != 14 {
+		t.Error("Test Failed - orderbook GetFillByAmount filled more than the orderbook holds")
+	}
+
+	if _, err = base.GetFillByAmount(true, 0); err == nil {
+		t.Error("Test Failed - orderbook GetFillByAmount accepted a zero amount")
+	}
+}
+
+func TestGetFillByNotional(t *testing.T) {
+	t.Parallel()
+
+	base := testOrderbookBase()
+	fill, err := base.GetFillByNotional(true, 203)
+	if err != nil {
+		t.Fatalf("Test Failed - orderbook GetFillByNotional error: %s", err)
+	}
+
+	if !fill.FullyFilled || !floatEquals(fill.Amount, 2) || fill.WorstPrice != 102 {
+		t.Errorf("Test Failed - orderbook GetFillByNotional values are incorrect: %+v", fill)
+	}
+}
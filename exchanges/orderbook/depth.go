@@ -0,0 +1,348 @@
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+const (
+	DEPTH_ACTION_INSERT = "INSERT"
+	DEPTH_ACTION_UPDATE = "UPDATE"
+	DEPTH_ACTION_DELETE = "DELETE"
+	DEPTH_ACTION_CHANGE = "CHANGE"
+
+	ErrDepthSequenceGap   = "%s %s orderbook sequence gap, expected %d received %d."
+	ErrDepthInvalidAction = "Invalid orderbook depth action."
+	ErrDepthNotSynced     = "Orderbook depth is not synced."
+)
+
+const (
+	DEPTH_MAX_PENDING_UPDATES = 10000
+)
+
+var (
+	depths   = make(map[string]*Depth)
+	depthMtx sync.Mutex
+)
+
+//DepthUpdate : Incremental change to a single price level. Insert and update
+//set the level amount, change adds Amount to it and delete removes the level.
+//Levels whose amount falls to zero or below are removed
+type DepthUpdate struct {
+	Bid    bool
+	Action string
+	Price  float64
+	Amount float64
+}
+
+//Depth : Level 2 orderbook of an exchange pair maintained from incremental
+//updates. Bids are kept sorted by descending and asks by ascending price
+type Depth struct {
+	ExchangeName  string
+	Pair          pair.CurrencyPair
+	mtx           sync.Mutex
+	bids          []OrderbookItem
+	asks          []OrderbookItem
+	sequence      int64
+	snapshot      int64
+	timestamped   bool
+	synced        bool
+	fetching      bool
+	pending       []depthBatch
+	fetchSnapshot func() (OrderbookBase, error)
+}
+
+type depthBatch struct {
+	sequence int64
+	updates  []DepthUpdate
+}
+
+//NewDepth returns an unsynced depth for the exchange pair. fetchSnapshot is
+//called to resync the depth and should return a fresh REST orderbook, such
+//as the one returned by GetOrderbookEx once the stored orderbook is removed
+func NewDepth(exchangeName string, p pair.CurrencyPair, fetchSnapshot func() (OrderbookBase, error)) *Depth {
+	return &Depth{ExchangeName: exchangeName, Pair: p, fetchSnapshot: fetchSnapshot}
+}
+
+//GetDepth returns the depth of the exchange pair, creating it with
+//fetchSnapshot when it does not exist yet
+func GetDepth(exchangeName string, p pair.CurrencyPair, fetchSnapshot func() (OrderbookBase, error)) *Depth {
+	depthMtx.Lock()
+	defer depthMtx.Unlock()
+
+	key := exchangeName + p.GetFirstCurrency().Upper().String() + p.GetSecondCurrency().Upper().String()
+	depth, ok := depths[key]
+	if !ok {
+		depth = NewDepth(exchangeName, p, fetchSnapshot)
+		depths[key] = depth
+	}
+	return depth
+}
+
+//IsSynced returns whether the depth holds a snapshot and every update since
+func (d *Depth) IsSynced() bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.synced
+}
+
+//SetTimestamped marks the sequences of the depth as exchange timestamps. These
+//are shared by several batches and not contiguous, so only batches at or
+//before the snapshot are dropped and no gap is detected
+func (d *Depth) SetTimestamped(timestamped bool) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.timestamped = timestamped
+}
+
+//Desync drops the depth until a new snapshot is loaded, for when updates may
+//have been missed such as after a reconnect
+func (d *Depth) Desync() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.pending = nil
+	d.desync()
+}
+
+//GetSequence returns the sequence of the last applied update
+func (d *Depth) GetSequence() int64 {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.sequence
+}
+
+//GetOrderbook returns a copy of the current depth
+func (d *Depth) GetOrderbook() (OrderbookBase, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if !d.synced {
+		return OrderbookBase{}, errors.New(ErrDepthNotSynced)
+	}
+	return d.orderbook(), nil
+}
+
+//LoadSnapshot replaces every level with the snapshot. Updates received while
+//the depth was out of sync are replayed when they are newer than the snapshot
+func (d *Depth) LoadSnapshot(snapshot OrderbookBase) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.loadSnapshot(snapshot)
+}
+
+//Sync fetches a snapshot and loads it
+func (d *Depth) Sync() error {
+	snapshot, err := d.fetchSnapshot()
+	if err != nil {
+		return err
+	}
+	return d.LoadSnapshot(snapshot)
+}
+
+//Apply applies a batch of updates sharing a sequence number. Exchanges which
+//do not sequence their updates pass zero. Batches older than the depth, or at
+//or before the snapshot of a timestamped depth, are ignored, while a gap in
+//the sequence marks the depth out of sync and starts fetching a new snapshot.
+//Updates received before the depth is synced are queued and replayed over the
+//snapshot
+func (d *Depth) Apply(sequence int64, updates []DepthUpdate) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if !d.synced {
+		d.queue(sequence, updates)
+		return nil
+	}
+
+	if d.timestamped {
+		if sequence != 0 && sequence <= d.snapshot {
+			return nil
+		}
+	} else if sequence != 0 && d.sequence != 0 {
+		if sequence <= d.sequence {
+			return nil
+		}
+
+		if sequence != d.sequence+1 {
+			err := fmt.Errorf(ErrDepthSequenceGap, d.ExchangeName, d.Pair.Pair(), d.sequence+1, sequence)
+			d.desync()
+			d.queue(sequence, updates)
+			return err
+		}
+	}
+
+	err := d.apply(sequence, updates)
+	if err != nil {
+		return err
+	}
+	d.publish()
+	return nil
+}
+
+func (d *Depth) orderbook() OrderbookBase {
+	return OrderbookBase{
+		Pair:     d.Pair,
+		Bids:     append([]OrderbookItem(nil), d.bids...),
+		Asks:     append([]OrderbookItem(nil), d.asks...),
+		Sequence: d.sequence,
+	}
+}
+
+func (d *Depth) publish() {
+	ProcessOrderbook(d.ExchangeName, d.Pair, d.orderbook())
+}
+
+func (d *Depth) loadSnapshot(snapshot OrderbookBase) error {
+	d.bids = d.bids[:0]
+	d.asks = d.asks[:0]
+	for _, x := range snapshot.Bids {
+		d.bids = setLevel(d.bids, x.Price, x.Amount, true)
+	}
+	for _, x := range snapshot.Asks {
+		d.asks = setLevel(d.asks, x.Price, x.Amount, false)
+	}
+	d.sequence = snapshot.Sequence
+	d.snapshot = snapshot.Sequence
+	d.synced = true
+
+	pending := d.pending
+	d.pending = nil
+	for _, x := range pending {
+		if d.timestamped {
+			if x.sequence != 0 && x.sequence <= d.snapshot {
+				continue
+			}
+		} else if x.sequence != 0 && d.sequence != 0 {
+			if x.sequence <= d.sequence {
+				continue
+			}
+
+			if x.sequence != d.sequence+1 {
+				d.desync()
+				return fmt.Errorf(ErrDepthSequenceGap, d.ExchangeName, d.Pair.Pair(), d.sequence+1, x.sequence)
+			}
+		}
+
+		err := d.apply(x.sequence, x.updates)
+		if err != nil {
+			return err
+		}
+	}
+	d.publish()
+	return nil
+}
+
+func (d *Depth) apply(sequence int64, updates []DepthUpdate) error {
+	for _, x := range updates {
+		levels := d.asks
+		if x.Bid {
+			levels = d.bids
+		}
+
+		switch x.Action {
+		case DEPTH_ACTION_INSERT, DEPTH_ACTION_UPDATE:
+			levels = setLevel(levels, x.Price, x.Amount, x.Bid)
+		case DEPTH_ACTION_CHANGE:
+			levels = setLevel(levels, x.Price, getLevel(levels, x.Price, x.Bid)+x.Amount, x.Bid)
+		case DEPTH_ACTION_DELETE:
+			levels = setLevel(levels, x.Price, 0, x.Bid)
+		default:
+			return errors.New(ErrDepthInvalidAction)
+		}
+
+		if x.Bid {
+			d.bids = levels
+		} else {
+			d.asks = levels
+		}
+	}
+
+	if sequence != 0 {
+		d.sequence = sequence
+	}
+	return nil
+}
+
+func (d *Depth) queue(sequence int64, updates []DepthUpdate) {
+	if len(d.pending) >= DEPTH_MAX_PENDING_UPDATES {
+		d.pending = d.pending[1:]
+	}
+	d.pending = append(d.pending, depthBatch{sequence: sequence, updates: updates})
+	d.resync()
+}
+
+//desync drops the stale orderbook from the store, so readers and the snapshot
+//fetch do not see it, and starts a resync
+func (d *Depth) desync() {
+	d.synced = false
+	RemoveOrderbook(d.ExchangeName, d.Pair)
+	d.resync()
+}
+
+func (d *Depth) resync() {
+	if d.fetching || d.fetchSnapshot == nil {
+		return
+	}
+	d.fetching = true
+
+	go func() {
+		snapshot, err := d.fetchSnapshot()
+
+		d.mtx.Lock()
+		defer d.mtx.Unlock()
+		d.fetching = false
+
+		if err != nil {
+			log.Printf("%s %s unable to fetch orderbook snapshot. Error: %s\n", d.ExchangeName, d.Pair.Pair(), err)
+			return
+		}
+
+		err = d.loadSnapshot(snapshot)
+		if err != nil {
+			log.Println(err)
+		}
+	}()
+}
+
+func searchLevel(levels []OrderbookItem, price float64, bid bool) int {
+	return sort.Search(len(levels), func(i int) bool {
+		if bid {
+			return levels[i].Price <= price
+		}
+		return levels[i].Price >= price
+	})
+}
+
+func getLevel(levels []OrderbookItem, price float64, bid bool) float64 {
+	i := searchLevel(levels, price, bid)
+	if i < len(levels) && levels[i].Price == price {
+		return levels[i].Amount
+	}
+	return 0
+}
+
+func setLevel(levels []OrderbookItem, price, amount float64, bid bool) []OrderbookItem {
+	i := searchLevel(levels, price, bid)
+	found := i < len(levels) && levels[i].Price == price
+
+	switch {
+	case amount <= 0 && found:
+		return append(levels[:i], levels[i+1:]...)
+	case amount <= 0:
+		return levels
+	case found:
+		levels[i].Amount = amount
+		return levels
+	}
+
+	levels = append(levels, OrderbookItem{})
+	copy(levels[i+1:], levels[i:])
+	levels[i] = OrderbookItem{Price: price, Amount: amount}
+	return levels
+}
@@ -0,0 +1,155 @@
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+func TestDepthApply(t *testing.T) {
+	t.Parallel()
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	depth := NewDepth("DepthApplyTest", newPair, nil)
+	err := depth.LoadSnapshot(OrderbookBase{
+		Bids:     []OrderbookItem{{Price: 99, Amount: 1}, {Price: 100, Amount: 2}},
+		Asks:     []OrderbookItem{{Price: 102, Amount: 1}, {Price: 101, Amount: 3}},
+		Sequence: 10,
+	})
+	if err != nil {
+		t.Fatalf("Test Failed - depth LoadSnapshot error: %s", err)
+	}
+
+	err = depth.Apply(11, []DepthUpdate{
+		{Bid: true, Action: DEPTH_ACTION_INSERT, Price: 99.5, Amount: 4},
+		{Bid: true, Action: DEPTH_ACTION_DELETE, Price: 100},
+		{Bid: false, Action: DEPTH_ACTION_UPDATE, Price: 101, Amount: 5},
+		{Bid: false, Action: DEPTH_ACTION_CHANGE, Price: 102, Amount: -1},
+		{Bid: false, Action: DEPTH_ACTION_CHANGE, Price: 103, Amount: 2},
+	})
+	if err != nil {
+		t.Fatalf("Test Failed - depth Apply error: %s", err)
+	}
+
+	result, err := depth.GetOrderbook()
+	if err != nil {
+		t.Fatalf("Test Failed - depth GetOrderbook error: %s", err)
+	}
+
+	if len(result.Bids) != 2 || result.Bids[0].Price != 99.5 || result.Bids[0].Amount != 4 || result.Bids[1].Price != 99 {
+		t.Errorf("Test Failed - depth bids are incorrect: %v", result.Bids)
+	}
+
+	if len(result.Asks) != 2 || result.Asks[0].Price != 101 || result.Asks[0].Amount != 5 || result.Asks[1].Price != 103 {
+		t.Errorf("Test Failed - depth asks are incorrect: %v", result.Asks)
+	}
+
+	if result.Sequence != 11 {
+		t.Error("Test Failed - depth sequence is incorrect")
+	}
+
+	stored, err := GetOrderbook("DepthApplyTest", newPair)
+	if err != nil || len(stored.Bids) != 2 || stored.Sequence != 11 {
+		t.Error("Test Failed - depth was not published to the orderbook store")
+	}
+
+	err = depth.Apply(11, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_DELETE, Price: 99}})
+	if err != nil || depth.GetSequence() != 11 {
+		t.Error("Test Failed - depth applied a stale update")
+	}
+
+	err = depth.Apply(12, []DepthUpdate{{Bid: true, Action: "obtuse", Price: 99}})
+	if err == nil {
+		t.Error("Test Failed - depth accepted an invalid action")
+	}
+}
+
+func TestDepthSequenceGap(t *testing.T) {
+	t.Parallel()
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	fetched := make(chan struct{}, 1)
+	depth := NewDepth("DepthSequenceGapTest", newPair, func() (OrderbookBase, error) {
+		select {
+		case fetched <- struct{}{}:
+		default:
+		}
+		return OrderbookBase{Bids: []OrderbookItem{{Price: 100, Amount: 1}}, Sequence: 20}, nil
+	})
+
+	err := depth.Apply(19, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_UPDATE, Price: 100, Amount: 5}})
+	if err != nil {
+		t.Fatalf("Test Failed - depth Apply error: %s", err)
+	}
+
+	err = depth.Apply(21, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_UPDATE, Price: 100, Amount: 2}})
+	if err != nil {
+		t.Fatalf("Test Failed - depth Apply error: %s", err)
+	}
+
+	select {
+	case <-fetched:
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - depth did not fetch a snapshot")
+	}
+
+	for i := 0; i < 100 && !depth.IsSynced(); i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	result, err := depth.GetOrderbook()
+	if err != nil {
+		t.Fatalf("Test Failed - depth GetOrderbook error: %s", err)
+	}
+
+	if result.Sequence != 21 || result.Bids[0].Amount != 2 {
+		t.Error("Test Failed - depth did not replay the queued updates over the snapshot")
+	}
+
+	err = depth.Apply(23, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_UPDATE, Price: 100, Amount: 3}})
+	if err == nil {
+		t.Error("Test Failed - depth did not detect the sequence gap")
+	}
+
+	if _, err = GetOrderbook("DepthSequenceGapTest", newPair); err == nil {
+		t.Error("Test Failed - depth left the stale orderbook in the store")
+	}
+}
+
+func TestDepthTimestamped(t *testing.T) {
+	t.Parallel()
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	depth := NewDepth("DepthTimestampedTest", newPair, nil)
+	depth.SetTimestamped(true)
+
+	depth.Apply(1000, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_DELETE, Price: 100}})
+	depth.Apply(1001, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_UPDATE, Price: 99, Amount: 2}})
+	err := depth.LoadSnapshot(OrderbookBase{Bids: []OrderbookItem{{Price: 100, Amount: 1}}, Sequence: 1000})
+	if err != nil {
+		t.Fatalf("Test Failed - depth LoadSnapshot error: %s", err)
+	}
+
+	err = depth.Apply(1001, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_UPDATE, Price: 98, Amount: 3}})
+	if err != nil {
+		t.Fatalf("Test Failed - depth Apply error: %s", err)
+	}
+	err = depth.Apply(1000, []DepthUpdate{{Bid: true, Action: DEPTH_ACTION_DELETE, Price: 99}})
+	if err != nil {
+		t.Fatalf("Test Failed - depth Apply error: %s", err)
+	}
+
+	result, err := depth.GetOrderbook()
+	if err != nil || len(result.Bids) != 3 || result.Bids[0].Price != 100 || result.Bids[1].Amount != 2 {
+		t.Errorf("Test Failed - timestamped depth incorrect: %+v %v", result, err)
+	}
+
+	depth.Desync()
+	if depth.IsSynced() {
+		t.Error("Test Failed - depth synced after Desync")
+	}
+	if _, err = GetOrderbook("DepthTimestampedTest", newPair); err == nil {
+		t.Error("Test Failed - Desync left the stale orderbook in the store")
+	}
+}
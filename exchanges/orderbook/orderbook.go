@@ -2,6 +2,7 @@ package orderbook
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/champii/gocryptotrader/currency/pair"
@@ -12,7 +13,10 @@ var (
 	ErrPrimaryCurrencyNotFound      = "Error primary currency for orderbook not found."
 	ErrSecondaryCurrencyNotFound    = "Error secondary currency for orderbook not found."
 
-	Orderbooks []Orderbook
+	orderbooks   = make(map[string]*Orderbook)
+	orderbookMtx sync.RWMutex
+	clocks       = make(map[string]func() time.Time)
+	clockMtx     sync.RWMutex
 )
 
 type OrderbookItem struct {
@@ -25,6 +29,7 @@ type OrderbookBase struct {
 	CurrencyPair string            `json:"CurrencyPair"`
 	Bids         []OrderbookItem   `json:"bids"`
 	Asks         []OrderbookItem   `json:"asks"`
+	Sequence     int64             `json:"sequence"`
 	LastUpdated  time.Time         `json:"last_updated"`
 }
 
@@ -59,89 +64,162 @@ func (o *OrderbookBase) Update(Bids, Asks []OrderbookItem) {
 	o.LastUpdated = time.Now()
 }
 
+func (o *OrderbookBase) copy() OrderbookBase {
+	result := *o
+	result.Bids = append([]OrderbookItem(nil), o.Bids...)
+	result.Asks = append([]OrderbookItem(nil), o.Asks...)
+	return result
+}
+
+func (o *Orderbook) copy() Orderbook {
+	result := Orderbook{
+		ExchangeName: o.ExchangeName,
+		Orderbook:    make(map[pair.CurrencyItem]map[pair.CurrencyItem]OrderbookBase),
+	}
+	for x, y := range o.Orderbook {
+		result.Orderbook[x] = make(map[pair.CurrencyItem]OrderbookBase)
+		for z, base := range y {
+			result.Orderbook[x][z] = base.copy()
+		}
+	}
+	return result
+}
+
 func GetOrderbook(exchange string, p pair.CurrencyPair) (OrderbookBase, error) {
-	orderbook, err := GetOrderbookByExchange(exchange)
-	if err != nil {
-		return OrderbookBase{}, err
+	orderbookMtx.RLock()
+	defer orderbookMtx.RUnlock()
+
+	orderbook, ok := orderbooks[exchange]
+	if !ok {
+		return OrderbookBase{}, errors.New(ErrOrderbookForExchangeNotFound)
 	}
 
-	if !FirstCurrencyExists(exchange, p.GetFirstCurrency()) {
+	first, ok := orderbook.Orderbook[p.GetFirstCurrency()]
+	if !ok {
 		return OrderbookBase{}, errors.New(ErrPrimaryCurrencyNotFound)
 	}
 
-	if !SecondCurrencyExists(exchange, p) {
+	base, ok := first[p.GetSecondCurrency()]
+	if !ok {
 		return OrderbookBase{}, errors.New(ErrSecondaryCurrencyNotFound)
 	}
-
-	return orderbook.Orderbook[p.GetFirstCurrency()][p.GetSecondCurrency()], nil
+	return base.copy(), nil
 }
 
+//GetOrderbookByExchange returns a snapshot of every stored orderbook of the
+//exchange. Later updates are not reflected in the returned orderbook
 func GetOrderbookByExchange(exchange string) (*Orderbook, error) {
-	for _, y := range Orderbooks {
-		if y.ExchangeName == exchange {
-			return &y, nil
-		}
+	orderbookMtx.RLock()
+	defer orderbookMtx.RUnlock()
+
+	orderbook, ok := orderbooks[exchange]
+	if !ok {
+		return nil, errors.New(ErrOrderbookForExchangeNotFound)
 	}
-	return nil, errors.New(ErrOrderbookForExchangeNotFound)
+
+	result := orderbook.copy()
+	return &result, nil
 }
 
 func FirstCurrencyExists(exchange string, currency pair.CurrencyItem) bool {
-	for _, y := range Orderbooks {
-		if y.ExchangeName == exchange {
-			if _, ok := y.Orderbook[currency]; ok {
-				return true
-			}
-		}
+	orderbookMtx.RLock()
+	defer orderbookMtx.RUnlock()
+
+	if orderbook, ok := orderbooks[exchange]; ok {
+		_, ok = orderbook.Orderbook[currency]
+		return ok
 	}
 	return false
 }
 
 func SecondCurrencyExists(exchange string, p pair.CurrencyPair) bool {
-	for _, y := range Orderbooks {
-		if y.ExchangeName == exchange {
-			if _, ok := y.Orderbook[p.GetFirstCurrency()]; ok {
-				if _, ok := y.Orderbook[p.GetFirstCurrency()][p.GetSecondCurrency()]; ok {
-					return true
-				}
-			}
+	orderbookMtx.RLock()
+	defer orderbookMtx.RUnlock()
+
+	if orderbook, ok := orderbooks[exchange]; ok {
+		if first, ok := orderbook.Orderbook[p.GetFirstCurrency()]; ok {
+			_, ok = first[p.GetSecondCurrency()]
+			return ok
 		}
 	}
 	return false
 }
 
+//CreateNewOrderbook stores a new orderbook for the exchange holding only the
+//supplied pair, replacing any orderbook already stored for the exchange
 func CreateNewOrderbook(exchangeName string, p pair.CurrencyPair, orderbookNew OrderbookBase) Orderbook {
-	orderbook := Orderbook{}
+	orderbookMtx.Lock()
+	defer orderbookMtx.Unlock()
+
+	orderbook := &Orderbook{}
 	orderbook.ExchangeName = exchangeName
 	orderbook.Orderbook = make(map[pair.CurrencyItem]map[pair.CurrencyItem]OrderbookBase)
 	sMap := make(map[pair.CurrencyItem]OrderbookBase)
-	sMap[p.GetSecondCurrency()] = orderbookNew
+	sMap[p.GetSecondCurrency()] = orderbookNew.copy()
 	orderbook.Orderbook[p.GetFirstCurrency()] = sMap
-	Orderbooks = append(Orderbooks, orderbook)
-	return orderbook
+	orderbooks[exchangeName] = orderbook
+	return orderbook.copy()
+}
+
+//SetClock sets the clock stamping the exchange orderbooks processed without an
+//update time. A nil clock restores time.Now
+func SetClock(exchangeName string, clock func() time.Time) {
+	clockMtx.Lock()
+	defer clockMtx.Unlock()
+
+	if clock == nil {
+		delete(clocks, exchangeName)
+		return
+	}
+	clocks[exchangeName] = clock
 }
 
+func now(exchangeName string) time.Time {
+	clockMtx.RLock()
+	clock, ok := clocks[exchangeName]
+	clockMtx.RUnlock()
+
+	if !ok {
+		return time.Now()
+	}
+	return clock()
+}
+
+//ProcessOrderbook stores the orderbook of an exchange pair, replacing the
+//previously stored one
 func ProcessOrderbook(exchangeName string, p pair.CurrencyPair, orderbookNew OrderbookBase) {
 	orderbookNew.CurrencyPair = p.Pair().String()
-	if len(Orderbooks) == 0 {
-		CreateNewOrderbook(exchangeName, p, orderbookNew)
-		return
-	} else {
-		orderbook, err := GetOrderbookByExchange(exchangeName)
-		if err != nil {
-			CreateNewOrderbook(exchangeName, p, orderbookNew)
-			return
+	if orderbookNew.LastUpdated.IsZero() {
+		orderbookNew.LastUpdated = now(exchangeName)
+	}
+
+	orderbookMtx.Lock()
+	defer orderbookMtx.Unlock()
+
+	orderbook, ok := orderbooks[exchangeName]
+	if !ok {
+		orderbook = &Orderbook{
+			ExchangeName: exchangeName,
+			Orderbook:    make(map[pair.CurrencyItem]map[pair.CurrencyItem]OrderbookBase),
 		}
+		orderbooks[exchangeName] = orderbook
+	}
+
+	if _, ok := orderbook.Orderbook[p.GetFirstCurrency()]; !ok {
+		orderbook.Orderbook[p.GetFirstCurrency()] = make(map[pair.CurrencyItem]OrderbookBase)
+	}
+	orderbook.Orderbook[p.GetFirstCurrency()][p.GetSecondCurrency()] = orderbookNew.copy()
+}
+
+//RemoveOrderbook removes the stored orderbook of an exchange pair, so it is
+//fetched again on the next GetOrderbookEx call
+func RemoveOrderbook(exchangeName string, p pair.CurrencyPair) {
+	orderbookMtx.Lock()
+	defer orderbookMtx.Unlock()
 
-		if FirstCurrencyExists(exchangeName, p.GetFirstCurrency()) {
-			if !SecondCurrencyExists(exchangeName, p) {
-				second := orderbook.Orderbook[p.GetFirstCurrency()]
-				second[p.GetSecondCurrency()] = orderbookNew
-				orderbook.Orderbook[p.GetFirstCurrency()] = second
-				return
-			}
+	if orderbook, ok := orderbooks[exchangeName]; ok {
+		if first, ok := orderbook.Orderbook[p.GetFirstCurrency()]; ok {
+			delete(first, p.GetSecondCurrency())
 		}
-		sMap := make(map[pair.CurrencyItem]OrderbookBase)
-		sMap[p.GetSecondCurrency()] = orderbookNew
-		orderbook.Orderbook[p.GetFirstCurrency()] = sMap
 	}
 }
@@ -0,0 +1,101 @@
+package orderbook
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+func TestCalculateTotals(t *testing.T) {
+	t.Parallel()
+
+	base := OrderbookBase{
+		Bids: []OrderbookItem{{Price: 100, Amount: 2}, {Price: 99, Amount: 1}},
+		Asks: []OrderbookItem{{Price: 101, Amount: 1}, {Price: 102, Amount: 3}},
+	}
+
+	amount, total := base.CalculateTotalBids()
+	if amount != 3 || total != 299 {
+		t.Error("Test Failed - orderbook CalculateTotalBids values are incorrect")
+	}
+
+	amount, total = base.CalculateTotalAsks()
+	if amount != 4 || total != 407 {
+		t.Error("Test Failed - orderbook CalculateTotalAsks values are incorrect")
+	}
+}
+
+func TestProcessOrderbook(t *testing.T) {
+	t.Parallel()
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	base := OrderbookBase{
+		Bids: []OrderbookItem{{Price: 100, Amount: 2}},
+		Asks: []OrderbookItem{{Price: 101, Amount: 1}},
+	}
+	ProcessOrderbook("ProcessOrderbookTest", newPair, base)
+
+	result, err := GetOrderbook("ProcessOrderbookTest", newPair)
+	if err != nil {
+		t.Fatalf("Test Failed - orderbook GetOrderbook error: %s", err)
+	}
+
+	if result.CurrencyPair != "BTCUSD" || result.LastUpdated.IsZero() {
+		t.Error("Test Failed - orderbook ProcessOrderbook did not set the pair and update time")
+	}
+
+	result.Bids[0].Amount = 10
+	result, _ = GetOrderbook("ProcessOrderbookTest", newPair)
+	if result.Bids[0].Amount != 2 {
+		t.Error("Test Failed - orderbook GetOrderbook returned the stored levels")
+	}
+
+	if !FirstCurrencyExists("ProcessOrderbookTest", "BTC") || !SecondCurrencyExists("ProcessOrderbookTest", newPair) {
+		t.Error("Test Failed - orderbook currency does not exist")
+	}
+
+	RemoveOrderbook("ProcessOrderbookTest", newPair)
+	_, err = GetOrderbook("ProcessOrderbookTest", newPair)
+	if err == nil {
+		t.Error("Test Failed - orderbook RemoveOrderbook did not remove the orderbook")
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	recorded := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetClock("clocktest", func() time.Time { return recorded })
+	defer SetClock("clocktest", nil)
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	ProcessOrderbook("clocktest", newPair, OrderbookBase{Bids: []OrderbookItem{{Price: 1000, Amount: 1}}})
+	book, err := GetOrderbook("clocktest", newPair)
+	if err != nil || !book.LastUpdated.Equal(recorded) {
+		t.Errorf("Test Failed - SetClock() expected %s, received %s", recorded, book.LastUpdated)
+	}
+}
+
+func TestProcessOrderbookConcurrent(t *testing.T) {
+	t.Parallel()
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			ProcessOrderbook("ConcurrentOrderbookTest", newPair, OrderbookBase{Bids: []OrderbookItem{{Price: float64(i), Amount: 1}}})
+		}(i)
+		go func() {
+			defer wg.Done()
+			GetOrderbook("ConcurrentOrderbookTest", newPair)
+			GetOrderbookByExchange("ConcurrentOrderbookTest")
+		}()
+	}
+	wg.Wait()
+
+	if _, err := GetOrderbook("ConcurrentOrderbookTest", newPair); err != nil {
+		t.Errorf("Test Failed - orderbook GetOrderbook error: %s", err)
+	}
+}
@@ -0,0 +1,206 @@
+package exchange
+
+import (
+	"errors"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+const (
+	ErrFunctionNotSupported  = "Function not supported by exchange."
+	ErrOrderTypeNotSupported = "Order type not supported by exchange."
+	ErrOrderNotFound         = "Order not found."
+	ErrInvalidOrderSide      = "Invalid order side."
+	ErrInvalidOrderType      = "Invalid order type."
+	ErrInvalidOrderAmount    = "Order amount must be greater than zero."
+	ErrInvalidOrderPrice     = "Limit order price must be greater than zero."
+)
+
+//OrderSide : Direction of an order
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
+//OrderType : Execution type of an order
+type OrderType string
+
+const (
+	OrderTypeLimit  OrderType = "LIMIT"
+	OrderTypeMarket OrderType = "MARKET"
+)
+
+//OrderStatus : Normalised lifecycle state of an order
+type OrderStatus string
+
+const (
+	OrderStatusActive          OrderStatus = "ACTIVE"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusCancelled       OrderStatus = "CANCELLED"
+	OrderStatusRejected        OrderStatus = "REJECTED"
+	OrderStatusUnknown         OrderStatus = "UNKNOWN"
+)
+
+//OrderRequest : Generic order parameters accepted by every exchange wrapper
+type OrderRequest struct {
+	Pair     pair.CurrencyPair
+	Side     OrderSide
+	Type     OrderType
+	Amount   float64
+	Price    float64
+	ClientID string
+}
+
+//OrderResult : Generic response returned after an order is submitted or modified
+type OrderResult struct {
+	Exchange     string
+	OrderID      string
+	ClientID     string
+	FilledAmount float64
+}
+
+//OrderDetail : Normalised view of a single order on an exchange
+type OrderDetail struct {
+	Exchange     string
+	OrderID      string
+	ClientID     string
+	Pair         pair.CurrencyPair
+	Side         OrderSide
+	Type         OrderType
+	Status       OrderStatus
+	Price        float64
+	Amount       float64
+	FilledAmount float64
+	AveragePrice float64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+//Fill : Normalised record of a single execution against one of the account's orders
+type Fill struct {
+	Exchange    string
+	TradeID     string
+	OrderID     string
+	Pair        pair.CurrencyPair
+	Side        OrderSide
+	Price       float64
+	Amount      float64
+	Fee         float64
+	FeeCurrency string
+	Timestamp   time.Time
+}
+
+//OrderFilter : Restricts an order or fill listing to a set of pairs and a time range
+type OrderFilter struct {
+	Pairs     []pair.CurrencyPair
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+//IsBuy returns true if the order is a buy order
+func (o *OrderRequest) IsBuy() bool {
+	return o.Side == OrderSideBuy
+}
+
+//IsMarket returns true if the order is a market order
+func (o *OrderRequest) IsMarket() bool {
+	return o.Type == OrderTypeMarket
+}
+
+//Validate checks the order request for missing or inconsistent fields
+func (o *OrderRequest) Validate() error {
+	if o.Side != OrderSideBuy && o.Side != OrderSideSell {
+		return errors.New(ErrInvalidOrderSide)
+	}
+
+	if o.Type != OrderTypeLimit && o.Type != OrderTypeMarket {
+		return errors.New(ErrInvalidOrderType)
+	}
+
+	if o.Amount <= 0 {
+		return errors.New(ErrInvalidOrderAmount)
+	}
+
+	if o.Type == OrderTypeLimit && o.Price <= 0 {
+		return errors.New(ErrInvalidOrderPrice)
+	}
+	return nil
+}
+
+//GetOrderStatus derives an order status from its original and filled amounts
+func GetOrderStatus(amount, filled float64, active, cancelled bool) OrderStatus {
+	switch {
+	case cancelled:
+		return OrderStatusCancelled
+	case active && filled > 0:
+		return OrderStatusPartiallyFilled
+	case active:
+		return OrderStatusActive
+	case amount > 0 && filled >= amount:
+		return OrderStatusFilled
+	}
+	return OrderStatusUnknown
+}
+
+//Match returns true if the order belongs to one of the filter pairs and was
+//created within the filter time range. Empty fields match everything
+func (f *OrderFilter) Match(order OrderDetail) bool {
+	return f.match(order.Pair, order.CreatedAt)
+}
+
+//MatchFill returns true if the fill belongs to one of the filter pairs and
+//was executed within the filter time range
+func (f *OrderFilter) MatchFill(fill Fill) bool {
+	return f.match(fill.Pair, fill.Timestamp)
+}
+
+func (f *OrderFilter) match(p pair.CurrencyPair, timestamp time.Time) bool {
+	if len(f.Pairs) > 0 {
+		found := false
+		for _, x := range f.Pairs {
+			if x.GetFirstCurrency().Upper() == p.GetFirstCurrency().Upper() &&
+				x.GetSecondCurrency().Upper() == p.GetSecondCurrency().Upper() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !f.StartTime.IsZero() && timestamp.Before(f.StartTime) {
+		return false
+	}
+
+	if !f.EndTime.IsZero() && timestamp.After(f.EndTime) {
+		return false
+	}
+	return true
+}
+
+//FilterOrders returns the orders which match the supplied filter
+func FilterOrders(orders []OrderDetail, filter OrderFilter) []OrderDetail {
+	result := []OrderDetail{}
+	for _, x := range orders {
+		if filter.Match(x) {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+//FilterFills returns the fills which match the supplied filter
+func FilterFills(fills []Fill, filter OrderFilter) []Fill {
+	result := []Fill{}
+	for _, x := range fills {
+		if filter.MatchFill(x) {
+			result = append(result, x)
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+func TestOrderRequestValidate(t *testing.T) {
+	order := OrderRequest{
+		Pair:   pair.NewCurrencyPair("BTC", "USD"),
+		Side:   OrderSideBuy,
+		Type:   OrderTypeLimit,
+		Amount: 1,
+		Price:  1000,
+	}
+
+	if err := order.Validate(); err != nil {
+		t.Errorf("Test Failed - OrderRequest Validate() error: %s", err)
+	}
+
+	order.Price = 0
+	if err := order.Validate(); err == nil {
+		t.Error("Test Failed - OrderRequest Validate() accepted a limit order without a price")
+	}
+
+	order.Type = OrderTypeMarket
+	if err := order.Validate(); err != nil {
+		t.Errorf("Test Failed - OrderRequest Validate() error: %s", err)
+	}
+
+	order.Amount = 0
+	if err := order.Validate(); err == nil {
+		t.Error("Test Failed - OrderRequest Validate() accepted a zero amount")
+	}
+
+	order.Amount = 1
+	order.Side = "HOLD"
+	if err := order.Validate(); err == nil {
+		t.Error("Test Failed - OrderRequest Validate() accepted an invalid side")
+	}
+}
+
+func TestGetOrderStatus(t *testing.T) {
+	if GetOrderStatus(1, 0, true, false) != OrderStatusActive {
+		t.Error("Test Failed - GetOrderStatus() expected active")
+	}
+
+	if GetOrderStatus(1, 0.5, true, false) != OrderStatusPartiallyFilled {
+		t.Error("Test Failed - GetOrderStatus() expected partially filled")
+	}
+
+	if GetOrderStatus(1, 1, false, false) != OrderStatusFilled {
+		t.Error("Test Failed - GetOrderStatus() expected filled")
+	}
+
+	if GetOrderStatus(1, 0.5, false, true) != OrderStatusCancelled {
+		t.Error("Test Failed - GetOrderStatus() expected cancelled")
+	}
+}
+
+func TestFilterOrders(t *testing.T) {
+	now := time.Now()
+	orders := []OrderDetail{
+		{OrderID: "1", Pair: pair.NewCurrencyPair("BTC", "USD"), CreatedAt: now.Add(-time.Hour)},
+		{OrderID: "2", Pair: pair.NewCurrencyPair("ltc", "usd"), CreatedAt: now},
+		{OrderID: "3", Pair: pair.NewCurrencyPair("BTC", "USD"), CreatedAt: now.Add(time.Hour)},
+	}
+
+	result := FilterOrders(orders, OrderFilter{})
+	if len(result) != 3 {
+		t.Errorf("Test Failed - FilterOrders() expected 3 orders, got %d", len(result))
+	}
+
+	result = FilterOrders(orders, OrderFilter{Pairs: []pair.CurrencyPair{pair.NewCurrencyPair("LTC", "USD")}})
+	if len(result) != 1 || result[0].OrderID != "2" {
+		t.Error("Test Failed - FilterOrders() pair filter mismatch")
+	}
+
+	result = FilterOrders(orders, OrderFilter{StartTime: now.Add(-time.Minute), EndTime: now.Add(time.Minute)})
+	if len(result) != 1 || result[0].OrderID != "2" {
+		t.Error("Test Failed - FilterOrders() time range filter mismatch")
+	}
+}
+
+func TestFilterFills(t *testing.T) {
+	now := time.Now()
+	fills := []Fill{
+		{TradeID: "1", Pair: pair.NewCurrencyPair("BTC", "USD"), Timestamp: now.Add(-time.Hour)},
+		{TradeID: "2", Pair: pair.NewCurrencyPair("BTC", "USD"), Timestamp: now},
+		{TradeID: "3", Pair: pair.NewCurrencyPair("LTC", "BTC"), Timestamp: now},
+	}
+
+	result := FilterFills(fills, OrderFilter{
+		Pairs:     []pair.CurrencyPair{pair.NewCurrencyPair("btc", "usd")},
+		StartTime: now.Add(-time.Minute),
+	})
+	if len(result) != 1 || result[0].TradeID != "2" {
+		t.Error("Test Failed - FilterFills() filter mismatch")
+	}
+}
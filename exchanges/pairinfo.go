@@ -0,0 +1,179 @@
+package exchange
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+const (
+	ErrPairInfoNotFound    = "Pair metadata not found."
+	ErrPairNotTradable     = "Pair is not currently tradable."
+	ErrOrderAmountTooSmall = "Order amount is below the pair minimum."
+	ErrOrderAmountTooLarge = "Order amount is above the pair maximum."
+	ErrOrderAmountStep     = "Order amount is not a multiple of the pair amount step."
+	ErrOrderPricePrecision = "Order price exceeds the pair price precision."
+)
+
+const (
+	pairInfoFloatTolerance = 1e-12
+)
+
+//PairStatus : Trading status of a pair on an exchange
+type PairStatus string
+
+const (
+	PairStatusTrading PairStatus = "TRADING"
+	PairStatusHalted  PairStatus = "HALTED"
+)
+
+//PairInfo : Order constraints for a single pair on an exchange. Zero values
+//mean the exchange does not publish the constraint
+type PairInfo struct {
+	Pair                   pair.CurrencyPair
+	MinAmount              float64
+	MaxAmount              float64
+	AmountStep             float64
+	PriceTick              float64
+	QuotePrecision         int
+	PriceSignificantDigits int
+	Status                 PairStatus
+}
+
+var pairInfoMtx sync.RWMutex
+
+func getPairInfoKey(p pair.CurrencyPair) string {
+	return p.GetFirstCurrency().Upper().String() + p.GetSecondCurrency().Upper().String()
+}
+
+//SetPairInfo replaces the pair metadata table with the supplied entries
+func (e *ExchangeBase) SetPairInfo(info []PairInfo) {
+	pairs := make(map[string]PairInfo)
+	for _, x := range info {
+		pairs[getPairInfoKey(x.Pair)] = x
+	}
+
+	pairInfoMtx.Lock()
+	e.pairInfo = pairs
+	pairInfoMtx.Unlock()
+}
+
+//GetPairInfo returns the metadata stored for a pair
+func (e *ExchangeBase) GetPairInfo(p pair.CurrencyPair) (PairInfo, error) {
+	pairInfoMtx.RLock()
+	defer pairInfoMtx.RUnlock()
+
+	info, ok := e.pairInfo[getPairInfoKey(p)]
+	if !ok {
+		return PairInfo{}, errors.New(ErrPairInfoNotFound)
+	}
+	return info, nil
+}
+
+//RoundPrice rounds a price to the nearest value accepted for the pair. The
+//price is returned unchanged if no metadata is stored for the pair
+func (e *ExchangeBase) RoundPrice(p pair.CurrencyPair, price float64) float64 {
+	info, err := e.GetPairInfo(p)
+	if err != nil {
+		return price
+	}
+	return info.RoundPrice(price)
+}
+
+//RoundAmount rounds an amount down to the amount step of the pair, so the
+//rounded amount never exceeds the requested one
+func (e *ExchangeBase) RoundAmount(p pair.CurrencyPair, amount float64) float64 {
+	info, err := e.GetPairInfo(p)
+	if err != nil {
+		return amount
+	}
+	return info.RoundAmount(amount)
+}
+
+//ValidateOrder checks the order fields and, when metadata is stored for the
+//pair, that the order satisfies the pair constraints
+func (e *ExchangeBase) ValidateOrder(order OrderRequest) error {
+	err := order.Validate()
+	if err != nil {
+		return err
+	}
+
+	info, err := e.GetPairInfo(order.Pair)
+	if err != nil {
+		return nil
+	}
+	return info.ValidateOrder(order)
+}
+
+//RoundPrice rounds a price to the significant digits, tick size or precision
+//of the pair, in that order
+func (p *PairInfo) RoundPrice(price float64) float64 {
+	if p.PriceSignificantDigits > 0 && price != 0 {
+		price, _ = strconv.ParseFloat(strconv.FormatFloat(price, 'g', p.PriceSignificantDigits, 64), 64)
+	}
+
+	if p.PriceTick > 0 {
+		return roundToStep(p.PriceTick, math.Floor(price/p.PriceTick+0.5))
+	}
+
+	if p.QuotePrecision > 0 {
+		price, _ = strconv.ParseFloat(strconv.FormatFloat(price, 'f', p.QuotePrecision, 64), 64)
+	}
+	return price
+}
+
+//RoundAmount rounds an amount down to the amount step of the pair
+func (p *PairInfo) RoundAmount(amount float64) float64 {
+	if p.AmountStep <= 0 {
+		return amount
+	}
+	return roundToStep(p.AmountStep, math.Floor(amount/p.AmountStep+pairInfoFloatTolerance))
+}
+
+//ValidateOrder checks that the order satisfies the pair constraints
+func (p *PairInfo) ValidateOrder(order OrderRequest) error {
+	if p.Status == PairStatusHalted {
+		return errors.New(ErrPairNotTradable)
+	}
+
+	if p.MinAmount > 0 && order.Amount < p.MinAmount {
+		return errors.New(ErrOrderAmountTooSmall)
+	}
+
+	if p.MaxAmount > 0 && order.Amount > p.MaxAmount {
+		return errors.New(ErrOrderAmountTooLarge)
+	}
+
+	if !floatEquals(p.RoundAmount(order.Amount), order.Amount) {
+		return errors.New(ErrOrderAmountStep)
+	}
+
+	if order.Type == OrderTypeLimit && !floatEquals(p.RoundPrice(order.Price), order.Price) {
+		return errors.New(ErrOrderPricePrecision)
+	}
+	return nil
+}
+
+func roundToStep(step, steps float64) float64 {
+	decimals := 0
+	stepString := strconv.FormatFloat(step, 'f', -1, 64)
+	if index := strings.Index(stepString, "."); index != -1 {
+		decimals = len(stepString) - index - 1
+	}
+
+	result, _ := strconv.ParseFloat(strconv.FormatFloat(steps*step, 'f', decimals, 64), 64)
+	return result
+}
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) <= pairInfoFloatTolerance*math.Max(1, math.Abs(b))
+}
+
+//GetPrecisionStep returns the smallest increment for a number of decimals
+func GetPrecisionStep(decimals int) float64 {
+	return math.Pow10(-decimals)
+}
@@ -0,0 +1,119 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+func TestSetPairInfo(t *testing.T) {
+	var e ExchangeBase
+	e.SetPairInfo([]PairInfo{{Pair: pair.NewCurrencyPair("btc", "usd"), MinAmount: 0.01}})
+
+	info, err := e.GetPairInfo(pair.NewCurrencyPairDelimiter("BTC-USD", "-"))
+	if err != nil {
+		t.Fatalf("Test Failed - GetPairInfo() error: %s", err)
+	}
+
+	if info.MinAmount != 0.01 {
+		t.Error("Test Failed - GetPairInfo() returned the wrong entry")
+	}
+
+	_, err = e.GetPairInfo(pair.NewCurrencyPair("LTC", "USD"))
+	if err == nil {
+		t.Error("Test Failed - GetPairInfo() returned an entry for an unknown pair")
+	}
+}
+
+func TestRoundPrice(t *testing.T) {
+	info := PairInfo{PriceTick: 0.01}
+	if result := info.RoundPrice(1234.5678); result != 1234.57 {
+		t.Errorf("Test Failed - RoundPrice() tick expected 1234.57, got %f", result)
+	}
+
+	info = PairInfo{PriceTick: 0.5}
+	if result := info.RoundPrice(100.74); result != 100.5 {
+		t.Errorf("Test Failed - RoundPrice() tick expected 100.5, got %f", result)
+	}
+
+	info = PairInfo{PriceSignificantDigits: 5}
+	if result := info.RoundPrice(2567.891); result != 2567.9 {
+		t.Errorf("Test Failed - RoundPrice() significant digits expected 2567.9, got %f", result)
+	}
+
+	info = PairInfo{QuotePrecision: 3}
+	if result := info.RoundPrice(0.12345); result != 0.123 {
+		t.Errorf("Test Failed - RoundPrice() precision expected 0.123, got %f", result)
+	}
+
+	var e ExchangeBase
+	if result := e.RoundPrice(pair.NewCurrencyPair("BTC", "USD"), 1.23456); result != 1.23456 {
+		t.Error("Test Failed - RoundPrice() changed a price without pair metadata")
+	}
+}
+
+func TestRoundAmount(t *testing.T) {
+	info := PairInfo{AmountStep: 0.001}
+	if result := info.RoundAmount(1.23456); result != 1.234 {
+		t.Errorf("Test Failed - RoundAmount() expected 1.234, got %f", result)
+	}
+
+	info = PairInfo{AmountStep: 0.1}
+	if result := info.RoundAmount(0.3); result != 0.3 {
+		t.Errorf("Test Failed - RoundAmount() expected 0.3, got %f", result)
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	var e ExchangeBase
+	order := OrderRequest{
+		Pair:   pair.NewCurrencyPair("BTC", "USD"),
+		Side:   OrderSideBuy,
+		Type:   OrderTypeLimit,
+		Amount: 0.5,
+		Price:  1000.01,
+	}
+
+	if err := e.ValidateOrder(order); err != nil {
+		t.Errorf("Test Failed - ValidateOrder() without pair metadata error: %s", err)
+	}
+
+	e.SetPairInfo([]PairInfo{{
+		Pair:       pair.NewCurrencyPair("BTC", "USD"),
+		MinAmount:  0.01,
+		MaxAmount:  100,
+		AmountStep: 0.01,
+		PriceTick:  0.01,
+		Status:     PairStatusTrading,
+	}})
+
+	if err := e.ValidateOrder(order); err != nil {
+		t.Errorf("Test Failed - ValidateOrder() error: %s", err)
+	}
+
+	tests := []struct {
+		amount, price float64
+		err           string
+	}{
+		{0.001, 1000, ErrOrderAmountTooSmall},
+		{1000, 1000, ErrOrderAmountTooLarge},
+		{0.505, 1000, ErrOrderAmountStep},
+		{0.5, 1000.005, ErrOrderPricePrecision},
+	}
+
+	for _, x := range tests {
+		order.Amount = x.amount
+		order.Price = x.price
+		err := e.ValidateOrder(order)
+		if err == nil || err.Error() != x.err {
+			t.Errorf("Test Failed - ValidateOrder() amount %f price %f expected %s, got %v", x.amount, x.price, x.err, err)
+		}
+	}
+
+	order.Amount = 0.5
+	order.Price = 1000
+	e.SetPairInfo([]PairInfo{{Pair: pair.NewCurrencyPair("BTC", "USD"), Status: PairStatusHalted}})
+	if err := e.ValidateOrder(order); err == nil || err.Error() != ErrPairNotTradable {
+		t.Error("Test Failed - ValidateOrder() accepted an order on a halted pair")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"log"
 	"net/url"
 	"strconv"
 	"time"
@@ -43,8 +44,19 @@ const (
 	POLONIEX_OPEN_LOAN_OFFERS       = "returnOpenLoanOffers"
 	POLONIEX_ACTIVE_LOANS           = "returnActiveLoans"
 	POLONIEX_AUTO_RENEW             = "toggleAutoRenew"
+	POLONIEX_NONCE_ERROR_PATTERN    = `nonce must be greater than\s*(\d+)`
 )
 
+const (
+	POLONIEX_TRADE_HISTORY_LIMIT = 10000
+	POLONIEX_AUTH_RATE_LIMIT     = 180
+	POLONIEX_UNAUTH_RATE_LIMIT   = 180
+)
+
+var poloniexErrorRules = []exchange.ErrorRule{
+	{Match: "invalid order number", Kind: exchange.ErrorKindOrderNotFound},
+}
+
 type Poloniex struct {
 	exchange.ExchangeBase
 }
@@ -56,6 +68,8 @@ func (p *Poloniex) SetDefaults() {
 	p.Verbose = false
 	p.Websocket = false
 	p.RESTPollingDelay = 10
+	p.SetRateLimit(POLONIEX_AUTH_RATE_LIMIT, POLONIEX_UNAUTH_RATE_LIMIT)
+	p.SetNonceErrorPattern(POLONIEX_NONCE_ERROR_PATTERN)
 }
 
 func (p *Poloniex) Setup(exch config.ExchangeConfig) {
@@ -66,6 +80,11 @@ func (p *Poloniex) Setup(exch config.ExchangeConfig) {
 		p.AuthenticatedAPISupport = exch.AuthenticatedAPISupport
 		p.SetAPIKeys(exch.APIKey, exch.APISecret, "", false)
 		p.RESTPollingDelay = exch.RESTPollingDelay
+		p.UpdateRateLimit(exch)
+		err := p.UpdateHTTPSettings(exch)
+		if err != nil {
+			log.Printf("%s Failed to apply HTTP settings: %s.\n", p.GetName(), err)
+		}
 		p.Verbose = exch.Verbose
 		p.Websocket = exch.Websocket
 		p.BaseCurrencies = common.SplitStrings(exch.BaseCurrencies, ",")
@@ -84,8 +103,8 @@ func (p *Poloniex) GetTicker() (map[string]PoloniexTicker, error) {
 	}
 
 	resp := response{}
-	path := fmt.Sprintf("%s/public?command=returnTicker", POLONIEX_API_URL)
-	err := common.SendHTTPGetRequest(path, true, &resp.Data)
+	path := fmt.Sprintf("%s/public?command=returnTicker", p.GetAPIUrl(POLONIEX_API_URL))
+	err := p.SendHTTPGetRequest(path, true, &resp.Data)
 
 	if err != nil {
 		return resp.Data, err
@@ -95,8 +114,8 @@ func (p *Poloniex) GetTicker() (map[string]PoloniexTicker, error) {
 
 func (p *Poloniex) GetVolume() (interface{}, error) {
 	var resp interface{}
-	path := fmt.Sprintf("%s/public?command=return24hVolume", POLONIEX_API_URL)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	path := fmt.Sprintf("%s/public?command=return24hVolume", p.GetAPIUrl(POLONIEX_API_URL))
+	err := p.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return resp, err
@@ -113,8 +132,8 @@ func (p *Poloniex) GetOrderbook(currencyPair string, depth int) (PoloniexOrderbo
 	}
 
 	resp := PoloniexOrderbookResponse{}
-	path := fmt.Sprintf("%s/public?command=returnOrderBook&%s", POLONIEX_API_URL, vals.Encode())
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	path := fmt.Sprintf("%s/public?command=returnOrderBook&%s", p.GetAPIUrl(POLONIEX_API_URL), vals.Encode())
+	err := p.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return PoloniexOrderbook{}, err
@@ -129,11 +148,12 @@ func (p *Poloniex) GetOrderbook(currencyPair string, depth int) (PoloniexOrderbo
 	}
 
 	for x, _ := range resp.Bids {
-		data := resp.Asks[x]
+		data := resp.Bids[x]
 		price, _ := strconv.ParseFloat(data[0].(string), 64)
 		amount := data[1].(float64)
 		ob.Bids = append(ob.Bids, PoloniexOrderbookItem{Price: price, Amount: amount})
 	}
+	ob.Seq = resp.Seq
 	return ob, nil
 }
 
@@ -150,8 +170,8 @@ func (p *Poloniex) GetTradeHistory(currencyPair, start, end string) ([]PoloniexT
 	}
 
 	resp := []PoloniexTradeHistory{}
-	path := fmt.Sprintf("%s/public?command=returnTradeHistory&%s", POLONIEX_API_URL, vals.Encode())
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	path := fmt.Sprintf("%s/public?command=returnTradeHistory&%s", p.GetAPIUrl(POLONIEX_API_URL), vals.Encode())
+	err := p.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return nil, err
@@ -176,8 +196,8 @@ func (p *Poloniex) GetChartData(currencyPair, start, end, period string) ([]Polo
 	}
 
 	resp := []PoloniexChartData{}
-	path := fmt.Sprintf("%s/public?command=returnChartData&%s", POLONIEX_API_URL, vals.Encode())
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	path := fmt.Sprintf("%s/public?command=returnChartData&%s", p.GetAPIUrl(POLONIEX_API_URL), vals.Encode())
+	err := p.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return nil, err
@@ -190,8 +210,8 @@ func (p *Poloniex) GetCurrencies() (map[string]PoloniexCurrencies, error) {
 		Data map[string]PoloniexCurrencies
 	}
 	resp := Response{}
-	path := fmt.Sprintf("%s/public?command=returnCurrencies", POLONIEX_API_URL)
-	err := common.SendHTTPGetRequest(path, true, &resp.Data)
+	path := fmt.Sprintf("%s/public?command=returnCurrencies", p.GetAPIUrl(POLONIEX_API_URL))
+	err := p.SendHTTPGetRequest(path, true, &resp.Data)
 
 	if err != nil {
 		return resp.Data, err
@@ -201,8 +221,8 @@ func (p *Poloniex) GetCurrencies() (map[string]PoloniexCurrencies, error) {
 
 func (p *Poloniex) GetLoanOrders(currency string) (PoloniexLoanOrders, error) {
 	resp := PoloniexLoanOrders{}
-	path := fmt.Sprintf("%s/public?command=returnLoanOrders&currency=%s", POLONIEX_API_URL, currency)
-	err := common.SendHTTPGetRequest(path, true, &resp)
+	path := fmt.Sprintf("%s/public?command=returnLoanOrders&currency=%s", p.GetAPIUrl(POLONIEX_API_URL), currency)
+	err := p.SendHTTPGetRequest(path, true, &resp)
 
 	if err != nil {
 		return resp, err
@@ -349,7 +369,7 @@ func (p *Poloniex) GetOpenOrders(currency string) (interface{}, error) {
 	}
 }
 
-func (p *Poloniex) GetAuthenticatedTradeHistory(currency, start, end string) (interface{}, error) {
+func (p *Poloniex) GetAuthenticatedTradeHistory(currency, start, end string, limit int) (interface{}, error) {
 	values := url.Values{}
 
 	if start != "" {
@@ -360,6 +380,10 @@ func (p *Poloniex) GetAuthenticatedTradeHistory(currency, start, end string) (in
 		values.Set("end", end)
 	}
 
+	if limit > 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+
 	if currency != "" && currency != "all" {
 		values.Set("currencyPair", currency)
 		result := PoloniexAuthenticatedTradeHistoryResponse{}
@@ -415,7 +439,7 @@ func (p *Poloniex) PlaceOrder(currency string, rate, amount float64, immediate,
 	return result, nil
 }
 
-func (p *Poloniex) CancelOrder(orderID int64) (bool, error) {
+func (p *Poloniex) CancelExistingOrder(orderID int64) (bool, error) {
 	result := PoloniexGenericResponse{}
 	values := url.Values{}
 	values.Set("orderNumber", strconv.FormatInt(orderID, 10))
@@ -724,11 +748,12 @@ func (p *Poloniex) ToggleAutoRenew(orderNumber int64) (bool, error) {
 }
 
 func (p *Poloniex) SendAuthenticatedHTTPRequest(method, endpoint string, values url.Values, result interface{}) error {
+	p.WaitRateLimit(true)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 	headers["Key"] = p.APIKey
 
-	nonce := time.Now().UnixNano()
+	nonce := p.GetNonce().Get()
 	nonceStr := strconv.FormatInt(nonce, 10)
 
 	values.Set("nonce", nonceStr)
@@ -737,17 +762,22 @@ func (p *Poloniex) SendAuthenticatedHTTPRequest(method, endpoint string, values
 	hmac := common.GetHMAC(common.HASH_SHA512, []byte(values.Encode()), []byte(p.APISecret))
 	headers["Sign"] = common.HexEncodeToString(hmac)
 
-	path := fmt.Sprintf("%s/%s", POLONIEX_API_URL, POLONIEX_API_TRADING_ENDPOINT)
-	resp, err := common.SendHTTPRequest(method, path, headers, bytes.NewBufferString(values.Encode()))
+	path := fmt.Sprintf("%s/%s", p.GetAPIUrl(POLONIEX_API_URL), POLONIEX_API_TRADING_ENDPOINT)
+	resp, err := p.SendHTTPRequest(method, path, headers, bytes.NewBufferString(values.Encode()))
+
+	errResponse := PoloniexErrorResponse{}
+	if common.JSONDecode([]byte(resp), &errResponse) == nil && errResponse.Error != "" {
+		return p.CheckNonceError(exchange.NewExchangeError(p.Name, errors.New(errResponse.Error), resp, poloniexErrorRules...))
+	}
 
 	if err != nil {
-		return err
+		return p.CheckNonceError(exchange.NewExchangeError(p.Name, err, resp, poloniexErrorRules...))
 	}
 
 	err = common.JSONDecode([]byte(resp), &result)
 
 	if err != nil {
-		return errors.New("Unable to JSON Unmarshal response.")
+		return p.CheckNonceError(exchange.NewExchangeError(p.Name, errors.New("Unable to JSON Unmarshal response."), resp))
 	}
 	return nil
 }
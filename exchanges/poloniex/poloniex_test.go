@@ -0,0 +1,96 @@
+package poloniex
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/exchanges"
+)
+
+func TestGetFills(t *testing.T) {
+	var requests []string
+	empty := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("command") != POLONIEX_TRADE_HISTORY || r.Form.Get("limit") != strconv.Itoa(POLONIEX_TRADE_HISTORY_LIMIT) {
+			w.Write([]byte(`{"error":"Invalid command."}`))
+			return
+		}
+		requests = append(requests, r.Form.Get("currencyPair")+" "+r.Form.Get("end"))
+
+		trade := `{"globalTradeID":%d,"tradeID":"%d","date":"%s","rate":"0.01","amount":"1","total":"0.01","fee":"0.002","orderNumber":"%d","type":"buy","category":"exchange"}`
+		switch {
+		case empty:
+			w.Write([]byte(`[]`))
+		case r.Form.Get("currencyPair") == "all":
+			w.Write([]byte(`{"BTC_ETH":[` + fmt.Sprintf(trade, 1, 1, "2017-01-01 00:00:10", 1) + `],"BTC_LTC":[` + fmt.Sprintf(trade, 2, 2, "2017-01-01 00:00:20", 2) + `]}`))
+		case r.Form.Get("currencyPair") == "BTC_ETH" && r.Form.Get("end") == "1483228810":
+			w.Write([]byte(`[` + fmt.Sprintf(trade, 1, 1, "2017-01-01 00:00:10", 1) + `,` + fmt.Sprintf(trade, 3, 3, "2017-01-01 00:00:05", 3) + `]`))
+		case r.Form.Get("currencyPair") == "BTC_ETH":
+			trades := []string{}
+			for i := 1; i <= POLONIEX_TRADE_HISTORY_LIMIT; i++ {
+				id := i
+				if i > 1 {
+					id = i + 100
+				}
+				trades = append(trades, fmt.Sprintf(trade, id, id, "2017-01-01 00:00:10", id))
+			}
+			w.Write([]byte(`[` + strings.Join(trades, ",") + `]`))
+		default:
+			w.Write([]byte(`[` + fmt.Sprintf(trade, 2, 2, "2017-01-01 00:00:20", 2) + `]`))
+		}
+	}))
+	defer server.Close()
+
+	p := Poloniex{}
+	p.SetDefaults()
+	p.APIUrl = server.URL
+	p.SetRateLimit(0, 0)
+	p.APIKey = "key"
+	p.APISecret = "secret"
+	end := time.Unix(1483228900, 0)
+
+	fills, err := p.GetFills(exchange.OrderFilter{EndTime: end})
+	if err != nil || len(fills) != 0 {
+		t.Errorf("Test Failed - GetFills() without trades incorrect: %+v %v", fills, err)
+	}
+
+	empty = false
+	requests = nil
+	fills, err = p.GetFills(exchange.OrderFilter{EndTime: end})
+	if err != nil || len(fills) != POLONIEX_TRADE_HISTORY_LIMIT+2 {
+		t.Fatalf("Test Failed - GetFills() expected %d fills, received %d %v", POLONIEX_TRADE_HISTORY_LIMIT+2, len(fills), err)
+	}
+
+	expected := []string{"all 1483228900", "BTC_ETH 1483228900", "BTC_ETH 1483228810", "BTC_LTC 1483228900"}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("Test Failed - GetFills() expected requests %v, received %v", expected, requests)
+	}
+}
+
+func TestCheckNonceError(t *testing.T) {
+	expected := time.Now().UnixNano() + int64(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fmt.Sprintf(`{"error":"Nonce must be greater than %d. You provided %d."}`, expected, expected+int64(time.Hour))))
+	}))
+	defer server.Close()
+
+	p := Poloniex{}
+	p.SetDefaults()
+	p.APIUrl = server.URL
+	p.SetRateLimit(0, 0)
+
+	_, err := p.GetBalances()
+	if !exchange.IsErrorKind(err, exchange.ErrorKindInvalidNonce) {
+		t.Errorf("Test Failed - GetBalances() expected an invalid nonce error, received %v", err)
+	}
+
+	if nonce := p.GetNonce().GetValue(); nonce != expected {
+		t.Errorf("Test Failed - CheckNonceError() expected the nonce resynced to %d, received %d", expected, nonce)
+	}
+}
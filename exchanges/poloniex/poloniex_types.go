@@ -2,6 +2,8 @@ package poloniex
 
 import (
 	"time"
+
+	"github.com/champii/gocryptotrader/common"
 )
 
 type PoloniexTicker struct {
@@ -20,6 +22,7 @@ type PoloniexOrderbookResponse struct {
 	Asks     [][]interface{} `json:"asks"`
 	Bids     [][]interface{} `json:"bids"`
 	IsFrozen string          `json:"isFrozen"`
+	Seq      int64           `json:"seq"`
 }
 
 type PoloniexOrderbookItem struct {
@@ -30,6 +33,7 @@ type PoloniexOrderbookItem struct {
 type PoloniexOrderbook struct {
 	Asks []PoloniexOrderbookItem `json:"asks"`
 	Bids []PoloniexOrderbookItem `json:"bids"`
+	Seq  int64                   `json:"seq"`
 }
 
 type PoloniexTradeHistory struct {
@@ -145,7 +149,27 @@ type PoloniexAuthentictedTradeHistory struct {
 }
 
 type PoloniexAuthenticatedTradeHistoryAll struct {
-	Data map[string][]PoloniexAuthentictedTradeHistory
+	Data PoloniexTradeHistoryMarkets
+}
+
+//PoloniexTradeHistoryMarkets : Trade history keyed by market
+type PoloniexTradeHistoryMarkets map[string][]PoloniexAuthentictedTradeHistory
+
+//UnmarshalJSON decodes the trade history of every market. Poloniex returns an
+//empty array instead of an object when there are no trades
+func (m *PoloniexTradeHistoryMarkets) UnmarshalJSON(data []byte) error {
+	if common.TrimString(string(data), " \t\r\n") == "[]" {
+		*m = PoloniexTradeHistoryMarkets{}
+		return nil
+	}
+
+	result := make(map[string][]PoloniexAuthentictedTradeHistory)
+	err := common.JSONDecode(data, &result)
+	if err != nil {
+		return err
+	}
+	*m = result
+	return nil
 }
 
 type PoloniexAuthenticatedTradeHistoryResponse struct {
@@ -222,3 +246,7 @@ type PoloniexActiveLoans struct {
 	Provided []PoloniexLoanOffer `json:"provided"`
 	Used     []PoloniexLoanOffer `json:"used"`
 }
+
+type PoloniexErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -3,8 +3,15 @@ package poloniex
 import (
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/beatgammit/turnpike"
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 const (
@@ -14,6 +21,12 @@ const (
 	POLONIEX_WEBSOCKET_TROLLBOX = "trollbox"
 )
 
+//PoloniexWebsocketEvent : Arguments of a channel event, as it is recorded
+type PoloniexWebsocketEvent struct {
+	Args   []interface{}          `json:"args"`
+	Kwargs map[string]interface{} `json:"kwargs"`
+}
+
 type PoloniexWebsocketTicker struct {
 	CurrencyPair  string
 	Last          float64
@@ -27,7 +40,10 @@ type PoloniexWebsocketTicker struct {
 	Low           float64
 }
 
-func PoloniexOnTicker(args []interface{}, kwargs map[string]interface{}) {
+//PoloniexOnTicker stores ticker channel updates of the enabled pairs in the
+//ticker store
+func (p *Poloniex) PoloniexOnTicker(args []interface{}, kwargs map[string]interface{}) {
+	exchange.RecordWebsocketMessage(p.GetName())
 	ticker := PoloniexWebsocketTicker{}
 	ticker.CurrencyPair = args[0].(string)
 	ticker.Last, _ = strconv.ParseFloat(args[1].(string), 64)
@@ -45,6 +61,32 @@ func PoloniexOnTicker(args []interface{}, kwargs map[string]interface{}) {
 
 	ticker.High, _ = strconv.ParseFloat(args[8].(string), 64)
 	ticker.Low, _ = strconv.ParseFloat(args[9].(string), 64)
+	p.WebsocketProcessTicker(ticker)
+}
+
+//WebsocketProcessTicker stores a ticker channel update when its pair is enabled
+func (p *Poloniex) WebsocketProcessTicker(tick PoloniexWebsocketTicker) {
+	enabled := false
+	for _, x := range p.EnabledPairs {
+		if x == tick.CurrencyPair {
+			enabled = true
+			break
+		}
+	}
+
+	if !enabled {
+		return
+	}
+
+	currency := pair.NewCurrencyPairDelimiter(tick.CurrencyPair, "_")
+	ticker.ProcessTicker(p.GetName(), currency, ticker.TickerPrice{
+		Last:   tick.Last,
+		High:   tick.High,
+		Low:    tick.Low,
+		Bid:    tick.HighestBid,
+		Ask:    tick.LowestAsk,
+		Volume: tick.BaseVolume,
+	})
 }
 
 type PoloniexWebsocketTrollboxMessage struct {
@@ -64,80 +106,171 @@ func PoloniexOnTrollbox(args []interface{}, kwargs map[string]interface{}) {
 	}
 }
 
-func PoloniexOnDepthOrTrade(args []interface{}, kwargs map[string]interface{}) {
-	for x := range args {
-		data := args[x].(map[string]interface{})
-		msgData := data["data"].(map[string]interface{})
-		msgType := data["type"].(string)
-
-		switch msgType {
-		case "orderBookModify":
-			{
-				type PoloniexWebsocketOrderbookModify struct {
-					Type   string
-					Rate   float64
-					Amount float64
-				}
-
-				orderModify := PoloniexWebsocketOrderbookModify{}
-				orderModify.Type = msgData["type"].(string)
-
-				rateStr := msgData["rate"].(string)
-				orderModify.Rate, _ = strconv.ParseFloat(rateStr, 64)
+//WebsocketGetDepth returns the websocket maintained orderbook of the pair, which
+//is resynced from the REST orderbook whenever a sequence gap is detected
+func (p *Poloniex) WebsocketGetDepth(currencyPair string) *orderbook.Depth {
+	currency := pair.NewCurrencyPairDelimiter(currencyPair, "_")
+	return orderbook.GetDepth(p.GetName(), currency, func() (orderbook.OrderbookBase, error) {
+		return p.GetOrderbookEx(currency)
+	})
+}
 
-				amountStr := msgData["amount"].(string)
-				orderModify.Amount, _ = strconv.ParseFloat(amountStr, 64)
-			}
-		case "orderBookRemove":
-			{
-				type PoloniexWebsocketOrderbookRemove struct {
-					Type string
-					Rate float64
+//PoloniexOnDepthOrTrade returns the handler of the pair channel. Every message
+//carries the channel sequence and its orderbook changes are applied as one
+//batch, while new trades are stored in the trade store
+func (p *Poloniex) PoloniexOnDepthOrTrade(currencyPair string) turnpike.EventHandler {
+	return func(args []interface{}, kwargs map[string]interface{}) {
+		exchange.RecordWebsocketMessage(p.GetName())
+		seq, _ := kwargs["seq"].(float64)
+		currency := pair.NewCurrencyPairDelimiter(currencyPair, "_")
+		updates := []orderbook.DepthUpdate{}
+		newTrades := []trades.Trade{}
+		for x := range args {
+			data := args[x].(map[string]interface{})
+			msgData := data["data"].(map[string]interface{})
+			msgType := data["type"].(string)
+
+			switch msgType {
+			case "orderBookModify":
+				{
+					type PoloniexWebsocketOrderbookModify struct {
+						Type   string
+						Rate   float64
+						Amount float64
+					}
+
+					orderModify := PoloniexWebsocketOrderbookModify{}
+					orderModify.Type = msgData["type"].(string)
+
+					rateStr := msgData["rate"].(string)
+					orderModify.Rate, _ = strconv.ParseFloat(rateStr, 64)
+
+					amountStr := msgData["amount"].(string)
+					orderModify.Amount, _ = strconv.ParseFloat(amountStr, 64)
+					updates = append(updates, orderbook.DepthUpdate{Bid: orderModify.Type == "bid", Action: orderbook.DEPTH_ACTION_UPDATE, Price: orderModify.Rate, Amount: orderModify.Amount})
 				}
-
-				orderRemoval := PoloniexWebsocketOrderbookRemove{}
-				orderRemoval.Type = msgData["type"].(string)
-
-				rateStr := msgData["rate"].(string)
-				orderRemoval.Rate, _ = strconv.ParseFloat(rateStr, 64)
-			}
-		case "newTrade":
-			{
-				type PoloniexWebsocketNewTrade struct {
-					Type    string
-					TradeID int64
-					Rate    float64
-					Amount  float64
-					Date    string
-					Total   float64
+			case "orderBookRemove":
+				{
+					type PoloniexWebsocketOrderbookRemove struct {
+						Type string
+						Rate float64
+					}
+
+					orderRemoval := PoloniexWebsocketOrderbookRemove{}
+					orderRemoval.Type = msgData["type"].(string)
+
+					rateStr := msgData["rate"].(string)
+					orderRemoval.Rate, _ = strconv.ParseFloat(rateStr, 64)
+					updates = append(updates, orderbook.DepthUpdate{Bid: orderRemoval.Type == "bid", Action: orderbook.DEPTH_ACTION_DELETE, Price: orderRemoval.Rate})
+				}
+			case "newTrade":
+				{
+					type PoloniexWebsocketNewTrade struct {
+						Type    string
+						TradeID int64
+						Rate    float64
+						Amount  float64
+						Date    string
+						Total   float64
+					}
+
+					trade := PoloniexWebsocketNewTrade{}
+					trade.Type = msgData["type"].(string)
+
+					tradeIDstr := msgData["tradeID"].(string)
+					trade.TradeID, _ = strconv.ParseInt(tradeIDstr, 10, 64)
+
+					rateStr := msgData["rate"].(string)
+					trade.Rate, _ = strconv.ParseFloat(rateStr, 64)
+
+					amountStr := msgData["amount"].(string)
+					trade.Amount, _ = strconv.ParseFloat(amountStr, 64)
+
+					totalStr := msgData["total"].(string)
+					trade.Total, _ = strconv.ParseFloat(totalStr, 64)
+
+					trade.Date = msgData["date"].(string)
+
+					newTrade := trades.Trade{
+						Pair:    currency,
+						TradeID: tradeIDstr,
+						Side:    trades.TRADE_SIDE_BUY,
+						Price:   trade.Rate,
+						Amount:  trade.Amount,
+					}
+					if trade.Type == "sell" {
+						newTrade.Side = trades.TRADE_SIDE_SELL
+					}
+					newTrade.Timestamp, _ = time.Parse("2006-01-02 15:04:05", trade.Date)
+					newTrades = append(newTrades, newTrade)
 				}
+			}
+		}
 
-				trade := PoloniexWebsocketNewTrade{}
-				trade.Type = msgData["type"].(string)
+		if len(newTrades) > 0 {
+			trades.ProcessTrades(p.GetName(), currency, newTrades)
+		}
 
-				tradeIDstr := msgData["tradeID"].(string)
-				trade.TradeID, _ = strconv.ParseInt(tradeIDstr, 10, 64)
+		err := p.WebsocketGetDepth(currencyPair).Apply(int64(seq), updates)
+		if err != nil && p.Verbose {
+			log.Println(err)
+		}
+	}
+}
 
-				rateStr := msgData["rate"].(string)
-				trade.Rate, _ = strconv.ParseFloat(rateStr, 64)
+//websocketHandler returns handler, writing the channel events to the stream
+//recording first when the exchange is recorded
+func (p *Poloniex) websocketHandler(channel string, handler turnpike.EventHandler) turnpike.EventHandler {
+	recorder := exchange.GetStreamRecorder(p.GetName())
+	if recorder == nil {
+		return handler
+	}
 
-				amountStr := msgData["amount"].(string)
-				trade.Amount, _ = strconv.ParseFloat(amountStr, 64)
+	return func(args []interface{}, kwargs map[string]interface{}) {
+		data, err := common.JSONEncode(PoloniexWebsocketEvent{Args: args, Kwargs: kwargs})
+		if err == nil {
+			recorder.RecordWebsocketEvent(channel, data)
+		}
+		handler(args, kwargs)
+	}
+}
 
-				totalStr := msgData["total"].(string)
-				trade.Rate, _ = strconv.ParseFloat(totalStr, 64)
+//websocketReplay passes the recorded channel events to their handlers
+func (p *Poloniex) websocketReplay(replayer *exchange.StreamReplayer) {
+	replayer.Replay(p.GetWebsocketContext(), func(entry exchange.StreamEntry) {
+		event := PoloniexWebsocketEvent{}
+		err := common.JSONDecode(entry.Data, &event)
+		if err != nil {
+			log.Println(err)
+			return
+		}
 
-				trade.Date = msgData["date"].(string)
-			}
+		switch entry.Channel {
+		case POLONIEX_WEBSOCKET_TICKER:
+			p.PoloniexOnTicker(event.Args, event.Kwargs)
+		case POLONIEX_WEBSOCKET_TROLLBOX:
+			PoloniexOnTrollbox(event.Args, event.Kwargs)
+		default:
+			p.PoloniexOnDepthOrTrade(entry.Channel)(event.Args, event.Kwargs)
 		}
-	}
+	})
 }
 
 func (p *Poloniex) WebsocketClient() {
+	if replayer := exchange.GetStreamReplayer(p.GetName()); replayer != nil {
+		p.websocketReplay(replayer)
+		return
+	}
+
+	ctx := p.GetWebsocketContext()
+	backoff := exchange.WebsocketBackoff{}
 	for p.Enabled && p.Websocket {
-		c, err := turnpike.NewWebsocketClient(turnpike.JSON, POLONIEX_WEBSOCKET_ADDRESS, nil)
+		c, err := turnpike.NewWebsocketClient(turnpike.JSON, p.GetWebsocketURL(POLONIEX_WEBSOCKET_ADDRESS), nil)
 		if err != nil {
 			log.Printf("%s Unable to connect to Websocket. Error: %s\n", p.GetName(), err)
+			if !backoff.Wait(ctx) {
+				return
+			}
 			continue
 		}
 
@@ -148,6 +281,10 @@ func (p *Poloniex) WebsocketClient() {
 		_, err = c.JoinRealm(POLONIEX_WEBSOCKET_REALM, nil)
 		if err != nil {
 			log.Printf("%s Unable to join realm. Error: %s\n", p.GetName(), err)
+			c.Close()
+			if !backoff.Wait(ctx) {
+				return
+			}
 			continue
 		}
 
@@ -157,17 +294,17 @@ func (p *Poloniex) WebsocketClient() {
 
 		c.ReceiveDone = make(chan bool)
 
-		if err := c.Subscribe(POLONIEX_WEBSOCKET_TICKER, PoloniexOnTicker); err != nil {
+		if err := c.Subscribe(POLONIEX_WEBSOCKET_TICKER, p.websocketHandler(POLONIEX_WEBSOCKET_TICKER, p.PoloniexOnTicker)); err != nil {
 			log.Printf("%s Error subscribing to ticker channel: %s\n", p.GetName(), err)
 		}
 
-		if err := c.Subscribe(POLONIEX_WEBSOCKET_TROLLBOX, PoloniexOnTrollbox); err != nil {
+		if err := c.Subscribe(POLONIEX_WEBSOCKET_TROLLBOX, p.websocketHandler(POLONIEX_WEBSOCKET_TROLLBOX, PoloniexOnTrollbox)); err != nil {
 			log.Printf("%s Error subscribing to trollbox channel: %s\n", p.GetName(), err)
 		}
 
 		for x := range p.EnabledPairs {
 			currency := p.EnabledPairs[x]
-			if err := c.Subscribe(currency, PoloniexOnDepthOrTrade); err != nil {
+			if err := c.Subscribe(currency, p.websocketHandler(currency, p.PoloniexOnDepthOrTrade(currency))); err != nil {
 				log.Printf("%s Error subscribing to %s channel: %s\n", p.GetName(), currency, err)
 			}
 		}
@@ -176,7 +313,21 @@ func (p *Poloniex) WebsocketClient() {
 			log.Printf("%s Subscribed to websocket channels.\n", p.GetName())
 		}
 
-		<-c.ReceiveDone
-		log.Printf("%s Websocket client disconnected.\n", p.GetName())
+		backoff.Reset()
+		exchange.RecordWebsocketState(p.GetName(), exchange.WEBSOCKET_STATE_CONNECTED)
+
+		select {
+		case <-c.ReceiveDone:
+			log.Printf("%s Websocket client disconnected.\n", p.GetName())
+			exchange.RecordWebsocketState(p.GetName(), exchange.WEBSOCKET_STATE_DISCONNECTED)
+		case <-ctx.Done():
+			c.Close()
+			exchange.RecordWebsocketState(p.GetName(), exchange.WEBSOCKET_STATE_STOPPED)
+			return
+		}
+
+		if !backoff.Wait(ctx) {
+			return
+		}
 	}
 }
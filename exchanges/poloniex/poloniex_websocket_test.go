@@ -0,0 +1,70 @@
+package poloniex
+
+import (
+	"testing"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
+)
+
+func TestPoloniexOnTicker(t *testing.T) {
+	p := Poloniex{}
+	p.SetDefaults()
+	p.EnabledPairs = []string{"BTC_LTC"}
+
+	p.PoloniexOnTicker([]interface{}{"BTC_LTC", "0.01", "0.011", "0.009", "0.5", "100", "10000", float64(0), "0.012", "0.008"}, nil)
+	p.PoloniexOnTicker([]interface{}{"BTC_ETH", "0.1", "0.11", "0.09", "0.5", "100", "1000", float64(0), "0.12", "0.08"}, nil)
+
+	price, err := ticker.GetTicker(p.GetName(), pair.NewCurrencyPairDelimiter("BTC_LTC", "_"))
+	if err != nil {
+		t.Fatalf("Test Failed - Poloniex websocket ticker error: %s", err)
+	}
+
+	if price.Last != 0.01 || price.Ask != 0.011 || price.Bid != 0.009 || price.High != 0.012 || price.Volume != 100 {
+		t.Errorf("Test Failed - Poloniex websocket ticker is incorrect: %+v", price)
+	}
+
+	_, err = ticker.GetTicker(p.GetName(), pair.NewCurrencyPairDelimiter("BTC_ETH", "_"))
+	if err == nil {
+		t.Error("Test Failed - Poloniex websocket ticker stored a disabled pair")
+	}
+}
+
+func TestPoloniexOnDepthOrTrade(t *testing.T) {
+	p := Poloniex{}
+	p.SetDefaults()
+	currency := pair.NewCurrencyPairDelimiter("BTC_XMR", "_")
+
+	err := p.WebsocketGetDepth("BTC_XMR").LoadSnapshot(orderbook.OrderbookBase{
+		Bids:     []orderbook.OrderbookItem{{Price: 0.01, Amount: 5}},
+		Asks:     []orderbook.OrderbookItem{{Price: 0.02, Amount: 5}},
+		Sequence: 10,
+	})
+	if err != nil {
+		t.Fatalf("Test Failed - Poloniex LoadSnapshot error: %s", err)
+	}
+
+	handler := p.PoloniexOnDepthOrTrade("BTC_XMR")
+	handler([]interface{}{
+		map[string]interface{}{"type": "orderBookModify", "data": map[string]interface{}{"type": "bid", "rate": "0.015", "amount": "2"}},
+		map[string]interface{}{"type": "orderBookRemove", "data": map[string]interface{}{"type": "ask", "rate": "0.02"}},
+		map[string]interface{}{"type": "newTrade", "data": map[string]interface{}{"type": "sell", "tradeID": "42", "rate": "0.014",
+			"amount": "1", "total": "0.014", "date": "2017-05-01 10:00:00"}},
+	}, map[string]interface{}{"seq": float64(11)})
+
+	book, err := orderbook.GetOrderbook(p.GetName(), currency)
+	if err != nil {
+		t.Fatalf("Test Failed - Poloniex websocket orderbook error: %s", err)
+	}
+
+	if book.Sequence != 11 || len(book.Bids) != 2 || book.Bids[0].Price != 0.015 || len(book.Asks) != 0 {
+		t.Errorf("Test Failed - Poloniex websocket orderbook is incorrect: %+v", book)
+	}
+
+	result, err := trades.GetTrades(p.GetName(), currency)
+	if err != nil || len(result) != 1 || result[0].TradeID != "42" || result[0].Side != trades.TRADE_SIDE_SELL {
+		t.Errorf("Test Failed - Poloniex websocket trade is incorrect: %+v", result)
+	}
+}
@@ -1,7 +1,10 @@
 package poloniex
 
 import (
+	"errors"
 	"log"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/champii/gocryptotrader/common"
@@ -10,6 +13,7 @@ import (
 	"github.com/champii/gocryptotrader/exchanges/orderbook"
 	"github.com/champii/gocryptotrader/exchanges/stats"
 	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/champii/gocryptotrader/exchanges/trades"
 )
 
 func (p *Poloniex) Start() {
@@ -18,7 +22,7 @@ func (p *Poloniex) Start() {
 
 func (p *Poloniex) Run() {
 	if p.Verbose {
-		log.Printf("%s Websocket: %s (url: %s).\n", p.GetName(), common.IsEnabled(p.Websocket), POLONIEX_WEBSOCKET_ADDRESS)
+		log.Printf("%s Websocket: %s (url: %s).\n", p.GetName(), common.IsEnabled(p.Websocket), p.GetWebsocketURL(POLONIEX_WEBSOCKET_ADDRESS))
 		log.Printf("%s polling delay: %ds.\n", p.GetName(), p.RESTPollingDelay)
 		log.Printf("%s %d currencies enabled: %s.\n", p.GetName(), len(p.EnabledPairs), p.EnabledPairs)
 	}
@@ -27,10 +31,15 @@ func (p *Poloniex) Run() {
 		go p.WebsocketClient()
 	}
 
+	err := p.UpdatePairInfo()
+	if err != nil {
+		log.Printf("%s Failed to get pair info.\n", p.GetName())
+	}
+
 	for p.Enabled {
 		for _, x := range p.EnabledPairs {
 			currency := pair.NewCurrencyPairDelimiter(x, "_")
-			go func() {
+			p.Poll(x, func() {
 				ticker, err := p.GetTickerPrice(currency)
 				if err != nil {
 					log.Println(err)
@@ -38,7 +47,7 @@ func (p *Poloniex) Run() {
 				}
 				log.Printf("Poloniex %s Last %f High %f Low %f Volume %f\n", currency.Pair().String(), ticker.Last, ticker.High, ticker.Low, ticker.Volume)
 				stats.AddExchangeInfo(p.GetName(), currency.GetFirstCurrency().String(), currency.GetSecondCurrency().String(), ticker.Last, ticker.Volume)
-			}()
+			})
 		}
 		time.Sleep(time.Second * p.RESTPollingDelay)
 	}
@@ -91,6 +100,7 @@ func (p *Poloniex) GetOrderbookEx(currencyPair pair.CurrencyPair) (orderbook.Ord
 		orderBook.Asks = append(orderBook.Asks, orderbook.OrderbookItem{Amount: data.Amount, Price: data.Price})
 	}
 	orderBook.Pair = currencyPair
+	orderBook.Sequence = orderbookNew.Seq
 	orderbook.ProcessOrderbook(p.GetName(), currencyPair, orderBook)
 	return orderBook, nil
 }
@@ -112,3 +122,429 @@ func (e *Poloniex) GetExchangeAccountInfo() (exchange.ExchangeAccountInfo, error
 	}
 	return response, nil
 }
+
+//SubmitOrder : Places a new limit order on Poloniex
+func (p *Poloniex) SubmitOrder(order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := p.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	response, err := p.PlaceOrder(p.formatOrderPair(order.Pair), order.Price, order.Amount, false, false, order.IsBuy())
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = p.GetName()
+	result.OrderID = strconv.FormatInt(response.OrderNumber, 10)
+	result.ClientID = order.ClientID
+	for _, x := range response.Trades {
+		result.FilledAmount += x.Amount
+	}
+	return result, nil
+}
+
+//CancelOrder : Cancels an order by its ID
+func (p *Poloniex) CancelOrder(orderID string, currencyPair pair.CurrencyPair) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.CancelExistingOrder(id)
+	return err
+}
+
+//ModifyOrder : Moves an existing order to a new price and amount
+func (p *Poloniex) ModifyOrder(orderID string, order exchange.OrderRequest) (exchange.OrderResult, error) {
+	var result exchange.OrderResult
+	err := p.ValidateOrder(order)
+	if err != nil {
+		return result, err
+	}
+
+	if order.IsMarket() {
+		return result, errors.New(exchange.ErrOrderTypeNotSupported)
+	}
+
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return result, err
+	}
+
+	response, err := p.MoveOrder(id, order.Price, order.Amount)
+	if err != nil {
+		return result, err
+	}
+
+	result.Exchange = p.GetName()
+	result.OrderID = strconv.FormatInt(response.OrderNumber, 10)
+	result.ClientID = order.ClientID
+	for _, trades := range response.Trades {
+		for _, x := range trades {
+			result.FilledAmount += x.Amount
+		}
+	}
+	return result, nil
+}
+
+//GetOrderInfo : Retrieves an order from the open orders of the supplied pair. Poloniex
+//has no order status endpoint, so orders which are no longer open are reported as not found
+func (p *Poloniex) GetOrderInfo(orderID string, currencyPair pair.CurrencyPair) (exchange.OrderDetail, error) {
+	var detail exchange.OrderDetail
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return detail, err
+	}
+
+	currency := p.formatOrderPair(currencyPair)
+	response, err := p.GetOpenOrders(currency)
+	if err != nil {
+		return detail, err
+	}
+
+	for _, x := range response.(PoloniexOpenOrdersResponse).Data {
+		if x.OrderNumber == id {
+			return p.getOrderDetail(currency, x), nil
+		}
+	}
+	return detail, errors.New(exchange.ErrOrderNotFound)
+}
+
+func (p *Poloniex) formatOrderPair(currencyPair pair.CurrencyPair) string {
+	return common.StringToUpper(currencyPair.GetFirstCurrency().String()) + "_" + common.StringToUpper(currencyPair.GetSecondCurrency().String())
+}
+
+func (p *Poloniex) getOrderDetail(currency string, order PoloniexOrder) exchange.OrderDetail {
+	var detail exchange.OrderDetail
+	detail.Exchange = p.GetName()
+	detail.OrderID = strconv.FormatInt(order.OrderNumber, 10)
+	detail.Pair = pair.NewCurrencyPairDelimiter(currency, "_")
+	detail.Side = exchange.OrderSide(common.StringToUpper(order.Type))
+	detail.Type = exchange.OrderTypeLimit
+	detail.Status = exchange.OrderStatusActive
+	detail.Price = order.Rate
+	detail.Amount = order.Amount
+	detail.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", order.Date)
+	return detail
+}
+
+//GetActiveOrders : Retrieves all resting orders matching the filter
+func (p *Poloniex) GetActiveOrders(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	response, err := p.GetOpenOrders("")
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []exchange.OrderDetail
+	for currency, x := range response.(PoloniexOpenOrdersResponseAll).Data {
+		for _, y := range x {
+			orders = append(orders, p.getOrderDetail(currency, y))
+		}
+	}
+	return exchange.FilterOrders(orders, filter), nil
+}
+
+//GetOrderHistory : Not supported, Poloniex only exposes fills through its trade history
+func (p *Poloniex) GetOrderHistory(filter exchange.OrderFilter) ([]exchange.OrderDetail, error) {
+	return nil, errors.New(exchange.ErrFunctionNotSupported)
+}
+
+//GetFills : Retrieves the account's executions matching the filter. Poloniex
+//truncates the history of every market together, so the markets traded are
+//found first and the history of each is paged backwards on its own
+func (p *Poloniex) GetFills(filter exchange.OrderFilter) ([]exchange.Fill, error) {
+	start := "0"
+	if !filter.StartTime.IsZero() {
+		start = strconv.FormatInt(filter.StartTime.Unix(), 10)
+	}
+
+	end := time.Now().Unix()
+	if !filter.EndTime.IsZero() {
+		end = filter.EndTime.Unix()
+	}
+
+	var markets []string
+	for _, x := range filter.Pairs {
+		markets = append(markets, p.formatOrderPair(x))
+	}
+
+	if len(markets) == 0 {
+		var err error
+		markets, err = p.getTradedMarkets(start, end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var fills []exchange.Fill
+	for _, x := range markets {
+		result, err := p.getMarketFills(x, start, end)
+		if err != nil {
+			return nil, err
+		}
+		fills = append(fills, result...)
+	}
+	return exchange.FilterFills(fills, filter), nil
+}
+
+//getTradedMarkets returns the markets with trades in the range, moving the end
+//of the range back until the history of every market is no longer truncated
+func (p *Poloniex) getTradedMarkets(start string, end int64) ([]string, error) {
+	var markets []string
+	seen := make(map[string]bool)
+	for {
+		response, err := p.GetAuthenticatedTradeHistory("all", start, strconv.FormatInt(end, 10), POLONIEX_TRADE_HISTORY_LIMIT)
+		if err != nil {
+			return nil, err
+		}
+
+		count := 0
+		oldest := end
+		for currency, x := range response.(PoloniexAuthenticatedTradeHistoryAll).Data {
+			if !seen[currency] {
+				seen[currency] = true
+				markets = append(markets, currency)
+			}
+
+			for _, y := range x {
+				count++
+				timestamp := p.getFill(currency, y).Timestamp.Unix()
+				if timestamp < oldest {
+					oldest = timestamp
+				}
+			}
+		}
+
+		if count < POLONIEX_TRADE_HISTORY_LIMIT || oldest >= end {
+			break
+		}
+		end = oldest
+	}
+	sort.Strings(markets)
+	return markets, nil
+}
+
+//getMarketFills pages backwards through the trade history of a market until a
+//page is no longer truncated. Pages overlap by a second, as trades sharing the
+//oldest timestamp may have been cut
+func (p *Poloniex) getMarketFills(currency, start string, end int64) ([]exchange.Fill, error) {
+	var fills []exchange.Fill
+	seen := make(map[int64]bool)
+	for {
+		response, err := p.GetAuthenticatedTradeHistory(currency, start, strconv.FormatInt(end, 10), POLONIEX_TRADE_HISTORY_LIMIT)
+		if err != nil {
+			return nil, err
+		}
+
+		trades := response.(PoloniexAuthenticatedTradeHistoryResponse).Data
+		added := 0
+		oldest := end
+		for _, x := range trades {
+			if seen[x.GlobalTradeID] {
+				continue
+			}
+			seen[x.GlobalTradeID] = true
+			added++
+
+			fill := p.getFill(currency, x)
+			if fill.Timestamp.Unix() < oldest {
+				oldest = fill.Timestamp.Unix()
+			}
+			fills = append(fills, fill)
+		}
+
+		if len(trades) < POLONIEX_TRADE_HISTORY_LIMIT || added == 0 {
+			break
+		}
+		end = oldest
+	}
+	return fills, nil
+}
+
+//getFill converts a Poloniex trade, whose fee is reported as a rate, into a
+//fill charged in the currency received
+func (p *Poloniex) getFill(currency string, trade PoloniexAuthentictedTradeHistory) exchange.Fill {
+	var fill exchange.Fill
+	fill.Exchange = p.GetName()
+	fill.TradeID = strconv.FormatInt(trade.GlobalTradeID, 10)
+	fill.OrderID = strconv.FormatInt(trade.OrderNumber, 10)
+	fill.Pair = pair.NewCurrencyPairDelimiter(currency, "_")
+	fill.Side = exchange.OrderSide(common.StringToUpper(trade.Type))
+	fill.Price = trade.Rate
+	fill.Amount = trade.Amount
+	fill.Timestamp, _ = time.Parse("2006-01-02 15:04:05", trade.Date)
+
+	if fill.Side == exchange.OrderSideBuy {
+		fill.Fee = trade.Amount * trade.Fee
+		fill.FeeCurrency = fill.Pair.GetSecondCurrency().String()
+	} else {
+		fill.Fee = trade.Total * trade.Fee
+		fill.FeeCurrency = fill.Pair.GetFirstCurrency().String()
+	}
+	return fill
+}
+
+//GetDepositAddress : Retrieves the deposit address for a currency, generating
+//one if the account does not have an address yet
+func (p *Poloniex) GetDepositAddress(currency string) (exchange.DepositAddress, error) {
+	var address exchange.DepositAddress
+	currency = common.StringToUpper(currency)
+	addresses, err := p.GetDepositAddresses()
+	if err != nil {
+		return address, exchange.NewFundingError(p.GetName(), currency, err)
+	}
+
+	depositAddress, ok := addresses.Addresses[currency]
+	if !ok {
+		depositAddress, err = p.GenerateNewAddress(currency)
+		if err != nil {
+			return address, exchange.NewFundingError(p.GetName(), currency, err)
+		}
+	}
+
+	if depositAddress == "" {
+		return address, exchange.NewFundingError(p.GetName(), currency, errors.New(exchange.ErrDepositAddressNotFound))
+	}
+
+	address.Exchange = p.GetName()
+	address.Currency = currency
+	address.Address = depositAddress
+	return address, nil
+}
+
+//WithdrawCryptocurrency : Withdraws a currency to an external address. Poloniex
+//does not return an ID for the withdrawal
+func (p *Poloniex) WithdrawCryptocurrency(request exchange.WithdrawRequest) (exchange.WithdrawResult, error) {
+	var result exchange.WithdrawResult
+	err := request.Validate()
+	if err != nil {
+		return result, exchange.NewFundingError(p.GetName(), request.Currency, err)
+	}
+
+	if request.Tag != "" {
+		return result, exchange.NewFundingError(p.GetName(), request.Currency, errors.New(exchange.ErrWithdrawTagUnsupported))
+	}
+
+	_, err = p.Withdraw(common.StringToUpper(request.Currency), request.Address, request.Amount)
+	if err != nil {
+		return result, exchange.NewFundingError(p.GetName(), request.Currency, err)
+	}
+
+	result.Exchange = p.GetName()
+	result.Currency = common.StringToUpper(request.Currency)
+	result.Address = request.Address
+	result.Amount = request.Amount
+	return result, nil
+}
+
+//UpdatePairInfo : Populates the pair metadata table for the available pairs.
+//Poloniex trades every pair with eight decimals, and a pair is halted while
+//either of its currencies is disabled, frozen or delisted
+func (p *Poloniex) UpdatePairInfo() error {
+	currencies, err := p.GetCurrencies()
+	if err != nil {
+		return err
+	}
+
+	var info []exchange.PairInfo
+	for _, x := range p.AvailablePairs {
+		if !common.StringContains(x, "_") {
+			continue
+		}
+		currencyPair := pair.NewCurrencyPairDelimiter(x, "_")
+		status := exchange.PairStatusTrading
+		if !p.isCurrencyTradable(currencies, currencyPair.GetFirstCurrency().String()) ||
+			!p.isCurrencyTradable(currencies, currencyPair.GetSecondCurrency().String()) {
+			status = exchange.PairStatusHalted
+		}
+		info = append(info, exchange.PairInfo{
+			Pair:           currencyPair,
+			AmountStep:     exchange.GetPrecisionStep(8),
+			PriceTick:      exchange.GetPrecisionStep(8),
+			QuotePrecision: 8,
+			Status:         status,
+		})
+	}
+	p.SetPairInfo(info)
+	return nil
+}
+
+func (p *Poloniex) isCurrencyTradable(currencies map[string]PoloniexCurrencies, currency string) bool {
+	x, ok := currencies[common.StringToUpper(currency)]
+	if !ok {
+		return false
+	}
+	return x.Disabled == 0 && x.Frozen == 0 && x.Delisted == 0
+}
+
+//GetHistoricCandles : Retrieves candles for a pair. Poloniex returns the whole
+//range in a single response
+func (p *Poloniex) GetHistoricCandles(currencyPair pair.CurrencyPair, start, end time.Time, interval time.Duration) (exchange.CandleSeries, error) {
+	err := exchange.ValidateCandleRange(start, end, interval)
+	if err != nil {
+		return exchange.CandleSeries{}, err
+	}
+
+	period := int64(interval / time.Second)
+	switch period {
+	case 300, 900, 1800, 7200, 14400, 86400:
+	default:
+		return exchange.CandleSeries{}, errors.New(exchange.ErrCandleIntervalNotSupported)
+	}
+
+	chartData, err := p.GetChartData(p.formatOrderPair(currencyPair), strconv.FormatInt(start.Unix(), 10),
+		strconv.FormatInt(end.Unix(), 10), strconv.FormatInt(period, 10))
+	if err != nil {
+		return exchange.CandleSeries{}, err
+	}
+
+	var candles []exchange.Candle
+	for _, x := range chartData {
+		if x.Date == 0 {
+			continue
+		}
+		candles = append(candles, exchange.Candle{
+			Time:   time.Unix(int64(x.Date), 0),
+			Open:   x.Open,
+			High:   x.High,
+			Low:    x.Low,
+			Close:  x.Close,
+			Volume: x.QuoteVolume,
+		})
+	}
+	return exchange.NewCandleSeries(p.GetName(), currencyPair, start, end, interval, candles), nil
+}
+
+//GetRecentTrades : Retrieves the latest public trades for a pair
+func (p *Poloniex) GetRecentTrades(currencyPair pair.CurrencyPair) ([]trades.Trade, error) {
+	response, err := p.GetTradeHistory(p.formatOrderPair(currencyPair), "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []trades.Trade
+	for _, x := range response {
+		trade := trades.Trade{
+			Pair:    currencyPair,
+			TradeID: strconv.FormatInt(x.TradeID, 10),
+			Side:    trades.TRADE_SIDE_BUY,
+			Price:   x.Rate,
+			Amount:  x.Amount,
+		}
+		if x.Type == "sell" {
+			trade.Side = trades.TRADE_SIDE_SELL
+		}
+		trade.Timestamp, _ = time.Parse("2006-01-02 15:04:05", x.Date)
+		result = append(result, trade)
+	}
+
+	sort.Sort(trades.ByTimestamp(result))
+	trades.ProcessTrades(p.GetName(), currencyPair, result)
+	return result, nil
+}
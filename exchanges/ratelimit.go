@@ -0,0 +1,185 @@
+package exchange
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/champii/gocryptotrader/config"
+)
+
+const (
+	RATE_LIMIT_DEFAULT_BURST = 1
+)
+
+var (
+	polling    = make(map[string]bool)
+	pollingMtx sync.Mutex
+)
+
+//RateLimiter : Token bucket refilled with one token every interval and holding
+//up to burst tokens. Requests which arrive while the bucket is empty reserve a
+//future token and queue in Wait
+type RateLimiter struct {
+	mtx      sync.Mutex
+	interval time.Duration
+	burst    int
+	tokens   float64
+	last     time.Time
+}
+
+//NewRateLimiter returns a full limiter allowing the supplied requests per
+//minute, of which burst may be sent at once. No limiter is returned when
+//requestsPerMinute is not positive
+func NewRateLimiter(requestsPerMinute, burst int) *RateLimiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{interval: time.Minute / time.Duration(requestsPerMinute), burst: burst, tokens: float64(burst)}
+}
+
+//GetInterval returns the minimum time between two requests
+func (r *RateLimiter) GetInterval() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return r.interval
+}
+
+//GetBurst returns the number of requests which may be sent at once
+func (r *RateLimiter) GetBurst() int {
+	if r == nil {
+		return 0
+	}
+	return r.burst
+}
+
+//Wait takes a token, or reserves the next one to be refilled and sleeps until
+//it is. The time spent waiting is returned. A nil limiter never waits
+func (r *RateLimiter) Wait() time.Duration {
+	if r == nil {
+		return 0
+	}
+
+	r.mtx.Lock()
+	now := time.Now()
+	if !r.last.IsZero() {
+		r.tokens += float64(now.Sub(r.last)) / float64(r.interval)
+		if r.tokens > float64(r.burst) {
+			r.tokens = float64(r.burst)
+		}
+	}
+	r.last = now
+	r.tokens--
+
+	var wait time.Duration
+	if r.tokens < 0 {
+		wait = time.Duration(-r.tokens * float64(r.interval))
+	}
+	r.mtx.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return wait
+}
+
+//SetRateLimit sets the requests per minute allowed on the authenticated and
+//unauthenticated endpoints, with the default burst. Zero removes the limit for
+//that endpoint type
+func (e *ExchangeBase) SetRateLimit(authRequestsPerMinute, unauthRequestsPerMinute int) {
+	e.authRateLimiter = NewRateLimiter(authRequestsPerMinute, RATE_LIMIT_DEFAULT_BURST)
+	e.unauthRateLimiter = NewRateLimiter(unauthRequestsPerMinute, RATE_LIMIT_DEFAULT_BURST)
+}
+
+//UpdateRateLimit replaces the default rate limits and burst with the ones set
+//in the exchange config, if any
+func (e *ExchangeBase) UpdateRateLimit(exch config.ExchangeConfig) {
+	if exch.RequestBurst <= 0 && exch.AuthenticatedRequestsPerMinute <= 0 && exch.UnauthenticatedRequestsPerMinute <= 0 {
+		return
+	}
+
+	burst := exch.RequestBurst
+	if burst <= 0 {
+		burst = RATE_LIMIT_DEFAULT_BURST
+	}
+
+	auth, unauth := e.GetRateLimit()
+	if exch.AuthenticatedRequestsPerMinute > 0 {
+		e.authRateLimiter = NewRateLimiter(exch.AuthenticatedRequestsPerMinute, burst)
+	} else if auth > 0 {
+		e.authRateLimiter = NewRateLimiter(int(time.Minute/auth), burst)
+	}
+
+	if exch.UnauthenticatedRequestsPerMinute > 0 {
+		e.unauthRateLimiter = NewRateLimiter(exch.UnauthenticatedRequestsPerMinute, burst)
+	} else if unauth > 0 {
+		e.unauthRateLimiter = NewRateLimiter(int(time.Minute/unauth), burst)
+	}
+}
+
+//GetRateLimit returns the minimum time between two requests on the
+//authenticated and unauthenticated endpoints
+func (e *ExchangeBase) GetRateLimit() (auth, unauth time.Duration) {
+	return e.authRateLimiter.GetInterval(), e.unauthRateLimiter.GetInterval()
+}
+
+//WaitRateLimit queues until the rate limiter of the endpoint type allows the
+//next request. Authenticated requests must wait before generating their nonce,
+//otherwise queued requests could reach the exchange with out of order nonces
+func (e *ExchangeBase) WaitRateLimit(authenticated bool) {
+	if e.isReplayedFast() {
+		return
+	}
+
+	limiter := e.unauthRateLimiter
+	endpoint := "unauthenticated"
+	if authenticated {
+		limiter = e.authRateLimiter
+		endpoint = "authenticated"
+	}
+
+	wait := limiter.Wait()
+	if wait > 0 && e.Verbose {
+		log.Printf("%s %s request rate limited, waited %s.\n", e.Name, endpoint, wait)
+	}
+}
+
+//Poll runs poll in a goroutine unless the previous poll of key is still
+//running. A running poll is queued on the rate limiter, so starting another
+//would only grow the queue, and the cycle is skipped instead
+func (e *ExchangeBase) Poll(key string, poll func()) {
+	name := e.Name + " " + key
+	pollingMtx.Lock()
+	if polling[name] {
+		pollingMtx.Unlock()
+		if e.Verbose {
+			log.Printf("%s poll still running, skipping cycle.\n", name)
+		}
+		return
+	}
+	polling[name] = true
+	pollingMtx.Unlock()
+
+	go func() {
+		defer func() {
+			pollingMtx.Lock()
+			delete(polling, name)
+			pollingMtx.Unlock()
+		}()
+		poll()
+	}()
+}
+
+//SendHTTPGetRequest waits for the unauthenticated rate limiter and sends the
+//GET request
+func (e *ExchangeBase) SendHTTPGetRequest(path string, jsonDecode bool, result interface{}) error {
+	e.WaitRateLimit(false)
+	err := e.GetRequester().SendHTTPGetRequest(path, jsonDecode, result)
+	RecordRESTResult(e.Name, err)
+	return err
+}
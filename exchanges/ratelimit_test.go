@@ -0,0 +1,129 @@
+package exchange
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/config"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	if NewRateLimiter(0, 1) != nil {
+		t.Error("Test Failed - NewRateLimiter() returned a limiter without a limit")
+	}
+
+	var limiter *RateLimiter
+	if limiter.Wait() != 0 {
+		t.Error("Test Failed - Wait() waited on a nil limiter")
+	}
+
+	if NewRateLimiter(60, 1).GetInterval() != time.Second {
+		t.Error("Test Failed - NewRateLimiter() incorrect interval")
+	}
+
+	if NewRateLimiter(60, 0).GetBurst() != 1 {
+		t.Error("Test Failed - NewRateLimiter() incorrect burst")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := NewRateLimiter(1200, 1)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			limiter.Wait()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 4*limiter.GetInterval() {
+		t.Errorf("Test Failed - Wait() did not queue requests, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := NewRateLimiter(60, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		limiter.Wait()
+	}
+	if elapsed := time.Since(start); elapsed > limiter.GetInterval()/2 {
+		t.Errorf("Test Failed - Wait() queued requests within the burst, took %s", elapsed)
+	}
+
+	limiter = NewRateLimiter(6000, 3)
+	for i := 0; i < 3; i++ {
+		limiter.Wait()
+	}
+	if wait := limiter.Wait(); wait <= 0 {
+		t.Error("Test Failed - Wait() did not queue a request beyond the burst")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	e := ExchangeBase{Name: "Poll Test"}
+	var polls int32
+	release := make(chan struct{})
+	done := make(chan struct{}, 3)
+	poll := func() {
+		atomic.AddInt32(&polls, 1)
+		<-release
+		done <- struct{}{}
+	}
+
+	e.Poll("BTCUSD", poll)
+	e.Poll("BTCUSD", poll)
+	e.Poll("LTCUSD", poll)
+	close(release)
+	<-done
+	<-done
+
+	if polls != 2 {
+		t.Errorf("Test Failed - Poll() expected 2 polls while the first was running, received %d", polls)
+	}
+
+	for i := 0; i < 100; i++ {
+		pollingMtx.Lock()
+		running := polling["Poll Test BTCUSD"] || polling["Poll Test LTCUSD"]
+		pollingMtx.Unlock()
+		if !running {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	e.Poll("BTCUSD", poll)
+	<-done
+	if atomic.LoadInt32(&polls) != 3 {
+		t.Error("Test Failed - Poll() skipped a poll after the previous one finished")
+	}
+}
+
+func TestUpdateRateLimit(t *testing.T) {
+	var e ExchangeBase
+	e.SetRateLimit(60, 120)
+
+	auth, unauth := e.GetRateLimit()
+	if auth != time.Second || unauth != time.Second/2 {
+		t.Error("Test Failed - SetRateLimit() incorrect intervals")
+	}
+
+	e.UpdateRateLimit(config.ExchangeConfig{AuthenticatedRequestsPerMinute: 30})
+	auth, unauth = e.GetRateLimit()
+	if auth != 2*time.Second || unauth != time.Second/2 {
+		t.Error("Test Failed - UpdateRateLimit() incorrect intervals")
+	}
+
+	e.UpdateRateLimit(config.ExchangeConfig{RequestBurst: 5})
+	auth, unauth = e.GetRateLimit()
+	if auth != 2*time.Second || unauth != time.Second/2 || e.authRateLimiter.GetBurst() != 5 || e.unauthRateLimiter.GetBurst() != 5 {
+		t.Error("Test Failed - UpdateRateLimit() incorrect burst")
+	}
+}
@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/config"
+)
+
+//SetRequester sets the requester used for the exchange HTTP requests. A nil
+//requester restores common.DefaultRequester
+func (e *ExchangeBase) SetRequester(requester *common.Requester) {
+	e.requester = requester
+}
+
+//GetRequester returns the requester used for the exchange HTTP requests
+func (e *ExchangeBase) GetRequester() *common.Requester {
+	if e.requester == nil {
+		return common.DefaultRequester
+	}
+	return e.requester
+}
+
+//UpdateHTTPSettings applies the endpoint overrides and the HTTP client
+//settings of the exchange config. The exchange keeps the shared requester when
+//the config does not change its timeout, retries or proxy. The recording or
+//replay set in the config is started last
+func (e *ExchangeBase) UpdateHTTPSettings(exch config.ExchangeConfig) error {
+	if exch.APIUrl != "" {
+		e.APIUrl = exch.APIUrl
+	}
+
+	if exch.WebsocketURL != "" {
+		e.WebsocketURL = exch.WebsocketURL
+	}
+
+	if exch.HTTPTimeoutSeconds <= 0 && exch.HTTPRetries <= 0 && exch.HTTPProxy == "" {
+		return e.UpdateStreamSettings(exch)
+	}
+
+	timeout := common.HTTP_DEFAULT_TIMEOUT
+	if exch.HTTPTimeoutSeconds > 0 {
+		timeout = time.Duration(exch.HTTPTimeoutSeconds) * time.Second
+	}
+
+	retries := common.HTTP_DEFAULT_RETRIES
+	if exch.HTTPRetries > 0 {
+		retries = exch.HTTPRetries
+	}
+
+	requester := common.NewRequester(timeout, retries)
+	if exch.HTTPProxy != "" {
+		err := requester.SetProxy(exch.HTTPProxy)
+		if err != nil {
+			return err
+		}
+	}
+
+	e.requester = requester
+	return e.UpdateStreamSettings(exch)
+}
+
+//GetAPIUrl returns the REST endpoint set from the config, or defaultURL when
+//none was set
+func (e *ExchangeBase) GetAPIUrl(defaultURL string) string {
+	if e.APIUrl == "" {
+		return defaultURL
+	}
+	return e.APIUrl
+}
+
+//GetWebsocketURL returns the websocket endpoint set from the config, or
+//defaultURL when none was set
+func (e *ExchangeBase) GetWebsocketURL(defaultURL string) string {
+	if e.WebsocketURL == "" {
+		return defaultURL
+	}
+	return e.WebsocketURL
+}
+
+//SendHTTPRequest sends the request with the exchange requester. Authenticated
+//requests must call WaitRateLimit before generating their nonce
+func (e *ExchangeBase) SendHTTPRequest(method, path string, headers map[string]string, body io.Reader) (string, error) {
+	resp, _, err := e.SendHTTPRequestWithHeaders(method, path, headers, body)
+	return resp, err
+}
+
+//SendHTTPRequestWithHeaders sends the request as SendHTTPRequest and also
+//returns the response headers
+func (e *ExchangeBase) SendHTTPRequestWithHeaders(method, path string, headers map[string]string, body io.Reader) (string, http.Header, error) {
+	resp, responseHeaders, err := e.GetRequester().SendHTTPRequestWithHeaders(method, path, headers, body)
+	RecordRESTResult(e.Name, err)
+	return resp, responseHeaders, err
+}
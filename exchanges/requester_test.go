@@ -0,0 +1,40 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/config"
+)
+
+func TestUpdateHTTPSettings(t *testing.T) {
+	var e ExchangeBase
+	if e.GetRequester() != common.DefaultRequester {
+		t.Error("Test Failed - GetRequester() did not return the default requester")
+	}
+
+	if e.GetAPIUrl("https://default") != "https://default" || e.GetWebsocketURL("wss://default") != "wss://default" {
+		t.Error("Test Failed - GetAPIUrl() did not return the default endpoint")
+	}
+
+	err := e.UpdateHTTPSettings(config.ExchangeConfig{APIUrl: "http://127.0.0.1", WebsocketURL: "ws://127.0.0.1"})
+	if err != nil || e.GetRequester() != common.DefaultRequester {
+		t.Error("Test Failed - UpdateHTTPSettings() replaced the default requester")
+	}
+
+	if e.GetAPIUrl("https://default") != "http://127.0.0.1" || e.GetWebsocketURL("wss://default") != "ws://127.0.0.1" {
+		t.Error("Test Failed - UpdateHTTPSettings() did not override the endpoints")
+	}
+
+	err = e.UpdateHTTPSettings(config.ExchangeConfig{HTTPTimeoutSeconds: 5, HTTPRetries: 4})
+	requester := e.GetRequester()
+	if err != nil || requester.HTTPClient.Timeout != 5*time.Second || requester.MaxRetries != 4 {
+		t.Error("Test Failed - UpdateHTTPSettings() did not apply the HTTP settings")
+	}
+
+	err = e.UpdateHTTPSettings(config.ExchangeConfig{HTTPProxy: "proxy"})
+	if err == nil {
+		t.Error("Test Failed - UpdateHTTPSettings() accepted an invalid proxy")
+	}
+}
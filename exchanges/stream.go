@@ -0,0 +1,523 @@
+package exchange
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/champii/gocryptotrader/common"
+	"github.com/champii/gocryptotrader/config"
+	"github.com/champii/gocryptotrader/exchanges/orderbook"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+)
+
+const (
+	STREAM_SOURCE_WEBSOCKET = "websocket"
+	STREAM_SOURCE_REST      = "rest"
+	STREAM_FILE_EXTENSION   = ".json.gz"
+	STREAM_FILE_TIME_FORMAT = "20060102T150405"
+
+	ErrStreamReplayNoResponse = "%s replay has no recorded response for %s %s."
+)
+
+const (
+	STREAM_FLUSH_INTERVAL = time.Second
+)
+
+var (
+	streamRecorders = make(map[string]*StreamRecorder)
+	streamReplayers = make(map[string]*StreamReplayer)
+	streamMtx       sync.Mutex
+)
+
+//StreamEntry : Raw websocket message, websocket state change or REST response
+//of a recording. Websocket messages received through a client library carry
+//the library event name as Channel
+type StreamEntry struct {
+	Timestamp   time.Time   `json:"timestamp"`
+	Source      string      `json:"source"`
+	Channel     string      `json:"channel,omitempty"`
+	State       string      `json:"state,omitempty"`
+	MessageType int         `json:"messageType,omitempty"`
+	Method      string      `json:"method,omitempty"`
+	URL         string      `json:"url,omitempty"`
+	StatusCode  int         `json:"statusCode,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Data        []byte      `json:"data,omitempty"`
+}
+
+//StreamRecorder : Writes the entries of an exchange to a gzip compressed file
+//holding one JSON entry per line. The file is flushed at most every
+//STREAM_FLUSH_INTERVAL, so the latest entries only reach it on Close. Methods
+//of a nil recorder do nothing
+type StreamRecorder struct {
+	mtx       sync.Mutex
+	fileName  string
+	file      *os.File
+	writer    *gzip.Writer
+	encoder   *json.Encoder
+	lastFlush time.Time
+	closed    bool
+}
+
+//StreamReplayer : Recording of an exchange served in place of the network.
+//Websocket messages are replayed in order, at their original pace when
+//Realtime is set, while REST responses are served when requested
+type StreamReplayer struct {
+	ExchangeName string
+	Realtime     bool
+
+	mtx       sync.Mutex
+	websocket []StreamEntry
+	position  int
+	rest      []StreamEntry
+	restUsed  []bool
+	started   time.Time
+	now       time.Time
+}
+
+type streamTransport struct {
+	base     *common.Requester
+	next     http.RoundTripper
+	recorder *StreamRecorder
+	replayer *StreamReplayer
+}
+
+//NewStreamRecorder creates the recording file of the exchange in directory,
+//named after the exchange and the UTC start time
+func NewStreamRecorder(directory, exchangeName string) (*StreamRecorder, error) {
+	err := os.MkdirAll(directory, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.Replace(common.StringToLower(exchangeName), " ", "_", -1)
+	fileName := filepath.Join(directory, fmt.Sprintf("%s_%s%s", name, time.Now().UTC().Format(STREAM_FILE_TIME_FORMAT), STREAM_FILE_EXTENSION))
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := gzip.NewWriter(file)
+	return &StreamRecorder{
+		fileName:  fileName,
+		file:      file,
+		writer:    writer,
+		encoder:   json.NewEncoder(writer),
+		lastFlush: time.Now(),
+	}, nil
+}
+
+//GetFileName returns the path of the recording file
+func (r *StreamRecorder) GetFileName() string {
+	if r == nil {
+		return ""
+	}
+	return r.fileName
+}
+
+//Record writes the entry, stamping it with the current time when it has none
+func (r *StreamRecorder) Record(entry StreamEntry) error {
+	if r == nil {
+		return nil
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	err := r.encoder.Encode(entry)
+	if err != nil {
+		return err
+	}
+
+	if time.Since(r.lastFlush) >= STREAM_FLUSH_INTERVAL {
+		r.lastFlush = time.Now()
+		return r.writer.Flush()
+	}
+	return nil
+}
+
+//RecordWebsocket writes a raw websocket frame
+func (r *StreamRecorder) RecordWebsocket(messageType int, data []byte) {
+	r.record(StreamEntry{Source: STREAM_SOURCE_WEBSOCKET, MessageType: messageType, Data: data})
+}
+
+//RecordWebsocketEvent writes a websocket message received from a client
+//library as an event of channel
+func (r *StreamRecorder) RecordWebsocketEvent(channel string, data []byte) {
+	r.record(StreamEntry{Source: STREAM_SOURCE_WEBSOCKET, Channel: channel, Data: data})
+}
+
+//RecordWebsocketState writes the connection state changes replayed as
+//reconnects, other states are dropped
+func (r *StreamRecorder) RecordWebsocketState(state string) {
+	if state != WEBSOCKET_STATE_CONNECTED && state != WEBSOCKET_STATE_DISCONNECTED {
+		return
+	}
+	r.record(StreamEntry{Source: STREAM_SOURCE_WEBSOCKET, State: state})
+}
+
+//Close flushes and closes the recording file. Later entries are dropped
+func (r *StreamRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	err := r.writer.Close()
+	if err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+func (r *StreamRecorder) record(entry StreamEntry) {
+	err := r.Record(entry)
+	if err != nil {
+		log.Printf("Unable to record to %s. Error: %s\n", r.fileName, err)
+	}
+}
+
+//NewStreamReplayer loads the recording file of the exchange
+func NewStreamReplayer(exchangeName, fileName string, realtime bool) (*StreamReplayer, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	replayer := &StreamReplayer{ExchangeName: exchangeName, Realtime: realtime}
+	decoder := json.NewDecoder(reader)
+	for {
+		entry := StreamEntry{}
+		err := decoder.Decode(&entry)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Source == STREAM_SOURCE_REST {
+			replayer.rest = append(replayer.rest, entry)
+			replayer.restUsed = append(replayer.restUsed, false)
+		} else {
+			replayer.websocket = append(replayer.websocket, entry)
+		}
+	}
+	return replayer, nil
+}
+
+//NextWebsocket returns the next recorded websocket entry, io.EOF once the
+//recording ends. In realtime mode it waits until the entry is due relative to
+//the first one, returning early with the error of a cancelled ctx
+func (r *StreamReplayer) NextWebsocket(ctx context.Context) (StreamEntry, error) {
+	r.mtx.Lock()
+	if r.position >= len(r.websocket) {
+		r.mtx.Unlock()
+		return StreamEntry{}, io.EOF
+	}
+
+	entry := r.websocket[r.position]
+	r.position++
+	if r.started.IsZero() {
+		r.started = time.Now()
+	}
+	delay := entry.Timestamp.Sub(r.websocket[0].Timestamp) - time.Since(r.started)
+	r.mtx.Unlock()
+
+	if r.Realtime && delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return StreamEntry{}, ctx.Err()
+		case <-timer.C:
+		}
+	} else if ctx.Err() != nil {
+		return StreamEntry{}, ctx.Err()
+	}
+
+	r.advance(entry.Timestamp)
+	return entry, nil
+}
+
+//NextREST returns the oldest unused response recorded for the request. It
+//falls back to the oldest unused response of the same endpoint, as signed
+//requests differ from their recording by their nonce
+func (r *StreamReplayer) NextREST(method, requestURL string) (StreamEntry, error) {
+	endpoint := getStreamEndpoint(requestURL)
+
+	r.mtx.Lock()
+	match := -1
+	for i, x := range r.rest {
+		if r.restUsed[i] || x.Method != method {
+			continue
+		}
+
+		if x.URL == requestURL {
+			match = i
+			break
+		}
+
+		if match < 0 && getStreamEndpoint(x.URL) == endpoint {
+			match = i
+		}
+	}
+
+	if match < 0 {
+		r.mtx.Unlock()
+		return StreamEntry{}, fmt.Errorf(ErrStreamReplayNoResponse, r.ExchangeName, method, requestURL)
+	}
+	r.restUsed[match] = true
+	entry := r.rest[match]
+	r.mtx.Unlock()
+
+	r.advance(entry.Timestamp)
+	return entry, nil
+}
+
+//Replay passes the recorded websocket messages to handler until the recording
+//ends or ctx is cancelled. It is used by the clients built on a websocket
+//library, recorded connection states only update the exchange health
+func (r *StreamReplayer) Replay(ctx context.Context, handler func(entry StreamEntry)) {
+	log.Printf("%s Replaying websocket recording.\n", r.ExchangeName)
+	for {
+		entry, err := r.NextWebsocket(ctx)
+		if err != nil {
+			break
+		}
+
+		if entry.State != "" {
+			RecordWebsocketState(r.ExchangeName, entry.State)
+			continue
+		}
+		handler(entry)
+	}
+
+	RecordWebsocketState(r.ExchangeName, WEBSOCKET_STATE_STOPPED)
+	log.Printf("%s Websocket replay finished.\n", r.ExchangeName)
+}
+
+//Now returns the time of the latest replayed entry, used in place of the
+//current time so replayed market data carries its recorded time
+func (r *StreamReplayer) Now() time.Time {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.now.IsZero() {
+		return time.Now()
+	}
+	return r.now
+}
+
+func (r *StreamReplayer) advance(timestamp time.Time) {
+	r.mtx.Lock()
+	if timestamp.After(r.now) {
+		r.now = timestamp
+	}
+	r.mtx.Unlock()
+}
+
+func getStreamEndpoint(requestURL string) string {
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return requestURL
+	}
+	return parsed.Scheme + "://" + parsed.Host + parsed.Path
+}
+
+//RoundTrip serves the request from the replay, or sends it and records the
+//response
+func (t *streamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.replayer != nil {
+		entry, err := t.replayer.NextREST(req.Method, req.URL.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Error != "" {
+			return nil, errors.New(entry.Error)
+		}
+
+		header := entry.Header
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+			StatusCode:    entry.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(entry.Data)),
+			ContentLength: int64(len(entry.Data)),
+			Request:       req,
+		}, nil
+	}
+
+	entry := StreamEntry{Source: STREAM_SOURCE_REST, Method: req.Method, URL: req.URL.String()}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+		t.recorder.record(entry)
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		entry.Error = err.Error()
+		t.recorder.record(entry)
+		return nil, err
+	}
+
+	entry.StatusCode = resp.StatusCode
+	entry.Header = resp.Header
+	entry.Data = data
+	t.recorder.record(entry)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+//GetStreamRecorder returns the recorder of the exchange, nil when it is not
+//recorded
+func GetStreamRecorder(exchangeName string) *StreamRecorder {
+	streamMtx.Lock()
+	defer streamMtx.Unlock()
+	return streamRecorders[exchangeName]
+}
+
+//GetStreamReplayer returns the replayer of the exchange, nil when it is not
+//replayed
+func GetStreamReplayer(exchangeName string) *StreamReplayer {
+	streamMtx.Lock()
+	defer streamMtx.Unlock()
+	return streamReplayers[exchangeName]
+}
+
+//CloseStreamRecorders closes the recording file of every exchange
+func CloseStreamRecorders() {
+	streamMtx.Lock()
+	defer streamMtx.Unlock()
+
+	for name, x := range streamRecorders {
+		err := x.Close()
+		if err != nil {
+			log.Printf("%s Unable to close recording %s. Error: %s\n", name, x.GetFileName(), err)
+		}
+		delete(streamRecorders, name)
+	}
+}
+
+//UpdateStreamSettings starts the recording or the replay set in the exchange
+//config, replacing the previous one. A replay takes precedence over a
+//recording. The REST requests of a replayed exchange are not retried, and are
+//not rate limited unless it replays in realtime
+func (e *ExchangeBase) UpdateStreamSettings(exch config.ExchangeConfig) error {
+	streamMtx.Lock()
+	defer streamMtx.Unlock()
+
+	if recorder, ok := streamRecorders[e.Name]; ok {
+		recorder.Close()
+		delete(streamRecorders, e.Name)
+	}
+	delete(streamReplayers, e.Name)
+	ticker.SetClock(e.Name, nil)
+	orderbook.SetClock(e.Name, nil)
+
+	if e.requester != nil {
+		if transport, ok := e.requester.HTTPClient.Transport.(*streamTransport); ok {
+			e.requester = transport.base
+		}
+	}
+
+	if exch.ReplayFile != "" {
+		replayer, err := NewStreamReplayer(e.Name, exch.ReplayFile, exch.ReplayRealtime)
+		if err != nil {
+			return err
+		}
+
+		streamReplayers[e.Name] = replayer
+		ticker.SetClock(e.Name, replayer.Now)
+		orderbook.SetClock(e.Name, replayer.Now)
+		e.setStreamTransport(&streamTransport{replayer: replayer})
+		e.requester.MaxRetries = 0
+		log.Printf("%s Replaying recording %s.\n", e.Name, exch.ReplayFile)
+		return nil
+	}
+
+	if exch.RecordDirectory != "" {
+		recorder, err := NewStreamRecorder(exch.RecordDirectory, e.Name)
+		if err != nil {
+			return err
+		}
+
+		streamRecorders[e.Name] = recorder
+		e.setStreamTransport(&streamTransport{recorder: recorder})
+		log.Printf("%s Recording to %s.\n", e.Name, recorder.GetFileName())
+	}
+	return nil
+}
+
+//setStreamTransport replaces the requester by a copy sending its requests
+//through transport
+func (e *ExchangeBase) setStreamTransport(transport *streamTransport) {
+	base := e.GetRequester()
+	transport.base = e.requester
+	transport.next = base.HTTPClient.Transport
+	if transport.next == nil {
+		transport.next = http.DefaultTransport
+	}
+
+	e.requester = &common.Requester{
+		HTTPClient:   &http.Client{Timeout: base.HTTPClient.Timeout, Transport: transport},
+		MaxRetries:   base.MaxRetries,
+		RetryBackoff: base.RetryBackoff,
+	}
+}
+
+//isReplayedFast returns whether the exchange replays a recording as fast as
+//possible
+func (e *ExchangeBase) isReplayedFast() bool {
+	replayer := GetStreamReplayer(e.Name)
+	return replayer != nil && !replayer.Realtime
+}
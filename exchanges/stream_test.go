@@ -0,0 +1,173 @@
+package exchange
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/config"
+	"github.com/champii/gocryptotrader/currency/pair"
+	"github.com/champii/gocryptotrader/exchanges/ticker"
+	"github.com/gorilla/websocket"
+)
+
+func receiveStreamMessages(t *testing.T, messages chan string, count int) []string {
+	result := []string{}
+	for len(result) < count {
+		select {
+		case x := <-messages:
+			result = append(result, x)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Test Failed - expected %d websocket messages, received %v", count, result)
+		}
+	}
+	return result
+}
+
+func TestStreamRecordReplay(t *testing.T) {
+	directory, err := ioutil.TempDir("", "stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("CB-AFTER", r.URL.Query().Get("pair"))
+		w.Write([]byte(r.URL.Path + "?" + r.URL.RawQuery))
+	}))
+	done := make(chan bool)
+	server, url := newWebsocketTestServer(func(connection int, conn *websocket.Conn) {
+		conn.WriteMessage(websocket.TextMessage, []byte("one"))
+		conn.WriteMessage(websocket.TextMessage, []byte("two"))
+		<-done
+	})
+
+	e := ExchangeBase{Name: "streamtest"}
+	err = e.UpdateStreamSettings(config.ExchangeConfig{RecordDirectory: directory})
+	if err != nil {
+		t.Fatalf("Test Failed - UpdateStreamSettings() error: %s", err)
+	}
+	fileName := GetStreamRecorder(e.Name).GetFileName()
+
+	for _, x := range []string{"/ticker?pair=BTCUSD", "/private?nonce=1"} {
+		_, err = e.SendHTTPRequest("GET", rest.URL+x, nil, nil)
+		if err != nil {
+			t.Fatalf("Test Failed - SendHTTPRequest() error: %s", err)
+		}
+	}
+
+	messages := make(chan string, 10)
+	w := NewWebsocketConnection(e.Name, url)
+	w.OnMessage = func(messageType int, data []byte) {
+		messages <- string(data)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan bool)
+	go func() {
+		w.Run(ctx)
+		close(stopped)
+	}()
+	receiveStreamMessages(t, messages, 2)
+	cancel()
+	<-stopped
+	close(done)
+	server.Close()
+	rest.Close()
+
+	err = e.UpdateStreamSettings(config.ExchangeConfig{ReplayFile: fileName})
+	if err != nil {
+		t.Fatalf("Test Failed - UpdateStreamSettings() replay error: %s", err)
+	}
+	defer e.UpdateStreamSettings(config.ExchangeConfig{})
+
+	resp, headers, err := e.SendHTTPRequestWithHeaders("GET", rest.URL+"/ticker?pair=BTCUSD", nil, nil)
+	if err != nil || resp != "/ticker?pair=BTCUSD" {
+		t.Errorf("Test Failed - SendHTTPRequestWithHeaders() replay expected the recorded response, received %s %v", resp, err)
+	}
+	if headers.Get("CB-AFTER") != "BTCUSD" {
+		t.Errorf("Test Failed - SendHTTPRequestWithHeaders() replay expected the recorded headers, received %v", headers)
+	}
+
+	resp, err = e.SendHTTPRequest("GET", rest.URL+"/private?nonce=2", nil, nil)
+	if err != nil || resp != "/private?nonce=1" {
+		t.Errorf("Test Failed - SendHTTPRequest() replay expected the endpoint response, received %s %v", resp, err)
+	}
+
+	_, err = e.SendHTTPRequest("GET", rest.URL+"/ticker?pair=BTCUSD", nil, nil)
+	if err == nil {
+		t.Error("Test Failed - SendHTTPRequest() replay served a response twice")
+	}
+
+	connects := 0
+	w = NewWebsocketConnection(e.Name, url)
+	w.OnConnect = func() error {
+		connects++
+		return w.Send([]byte("subscribe"))
+	}
+	w.OnMessage = func(messageType int, data []byte) {
+		if messageType != websocket.TextMessage {
+			t.Errorf("Test Failed - websocket replay message type incorrect: %d", messageType)
+		}
+		messages <- string(data)
+	}
+	w.Run(context.Background())
+
+	received := receiveStreamMessages(t, messages, 2)
+	if received[0] != "one" || received[1] != "two" {
+		t.Errorf("Test Failed - websocket replay expected the recorded messages, received %v", received)
+	}
+	if connects != 1 || w.GetState() != WEBSOCKET_STATE_STOPPED {
+		t.Errorf("Test Failed - websocket replay expected 1 connection, received %d in state %s", connects, w.GetState())
+	}
+
+	recorded := GetStreamReplayer(e.Name).Now()
+	p := pair.NewCurrencyPair("BTC", "USD")
+	ticker.ProcessTicker(e.Name, p, ticker.TickerPrice{Last: 1000})
+	price, err := ticker.GetTicker(e.Name, p)
+	if err != nil || !price.LastUpdated.Equal(recorded) {
+		t.Errorf("Test Failed - replayed ticker expected the recorded time %s, received %s", recorded, price.LastUpdated)
+	}
+}
+
+func TestStreamReplayRealtime(t *testing.T) {
+	directory, err := ioutil.TempDir("", "stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	recorder, err := NewStreamRecorder(directory, "Stream Test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	recorder.Record(StreamEntry{Timestamp: start, Source: STREAM_SOURCE_WEBSOCKET, Data: []byte("one")})
+	recorder.Record(StreamEntry{Timestamp: start.Add(200 * time.Millisecond), Source: STREAM_SOURCE_WEBSOCKET, Data: []byte("two")})
+	recorder.Close()
+
+	replayer, err := NewStreamReplayer("Stream Test", recorder.GetFileName(), true)
+	if err != nil {
+		t.Fatalf("Test Failed - NewStreamReplayer() error: %s", err)
+	}
+
+	replayed := time.Now()
+	received := []string{}
+	replayer.Replay(context.Background(), func(entry StreamEntry) {
+		received = append(received, string(entry.Data))
+	})
+	if len(received) != 2 || time.Since(replayed) < 200*time.Millisecond {
+		t.Errorf("Test Failed - Replay() realtime expected 2 messages over 200ms, received %v in %s", received, time.Since(replayed))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	replayer, _ = NewStreamReplayer("Stream Test", recorder.GetFileName(), true)
+	replayer.NextWebsocket(ctx)
+	if _, err = replayer.NextWebsocket(ctx); err == nil {
+		t.Error("Test Failed - NextWebsocket() did not stop on a cancelled context")
+	}
+}
@@ -3,22 +3,34 @@ package ticker
 import (
 	"errors"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/champii/gocryptotrader/common"
 	"github.com/champii/gocryptotrader/currency/pair"
 )
 
+const (
+	TICKER_SUBSCRIPTION_BUFFER = 100
+)
+
 var (
 	ErrTickerForExchangeNotFound = "Ticker for exchange does not exist."
 	ErrPrimaryCurrencyNotFound   = "Error primary currency for ticker not found."
 	ErrSecondaryCurrencyNotFound = "Error secondary currency for ticker not found."
 
-	Tickers []Ticker
+	tickers       = make(map[string]*Ticker)
+	tickerMtx     sync.RWMutex
+	subscriptions []*Subscription
+	subscribeMtx  sync.Mutex
+	clocks        = make(map[string]func() time.Time)
+	clockMtx      sync.RWMutex
 )
 
 type TickerPrice struct {
 	Pair         pair.CurrencyPair `json:"Pair"`
 	CurrencyPair string            `json:"CurrencyPair"`
+	ExchangeName string            `json:"ExchangeName"`
 	Last         float64           `json:"Last"`
 	High         float64           `json:"High"`
 	Low          float64           `json:"Low"`
@@ -26,6 +38,7 @@ type TickerPrice struct {
 	Ask          float64           `json:"Ask"`
 	Volume       float64           `json:"Volume"`
 	PriceATH     float64           `json:"PriceATH"`
+	LastUpdated  time.Time         `json:"LastUpdated"`
 }
 
 type Ticker struct {
@@ -33,6 +46,21 @@ type Ticker struct {
 	ExchangeName string
 }
 
+//Subscription : Receives the ticker price of an exchange pair on C whenever it
+//changes. An empty exchange name or pair matches every exchange or pair
+type Subscription struct {
+	ExchangeName string
+	Pair         pair.CurrencyPair
+	C            <-chan TickerPrice
+	c            chan TickerPrice
+	mtx          sync.Mutex
+	latest       map[string]TickerPrice
+	order        []string
+	delivering   bool
+	notify       chan struct{}
+	done         chan struct{}
+}
+
 func (t *Ticker) PriceToString(p pair.CurrencyPair, priceType string) string {
 	priceType = common.StringToLower(priceType)
 	switch priceType {
@@ -55,90 +83,287 @@ func (t *Ticker) PriceToString(p pair.CurrencyPair, priceType string) string {
 	}
 }
 
+func (t *Ticker) copy() Ticker {
+	result := Ticker{
+		ExchangeName: t.ExchangeName,
+		Price:        make(map[pair.CurrencyItem]map[pair.CurrencyItem]TickerPrice),
+	}
+	for x, y := range t.Price {
+		result.Price[x] = make(map[pair.CurrencyItem]TickerPrice)
+		for z, price := range y {
+			result.Price[x][z] = price
+		}
+	}
+	return result
+}
+
+//HasPriceChanged returns whether any of the quoted prices or the volume differ
+//from the previous ticker price
+func (t *TickerPrice) HasPriceChanged(previous TickerPrice) bool {
+	return t.Last != previous.Last || t.High != previous.High || t.Low != previous.Low ||
+		t.Bid != previous.Bid || t.Ask != previous.Ask || t.Volume != previous.Volume
+}
+
 func GetTicker(exchange string, p pair.CurrencyPair) (TickerPrice, error) {
-	ticker, err := GetTickerByExchange(exchange)
-	if err != nil {
-		return TickerPrice{}, err
+	tickerMtx.RLock()
+	defer tickerMtx.RUnlock()
+
+	ticker, ok := tickers[exchange]
+	if !ok {
+		return TickerPrice{}, errors.New(ErrTickerForExchangeNotFound)
 	}
 
-	if !FirstCurrencyExists(exchange, p.GetFirstCurrency()) {
+	first, ok := ticker.Price[p.GetFirstCurrency()]
+	if !ok {
 		return TickerPrice{}, errors.New(ErrPrimaryCurrencyNotFound)
 	}
 
-	if !SecondCurrencyExists(exchange, p) {
+	price, ok := first[p.GetSecondCurrency()]
+	if !ok {
 		return TickerPrice{}, errors.New(ErrSecondaryCurrencyNotFound)
 	}
-
-	return ticker.Price[p.GetFirstCurrency()][p.GetSecondCurrency()], nil
+	return price, nil
 }
 
+//GetTickerByExchange returns a snapshot of every stored ticker price of the
+//exchange. Later updates are not reflected in the returned ticker
 func GetTickerByExchange(exchange string) (*Ticker, error) {
-	for _, y := range Tickers {
-		if y.ExchangeName == exchange {
-			return &y, nil
-		}
+	tickerMtx.RLock()
+	defer tickerMtx.RUnlock()
+
+	ticker, ok := tickers[exchange]
+	if !ok {
+		return nil, errors.New(ErrTickerForExchangeNotFound)
 	}
-	return nil, errors.New(ErrTickerForExchangeNotFound)
+
+	result := ticker.copy()
+	return &result, nil
 }
 
 func FirstCurrencyExists(exchange string, currency pair.CurrencyItem) bool {
-	for _, y := range Tickers {
-		if y.ExchangeName == exchange {
-			if _, ok := y.Price[currency]; ok {
-				return true
-			}
-		}
+	tickerMtx.RLock()
+	defer tickerMtx.RUnlock()
+
+	if ticker, ok := tickers[exchange]; ok {
+		_, ok = ticker.Price[currency]
+		return ok
 	}
 	return false
 }
 
 func SecondCurrencyExists(exchange string, p pair.CurrencyPair) bool {
-	for _, y := range Tickers {
-		if y.ExchangeName == exchange {
-			if _, ok := y.Price[p.GetFirstCurrency()]; ok {
-				if _, ok := y.Price[p.GetFirstCurrency()][p.GetSecondCurrency()]; ok {
-					return true
-				}
-			}
+	tickerMtx.RLock()
+	defer tickerMtx.RUnlock()
+
+	if ticker, ok := tickers[exchange]; ok {
+		if first, ok := ticker.Price[p.GetFirstCurrency()]; ok {
+			_, ok = first[p.GetSecondCurrency()]
+			return ok
 		}
 	}
 	return false
 }
 
+//CreateNewTicker stores a new ticker for the exchange holding only the supplied
+//pair, replacing any ticker already stored for the exchange
 func CreateNewTicker(exchangeName string, p pair.CurrencyPair, tickerNew TickerPrice) Ticker {
-	ticker := Ticker{}
+	tickerMtx.Lock()
+	defer tickerMtx.Unlock()
+
+	ticker := &Ticker{}
 	ticker.ExchangeName = exchangeName
 	ticker.Price = make(map[pair.CurrencyItem]map[pair.CurrencyItem]TickerPrice)
 	sMap := make(map[pair.CurrencyItem]TickerPrice)
 	sMap[p.GetSecondCurrency()] = tickerNew
 	ticker.Price[p.GetFirstCurrency()] = sMap
-	Tickers = append(Tickers, ticker)
-	return ticker
+	tickers[exchangeName] = ticker
+	return ticker.copy()
+}
+
+//SetClock sets the clock stamping the exchange ticker prices processed without
+//an update time. A nil clock restores time.Now
+func SetClock(exchangeName string, clock func() time.Time) {
+	clockMtx.Lock()
+	defer clockMtx.Unlock()
+
+	if clock == nil {
+		delete(clocks, exchangeName)
+		return
+	}
+	clocks[exchangeName] = clock
+}
+
+func now(exchangeName string) time.Time {
+	clockMtx.RLock()
+	clock, ok := clocks[exchangeName]
+	clockMtx.RUnlock()
+
+	if !ok {
+		return time.Now()
+	}
+	return clock()
 }
 
+//ProcessTicker stores the ticker price of an exchange pair and pushes it to the
+//matching subscriptions when it differs from the stored price
 func ProcessTicker(exchangeName string, p pair.CurrencyPair, tickerNew TickerPrice) {
+	tickerNew.Pair = p
 	tickerNew.CurrencyPair = p.Pair().String()
-	if len(Tickers) == 0 {
-		CreateNewTicker(exchangeName, p, tickerNew)
-		//issue - not appending
-		return
-	} else {
-		ticker, err := GetTickerByExchange(exchangeName)
-		if err != nil {
-			CreateNewTicker(exchangeName, p, tickerNew)
+	tickerNew.ExchangeName = exchangeName
+	if tickerNew.LastUpdated.IsZero() {
+		tickerNew.LastUpdated = now(exchangeName)
+	}
+
+	tickerMtx.Lock()
+	ticker, ok := tickers[exchangeName]
+	if !ok {
+		ticker = &Ticker{
+			ExchangeName: exchangeName,
+			Price:        make(map[pair.CurrencyItem]map[pair.CurrencyItem]TickerPrice),
+		}
+		tickers[exchangeName] = ticker
+	}
+
+	if _, ok := ticker.Price[p.GetFirstCurrency()]; !ok {
+		ticker.Price[p.GetFirstCurrency()] = make(map[pair.CurrencyItem]TickerPrice)
+	}
+
+	previous, ok := ticker.Price[p.GetFirstCurrency()][p.GetSecondCurrency()]
+	ticker.Price[p.GetFirstCurrency()][p.GetSecondCurrency()] = tickerNew
+	tickerMtx.Unlock()
+
+	if !ok || tickerNew.HasPriceChanged(previous) {
+		publish(tickerNew)
+	}
+}
+
+//Subscribe returns a subscription receiving the ticker prices of the exchange
+//pair whenever they change. A subscriber which is not keeping up does not block
+//the exchange. Once its buffer is full only the latest undelivered price of
+//each exchange pair is kept, so it skips intermediate prices but always
+//receives the newest one
+func Subscribe(exchangeName string, p pair.CurrencyPair) *Subscription {
+	c := make(chan TickerPrice, TICKER_SUBSCRIPTION_BUFFER)
+	subscription := &Subscription{
+		ExchangeName: exchangeName,
+		Pair:         p,
+		C:            c,
+		c:            c,
+		latest:       make(map[string]TickerPrice),
+		notify:       make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+	go subscription.deliver()
+
+	subscribeMtx.Lock()
+	subscriptions = append(subscriptions, subscription)
+	subscribeMtx.Unlock()
+	return subscription
+}
+
+//Unsubscribe stops the pushes to the subscription and closes its channel
+func Unsubscribe(subscription *Subscription) {
+	subscribeMtx.Lock()
+	defer subscribeMtx.Unlock()
+
+	for i, x := range subscriptions {
+		if x == subscription {
+			subscriptions = append(subscriptions[:i], subscriptions[i+1:]...)
+			close(x.done)
+			return
+		}
+	}
+}
+
+func (s *Subscription) matches(price TickerPrice) bool {
+	if s.ExchangeName != "" && s.ExchangeName != price.ExchangeName {
+		return false
+	}
+
+	if s.Pair.GetFirstCurrency() != "" && s.Pair.GetFirstCurrency() != price.Pair.GetFirstCurrency() {
+		return false
+	}
+
+	if s.Pair.GetSecondCurrency() != "" && s.Pair.GetSecondCurrency() != price.Pair.GetSecondCurrency() {
+		return false
+	}
+	return true
+}
+
+//push sends the price to C while its buffer has room and nothing is waiting
+//for deliver. Otherwise it replaces the undelivered price of the exchange pair,
+//if any, and wakes up deliver
+func (s *Subscription) push(price TickerPrice) {
+	key := price.ExchangeName + " " + price.CurrencyPair
+	s.mtx.Lock()
+	if len(s.order) == 0 && !s.delivering {
+		select {
+		case s.c <- price:
+			s.mtx.Unlock()
 			return
+		default:
 		}
+	}
+
+	if _, ok := s.latest[key]; !ok {
+		s.order = append(s.order, key)
+	}
+	s.latest[key] = price
+	s.mtx.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+//pop returns the oldest undelivered exchange pair price, marking it as being
+//delivered
+func (s *Subscription) pop() (TickerPrice, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.delivering = len(s.order) > 0
+	if !s.delivering {
+		return TickerPrice{}, false
+	}
+	key := s.order[0]
+	s.order = s.order[1:]
+	price := s.latest[key]
+	delete(s.latest, key)
+	return price, true
+}
 
-		if FirstCurrencyExists(exchangeName, p.GetFirstCurrency()) {
-			if !SecondCurrencyExists(exchangeName, p) {
-				second := ticker.Price[p.GetFirstCurrency()]
-				second[p.GetSecondCurrency()] = tickerNew
-				ticker.Price[p.GetFirstCurrency()] = second
+//deliver sends the pushed prices to C until the subscription is cancelled,
+//then closes C
+func (s *Subscription) deliver() {
+	defer close(s.c)
+	for {
+		price, ok := s.pop()
+		if !ok {
+			select {
+			case <-s.notify:
+				continue
+			case <-s.done:
 				return
 			}
 		}
-		sMap := make(map[pair.CurrencyItem]TickerPrice)
-		sMap[p.GetSecondCurrency()] = tickerNew
-		ticker.Price[p.GetFirstCurrency()] = sMap
+
+		select {
+		case s.c <- price:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func publish(price TickerPrice) {
+	subscribeMtx.Lock()
+	defer subscribeMtx.Unlock()
+
+	for _, x := range subscriptions {
+		if x.matches(price) {
+			x.push(price)
+		}
 	}
 }
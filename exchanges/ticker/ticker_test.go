@@ -2,7 +2,9 @@ package ticker
 
 import (
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/champii/gocryptotrader/currency/pair"
 )
@@ -67,8 +69,7 @@ func TestGetTicker(t *testing.T) {
 		PriceATH:     1337,
 	}
 
-	bitfinexTicker := CreateNewTicker("bitfinex", newPair, priceStruct)
-	Tickers = append(Tickers, bitfinexTicker)
+	CreateNewTicker("bitfinex", newPair, priceStruct)
 
 	tickerPrice, err := GetTicker("bitfinex", newPair)
 	if err != nil {
@@ -95,8 +96,7 @@ func TestGetTickerByExchange(t *testing.T) {
 		PriceATH:     1337,
 	}
 
-	anxTicker := CreateNewTicker("ANX", newPair, priceStruct)
-	Tickers = append(Tickers, anxTicker)
+	CreateNewTicker("ANX", newPair, priceStruct)
 
 	tickerPtr, err := GetTickerByExchange("ANX")
 	if err != nil {
@@ -123,8 +123,7 @@ func TestFirstCurrencyExists(t *testing.T) {
 		PriceATH:     1337,
 	}
 
-	alphaTicker := CreateNewTicker("alphapoint", newPair, priceStruct)
-	Tickers = append(Tickers, alphaTicker)
+	CreateNewTicker("alphapoint", newPair, priceStruct)
 
 	if !FirstCurrencyExists("alphapoint", "BTC") {
 		t.Error("Test Failed - FirstCurrencyExists1 value return is incorrect")
@@ -150,8 +149,7 @@ func TestSecondCurrencyExists(t *testing.T) {
 		PriceATH:     1337,
 	}
 
-	bitstampTicker := CreateNewTicker("bitstamp", newPair, priceStruct)
-	Tickers = append(Tickers, bitstampTicker)
+	CreateNewTicker("bitstamp", newPair, priceStruct)
 
 	if !SecondCurrencyExists("bitstamp", newPair) {
 		t.Error("Test Failed - SecondCurrencyExists1 value return is incorrect")
@@ -238,3 +236,111 @@ func TestProcessTicker(t *testing.T) { //non-appending function to tickers
 
 	ProcessTicker("btcc", newPair, priceStruct)
 }
+
+func TestSetClock(t *testing.T) {
+	recorded := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetClock("clocktest", func() time.Time { return recorded })
+	defer SetClock("clocktest", nil)
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	ProcessTicker("clocktest", newPair, TickerPrice{Last: 1200})
+	price, err := GetTicker("clocktest", newPair)
+	if err != nil || !price.LastUpdated.Equal(recorded) {
+		t.Errorf("Test Failed - SetClock() expected %s, received %s", recorded, price.LastUpdated)
+	}
+
+	SetClock("clocktest", nil)
+	ProcessTicker("clocktest", newPair, TickerPrice{Last: 1201})
+	price, _ = GetTicker("clocktest", newPair)
+	if price.LastUpdated.Equal(recorded) {
+		t.Error("Test Failed - SetClock() did not restore time.Now")
+	}
+}
+
+func TestProcessTickerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			ProcessTicker("concurrent", pair.NewCurrencyPair("BTC", "USD"), TickerPrice{Last: float64(i)})
+			ProcessTicker("concurrent", pair.NewCurrencyPair("LTC", "USD"), TickerPrice{Last: float64(i)})
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	tickerPtr, err := GetTickerByExchange("concurrent")
+	if err != nil || len(tickerPtr.Price) != 2 {
+		t.Fatal("Test Failed - ProcessTicker lost concurrent updates")
+	}
+
+	price, err := GetTicker("concurrent", pair.NewCurrencyPair("LTC", "USD"))
+	if err != nil || price.LastUpdated.IsZero() || price.ExchangeName != "concurrent" {
+		t.Error("Test Failed - ProcessTicker did not set the ticker timestamp and exchange")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	subscription := Subscribe("subscribe", newPair)
+	other := Subscribe("subscribe", pair.NewCurrencyPair("LTC", "USD"))
+	defer Unsubscribe(other)
+
+	ProcessTicker("subscribe", newPair, TickerPrice{Last: 1000})
+	ProcessTicker("subscribe", newPair, TickerPrice{Last: 1000})
+	ProcessTicker("subscribe", newPair, TickerPrice{Last: 1001})
+
+	for _, expected := range []float64{1000, 1001} {
+		select {
+		case price := <-subscription.C:
+			if price.Last != expected {
+				t.Errorf("Test Failed - Subscribe pushed %f, expected %f", price.Last, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Test Failed - Subscribe did not push the ticker change")
+		}
+	}
+
+	select {
+	case <-subscription.C:
+		t.Error("Test Failed - Subscribe pushed an unchanged ticker")
+	case <-other.C:
+		t.Error("Test Failed - Subscribe pushed a ticker of another pair")
+	default:
+	}
+
+	Unsubscribe(subscription)
+	if _, ok := <-subscription.C; ok {
+		t.Error("Test Failed - Unsubscribe did not close the subscription")
+	}
+}
+
+func TestSubscribeSlowConsumer(t *testing.T) {
+	t.Parallel()
+
+	newPair := pair.NewCurrencyPair("BTC", "EUR")
+	subscription := Subscribe("slowsubscriber", newPair)
+	defer Unsubscribe(subscription)
+
+	last := float64(3 * TICKER_SUBSCRIPTION_BUFFER)
+	for i := 1; i <= int(last); i++ {
+		ProcessTicker("slowsubscriber", newPair, TickerPrice{Last: float64(i)})
+	}
+
+	var received float64
+	for received != last {
+		select {
+		case price := <-subscription.C:
+			if price.Last <= received {
+				t.Fatalf("Test Failed - Subscribe pushed %f after %f", price.Last, received)
+			}
+			received = price.Last
+		case <-time.After(time.Second):
+			t.Fatalf("Test Failed - Subscribe did not push the newest ticker, last received %f", received)
+		}
+	}
+}
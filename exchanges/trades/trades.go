@@ -0,0 +1,154 @@
+package trades
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+const (
+	TRADE_SIDE_BUY     = "BUY"
+	TRADE_SIDE_SELL    = "SELL"
+	TRADE_SIDE_UNKNOWN = "UNKNOWN"
+
+	TRADE_HISTORY_LENGTH = 1000
+)
+
+var (
+	ErrTradesForExchangeNotFound = "Trades for exchange do not exist."
+	ErrPrimaryCurrencyNotFound   = "Error primary currency for trades not found."
+	ErrSecondaryCurrencyNotFound = "Error secondary currency for trades not found."
+
+	Trades   []TradeHistory
+	tradeMtx sync.RWMutex
+)
+
+//Trade : A single public trade print. Side is the taker side of the trade
+type Trade struct {
+	Pair      pair.CurrencyPair `json:"pair"`
+	TradeID   string            `json:"trade_id"`
+	Side      string            `json:"side"`
+	Price     float64           `json:"price"`
+	Amount    float64           `json:"amount"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+//TradeHistory : Most recent trades of every pair of an exchange
+type TradeHistory struct {
+	Trades       map[pair.CurrencyItem]map[pair.CurrencyItem][]Trade
+	ExchangeName string
+}
+
+//ByTimestamp : Sorts trades by execution time
+type ByTimestamp []Trade
+
+func (t ByTimestamp) Len() int {
+	return len(t)
+}
+
+func (t ByTimestamp) Less(i, j int) bool {
+	return t[i].Timestamp.Before(t[j].Timestamp)
+}
+
+func (t ByTimestamp) Swap(i, j int) {
+	t[i], t[j] = t[j], t[i]
+}
+
+//GetTradeKey returns the key used to deduplicate a trade. Exchanges which do
+//not publish trade IDs are keyed on the trade contents
+func (t *Trade) GetTradeKey() string {
+	if t.TradeID != "" {
+		return t.TradeID
+	}
+	return strconv.FormatInt(t.Timestamp.UnixNano(), 10) + t.Side +
+		strconv.FormatFloat(t.Price, 'f', -1, 64) + strconv.FormatFloat(t.Amount, 'f', -1, 64)
+}
+
+//GetTrades returns a copy of the stored trades for an exchange pair, oldest first
+func GetTrades(exchange string, p pair.CurrencyPair) ([]Trade, error) {
+	tradeMtx.RLock()
+	defer tradeMtx.RUnlock()
+
+	history, err := getTradeHistoryByExchange(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	first, ok := history.Trades[p.GetFirstCurrency()]
+	if !ok {
+		return nil, errors.New(ErrPrimaryCurrencyNotFound)
+	}
+
+	stored, ok := first[p.GetSecondCurrency()]
+	if !ok {
+		return nil, errors.New(ErrSecondaryCurrencyNotFound)
+	}
+
+	result := make([]Trade, len(stored))
+	copy(result, stored)
+	return result, nil
+}
+
+func getTradeHistoryByExchange(exchange string) (*TradeHistory, error) {
+	for x := range Trades {
+		if Trades[x].ExchangeName == exchange {
+			return &Trades[x], nil
+		}
+	}
+	return nil, errors.New(ErrTradesForExchangeNotFound)
+}
+
+//ProcessTrades merges new trades into the store for an exchange pair. Trades
+//already stored are skipped and only the latest TRADE_HISTORY_LENGTH are kept.
+//The trades which were not stored yet are returned, oldest first
+func ProcessTrades(exchangeName string, p pair.CurrencyPair, newTrades []Trade) []Trade {
+	tradeMtx.Lock()
+	defer tradeMtx.Unlock()
+
+	history, err := getTradeHistoryByExchange(exchangeName)
+	if err != nil {
+		Trades = append(Trades, TradeHistory{
+			Trades:       make(map[pair.CurrencyItem]map[pair.CurrencyItem][]Trade),
+			ExchangeName: exchangeName,
+		})
+		history = &Trades[len(Trades)-1]
+	}
+
+	if _, ok := history.Trades[p.GetFirstCurrency()]; !ok {
+		history.Trades[p.GetFirstCurrency()] = make(map[pair.CurrencyItem][]Trade)
+	}
+	stored := history.Trades[p.GetFirstCurrency()][p.GetSecondCurrency()]
+
+	known := make(map[string]bool)
+	for _, x := range stored {
+		known[x.GetTradeKey()] = true
+	}
+
+	var added []Trade
+	for _, x := range newTrades {
+		key := x.GetTradeKey()
+		if known[key] {
+			continue
+		}
+		known[key] = true
+		x.Pair = p
+		added = append(added, x)
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	sort.Stable(ByTimestamp(added))
+	stored = append(stored, added...)
+	sort.Stable(ByTimestamp(stored))
+	if len(stored) > TRADE_HISTORY_LENGTH {
+		stored = stored[len(stored)-TRADE_HISTORY_LENGTH:]
+	}
+	history.Trades[p.GetFirstCurrency()][p.GetSecondCurrency()] = stored
+	return added
+}
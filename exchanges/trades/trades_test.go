@@ -0,0 +1,75 @@
+package trades
+
+import (
+	"testing"
+	"time"
+
+	"github.com/champii/gocryptotrader/currency/pair"
+)
+
+func TestProcessTrades(t *testing.T) {
+	newPair := pair.NewCurrencyPair("BTC", "USD")
+	now := time.Now()
+
+	added := ProcessTrades("ProcessTest", newPair, []Trade{
+		{TradeID: "2", Side: TRADE_SIDE_SELL, Price: 1001, Amount: 1, Timestamp: now},
+		{TradeID: "1", Side: TRADE_SIDE_BUY, Price: 1000, Amount: 2, Timestamp: now.Add(-time.Second)},
+	})
+	if len(added) != 2 || added[0].TradeID != "1" {
+		t.Error("Test Failed - ProcessTrades() did not return the new trades oldest first")
+	}
+
+	added = ProcessTrades("ProcessTest", newPair, []Trade{
+		{TradeID: "2", Side: TRADE_SIDE_SELL, Price: 1001, Amount: 1, Timestamp: now},
+		{TradeID: "3", Side: TRADE_SIDE_BUY, Price: 1002, Amount: 1, Timestamp: now.Add(time.Second)},
+	})
+	if len(added) != 1 || added[0].TradeID != "3" {
+		t.Error("Test Failed - ProcessTrades() returned an already stored trade")
+	}
+
+	result, err := GetTrades("ProcessTest", newPair)
+	if err != nil {
+		t.Fatalf("Test Failed - GetTrades() error: %s", err)
+	}
+
+	if len(result) != 3 || result[0].TradeID != "1" || result[2].TradeID != "3" {
+		t.Error("Test Failed - GetTrades() returned unexpected trades")
+	}
+
+	if result[0].Pair.Pair() != newPair.Pair() {
+		t.Error("Test Failed - ProcessTrades() did not set the trade pair")
+	}
+}
+
+func TestProcessTradesLength(t *testing.T) {
+	newPair := pair.NewCurrencyPair("LTC", "USD")
+	start := time.Now()
+
+	var newTrades []Trade
+	for i := 0; i < TRADE_HISTORY_LENGTH+10; i++ {
+		newTrades = append(newTrades, Trade{Price: 1, Amount: float64(i), Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+	ProcessTrades("LengthTest", newPair, newTrades)
+
+	result, err := GetTrades("LengthTest", newPair)
+	if err != nil {
+		t.Fatalf("Test Failed - GetTrades() error: %s", err)
+	}
+
+	if len(result) != TRADE_HISTORY_LENGTH || result[0].Amount != 10 {
+		t.Error("Test Failed - ProcessTrades() did not keep the latest trades")
+	}
+}
+
+func TestGetTrades(t *testing.T) {
+	_, err := GetTrades("NotAnExchange", pair.NewCurrencyPair("BTC", "USD"))
+	if err == nil {
+		t.Error("Test Failed - GetTrades() returned trades for an unknown exchange")
+	}
+
+	ProcessTrades("GetTest", pair.NewCurrencyPair("BTC", "USD"), []Trade{{TradeID: "1"}})
+	_, err = GetTrades("GetTest", pair.NewCurrencyPair("BTC", "EUR"))
+	if err == nil {
+		t.Error("Test Failed - GetTrades() returned trades for an unknown pair")
+	}
+}
@@ -0,0 +1,639 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/champii/gocryptotrader/common"
+)
+
+const (
+	WEBSOCKET_STATE_DISCONNECTED = "DISCONNECTED"
+	WEBSOCKET_STATE_CONNECTING   = "CONNECTING"
+	WEBSOCKET_STATE_CONNECTED    = "CONNECTED"
+	WEBSOCKET_STATE_STOPPED      = "STOPPED"
+
+	ErrWebsocketNotConnected = "%s websocket is not connected."
+	ErrWebsocketStale        = "%s websocket received no message for %s."
+)
+
+const (
+	WEBSOCKET_DEFAULT_MIN_BACKOFF   = time.Second
+	WEBSOCKET_DEFAULT_MAX_BACKOFF   = time.Minute
+	WEBSOCKET_DEFAULT_PING_INTERVAL = 30 * time.Second
+	WEBSOCKET_DEFAULT_STALE_TIMEOUT = 2 * time.Minute
+	WEBSOCKET_HANDSHAKE_TIMEOUT     = 30 * time.Second
+	WEBSOCKET_WRITE_TIMEOUT         = 10 * time.Second
+	WEBSOCKET_MONITOR_INTERVAL      = time.Second
+	WEBSOCKET_EVENT_BUFFER          = 100
+)
+
+var websocketMtx sync.Mutex
+
+//WebsocketEvent : Connection state change pushed to the event subscribers. Err
+//holds the reason a connection was lost
+type WebsocketEvent struct {
+	ExchangeName string
+	State        string
+	Err          error
+	Timestamp    time.Time
+}
+
+//WebsocketSubscription : Channel subscription sent again after every reconnect.
+//The meaning of Params is left to the exchange
+type WebsocketSubscription struct {
+	Channel string
+	Params  map[string]string
+}
+
+//WebsocketBackoff : Exponential reconnect delay with jitter. The delay doubles
+//after every attempt from Min up to Max, and a random half of it is dropped so
+//clients disconnected together do not reconnect together
+type WebsocketBackoff struct {
+	Min      time.Duration
+	Max      time.Duration
+	attempts int
+}
+
+//WebsocketConnection : Websocket connection of an exchange kept up by Run. The
+//connection is redialed with backoff when it fails or no message arrives within
+//StaleTimeout, and the registered subscriptions are sent again once connected.
+//The handlers are called from the goroutine running Run
+type WebsocketConnection struct {
+	ExchangeName string
+	URL          string
+	Verbose      bool
+	Backoff      WebsocketBackoff
+	PingInterval time.Duration
+	StaleTimeout time.Duration
+
+	//OnConnect is called once connected, before the subscriptions are sent
+	OnConnect     func() error
+	OnSubscribe   func(subscription WebsocketSubscription) error
+	OnUnsubscribe func(subscription WebsocketSubscription) error
+	OnMessage     func(messageType int, data []byte)
+	//OnDisconnect is called once a connection which reached the connected state
+	//is lost, so the exchange can drop state rebuilt on the next connection
+	OnDisconnect func()
+	//Ping replaces the websocket ping frame for exchanges using a ping message
+	Ping func() error
+
+	mtx           sync.Mutex
+	writeMtx      sync.Mutex
+	conn          *websocket.Conn
+	closeErr      error
+	state         string
+	connects      int64
+	replaying     bool
+	lastMessage   time.Time
+	subscriptions map[string]WebsocketSubscription
+	listeners     []chan WebsocketEvent
+}
+
+//NewWebsocketConnection returns a disconnected connection to url using the
+//default backoff, ping interval and stale timeout
+func NewWebsocketConnection(exchangeName, url string) *WebsocketConnection {
+	return &WebsocketConnection{
+		ExchangeName:  exchangeName,
+		URL:           url,
+		Backoff:       WebsocketBackoff{Min: WEBSOCKET_DEFAULT_MIN_BACKOFF, Max: WEBSOCKET_DEFAULT_MAX_BACKOFF},
+		PingInterval:  WEBSOCKET_DEFAULT_PING_INTERVAL,
+		StaleTimeout:  WEBSOCKET_DEFAULT_STALE_TIMEOUT,
+		state:         WEBSOCKET_STATE_DISCONNECTED,
+		subscriptions: make(map[string]WebsocketSubscription),
+	}
+}
+
+//Next returns the delay before the next reconnect attempt
+func (b *WebsocketBackoff) Next() time.Duration {
+	min, max := b.Min, b.Max
+	if min <= 0 {
+		min = WEBSOCKET_DEFAULT_MIN_BACKOFF
+	}
+	if max <= 0 {
+		max = WEBSOCKET_DEFAULT_MAX_BACKOFF
+	}
+	if max < min {
+		max = min
+	}
+
+	delay := min
+	for i := 0; i < b.attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay >= max {
+		delay = max
+	} else {
+		b.attempts++
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+//Reset restarts the delay from Min, once a connection is known to work
+func (b *WebsocketBackoff) Reset() {
+	b.attempts = 0
+}
+
+//Wait sleeps for the next delay. It returns false when ctx is cancelled first
+func (b *WebsocketBackoff) Wait(ctx context.Context) bool {
+	timer := time.NewTimer(b.Next())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (s WebsocketSubscription) key() string {
+	params := []string{}
+	for k, v := range s.Params {
+		params = append(params, k+"="+v)
+	}
+	sort.Strings(params)
+	return s.Channel + "?" + common.JoinStrings(params, "&")
+}
+
+//Connect dials the endpoint once, replacing the current connection. A replayed
+//connection is not dialed
+func (w *WebsocketConnection) Connect() error {
+	if w.isReplaying() {
+		w.touch()
+		return nil
+	}
+
+	dialer := websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: WEBSOCKET_HANDSHAKE_TIMEOUT}
+	conn, _, err := dialer.Dial(w.URL, http.Header{})
+	if err != nil {
+		return err
+	}
+
+	conn.SetPongHandler(func(string) error {
+		w.touch()
+		return nil
+	})
+	conn.SetPingHandler(func(data string) error {
+		w.touch()
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(WEBSOCKET_WRITE_TIMEOUT))
+		if err == websocket.ErrCloseSent {
+			return nil
+		}
+		return err
+	})
+
+	w.mtx.Lock()
+	previous := w.conn
+	w.conn = conn
+	w.closeErr = nil
+	w.lastMessage = time.Now()
+	w.mtx.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+//ReadMessage blocks until the next message of the current connection arrives
+func (w *WebsocketConnection) ReadMessage() (int, []byte, error) {
+	conn := w.getConn()
+	if conn == nil {
+		return 0, nil, fmt.Errorf(ErrWebsocketNotConnected, w.ExchangeName)
+	}
+
+	messageType, data, err := conn.ReadMessage()
+	if err != nil {
+		return messageType, data, err
+	}
+	w.touch()
+	RecordWebsocketMessage(w.ExchangeName)
+	GetStreamRecorder(w.ExchangeName).RecordWebsocket(messageType, data)
+	return messageType, data, nil
+}
+
+//Send writes a text message to the current connection. Writes from several
+//goroutines are serialised. Messages sent while replaying are dropped
+func (w *WebsocketConnection) Send(data []byte) error {
+	if w.isReplaying() {
+		return nil
+	}
+
+	conn := w.getConn()
+	if conn == nil {
+		return fmt.Errorf(ErrWebsocketNotConnected, w.ExchangeName)
+	}
+
+	w.writeMtx.Lock()
+	defer w.writeMtx.Unlock()
+
+	err := conn.SetWriteDeadline(time.Now().Add(WEBSOCKET_WRITE_TIMEOUT))
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+//SendJSON encodes data and sends it as a text message
+func (w *WebsocketConnection) SendJSON(data interface{}) error {
+	json, err := common.JSONEncode(data)
+	if err != nil {
+		return err
+	}
+	return w.Send(json)
+}
+
+//Close closes the current connection. A running Run reconnects, cancel its
+//context to stop it instead
+func (w *WebsocketConnection) Close() error {
+	w.mtx.Lock()
+	conn := w.conn
+	w.conn = nil
+	w.mtx.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
+
+//Run connects and keeps the connection up until ctx is cancelled, when the
+//connection is closed and Run returns. When the exchange replays a recording,
+//Run returns once the recording ends instead
+func (w *WebsocketConnection) Run(ctx context.Context) {
+	if replayer := GetStreamReplayer(w.ExchangeName); replayer != nil {
+		w.replay(ctx, replayer)
+		return
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.Close()
+		case <-stop:
+		}
+	}()
+
+	for ctx.Err() == nil {
+		w.setState(WEBSOCKET_STATE_CONNECTING, nil)
+		err := w.connect(ctx)
+		if err == nil {
+			err = w.read()
+		}
+
+		if w.GetState() == WEBSOCKET_STATE_CONNECTED && w.OnDisconnect != nil {
+			w.OnDisconnect()
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+		w.setState(WEBSOCKET_STATE_DISCONNECTED, err)
+
+		delay := w.Backoff.Next()
+		log.Printf("%s Websocket disconnected, reconnecting in %s. Error: %s\n", w.ExchangeName, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+
+	w.Close()
+	w.setState(WEBSOCKET_STATE_STOPPED, nil)
+	if w.Verbose {
+		log.Printf("%s Websocket stopped.\n", w.ExchangeName)
+	}
+}
+
+//Subscribe registers the subscription, so it is sent on every connection, and
+//sends it now when connected
+func (w *WebsocketConnection) Subscribe(subscription WebsocketSubscription) error {
+	w.mtx.Lock()
+	w.subscriptions[subscription.key()] = subscription
+	connected := w.state == WEBSOCKET_STATE_CONNECTED
+	w.mtx.Unlock()
+
+	if !connected || w.OnSubscribe == nil {
+		return nil
+	}
+	return w.OnSubscribe(subscription)
+}
+
+//Unsubscribe removes the subscription, unsubscribing now when connected
+func (w *WebsocketConnection) Unsubscribe(subscription WebsocketSubscription) error {
+	w.mtx.Lock()
+	delete(w.subscriptions, subscription.key())
+	connected := w.state == WEBSOCKET_STATE_CONNECTED
+	w.mtx.Unlock()
+
+	if !connected || w.OnUnsubscribe == nil {
+		return nil
+	}
+	return w.OnUnsubscribe(subscription)
+}
+
+//GetSubscriptions returns the registered subscriptions in the order they are
+//sent
+func (w *WebsocketConnection) GetSubscriptions() []WebsocketSubscription {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.getSubscriptions()
+}
+
+//GetState returns the connection state
+func (w *WebsocketConnection) GetState() string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.state
+}
+
+//IsConnected returns whether the connection is up and subscribed
+func (w *WebsocketConnection) IsConnected() bool {
+	return w.GetState() == WEBSOCKET_STATE_CONNECTED
+}
+
+//GetLastMessage returns when the last message, ping or pong was received
+func (w *WebsocketConnection) GetLastMessage() time.Time {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.lastMessage
+}
+
+//GetReconnects returns how many times the connection was established again
+//after the first connection
+func (w *WebsocketConnection) GetReconnects() int64 {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.connects == 0 {
+		return 0
+	}
+	return w.connects - 1
+}
+
+//SubscribeEvents returns a channel receiving the connection state changes.
+//Events are dropped for subscribers which are not keeping up
+func (w *WebsocketConnection) SubscribeEvents() <-chan WebsocketEvent {
+	c := make(chan WebsocketEvent, WEBSOCKET_EVENT_BUFFER)
+	w.mtx.Lock()
+	w.listeners = append(w.listeners, c)
+	w.mtx.Unlock()
+	return c
+}
+
+//UnsubscribeEvents stops the events sent to c and closes it
+func (w *WebsocketConnection) UnsubscribeEvents(c <-chan WebsocketEvent) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for i, x := range w.listeners {
+		if x == c {
+			w.listeners = append(w.listeners[:i], w.listeners[i+1:]...)
+			close(x)
+			return
+		}
+	}
+}
+
+func (w *WebsocketConnection) connect(ctx context.Context) error {
+	err := w.Connect()
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		w.Close()
+		return ctx.Err()
+	}
+
+	if w.OnConnect != nil {
+		err = w.OnConnect()
+		if err != nil {
+			w.Close()
+			return err
+		}
+	}
+
+	w.mtx.Lock()
+	w.connects++
+	subscriptions := w.getSubscriptions()
+	w.setStateLocked(WEBSOCKET_STATE_CONNECTED, nil)
+	w.mtx.Unlock()
+
+	if w.Verbose {
+		log.Printf("%s Connected to Websocket.\n", w.ExchangeName)
+	}
+
+	if w.OnSubscribe == nil {
+		return nil
+	}
+
+	for _, x := range subscriptions {
+		err = w.OnSubscribe(x)
+		if err != nil {
+			log.Printf("%s Websocket subscription error: %s\n", w.ExchangeName, err)
+		}
+	}
+	return nil
+}
+
+func (w *WebsocketConnection) read() error {
+	done := make(chan struct{})
+	defer close(done)
+	go w.monitor(done)
+
+	for {
+		messageType, data, err := w.ReadMessage()
+		if err != nil {
+			w.mtx.Lock()
+			if w.closeErr != nil {
+				err = w.closeErr
+			}
+			w.mtx.Unlock()
+			return err
+		}
+
+		w.Backoff.Reset()
+		if w.OnMessage != nil {
+			w.OnMessage(messageType, data)
+		}
+	}
+}
+
+//replay passes the recorded messages to OnMessage. Recorded connections go
+//through the handlers of a real connection, so the exchange state is rebuilt
+//the same way
+func (w *WebsocketConnection) replay(ctx context.Context, replayer *StreamReplayer) {
+	w.mtx.Lock()
+	w.replaying = true
+	w.mtx.Unlock()
+	log.Printf("%s Replaying websocket recording.\n", w.ExchangeName)
+
+	for {
+		entry, err := replayer.NextWebsocket(ctx)
+		if err != nil {
+			break
+		}
+
+		if entry.State != "" {
+			if w.GetState() == WEBSOCKET_STATE_CONNECTED && w.OnDisconnect != nil {
+				w.OnDisconnect()
+			}
+
+			if entry.State == WEBSOCKET_STATE_CONNECTED {
+				w.setState(WEBSOCKET_STATE_CONNECTING, nil)
+				err = w.connect(ctx)
+				if err != nil {
+					log.Printf("%s Websocket replay connection error: %s\n", w.ExchangeName, err)
+				}
+				continue
+			}
+			w.setState(WEBSOCKET_STATE_DISCONNECTED, nil)
+			continue
+		}
+
+		w.touch()
+		RecordWebsocketMessage(w.ExchangeName)
+		if w.OnMessage != nil {
+			w.OnMessage(entry.MessageType, entry.Data)
+		}
+	}
+
+	w.setState(WEBSOCKET_STATE_STOPPED, nil)
+	log.Printf("%s Websocket replay finished.\n", w.ExchangeName)
+}
+
+//monitor pings the exchange and drops the connection once the feed is stale,
+//until done is closed
+func (w *WebsocketConnection) monitor(done chan struct{}) {
+	interval := WEBSOCKET_MONITOR_INTERVAL
+	if w.StaleTimeout > 0 && w.StaleTimeout/2 < interval {
+		interval = w.StaleTimeout / 2
+	}
+	check := time.NewTicker(interval)
+	defer check.Stop()
+	lastPing := time.Now()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-check.C:
+			if w.PingInterval > 0 && now.Sub(lastPing) >= w.PingInterval {
+				lastPing = now
+				err := w.ping()
+				if err != nil && w.Verbose {
+					log.Printf("%s Websocket ping error: %s\n", w.ExchangeName, err)
+				}
+			}
+
+			idle := now.Sub(w.GetLastMessage())
+			if w.StaleTimeout > 0 && idle > w.StaleTimeout {
+				w.mtx.Lock()
+				w.closeErr = fmt.Errorf(ErrWebsocketStale, w.ExchangeName, idle)
+				w.mtx.Unlock()
+				w.Close()
+				return
+			}
+		}
+	}
+}
+
+func (w *WebsocketConnection) ping() error {
+	if w.Ping != nil {
+		return w.Ping()
+	}
+
+	conn := w.getConn()
+	if conn == nil {
+		return fmt.Errorf(ErrWebsocketNotConnected, w.ExchangeName)
+	}
+	return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(WEBSOCKET_WRITE_TIMEOUT))
+}
+
+func (w *WebsocketConnection) isReplaying() bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.replaying
+}
+
+func (w *WebsocketConnection) getConn() *websocket.Conn {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.conn
+}
+
+func (w *WebsocketConnection) touch() {
+	w.mtx.Lock()
+	w.lastMessage = time.Now()
+	w.mtx.Unlock()
+}
+
+func (w *WebsocketConnection) getSubscriptions() []WebsocketSubscription {
+	keys := []string{}
+	for x := range w.subscriptions {
+		keys = append(keys, x)
+	}
+	sort.Strings(keys)
+
+	result := []WebsocketSubscription{}
+	for _, x := range keys {
+		result = append(result, w.subscriptions[x])
+	}
+	return result
+}
+
+func (w *WebsocketConnection) setState(state string, err error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.setStateLocked(state, err)
+}
+
+func (w *WebsocketConnection) setStateLocked(state string, err error) {
+	w.state = state
+	RecordWebsocketState(w.ExchangeName, state)
+	event := WebsocketEvent{ExchangeName: w.ExchangeName, State: state, Err: err, Timestamp: time.Now()}
+	for _, x := range w.listeners {
+		select {
+		case x <- event:
+		default:
+		}
+	}
+}
+
+//GetWebsocketContext returns the context the websocket clients of the exchange
+//run under
+func (e *ExchangeBase) GetWebsocketContext() context.Context {
+	websocketMtx.Lock()
+	defer websocketMtx.Unlock()
+
+	if e.websocketCtx == nil {
+		e.websocketCtx, e.websocketCancel = context.WithCancel(context.Background())
+	}
+	return e.websocketCtx
+}
+
+//StopWebsocket cancels the websocket context, closing the websocket clients of
+//the exchange. Clients started afterwards run under a new context
+func (e *ExchangeBase) StopWebsocket() {
+	websocketMtx.Lock()
+	defer websocketMtx.Unlock()
+
+	if e.websocketCancel != nil {
+		e.websocketCancel()
+	}
+	e.websocketCtx = nil
+	e.websocketCancel = nil
+}
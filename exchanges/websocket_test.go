@@ -0,0 +1,181 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func newWebsocketTestServer(handler func(connection int, conn *websocket.Conn)) (*httptest.Server, string) {
+	var mtx sync.Mutex
+	connections := 0
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mtx.Lock()
+		connections++
+		connection := connections
+		mtx.Unlock()
+		handler(connection, conn)
+	}))
+	return server, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func waitWebsocketEvent(t *testing.T, events <-chan WebsocketEvent, state string) WebsocketEvent {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.State == state {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("Test Failed - websocket state %s not reached", state)
+		}
+	}
+}
+
+func TestWebsocketBackoff(t *testing.T) {
+	backoff := WebsocketBackoff{Min: 100 * time.Millisecond, Max: 400 * time.Millisecond}
+	expected := []time.Duration{100, 200, 400, 400}
+	for _, x := range expected {
+		delay := backoff.Next()
+		if delay < x*time.Millisecond/2 || delay > x*time.Millisecond {
+			t.Errorf("Test Failed - WebsocketBackoff Next() expected between %dms and %dms, received %s", x/2, x, delay)
+		}
+	}
+
+	backoff.Reset()
+	if delay := backoff.Next(); delay > 100*time.Millisecond {
+		t.Errorf("Test Failed - WebsocketBackoff Reset() did not restart the delay, received %s", delay)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if backoff.Wait(ctx) {
+		t.Error("Test Failed - WebsocketBackoff Wait() did not stop on a cancelled context")
+	}
+}
+
+func TestWebsocketConnectionReconnect(t *testing.T) {
+	received := make(chan string, 10)
+	server, url := newWebsocketTestServer(func(connection int, conn *websocket.Conn) {
+		for i := 0; i < 2; i++ {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- fmt.Sprintf("%d:%s", connection, data)
+		}
+
+		if connection == 1 {
+			return
+		}
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	w := NewWebsocketConnection("test", url)
+	w.Backoff = WebsocketBackoff{Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}
+	w.OnSubscribe = func(subscription WebsocketSubscription) error {
+		return w.Send([]byte(subscription.Channel))
+	}
+	disconnects := make(chan bool, 10)
+	w.OnDisconnect = func() {
+		disconnects <- true
+	}
+	w.Subscribe(WebsocketSubscription{Channel: "b"})
+	w.Subscribe(WebsocketSubscription{Channel: "a", Params: map[string]string{"pair": "BTCUSD"}})
+	events := w.SubscribeEvents()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan bool)
+	go func() {
+		w.Run(ctx)
+		close(stopped)
+	}()
+
+	expected := []string{"1:a", "1:b", "2:a", "2:b"}
+	for _, x := range expected {
+		select {
+		case message := <-received:
+			if message != x {
+				t.Errorf("Test Failed - websocket subscription expected %s, received %s", x, message)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Test Failed - websocket subscription %s not received", x)
+		}
+	}
+
+	event := waitWebsocketEvent(t, events, WEBSOCKET_STATE_DISCONNECTED)
+	if event.Err == nil || event.ExchangeName != "test" {
+		t.Errorf("Test Failed - websocket disconnect event incorrect: %+v", event)
+	}
+	waitWebsocketEvent(t, events, WEBSOCKET_STATE_CONNECTED)
+
+	if w.GetReconnects() != 1 {
+		t.Errorf("Test Failed - websocket expected 1 reconnect, received %d", w.GetReconnects())
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Test Failed - websocket Run() did not stop once the context was cancelled")
+	}
+
+	if w.GetState() != WEBSOCKET_STATE_STOPPED {
+		t.Errorf("Test Failed - websocket expected state %s, received %s", WEBSOCKET_STATE_STOPPED, w.GetState())
+	}
+
+	if len(disconnects) != 2 {
+		t.Errorf("Test Failed - websocket expected 2 disconnects, received %d", len(disconnects))
+	}
+
+	if err := w.Send([]byte("a")); err == nil {
+		t.Error("Test Failed - websocket Send() succeeded once stopped")
+	}
+}
+
+func TestWebsocketConnectionStale(t *testing.T) {
+	done := make(chan bool)
+	server, url := newWebsocketTestServer(func(connection int, conn *websocket.Conn) {
+		<-done
+	})
+	defer server.Close()
+	defer close(done)
+
+	w := NewWebsocketConnection("test", url)
+	w.PingInterval = 0
+	w.StaleTimeout = 100 * time.Millisecond
+	w.Backoff = WebsocketBackoff{Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}
+	events := w.SubscribeEvents()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	event := waitWebsocketEvent(t, events, WEBSOCKET_STATE_DISCONNECTED)
+	if event.Err == nil || !strings.Contains(event.Err.Error(), "received no message") {
+		t.Errorf("Test Failed - websocket stale disconnect incorrect: %v", event.Err)
+	}
+	w.UnsubscribeEvents(events)
+}
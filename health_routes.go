@@ -0,0 +1,53 @@
+package gocryptotrader
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/gorilla/mux"
+)
+
+type AllExchangeHealth struct {
+	Data []exchange.ExchangeHealth `json:"data"`
+}
+
+func getAllExchangeHealthResponse(w http.ResponseWriter, r *http.Request) {
+	response := AllExchangeHealth{Data: bot.GetExchangeHealth()}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		panic(err)
+	}
+}
+
+func getExchangeHealthResponse(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	response, err := bot.GetExchangeHealthByName(vars["exchangeName"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		panic(err)
+	}
+}
+
+var HealthRoutes = Routes{
+	Route{
+		"AllExchangesHealth",
+		"GET",
+		"/exchanges/health",
+		getAllExchangeHealthResponse,
+	},
+	Route{
+		"IndividualExchangeHealth",
+		"GET",
+		"/exchanges/{exchangeName}/health",
+		getExchangeHealthResponse,
+	},
+}
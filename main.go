@@ -1,12 +1,14 @@
 package gocryptotrader
 
 import (
+	"errors"
 	"log"
 	"os"
 	"os/signal"
 	"runtime"
 	"strconv"
 	"syscall"
+	"time"
 
 	"fmt"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/champii/gocryptotrader/config"
 	"github.com/champii/gocryptotrader/events"
 	"github.com/champii/gocryptotrader/exchanges"
+	"github.com/champii/gocryptotrader/exchanges/alphapoint"
 	"github.com/champii/gocryptotrader/exchanges/anx"
 	"github.com/champii/gocryptotrader/exchanges/bitfinex"
 	"github.com/champii/gocryptotrader/exchanges/bitstamp"
@@ -36,6 +39,7 @@ import (
 )
 
 type ExchangeMain struct {
+	alphapoint    alphapoint.Alphapoint
 	anx           anx.ANX
 	btcc          btcc.BTCC
 	bitstamp      bitstamp.Bitstamp
@@ -84,6 +88,29 @@ func setupBotExchanges() {
 	}
 }
 
+//addPlatformExchanges adds an exchange for every config entry run on a shared
+//trading platform, named after the entry. Entries named after an exchange
+//already in the list use that exchange
+func (b *Bot) addPlatformExchanges() {
+	for _, exch := range b.config.Exchanges {
+		if exch.Platform != config.EXCHANGE_PLATFORM_ALPHAPOINT {
+			continue
+		}
+
+		if existing := b.GetExchangeByName(exch.Name); existing != nil {
+			if _, ok := existing.(*alphapoint.Alphapoint); !ok {
+				log.Printf("%s: Exchange name is already used by a non %s exchange.\n", exch.Name, exch.Platform)
+			}
+			continue
+		}
+
+		platformExchange := alphapoint.NewAlphapoint(exch.Name)
+		platformExchange.SetDefaults()
+		b.Exchanges = append(b.Exchanges, platformExchange)
+		log.Printf("Exchange %s successfully set default settings.\n", platformExchange.GetName())
+	}
+}
+
 func (b *Bot) Test() {
 	fmt.Println("lol")
 }
@@ -101,6 +128,40 @@ func (b Bot) GetExchangeByName(name string) exchange.IBotExchange {
 	return nil
 }
 
+//GetExchangeHealth returns the health of every enabled exchange
+func (b *Bot) GetExchangeHealth() []exchange.ExchangeHealth {
+	result := []exchange.ExchangeHealth{}
+	for _, exch := range b.Exchanges {
+		if exch != nil && exch.IsEnabled() {
+			result = append(result, exch.GetHealth(b.getStaleTimeout(exch.GetName())))
+		}
+	}
+	return result
+}
+
+//GetExchangeHealthByName returns the health of the named exchange
+func (b *Bot) GetExchangeHealthByName(name string) (exchange.ExchangeHealth, error) {
+	exch := b.GetExchangeByName(name)
+	if exch == nil {
+		return exchange.ExchangeHealth{}, errors.New(exchange.ErrExchangeNotFound)
+	}
+	return exch.GetHealth(b.getStaleTimeout(name)), nil
+}
+
+//getStaleTimeout returns the market data staleness threshold configured for
+//the exchange, zero selecting the exchange package default
+func (b *Bot) getStaleTimeout(name string) time.Duration {
+	if b.config == nil {
+		return 0
+	}
+
+	exch, err := b.config.GetExchangeConfig(name)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(exch.StaleDataSeconds) * time.Second
+}
+
 func (b *Bot) Wait() {
 	<-b.shutdown
 	Shutdown()
@@ -135,6 +196,7 @@ func (b *Bot) Start(c chan string) {
 	log.Println("Bot Exchange support:")
 
 	b.Exchanges = []exchange.IBotExchange{
+		new(alphapoint.Alphapoint),
 		new(anx.ANX),
 		new(kraken.Kraken),
 		new(btcc.BTCC),
@@ -161,6 +223,7 @@ func (b *Bot) Start(c chan string) {
 		}
 	}
 
+	b.addPlatformExchanges()
 	setupBotExchanges()
 
 	b.config.RetrieveConfigCurrencyPairs()
@@ -231,6 +294,12 @@ func HandleInterrupt() {
 
 func Shutdown() {
 	log.Println("Bot shutting down..")
+	for _, exch := range bot.Exchanges {
+		if exch != nil {
+			exch.StopWebsocket()
+		}
+	}
+	exchange.CloseStreamRecorders()
 	bot.config.Portfolio = portfolio.Portfolio
 
 	// Do not save config on Exit
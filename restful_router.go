@@ -12,6 +12,7 @@ func NewRouter(exchanges []exchange.IBotExchange) *mux.Router {
 	allRoutes := append(routes, ExchangeRoutes...)
 	allRoutes = append(allRoutes, ConfigRoutes...)
 	allRoutes = append(allRoutes, WalletRoutes...)
+	allRoutes = append(allRoutes, HealthRoutes...)
 	for _, route := range allRoutes {
 		var handler http.Handler
 		handler = route.HandlerFunc